@@ -0,0 +1,116 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// SimpleAPI is a small facade over HttpClient for scripts and quick integrations with JSON APIs:
+//
+//	api := http.API("https://api.example.com")
+//	var users []User
+//	err := api.Get("/users").Query("active", "true").Into(&users)
+//
+// Requests pass the client's authentication, middleware and error mapping like any other. Client returns the
+// underlying HttpClient for everything the facade does not cover.
+type SimpleAPI struct {
+	client *HttpClient
+}
+
+// API creates a SimpleAPI for the JSON API at baseURL.
+func API(baseURL string, opts ...Option) *SimpleAPI {
+	return &SimpleAPI{client: NewHttpClientWithConfig(NewDefaultHttpConfig(baseURL, opts...))}
+}
+
+// Client returns the HttpClient behind the facade.
+func (a *SimpleAPI) Client() *HttpClient {
+	return a.client
+}
+
+// Get prepares a GET request for path.
+func (a *SimpleAPI) Get(path string) *APIRequest {
+	return a.request(http.MethodGet, path, Empty{})
+}
+
+// Post prepares a POST request for path sending body as JSON.
+func (a *SimpleAPI) Post(path string, body interface{}) *APIRequest {
+	return a.request(http.MethodPost, path, body)
+}
+
+// Put prepares a PUT request for path sending body as JSON.
+func (a *SimpleAPI) Put(path string, body interface{}) *APIRequest {
+	return a.request(http.MethodPut, path, body)
+}
+
+// Patch prepares a PATCH request for path sending body as JSON.
+func (a *SimpleAPI) Patch(path string, body interface{}) *APIRequest {
+	return a.request(http.MethodPatch, path, body)
+}
+
+// Delete prepares a DELETE request for path.
+func (a *SimpleAPI) Delete(path string) *APIRequest {
+	return a.request(http.MethodDelete, path, Empty{})
+}
+
+func (a *SimpleAPI) request(method string, path string, body interface{}) *APIRequest {
+	if body == nil {
+		body = Empty{}
+	}
+	return &APIRequest{client: a.client, ctx: context.Background(), body: body, spec: Spec{Method: method, Path: path}}
+}
+
+// APIRequest is a request prepared by SimpleAPI, sent by Into or Send.
+type APIRequest struct {
+	client *HttpClient
+	ctx    context.Context
+	spec   Spec
+	body   interface{}
+}
+
+// Context sends the request with ctx.
+func (r *APIRequest) Context(ctx context.Context) *APIRequest {
+	r.ctx = ctx
+	return r
+}
+
+// Query adds a query parameter.
+func (r *APIRequest) Query(key string, value string) *APIRequest {
+	if r.spec.Query == nil {
+		r.spec.Query = url.Values{}
+	}
+	r.spec.Query.Add(key, value)
+	return r
+}
+
+// Header sets a header.
+func (r *APIRequest) Header(key string, value string) *APIRequest {
+	if r.spec.Headers == nil {
+		r.spec.Headers = http.Header{}
+	}
+	r.spec.Headers.Set(key, value)
+	return r
+}
+
+// Into sends the request and decodes the response into out. Unsuccessful statuses are reported as error, like
+// UnauthorizedError or NotFoundError.
+func (r *APIRequest) Into(out interface{}) error {
+	body, err := encodeCallBody(r.spec.Method, r.client.contentType(), r.body)
+	if err != nil {
+		return err
+	}
+	resp, err := r.client.sendSpec(r.ctx, r.spec, body)
+	if err != nil {
+		return err
+	}
+	if out == nil {
+		drainAndClose(resp.Body)
+		return nil
+	}
+	return r.client.DecodeResponse(resp, out)
+}
+
+// Send sends the request and discards the response.
+func (r *APIRequest) Send() error {
+	return r.Into(nil)
+}
@@ -0,0 +1,55 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestAPI(t *testing.T) {
+	server := mockServerWith(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/users":
+			if r.URL.Query().Get("active") != "true" || r.Header.Get("X-Tenant") != "acme" {
+				t.Errorf("Expected query and header but got %s, %v", r.URL.RawQuery, r.Header)
+			}
+			w.Write([]byte(`[{"id": 1, "name": "jane"}]`))
+		case r.Method == http.MethodPost && r.URL.Path == "/users":
+			var user callUser
+			json.NewDecoder(r.Body).Decode(&user)
+			user.ID = 2
+			json.NewEncoder(w).Encode(user)
+		case r.Method == http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	defer server.Close()
+
+	api := API(server.URL)
+
+	var users []callUser
+	if err := api.Get("/users").Query("active", "true").Header("X-Tenant", "acme").Into(&users); err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 1 || users[0].Name != "jane" {
+		t.Errorf("Expected decoded users but got %+v", users)
+	}
+
+	var created callUser
+	if err := api.Post("/users", callUser{Name: "joe"}).Into(&created); err != nil || created.ID != 2 || created.Name != "joe" {
+		t.Errorf("Expected created user but got %+v, %v", created, err)
+	}
+
+	if err := api.Delete("/users/2").Send(); err != nil {
+		t.Errorf("Expected delete to succeed but got %v", err)
+	}
+
+	var notFound *NotFoundError
+	if err := api.Get("/missing").Into(&users); !errors.As(err, &notFound) {
+		t.Errorf("Expected NotFoundError but got %v", err)
+	}
+}
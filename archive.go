@@ -0,0 +1,347 @@
+package http
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// ArchiveFormat is the format of an archive extracted by ExtractArchive.
+type ArchiveFormat int
+
+const (
+	// ArchiveAuto detects the format by the leading bytes of the archive.
+	ArchiveAuto ArchiveFormat = iota
+	ArchiveTar
+	ArchiveTarGz
+	ArchiveZip
+)
+
+func (f ArchiveFormat) String() string {
+	switch f {
+	case ArchiveTar:
+		return "tar"
+	case ArchiveTarGz:
+		return "tar.gz"
+	case ArchiveZip:
+		return "zip"
+	default:
+		return "auto"
+	}
+}
+
+// ArchiveError is an archive which can not be extracted, because its format is unknown, it is too large or an
+// entry would be written outside of the target directory.
+type ArchiveError struct {
+	Message string
+	// Name of the offending entry, empty if the archive as a whole is affected.
+	Name string
+}
+
+func (e ArchiveError) Error() string {
+	return e.Message
+}
+
+// ExtractOptions configure ExtractArchive and DownloadArchive.
+type ExtractOptions struct {
+	// Format of the archive, detected if ArchiveAuto.
+	Format ArchiveFormat
+	// StripComponents removes as many leading directories from the names of entries, like tar's option of the
+	// same name. Entries without remaining name are skipped.
+	StripComponents int
+	// MaxSize limits the total size of the extracted files, unlimited if zero.
+	MaxSize int64
+}
+
+// DownloadArchive gets path and extracts the tar, tar.gz or zip archive in the response into dir, see
+// ExtractArchive. It returns the extracted directory as fs.FS. Unsuccessful statuses are reported as error, like
+// UnauthorizedError or NotFoundError.
+func (h *HttpClient) DownloadArchive(ctx context.Context, path string, dir string, opts ExtractOptions) (fs.FS, error) {
+	resp, err := h.sendSpec(ctx, Spec{Method: http.MethodGet, Path: path, Headers: http.Header{"Accept": {"*/*"}}}, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer drainAndClose(resp.Body)
+
+	if _, err := ExtractArchive(resp.Body, dir, opts); err != nil {
+		return nil, err
+	}
+	return os.DirFS(dir), nil
+}
+
+// ExtractArchive extracts the tar, tar.gz or zip archive read from r into dir, which is created if missing, and
+// returns the names of the extracted entries. Tar archives are extracted while they are read, zip archives are
+// buffered in a temporary file first as their index is at the end.
+//
+// Entries are written through an os.Root, so neither absolute names, nor names containing "..", nor symbolic
+// links can place files outside of dir; such entries fail the extraction with an ArchiveError. Links are only
+// extracted if their target lies within dir, and permissions are limited to the file mode bits. Entries
+// extracted before an error are left in place.
+func ExtractArchive(r io.Reader, dir string, opts ExtractOptions) ([]string, error) {
+	format := opts.Format
+	buffered := bufio.NewReader(r)
+	if format == ArchiveAuto {
+		detected, err := detectArchiveFormat(buffered)
+		if err != nil {
+			return nil, err
+		}
+		format = detected
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	root, err := os.OpenRoot(dir)
+	if err != nil {
+		return nil, err
+	}
+	defer root.Close()
+	x := &extraction{root: root, opts: opts}
+
+	switch format {
+	case ArchiveTarGz:
+		gz, gzErr := gzip.NewReader(buffered)
+		if gzErr != nil {
+			return nil, gzErr
+		}
+		defer gz.Close()
+		err = x.tar(tar.NewReader(gz))
+	case ArchiveTar:
+		err = x.tar(tar.NewReader(buffered))
+	case ArchiveZip:
+		err = x.zip(buffered)
+	default:
+		return nil, &ArchiveError{Message: fmt.Sprintf("Archive format %d is unknown.", format)}
+	}
+	return x.names, err
+}
+
+// detectArchiveFormat detects the format of the archive by its magic bytes without consuming them.
+func detectArchiveFormat(r *bufio.Reader) (ArchiveFormat, error) {
+	header, err := r.Peek(512)
+	if err != nil && err != io.EOF {
+		return ArchiveAuto, err
+	}
+	switch {
+	case bytes.HasPrefix(header, []byte{0x1f, 0x8b}):
+		return ArchiveTarGz, nil
+	case bytes.HasPrefix(header, []byte("PK\x03\x04")) || bytes.HasPrefix(header, []byte("PK\x05\x06")):
+		return ArchiveZip, nil
+	case len(header) >= 262 && string(header[257:262]) == "ustar":
+		return ArchiveTar, nil
+	}
+	return ArchiveAuto, &ArchiveError{Message: "Archive format could not be detected, only tar, tar.gz and zip are supported."}
+}
+
+// extraction writes the entries of an archive into root.
+type extraction struct {
+	root    *os.Root
+	opts    ExtractOptions
+	written int64
+	names   []string
+}
+
+func (x *extraction) tar(r *tar.Reader) error {
+	for {
+		header, err := r.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			err = x.dir(header.Name)
+		case tar.TypeReg:
+			err = x.file(header.Name, header.FileInfo().Mode(), r)
+		case tar.TypeSymlink:
+			err = x.symlink(header.Name, header.Linkname)
+		case tar.TypeLink:
+			err = x.link(header.Name, header.Linkname)
+		default:
+			// devices, fifos and extended headers are not extracted
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (x *extraction) zip(r io.Reader) error {
+	temp, err := os.CreateTemp("", "archive-*.zip")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(temp.Name())
+	defer temp.Close()
+
+	size, err := io.Copy(temp, r)
+	if err != nil {
+		return err
+	}
+	archive, err := zip.NewReader(temp, size)
+	if err != nil {
+		return err
+	}
+
+	for _, f := range archive.File {
+		mode := f.Mode()
+		switch {
+		case mode.IsDir():
+			err = x.dir(f.Name)
+		case mode&fs.ModeSymlink != 0:
+			err = x.zipSymlink(f)
+		case mode.IsRegular():
+			err = x.zipFile(f)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (x *extraction) zipFile(f *zip.File) error {
+	content, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer content.Close()
+	return x.file(f.Name, f.Mode(), content)
+}
+
+func (x *extraction) zipSymlink(f *zip.File) error {
+	content, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer content.Close()
+	target, err := io.ReadAll(io.LimitReader(content, 4096))
+	if err != nil {
+		return err
+	}
+	return x.symlink(f.Name, string(target))
+}
+
+// name returns the name entry is extracted to, empty if it is skipped, or an ArchiveError if it is not local.
+func (x *extraction) name(entry string) (string, error) {
+	cleaned := path.Clean(strings.ReplaceAll(entry, `\`, "/"))
+	if path.IsAbs(cleaned) || !filepath.IsLocal(filepath.FromSlash(cleaned)) {
+		return "", &ArchiveError{Message: fmt.Sprintf("Archive entry %q is outside of the target directory.", entry), Name: entry}
+	}
+
+	if cleaned == "." {
+		return "", nil
+	}
+	parts := strings.Split(cleaned, "/")
+	if len(parts) <= x.opts.StripComponents {
+		return "", nil
+	}
+	return filepath.FromSlash(path.Join(parts[x.opts.StripComponents:]...)), nil
+}
+
+func (x *extraction) dir(entry string) error {
+	name, err := x.name(entry)
+	if err != nil || name == "" {
+		return err
+	}
+	if err := x.root.MkdirAll(name, 0o755); err != nil {
+		return err
+	}
+	x.names = append(x.names, filepath.ToSlash(name))
+	return nil
+}
+
+func (x *extraction) file(entry string, mode fs.FileMode, content io.Reader) error {
+	name, err := x.name(entry)
+	if err != nil || name == "" {
+		return err
+	}
+	if err := x.root.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return err
+	}
+
+	f, err := x.root.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode.Perm()|0o600)
+	if err != nil {
+		return err
+	}
+	if x.opts.MaxSize > 0 {
+		// read one byte more than allowed to tell an archive of exactly the maximum size from a larger one
+		content = io.LimitReader(content, x.opts.MaxSize-x.written+1)
+	}
+	n, err := io.Copy(f, content)
+	x.written += n
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	if x.opts.MaxSize > 0 && x.written > x.opts.MaxSize {
+		return &ArchiveError{Message: fmt.Sprintf("Archive exceeds the maximum size of %d bytes.", x.opts.MaxSize), Name: entry}
+	}
+	x.names = append(x.names, filepath.ToSlash(name))
+	return nil
+}
+
+func (x *extraction) symlink(entry string, target string) error {
+	name, err := x.name(entry)
+	if err != nil || name == "" {
+		return err
+	}
+	target = filepath.FromSlash(target)
+	if filepath.IsAbs(target) || !filepath.IsLocal(filepath.Join(filepath.Dir(name), target)) {
+		return &ArchiveError{Message: fmt.Sprintf("Link %q points outside of the target directory.", entry), Name: entry}
+	}
+	if err := x.root.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return err
+	}
+	if err := x.root.Remove(name); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	if err := x.root.Symlink(target, name); err != nil {
+		return err
+	}
+	x.names = append(x.names, filepath.ToSlash(name))
+	return nil
+}
+
+func (x *extraction) link(entry string, target string) error {
+	name, err := x.name(entry)
+	if err != nil || name == "" {
+		return err
+	}
+	linked, err := x.name(target)
+	if err != nil {
+		return &ArchiveError{Message: fmt.Sprintf("Link %q points outside of the target directory.", entry), Name: entry}
+	}
+	if linked == "" {
+		return nil
+	}
+	if err := x.root.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return err
+	}
+	if err := x.root.Remove(name); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	if err := x.root.Link(linked, name); err != nil {
+		return err
+	}
+	x.names = append(x.names, filepath.ToSlash(name))
+	return nil
+}
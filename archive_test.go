@@ -0,0 +1,168 @@
+package http
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+type archiveEntry struct {
+	name     string
+	content  string
+	linkname string
+	dir      bool
+}
+
+func tarGz(t *testing.T, entries ...archiveEntry) []byte {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for _, e := range entries {
+		header := &tar.Header{Name: e.name, Mode: 0o644, Size: int64(len(e.content)), Typeflag: tar.TypeReg}
+		switch {
+		case e.dir:
+			header.Typeflag, header.Mode, header.Size = tar.TypeDir, 0o755, 0
+		case e.linkname != "":
+			header.Typeflag, header.Linkname, header.Size = tar.TypeSymlink, e.linkname, 0
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			t.Fatal(err)
+		}
+		tw.Write([]byte(e.content))
+	}
+	tw.Close()
+	gz.Close()
+	return buf.Bytes()
+}
+
+func zipped(t *testing.T, entries ...archiveEntry) []byte {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, e := range entries {
+		w, err := zw.Create(e.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(e.content))
+	}
+	zw.Close()
+	return buf.Bytes()
+}
+
+func TestExtractArchive_TarGz(t *testing.T) {
+	dir := t.TempDir()
+	archive := tarGz(t,
+		archiveEntry{name: "release-1.0/", dir: true},
+		archiveEntry{name: "release-1.0/bin/tool", content: "binary"},
+		archiveEntry{name: "release-1.0/README", content: "readme"},
+		archiveEntry{name: "release-1.0/latest", linkname: "bin/tool"},
+	)
+
+	names, err := ExtractArchive(bytes.NewReader(archive), dir, ExtractOptions{StripComponents: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sort.Strings(names)
+	if strings.Join(names, ",") != "README,bin/tool,latest" {
+		t.Errorf("Unexpected entries %v", names)
+	}
+	if content, _ := os.ReadFile(filepath.Join(dir, "latest")); string(content) != "binary" {
+		t.Errorf("Expected link to the tool but got %q", content)
+	}
+}
+
+func TestExtractArchive_Zip(t *testing.T) {
+	dir := t.TempDir()
+	archive := zipped(t, archiveEntry{name: "a/b.txt", content: "b"})
+
+	if _, err := ExtractArchive(bytes.NewReader(archive), dir, ExtractOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if content, _ := os.ReadFile(filepath.Join(dir, "a", "b.txt")); string(content) != "b" {
+		t.Errorf("Unexpected content %q", content)
+	}
+}
+
+func TestExtractArchive_PathTraversal(t *testing.T) {
+	archives := map[string][]byte{
+		"dot dot":      tarGz(t, archiveEntry{name: "../escape", content: "x"}),
+		"absolute":     tarGz(t, archiveEntry{name: "/tmp/escape", content: "x"}),
+		"symlink":      tarGz(t, archiveEntry{name: "link", linkname: "../../etc"}),
+		"zip dot dot":  zipped(t, archiveEntry{name: "a/../../escape", content: "x"}),
+		"zip absolute": zipped(t, archiveEntry{name: `\windows\escape`, content: "x"}),
+	}
+	for name, archive := range archives {
+		parent := t.TempDir()
+		dir := filepath.Join(parent, "target")
+
+		_, err := ExtractArchive(bytes.NewReader(archive), dir, ExtractOptions{})
+		if _, ok := err.(*ArchiveError); !ok {
+			t.Errorf("%s: expected ArchiveError but got %v", name, err)
+		}
+		if _, err := os.Stat(filepath.Join(parent, "escape")); err == nil {
+			t.Errorf("%s: entry was written outside of the target directory", name)
+		}
+	}
+}
+
+func TestExtractArchive_LinkedDirectory(t *testing.T) {
+	outside := t.TempDir()
+	dir := t.TempDir()
+	if err := os.Symlink(outside, filepath.Join(dir, "out")); err != nil {
+		t.Skip(err)
+	}
+
+	archive := tarGz(t, archiveEntry{name: "out/escape", content: "x"})
+	if _, err := ExtractArchive(bytes.NewReader(archive), dir, ExtractOptions{}); err == nil {
+		t.Error("Expected extraction through a symbolic link to fail")
+	}
+	if _, err := os.Stat(filepath.Join(outside, "escape")); err == nil {
+		t.Error("Entry was written through a symbolic link outside of the target directory")
+	}
+}
+
+func TestExtractArchive_MaxSize(t *testing.T) {
+	archive := tarGz(t, archiveEntry{name: "a", content: "12345"}, archiveEntry{name: "b", content: "67890"})
+
+	if _, err := ExtractArchive(bytes.NewReader(archive), t.TempDir(), ExtractOptions{MaxSize: 10}); err != nil {
+		t.Errorf("Expected archive of the maximum size to be extracted but got %v", err)
+	}
+	_, err := ExtractArchive(bytes.NewReader(archive), t.TempDir(), ExtractOptions{MaxSize: 9})
+	if _, ok := err.(*ArchiveError); !ok {
+		t.Errorf("Expected ArchiveError but got %v", err)
+	}
+}
+
+func TestExtractArchive_UnknownFormat(t *testing.T) {
+	_, err := ExtractArchive(strings.NewReader("plain text"), t.TempDir(), ExtractOptions{})
+	if _, ok := err.(*ArchiveError); !ok {
+		t.Errorf("Expected ArchiveError but got %v", err)
+	}
+}
+
+func TestHttpClient_DownloadArchive(t *testing.T) {
+	archive := tarGz(t, archiveEntry{name: "tool/VERSION", content: "1.2.3"})
+	server := mockServerWith(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/gzip")
+		w.Write(archive)
+	})
+	defer server.Close()
+
+	fsys, err := NewDefaultHttpClient(server.URL).DownloadArchive(context.Background(), "/releases/tool.tar.gz", t.TempDir(), ExtractOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version, err := fs.ReadFile(fsys, "tool/VERSION"); err != nil || string(version) != "1.2.3" {
+		t.Errorf("Unexpected version %q, %v", version, err)
+	}
+}
@@ -0,0 +1,233 @@
+package http
+
+import (
+	"io"
+	"net/http"
+)
+
+// AuthProvider adds authentication to an outgoing request.
+type AuthProvider interface {
+	Authenticate(r *http.Request) error
+}
+
+// AuthProviderFunc adapts a function to the AuthProvider interface.
+type AuthProviderFunc func(r *http.Request) error
+
+func (f AuthProviderFunc) Authenticate(r *http.Request) error {
+	return f(r)
+}
+
+type basicAuth struct {
+	source CredentialSource
+}
+
+// BasicAuth returns an AuthProvider setting basic auth from the credentials of source.
+func BasicAuth(source CredentialSource) AuthProvider {
+	if source == nil {
+		panic("source is nil")
+	}
+	return &basicAuth{source: source}
+}
+
+func (a *basicAuth) Authenticate(r *http.Request) error {
+	credentials, err := a.source.Credentials(r.Context())
+	if err != nil {
+		return err
+	}
+	if credentials.Username != "" || credentials.Password != "" {
+		r.SetBasicAuth(credentials.Username, credentials.Password)
+	}
+	return nil
+}
+
+type bearerAuth struct {
+	source CredentialSource
+}
+
+// BearerAuth returns an AuthProvider sending the token of source as bearer token.
+func BearerAuth(source CredentialSource) AuthProvider {
+	if source == nil {
+		panic("source is nil")
+	}
+	return &bearerAuth{source: source}
+}
+
+func (a *bearerAuth) Authenticate(r *http.Request) error {
+	credentials, err := a.source.Credentials(r.Context())
+	if err != nil {
+		return err
+	}
+	if credentials.Token != "" {
+		r.Header.Set("Authorization", "Bearer "+credentials.Token)
+	}
+	return nil
+}
+
+// SetAuthProvider atomically replaces the AuthProvider used for subsequent requests.
+func (h *HttpClient) SetAuthProvider(provider AuthProvider) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	// copy on write like Reconfigure, the config may be shared with the caller
+	config := *h.config
+	config.auth = provider
+	h.config = &config
+}
+
+func (h *HttpClient) authProvider() AuthProvider {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.config.auth
+}
+
+// selectAuthProvider returns the provider to authenticate r with. A per-request override replaces
+// any Authorization header, otherwise credentials set when creating the request take precedence.
+// The client's provider is only used for the host of its base URL, never for a tenant, an environment
+// or other hosts, so credentials do not leak through absolute URLs, redirects or pagination links.
+func (h *HttpClient) selectAuthProvider(r *http.Request) (*http.Request, AuthProvider) {
+	if options := requestOptionsFrom(r.Context()); options != nil && options.authOverride {
+		if r.Header.Get("Authorization") != "" {
+			r = r.Clone(r.Context())
+			r.Header.Del("Authorization")
+		}
+		return r, options.auth
+	}
+
+	if r.Header.Get("Authorization") != "" || !h.ownTarget(r) {
+		return r, nil
+	}
+	return r, h.authProvider()
+}
+
+// authenticate applies provider to a copy of r.
+func authenticate(r *http.Request, provider AuthProvider) (*http.Request, error) {
+	if provider == nil {
+		return r, nil
+	}
+
+	r = r.Clone(r.Context())
+	if err := provider.Authenticate(r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// ChallengeAuthProvider is an AuthProvider performing a handshake with the server by answering
+// the authentication challenges of 401 (or, for proxies, 407) responses.
+type ChallengeAuthProvider interface {
+	AuthProvider
+	// Challenge updates r to answer the challenge in resp. It returns false if there is nothing to answer.
+	Challenge(r *http.Request, resp *http.Response) (bool, error)
+}
+
+// maxAuthRounds limits the number of challenges answered for a single request.
+const maxAuthRounds = 3
+
+// answerChallenges re-sends r while the server rejects it and provider has a way to answer:
+// a handshake step of a ChallengeAuthProvider or the previous credentials of a rotated source.
+func (h *HttpClient) answerChallenges(r *http.Request, resp *http.Response, provider AuthProvider) (*http.Response, error) {
+	challenger, _ := provider.(ChallengeAuthProvider)
+	rotationTried := false
+
+	for round := 0; round < maxAuthRounds; round++ {
+		if resp.StatusCode != http.StatusUnauthorized && resp.StatusCode != http.StatusProxyAuthRequired {
+			return resp, nil
+		}
+
+		var retry *http.Request
+		if challenger != nil {
+			next, ok := replayableClone(r)
+			if !ok {
+				return resp, nil
+			}
+			answered, err := challenger.Challenge(next, resp)
+			if err != nil {
+				drainAndClose(resp.Body)
+				return nil, err
+			}
+			if answered {
+				retry = next
+			}
+		} else if !rotationTried && resp.StatusCode == http.StatusUnauthorized {
+			// the credentials may just have been rotated, try the previous ones during their grace window
+			rotationTried = true
+			retry, _ = reauthenticate(r, provider)
+		}
+		if retry == nil {
+			return resp, nil
+		}
+
+		drainAndClose(resp.Body)
+		r = retry
+		var err error
+		if resp, err = h.do(r); err != nil {
+			return resp, err
+		}
+	}
+
+	return resp, nil
+}
+
+// previousCredentialSource is implemented by sources which still accept the credentials they rotated away from.
+type previousCredentialSource interface {
+	Previous() (Credentials, bool)
+}
+
+// reauthenticate returns a copy of the rejected request r authenticated with the previous credentials
+// of provider's source, if the provider supports it and r's body can be replayed.
+func reauthenticate(r *http.Request, provider AuthProvider) (*http.Request, bool) {
+	var previous Credentials
+	var ok bool
+
+	switch p := provider.(type) {
+	case *basicAuth:
+		previous, ok = previousCredentials(p.source)
+	case *bearerAuth:
+		previous, ok = previousCredentials(p.source)
+	}
+	if !ok {
+		return nil, false
+	}
+
+	retry, ok := replayableClone(r)
+	if !ok {
+		return nil, false
+	}
+
+	if _, isBearer := provider.(*bearerAuth); isBearer {
+		retry.Header.Set("Authorization", "Bearer "+previous.Token)
+	} else {
+		retry.SetBasicAuth(previous.Username, previous.Password)
+	}
+	return retry, true
+}
+
+// replayableClone returns a copy of r with a fresh body, or false if the body can not be replayed.
+func replayableClone(r *http.Request) (*http.Request, bool) {
+	retry := r.Clone(r.Context())
+	if r.Body == nil || r.Body == http.NoBody {
+		return retry, true
+	}
+	if r.GetBody == nil {
+		return nil, false
+	}
+
+	body, err := r.GetBody()
+	if err != nil {
+		return nil, false
+	}
+	retry.Body = body
+	return retry, true
+}
+
+func previousCredentials(source CredentialSource) (Credentials, bool) {
+	if s, ok := source.(previousCredentialSource); ok {
+		return s.Previous()
+	}
+	return Credentials{}, false
+}
+
+func drainAndClose(body io.ReadCloser) {
+	io.Copy(io.Discard, body)
+	body.Close()
+}
@@ -0,0 +1,84 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestHttpClient_BasicAuthFromCredentialSource(t *testing.T) {
+	var username, password string
+	f := func(w http.ResponseWriter, r *http.Request) {
+		username, password, _ = r.BasicAuth()
+		w.WriteHeader(http.StatusOK)
+	}
+	server := mockServerWith(http.HandlerFunc(f))
+	defer server.Close()
+
+	config := NewHttpConfigWithCredentials(server.URL, StaticCredentials("foo", "bar"), contentTypeJSON)
+	resp, err := NewHttpClientWithConfig(config).GetFrom("")
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	assertResponseHasStatus(resp, http.StatusOK, t)
+	if username != "foo" || password != "bar" {
+		t.Errorf("Expected foo:bar but got %s:%s", username, password)
+	}
+}
+
+func TestHttpClient_CredentialSourceFails(t *testing.T) {
+	sourceErr := errors.New("vault sealed")
+	source := CredentialSourceFunc(func(ctx context.Context) (Credentials, error) {
+		return Credentials{}, sourceErr
+	})
+
+	config := NewHttpConfigWithCredentials(fixtureBaseURL, source, contentTypeJSON)
+	_, err := NewHttpClientWithConfig(config).GetFrom("")
+
+	if !errors.Is(err, sourceErr) {
+		t.Errorf("Expected %v but got %v", sourceErr, err)
+	}
+}
+
+func TestHttpClient_SetAuthProviderKeepsConfig(t *testing.T) {
+	config := NewDefaultHttpConfig(fixtureBaseURL)
+	client := NewHttpClientWithConfig(config)
+
+	client.SetAuthProvider(BasicAuth(StaticCredentials("user", "secret")))
+
+	if config.auth != nil {
+		t.Error("Expected the caller's config to be left unchanged")
+	}
+	if client.authProvider() == nil {
+		t.Error("Expected the client to use the new provider")
+	}
+}
+
+func TestHttpClient_AuthProviderOnlyForBaseHost(t *testing.T) {
+	authorization := map[string]string{}
+	handler := func(name string) func(w http.ResponseWriter, r *http.Request) {
+		return func(w http.ResponseWriter, r *http.Request) {
+			authorization[name] = r.Header.Get("Authorization")
+		}
+	}
+	base := mockServerWith(handler("base"))
+	defer base.Close()
+	other := mockServerWith(handler("other"))
+	defer other.Close()
+
+	client := NewHttpClientWithConfig(NewDefaultHttpConfig(base.URL, WithAuthProvider(BasicAuth(StaticCredentials("user", "secret")))))
+	for _, target := range []string{base.URL + "/x", other.URL + "/x"} {
+		request, _ := http.NewRequest(http.MethodGet, target, nil)
+		resp, err := client.ExecuteRequest(request)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+
+	if authorization["base"] == "" || authorization["other"] != "" {
+		t.Errorf("Expected credentials for the base host only but got %v", authorization)
+	}
+}
@@ -0,0 +1,172 @@
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strconv"
+	"strings"
+)
+
+// ExecuteBatch packs requests into a single multipart/mixed request, as used by OData $batch endpoints, posts it
+// to path and unpacks the parts of the multipart response into one response per request, in the order of
+// requests. Responses of nested change sets are flattened. Sub-requests are not passed through the client's
+// middleware; their URLs are sent in origin form.
+func (h *HttpClient) ExecuteBatch(ctx context.Context, path string, requests ...*http.Request) ([]*http.Response, error) {
+	body, contentType, err := encodeBatch(requests)
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := h.newRequest(ctx, http.MethodPost, path, body)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", contentType)
+	if ctx != nil {
+		request = request.WithContext(ctx)
+	}
+
+	resp, err := h.ExecuteRequest(request)
+	if err != nil {
+		return nil, err
+	}
+	if ClassifyStatus(resp.StatusCode) != StatusClassSuccess {
+		return nil, h.responseError(resp)
+	}
+	return decodeBatch(resp, requests)
+}
+
+// encodeBatch writes every request as application/http part of a multipart/mixed body.
+func encodeBatch(requests []*http.Request) (io.Reader, string, error) {
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+	for i, r := range requests {
+		header := textproto.MIMEHeader{}
+		header.Set("Content-Type", "application/http")
+		header.Set("Content-Transfer-Encoding", "binary")
+		header.Set("Content-ID", strconv.Itoa(i+1))
+		part, err := writer.CreatePart(header)
+		if err != nil {
+			return nil, "", err
+		}
+		if err := writeBatchRequest(part, r); err != nil {
+			return nil, "", err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, "", err
+	}
+	return &buf, "multipart/mixed; boundary=" + writer.Boundary(), nil
+}
+
+func writeBatchRequest(w io.Writer, r *http.Request) error {
+	var body []byte
+	if r.Body != nil {
+		var err error
+		if body, err = io.ReadAll(r.Body); err != nil {
+			return err
+		}
+		r.Body.Close()
+	}
+
+	fmt.Fprintf(w, "%s %s HTTP/1.1\r\n", r.Method, r.URL.RequestURI())
+	header := r.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	if r.Host != "" || r.URL.Host != "" {
+		header.Set("Host", orDefault(r.Host, r.URL.Host))
+	}
+	if len(body) > 0 {
+		header.Set("Content-Length", strconv.Itoa(len(body)))
+	}
+	if err := header.Write(w); err != nil {
+		return err
+	}
+	io.WriteString(w, "\r\n")
+	_, err := w.Write(body)
+	return err
+}
+
+// decodeBatch reads the application/http parts of resp, matching them to requests by Content-ID or position.
+func decodeBatch(resp *http.Response, requests []*http.Request) ([]*http.Response, error) {
+	reader, err := NewMultipartReader(resp)
+	if err != nil {
+		drainAndClose(resp.Body)
+		return nil, err
+	}
+	defer reader.Close()
+
+	var parts []*http.Response
+	var ids []string
+	if err := readBatchParts(reader, &parts, &ids); err != nil {
+		return nil, err
+	}
+
+	responses := make([]*http.Response, len(requests))
+	for i, part := range parts {
+		index := i
+		if id, err := strconv.Atoi(strings.Trim(ids[i], "<>")); err == nil && id >= 1 && id <= len(requests) {
+			index = id - 1
+		}
+		if index < len(requests) && responses[index] == nil {
+			part.Request = requests[index]
+			responses[index] = part
+		}
+	}
+	for i, response := range responses {
+		if response == nil {
+			return nil, &MultipartError{Message: fmt.Sprintf("Batch response lacks the response of request %d.", i+1)}
+		}
+	}
+	return responses, nil
+}
+
+func readBatchParts(reader *MultipartReader, parts *[]*http.Response, ids *[]string) error {
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		mediaType, params, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		if mediaType == "multipart/mixed" {
+			changeset := &MultipartReader{reader: multipart.NewReader(part.Body, params["boundary"])}
+			if err := readBatchParts(changeset, parts, ids); err != nil {
+				return err
+			}
+			continue
+		}
+
+		resp, err := http.ReadResponse(bufio.NewReader(part.Body), nil)
+		if err != nil {
+			return &MultipartError{Message: fmt.Sprintf("Invalid batch response part: %v.", err)}
+		}
+		// the part is only valid until the next one, so its body is buffered
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return err
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		*parts = append(*parts, resp)
+		*ids = append(*ids, part.Header.Get("Content-ID"))
+	}
+}
+
+func orDefault(value string, fallback string) string {
+	if value != "" {
+		return value
+	}
+	return fallback
+}
@@ -0,0 +1,59 @@
+package http
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestHttpClient_ExecuteBatch(t *testing.T) {
+	server := mockServerWith(func(w http.ResponseWriter, r *http.Request) {
+		_, params, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		reader := multipart.NewReader(r.Body, params["boundary"])
+
+		var parts []string
+		for {
+			part, err := reader.NextPart()
+			if err == io.EOF {
+				break
+			}
+			sub, err := http.ReadRequest(bufio.NewReader(part))
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			body, _ := io.ReadAll(sub.Body)
+			parts = append(parts, fmt.Sprintf("Content-Type: application/http\r\nContent-ID: %s\r\n\r\n"+
+				"HTTP/1.1 200 OK\r\nContent-Type: text/plain\r\n\r\n%s %s %s", part.Header.Get("Content-ID"), sub.Method, sub.URL, body))
+		}
+
+		// answer in reverse order, the second response within a change set
+		w.Header().Set("Content-Type", "multipart/mixed; boundary=batch")
+		io.WriteString(w, "--batch\r\n"+parts[1]+"\r\n--batch\r\nContent-Type: multipart/mixed; boundary=changeset\r\n\r\n"+
+			"--changeset\r\n"+parts[0]+"\r\n--changeset--\r\n\r\n--batch--\r\n")
+	})
+	defer server.Close()
+
+	client := createTestHTTPClient(server.URL)
+	get, _ := http.NewRequest(http.MethodGet, server.URL+"/users/1?expand=roles", nil)
+	post, _ := http.NewRequest(http.MethodPost, server.URL+"/users", strings.NewReader(`{"name":"jane"}`))
+
+	responses, err := client.ExecuteBatch(context.Background(), "/$batch", get, post)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"GET /users/1?expand=roles ", `POST /users {"name":"jane"}`}
+	for i, resp := range responses {
+		body, _ := io.ReadAll(resp.Body)
+		if string(body) != expected[i] || resp.StatusCode != http.StatusOK || resp.Request == nil {
+			t.Errorf("Expected %q for request %d but got %q", expected[i], i, body)
+		}
+	}
+}
@@ -0,0 +1,245 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+// APIDefinitionError describes a field of an API definition Bind can not implement.
+type APIDefinitionError struct {
+	Message string
+	Field   string
+}
+
+func (e APIDefinitionError) Error() string {
+	return e.Message
+}
+
+var (
+	contextType  = reflect.TypeOf((*context.Context)(nil)).Elem()
+	errorType    = reflect.TypeOf((*error)(nil)).Elem()
+	valuesType   = reflect.TypeOf(url.Values(nil))
+	headerType   = reflect.TypeOf(http.Header(nil))
+	responseType = reflect.TypeOf((*http.Response)(nil))
+)
+
+// Bind implements the operations of an API declared as func fields of the struct api points to, so small SDKs
+// need neither hand-written methods nor a code generation step. Go can not implement interfaces at runtime, so
+// the operations are declared as fields with method and path tags:
+//
+//	type UserAPI struct {
+//		Get    func(ctx context.Context, id int) (User, error)               `method:"GET" path:"/users/{id}"`
+//		List   func(ctx context.Context, query url.Values) ([]User, error)   `method:"GET" path:"/users"`
+//		Create func(ctx context.Context, user NewUser) (User, error)         `method:"POST" path:"/users"`
+//		Delete func(ctx context.Context, id int) error                       `method:"DELETE" path:"/users/{id}"`
+//	}
+//
+//	var users UserAPI
+//	err := http.Bind(client, &users)
+//
+// The optional first parameter is the context of the request. The following parameters fill the placeholders
+// of the path in order, formatted with fmt.Sprint. Of the remaining parameters, url.Values are sent as query,
+// http.Header as headers and a single other value as body, encoded like the bodies of Call. The last result is
+// an error, the optional first result is decoded from the response, or is the *http.Response itself whose body
+// the caller closes. Unsuccessful statuses are reported as error, like UnauthorizedError or NotFoundError.
+//
+// Fields without method tag are left untouched. Bind returns an APIDefinitionError for fields it can not
+// implement, before any field is set.
+func Bind(client *HttpClient, api interface{}) error {
+	if client == nil {
+		panic("client is nil")
+	}
+	target := reflect.ValueOf(api)
+	if target.Kind() != reflect.Ptr || target.Elem().Kind() != reflect.Struct {
+		return &APIDefinitionError{Message: fmt.Sprintf("API definition must be a pointer to a struct, not %T.", api)}
+	}
+	target = target.Elem()
+
+	operations := map[int]reflect.Value{}
+	for i := 0; i < target.NumField(); i++ {
+		field := target.Type().Field(i)
+		method, ok := field.Tag.Lookup("method")
+		if !ok {
+			continue
+		}
+		op, err := newBoundOperation(field, method)
+		if err != nil {
+			return err
+		}
+		operations[i] = reflect.MakeFunc(field.Type, func(args []reflect.Value) []reflect.Value {
+			return op.call(client, args)
+		})
+	}
+
+	for i, fn := range operations {
+		target.Field(i).Set(fn)
+	}
+	return nil
+}
+
+// boundOperation is an operation of an API definition with the roles of its parameters and results.
+type boundOperation struct {
+	method       string
+	path         string
+	placeholders []string
+	hasContext   bool
+	// result is the type of the decoded result, nil if only an error is returned
+	result reflect.Type
+}
+
+func newBoundOperation(field reflect.StructField, method string) (*boundOperation, error) {
+	invalid := func(format string, args ...interface{}) error {
+		return &APIDefinitionError{Message: fmt.Sprintf("Field %s: ", field.Name) + fmt.Sprintf(format, args...), Field: field.Name}
+	}
+
+	if field.PkgPath != "" {
+		return nil, invalid("operations must be exported.")
+	}
+	fn := field.Type
+	if fn.Kind() != reflect.Func || fn.IsVariadic() {
+		return nil, invalid("operations must be non-variadic funcs, not %s.", fn)
+	}
+
+	op := &boundOperation{method: strings.ToUpper(method), path: field.Tag.Get("path")}
+	if op.method == "" {
+		return nil, invalid("method tag is empty.")
+	}
+	placeholders, err := pathPlaceholders(op.path)
+	if err != nil {
+		return nil, invalid("%v.", err)
+	}
+	op.placeholders = placeholders
+
+	in := 0
+	if fn.NumIn() > 0 && fn.In(0) == contextType {
+		op.hasContext = true
+		in = 1
+	}
+	if fn.NumIn()-in < len(placeholders) {
+		return nil, invalid("path %q needs %d parameters.", op.path, len(placeholders))
+	}
+	hasBody := false
+	for i := in + len(placeholders); i < fn.NumIn(); i++ {
+		switch fn.In(i) {
+		case valuesType, headerType:
+			continue
+		}
+		if hasBody {
+			return nil, invalid("only one parameter can be sent as body.")
+		}
+		if op.method == http.MethodGet || op.method == http.MethodHead {
+			return nil, invalid("%s requests have no body, but parameter %d is %s.", op.method, i+1, fn.In(i))
+		}
+		hasBody = true
+	}
+
+	switch {
+	case fn.NumOut() == 1 && fn.Out(0) == errorType:
+	case fn.NumOut() == 2 && fn.Out(1) == errorType:
+		op.result = fn.Out(0)
+	default:
+		return nil, invalid("operations must return an error, optionally preceded by a result.")
+	}
+	return op, nil
+}
+
+// call sends the request described by args and converts the response into the results of the operation.
+func (op *boundOperation) call(client *HttpClient, args []reflect.Value) []reflect.Value {
+	result, err := op.send(client, args)
+
+	var out []reflect.Value
+	if op.result != nil {
+		if err != nil || !result.IsValid() {
+			result = reflect.Zero(op.result)
+		}
+		out = append(out, result)
+	}
+	if err != nil {
+		return append(out, reflect.ValueOf(&err).Elem())
+	}
+	return append(out, reflect.Zero(errorType))
+}
+
+func (op *boundOperation) send(client *HttpClient, args []reflect.Value) (reflect.Value, error) {
+	ctx := context.Background()
+	in := 0
+	if op.hasContext {
+		if c, ok := args[0].Interface().(context.Context); ok && c != nil {
+			ctx = c
+		}
+		in = 1
+	}
+
+	params := make(map[string]string, len(op.placeholders))
+	for i, name := range op.placeholders {
+		params[name] = fmt.Sprint(args[in+i].Interface())
+	}
+	path, _, err := expandPath(op.path, params)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	spec := Spec{Method: op.method, Path: path}
+	var body io.Reader
+	for i := in + len(op.placeholders); i < len(args); i++ {
+		switch value := args[i].Interface().(type) {
+		case url.Values:
+			if spec.Query == nil {
+				spec.Query = url.Values{}
+			}
+			for key, values := range value {
+				spec.Query[key] = append(spec.Query[key], values...)
+			}
+		case http.Header:
+			if spec.Headers == nil {
+				spec.Headers = http.Header{}
+			}
+			for key, values := range value {
+				spec.Headers[key] = append(spec.Headers[key], values...)
+			}
+		default:
+			if body, err = encodeCallBody(op.method, client.contentType(), value); err != nil {
+				return reflect.Value{}, err
+			}
+		}
+	}
+
+	resp, err := client.sendSpec(ctx, spec, body)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	switch op.result {
+	case nil:
+		drainAndClose(resp.Body)
+		return reflect.Value{}, nil
+	case responseType:
+		return reflect.ValueOf(resp), nil
+	}
+	decoded := reflect.New(op.result)
+	if err := client.DecodeResponse(resp, decoded.Interface()); err != nil {
+		return reflect.Value{}, err
+	}
+	return decoded.Elem(), nil
+}
+
+// pathPlaceholders returns the names of the {name} placeholders of path in order.
+func pathPlaceholders(path string) ([]string, error) {
+	var names []string
+	for {
+		start := strings.IndexByte(path, '{')
+		if start < 0 {
+			return names, nil
+		}
+		end := strings.IndexByte(path[start:], '}')
+		if end < 0 {
+			return nil, fmt.Errorf("unterminated placeholder in path %q", path)
+		}
+		names = append(names, path[start+1:start+end])
+		path = path[start+end+1:]
+	}
+}
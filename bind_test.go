@@ -0,0 +1,123 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+type boundUser struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type boundUserAPI struct {
+	Get    func(ctx context.Context, id int) (boundUser, error)             `method:"GET" path:"/users/{id}"`
+	List   func(ctx context.Context, query url.Values) ([]boundUser, error) `method:"GET" path:"/users"`
+	Create func(ctx context.Context, user boundUser) (*boundUser, error)    `method:"POST" path:"/users"`
+	Delete func(id int, header http.Header) error                           `method:"delete" path:"/users/{id}"`
+	Raw    func(ctx context.Context) (*http.Response, error)                `method:"GET" path:"/raw"`
+
+	unrelated string
+}
+
+func TestBind(t *testing.T) {
+	var requests []string
+	server := mockServerWith(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, fmt.Sprintf("%s %s %s %s", r.Method, r.URL.RequestURI(), r.Header.Get("X-Reason"), body))
+		w.Header().Set("Content-Type", contentTypeJSON)
+		switch {
+		case r.Method == http.MethodPost:
+			var user boundUser
+			json.Unmarshal(body, &user)
+			user.ID = 3
+			json.NewEncoder(w).Encode(user)
+		case r.URL.Path == "/users":
+			fmt.Fprint(w, `[{"id": 1}, {"id": 2}]`)
+		case r.URL.Path == "/users/404":
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			fmt.Fprint(w, `{"id": 7, "name": "gopher"}`)
+		}
+	})
+	defer server.Close()
+
+	var users boundUserAPI
+	if err := Bind(NewDefaultHttpClient(server.URL), &users); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	user, err := users.Get(ctx, 7)
+	if err != nil || user.Name != "gopher" {
+		t.Errorf("Unexpected user %+v, %v", user, err)
+	}
+	list, err := users.List(ctx, url.Values{"active": {"true"}})
+	if err != nil || len(list) != 2 {
+		t.Errorf("Unexpected users %+v, %v", list, err)
+	}
+	created, err := users.Create(ctx, boundUser{Name: "new"})
+	if err != nil || created.ID != 3 || created.Name != "new" {
+		t.Errorf("Unexpected created user %+v, %v", created, err)
+	}
+	if err := users.Delete(5, http.Header{"X-Reason": {"cleanup"}}); err != nil {
+		t.Error(err)
+	}
+	resp, err := users.Raw(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if _, err := users.Get(ctx, 404); err == nil {
+		t.Error("Expected NotFoundError")
+	} else if _, ok := err.(*NotFoundError); !ok {
+		t.Errorf("Expected NotFoundError but got %T", err)
+	}
+
+	expected := []string{
+		"GET /users/7  ",
+		"GET /users?active=true  ",
+		`POST /users  {"id":0,"name":"new"}`,
+		"DELETE /users/5 cleanup ",
+		"GET /raw  ",
+		"GET /users/404  ",
+	}
+	if fmt.Sprintf("%q", requests) != fmt.Sprintf("%q", expected) {
+		t.Errorf("Expected requests %q but got %q", expected, requests)
+	}
+}
+
+func TestBind_InvalidDefinitions(t *testing.T) {
+	client := NewDefaultHttpClient("http://localhost")
+	definitions := []interface{}{
+		boundUserAPI{},
+		&struct {
+			Get func(ctx context.Context) (boundUser, error) `method:"GET" path:"/users/{id}"`
+		}{},
+		&struct {
+			Get func(ctx context.Context, user boundUser) error `method:"GET" path:"/users"`
+		}{},
+		&struct {
+			Post func(a boundUser, b boundUser) error `method:"POST" path:"/users"`
+		}{},
+		&struct {
+			Get func(ctx context.Context) boundUser `method:"GET" path:"/users"`
+		}{},
+		&struct {
+			Get string `method:"GET" path:"/users"`
+		}{},
+	}
+	for _, definition := range definitions {
+		if err := Bind(client, definition); err == nil {
+			t.Errorf("Expected definition %T to be rejected", definition)
+		} else if _, ok := err.(*APIDefinitionError); !ok {
+			t.Errorf("Expected APIDefinitionError but got %T", err)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package http
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	defaultBudgetWindow     = 10 * time.Second
+	defaultBudgetMinRetries = 10
+	budgetBuckets           = 10
+)
+
+// RetryBudget limits retries per host to a ratio of the requests sent to it over a sliding window, so retries
+// can not multiply the load on a struggling upstream. A minimum number of retries per window is always allowed
+// to keep retrying useful for hosts with little traffic. Hosts without requests in the last window are
+// forgotten.
+type RetryBudget struct {
+	ratio      float64
+	minRetries int
+	window     time.Duration
+	now        func() time.Time
+
+	mu        sync.Mutex
+	hosts     map[string]*budgetWindow
+	lastSweep time.Time
+}
+
+// NewRetryBudget creates a RetryBudget allowing retries up to ratio (e.g. 0.2 for 20%) of the requests per host
+// in the last window. A window of zero uses 10 seconds, windows shorter than 10ns are not supported.
+func NewRetryBudget(ratio float64, window time.Duration) *RetryBudget {
+	if window <= 0 {
+		window = defaultBudgetWindow
+	}
+	if window < budgetBuckets {
+		panic("window must be at least 10ns")
+	}
+	return &RetryBudget{
+		ratio:      ratio,
+		minRetries: defaultBudgetMinRetries,
+		window:     window,
+		now:        time.Now,
+		hosts:      make(map[string]*budgetWindow),
+	}
+}
+
+// WithMinRetries sets how many retries per host and window are allowed regardless of the ratio.
+func (b *RetryBudget) WithMinRetries(min int) *RetryBudget {
+	b.minRetries = min
+	return b
+}
+
+// WithClock sets the clock the budget windows are measured with.
+func (b *RetryBudget) WithClock(clock Clock) *RetryBudget {
+	b.now = clock.Now
+	return b
+}
+
+// RecordRequest counts a first attempt sent to host.
+func (b *RetryBudget) RecordRequest(host string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	b.sweep(now)
+	b.host(host).current(now, b.window).requests++
+}
+
+// TryRetry reports whether the budget of host allows another retry and counts it if so.
+func (b *RetryBudget) TryRetry(host string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	b.sweep(now)
+	w := b.host(host)
+	requests, retries := w.totals(now, b.window)
+	if retries >= b.minRetries && float64(retries+1) > b.ratio*float64(requests) {
+		return false
+	}
+	w.current(now, b.window).retries++
+	return true
+}
+
+func (b *RetryBudget) host(host string) *budgetWindow {
+	w, ok := b.hosts[host]
+	if !ok {
+		w = &budgetWindow{}
+		b.hosts[host] = w
+	}
+	return w
+}
+
+// sweep removes the hosts idle for a whole window, at most once per window. It must be called with b.mu held.
+func (b *RetryBudget) sweep(now time.Time) {
+	if now.Sub(b.lastSweep) < b.window {
+		return
+	}
+	b.lastSweep = now
+	for host, w := range b.hosts {
+		if w.idle(now, b.window) {
+			delete(b.hosts, host)
+		}
+	}
+}
+
+// budgetWindow counts requests and retries in buckets covering a sliding window.
+type budgetWindow struct {
+	buckets [budgetBuckets]budgetBucket
+}
+
+type budgetBucket struct {
+	start    time.Time
+	requests int
+	retries  int
+}
+
+func (w *budgetWindow) current(now time.Time, window time.Duration) *budgetBucket {
+	width := window / budgetBuckets
+	start := now.Truncate(width)
+	bucket := &w.buckets[(start.UnixNano()/int64(width))%budgetBuckets]
+	if !bucket.start.Equal(start) {
+		*bucket = budgetBucket{start: start}
+	}
+	return bucket
+}
+
+func (w *budgetWindow) totals(now time.Time, window time.Duration) (int, int) {
+	requests, retries := 0, 0
+	for _, bucket := range w.buckets {
+		if now.Sub(bucket.start) < window {
+			requests += bucket.requests
+			retries += bucket.retries
+		}
+	}
+	return requests, retries
+}
+
+func (w *budgetWindow) idle(now time.Time, window time.Duration) bool {
+	for _, bucket := range w.buckets {
+		if now.Sub(bucket.start) < window {
+			return false
+		}
+	}
+	return true
+}
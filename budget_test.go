@@ -0,0 +1,83 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRetryBudget(t *testing.T) {
+	clock := NewFakeClock(time.Unix(1000, 0))
+	budget := NewRetryBudget(0.5, 10*time.Second).WithMinRetries(1).WithClock(clock)
+
+	if !budget.TryRetry("a") {
+		t.Error("Expected the minimum retries to be allowed without requests")
+	}
+	if budget.TryRetry("a") {
+		t.Error("Expected retries beyond the minimum to need requests")
+	}
+
+	for i := 0; i < 4; i++ {
+		budget.RecordRequest("a")
+	}
+	if !budget.TryRetry("a") || budget.TryRetry("a") {
+		t.Error("Expected 2 retries for 4 requests at a ratio of 0.5")
+	}
+	if !budget.TryRetry("b") {
+		t.Error("Expected budgets to be tracked per host")
+	}
+
+	clock.Advance(11 * time.Second)
+	if !budget.TryRetry("a") {
+		t.Error("Expected the budget to recover after the window passed")
+	}
+}
+
+func TestDurableQueue_RetryBudget(t *testing.T) {
+	server := mockServer(http.StatusServiceUnavailable, contentTypeJSON, "")
+	defer server.Close()
+
+	store := NewMemoryQueueStore()
+	budget := NewRetryBudget(0, time.Minute).WithMinRetries(0)
+	queue := NewDurableQueue(createTestHTTPClient(server.URL), store).
+		WithRedeliveryInterval(time.Millisecond).
+		WithRetryBudget(budget)
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	queue.Submit(req)
+
+	time.Sleep(5 * time.Millisecond)
+	queue.Flush(context.Background())
+
+	items, _ := store.Load()
+	if len(items) != 1 || items[0].Attempts != 1 {
+		t.Errorf("Expected redelivery to be postponed by the exhausted budget but got %v", items)
+	}
+}
+
+func TestRetryBudget_ForgetsIdleHosts(t *testing.T) {
+	clock := NewFakeClock(time.Unix(1000, 0))
+	budget := NewRetryBudget(0.5, 10*time.Second).WithClock(clock)
+
+	for _, host := range []string{"a", "b", "c"} {
+		budget.RecordRequest(host)
+	}
+	clock.Advance(5 * time.Second)
+	budget.RecordRequest("a")
+	clock.Advance(11 * time.Second)
+	budget.RecordRequest("d")
+
+	if len(budget.hosts) != 1 {
+		t.Errorf("Expected idle hosts to be forgotten but tracked %d hosts", len(budget.hosts))
+	}
+}
+
+func TestNewRetryBudget_RejectsTinyWindow(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected a panic for a window shorter than 10ns")
+		}
+	}()
+	NewRetryBudget(0.5, 5*time.Nanosecond)
+}
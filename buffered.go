@@ -0,0 +1,79 @@
+package http
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// DefaultBufferLimit is the size limit of bodies buffered by WithBufferedResponse.
+const DefaultBufferLimit = 10 << 20
+
+// ResponseTooLargeError is returned when a body to buffer exceeds the limit.
+type ResponseTooLargeError struct {
+	Message string
+	Limit   int64
+}
+
+func (e ResponseTooLargeError) Error() string {
+	return e.Message
+}
+
+// WithBufferedResponse reads the response body into memory before returning the response, so several consumers,
+// like decoding, logging and checksums, can read it without coordinating a single pass. Closing the body rewinds
+// it for the next consumer, see also RewindBody and BufferedBody. Bodies larger than DefaultBufferLimit fail
+// with a ResponseTooLargeError.
+func WithBufferedResponse() RequestOption {
+	return WithBufferedResponseLimit(DefaultBufferLimit)
+}
+
+// WithBufferedResponseLimit is WithBufferedResponse with a custom size limit.
+func WithBufferedResponseLimit(limit int64) RequestOption {
+	return func(o *requestOptions) {
+		o.bufferLimit = limit
+	}
+}
+
+// RewindBody resets the buffered body of resp to its start and reports whether resp has a buffered body.
+func RewindBody(resp *http.Response) bool {
+	body, ok := resp.Body.(*bufferedBody)
+	if ok {
+		body.Seek(0, io.SeekStart)
+	}
+	return ok
+}
+
+// BufferedBody returns the buffered body of resp without consuming it.
+func BufferedBody(resp *http.Response) ([]byte, bool) {
+	body, ok := resp.Body.(*bufferedBody)
+	if !ok {
+		return nil, false
+	}
+	return body.data, true
+}
+
+// bufferedBody is a replayable body, closing it rewinds it.
+type bufferedBody struct {
+	*bytes.Reader
+	data []byte
+}
+
+func (b *bufferedBody) Close() error {
+	b.Seek(0, io.SeekStart)
+	return nil
+}
+
+// bufferResponse replaces the body of resp by a bufferedBody of at most limit bytes.
+func bufferResponse(resp *http.Response, limit int64) error {
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return err
+	}
+	if int64(len(data)) > limit {
+		return &ResponseTooLargeError{Message: fmt.Sprintf("Response body exceeds the buffer limit of %d bytes.", limit), Limit: limit}
+	}
+	resp.Body = &bufferedBody{Reader: bytes.NewReader(data), data: data}
+	return nil
+}
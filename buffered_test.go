@@ -0,0 +1,61 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithBufferedResponse(t *testing.T) {
+	server := mockServerWith(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"name": "jane"}`))
+	})
+	defer server.Close()
+
+	client := createTestHTTPClient(server.URL)
+	resp, err := client.Get(WithRequestOptions(context.Background(), WithBufferedResponse()), "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	var user callUser
+	if err := client.DecodeResponse(resp, &user); err != nil || user.Name != "jane" {
+		t.Errorf("Expected the body to be decodable after reading it but got %+v, %v", user, err)
+	}
+
+	io.ReadAll(resp.Body)
+	if !RewindBody(resp) {
+		t.Fatal("Expected a buffered body")
+	}
+	second, _ := io.ReadAll(resp.Body)
+	buffered, _ := BufferedBody(resp)
+	if string(first) != string(second) || string(buffered) != string(first) {
+		t.Errorf("Expected every read to see the whole body but got %q, %q and %q", first, second, buffered)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if err := client.Shutdown(ctx); err != nil {
+		t.Errorf("Expected a buffered request to be finished but got %v", err)
+	}
+}
+
+func TestWithBufferedResponseLimit(t *testing.T) {
+	server := mockServerWith(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(strings.Repeat("x", 100)))
+	})
+	defer server.Close()
+
+	client := createTestHTTPClient(server.URL)
+	_, err := client.Get(WithRequestOptions(context.Background(), WithBufferedResponseLimit(10)), "/")
+
+	var tooLarge *ResponseTooLargeError
+	if !errors.As(err, &tooLarge) || tooLarge.Limit != 10 {
+		t.Errorf("Expected ResponseTooLargeError but got %v", err)
+	}
+}
@@ -0,0 +1,42 @@
+package http
+
+import (
+	"net/http"
+)
+
+// CacheStatusHeader is the response header a cache reports its CacheStatus in, similar to a CDN's X-Cache.
+const CacheStatusHeader = "X-Cache-Status"
+
+// CacheStatus tells how a cache answered a request.
+type CacheStatus string
+
+const (
+	// CacheStatusHit is a response served from the cache.
+	CacheStatusHit CacheStatus = "HIT"
+	// CacheStatusMiss is a response fetched from the server, stored if cacheable.
+	CacheStatusMiss CacheStatus = "MISS"
+	// CacheStatusStale is an expired response served because the server could not be reached.
+	CacheStatusStale CacheStatus = "STALE"
+	// CacheStatusRevalidated is a cached response the server confirmed to be current.
+	CacheStatusRevalidated CacheStatus = "REVALIDATED"
+)
+
+// CacheStatusOf returns the CacheStatus of resp, or an empty status if it did not pass a cache.
+func CacheStatusOf(resp *http.Response) CacheStatus {
+	if resp == nil {
+		return ""
+	}
+	return CacheStatus(resp.Header.Get(CacheStatusHeader))
+}
+
+// CacheStatus returns the CacheStatus of the response, see CacheStatusOf.
+func (r *Response) CacheStatus() CacheStatus {
+	return CacheStatusOf(r.Response)
+}
+
+func setCacheStatus(resp *http.Response, status CacheStatus) {
+	if resp.Header == nil {
+		resp.Header = http.Header{}
+	}
+	resp.Header.Set(CacheStatusHeader, string(status))
+}
@@ -0,0 +1,18 @@
+package http
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestCacheStatusOf(t *testing.T) {
+	resp := &http.Response{}
+	if CacheStatusOf(resp) != "" || CacheStatusOf(nil) != "" {
+		t.Error("Expected no cache status")
+	}
+
+	setCacheStatus(resp, CacheStatusStale)
+	if status := (&Response{Response: resp}).CacheStatus(); status != CacheStatusStale {
+		t.Errorf("Expected STALE but got %s", status)
+	}
+}
@@ -0,0 +1,94 @@
+package http
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Spec describes an API operation called with Call.
+type Spec struct {
+	Method  string
+	Path    string
+	Query   url.Values
+	Headers http.Header
+}
+
+// Empty is the request or response type of operations without a body.
+type Empty struct{}
+
+// Call builds a request from spec, encodes req as JSON body, executes it with the client's authentication and
+// middleware, checks the status and decodes the response into TResp. It is the single entry point generated
+// SDKs can target:
+//
+//	user, err := http.Call[CreateUser, User](ctx, client, http.Spec{Method: "POST", Path: "/users"}, create)
+//
+// Requests of type Empty, and those of GET and HEAD operations, are sent without body; responses are not
+// decoded into Empty. Unsuccessful statuses are reported as error, like UnauthorizedError or NotFoundError.
+func Call[TReq any, TResp any](ctx context.Context, client *HttpClient, spec Spec, req TReq) (TResp, error) {
+	var resp TResp
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	body, err := encodeCallBody(spec.Method, client.contentType(), req)
+	if err != nil {
+		return resp, err
+	}
+
+	response, err := client.sendSpec(ctx, spec, body)
+	if err != nil {
+		return resp, err
+	}
+
+	if _, empty := interface{}(resp).(Empty); empty {
+		drainAndClose(response.Body)
+		return resp, nil
+	}
+	err = client.DecodeResponse(response, &resp)
+	return resp, err
+}
+
+// sendSpec executes the request described by spec and returns the response if its status is successful.
+func (h *HttpClient) sendSpec(ctx context.Context, spec Spec, body io.Reader) (*http.Response, error) {
+	path := spec.Path
+	if len(spec.Query) > 0 {
+		separator := "?"
+		if strings.Contains(path, "?") {
+			separator = "&"
+		}
+		path += separator + spec.Query.Encode()
+	}
+
+	request, err := h.newRequest(ctx, spec.Method, path, body)
+	if err != nil {
+		return nil, err
+	}
+	if body == nil {
+		request.Header.Del("Content-Type")
+	}
+	for key, values := range spec.Headers {
+		request.Header.Del(key)
+		for _, value := range values {
+			request.Header.Add(key, value)
+		}
+	}
+
+	response, err := h.ExecuteRequest(request.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	if ClassifyStatus(response.StatusCode) != StatusClassSuccess {
+		return nil, h.responseError(response)
+	}
+	return response, nil
+}
+
+func encodeCallBody(method string, contentType string, req interface{}) (io.Reader, error) {
+	if _, empty := req.(Empty); empty || method == http.MethodGet || method == http.MethodHead {
+		return nil, nil
+	}
+	return EncodeBody(contentType, req)
+}
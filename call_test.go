@@ -0,0 +1,58 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+type callUser struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func TestCall(t *testing.T) {
+	var received *http.Request
+	var payload callUser
+	server := mockServerWith(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		json.NewDecoder(r.Body).Decode(&payload)
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		payload.ID = 7
+		json.NewEncoder(w).Encode(payload)
+	})
+	defer server.Close()
+
+	client := createTestHTTPClient(server.URL)
+	spec := Spec{
+		Method:  http.MethodPost,
+		Path:    "/users",
+		Query:   url.Values{"dry_run": {"false"}},
+		Headers: http.Header{"X-Tenant": {"a"}},
+	}
+	user, err := Call[callUser, callUser](context.Background(), client, spec, callUser{Name: "jane"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.ID != 7 || user.Name != "jane" {
+		t.Errorf("Unexpected user %+v", user)
+	}
+	if received.URL.RawQuery != "dry_run=false" || received.Header.Get("X-Tenant") != "a" {
+		t.Errorf("Expected query and headers of the spec but got %s %v", received.URL, received.Header)
+	}
+
+	_, err = Call[Empty, Empty](context.Background(), client, Spec{Method: http.MethodDelete, Path: "/missing"}, Empty{})
+	var notFound *NotFoundError
+	if !errors.As(err, &notFound) {
+		t.Errorf("Expected NotFoundError but got %v", err)
+	}
+	if received.ContentLength != 0 {
+		t.Error("Expected an Empty request to be sent without body")
+	}
+}
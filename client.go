@@ -2,12 +2,13 @@ package http
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"io"
-	"log"
-	"net"
 	"net/http"
+	"net/url"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -16,37 +17,107 @@ const (
 	defaultRequestTimeOut = 30 * time.Second
 )
 
-// Client provides a high-level API for working with HTTP requests and constructing them.
+// Client provides a high-level API for working with HTTP requests and constructing them. It is composed of
+// smaller interfaces, so consumers can depend on and mock only the operations they use. All operations take a
+// context; HttpClient keeps the former context-less methods as deprecated wrappers.
 type Client interface {
-	GetFrom(path string) (*http.Response, error)
-	PostTo(path string, body io.Reader) (*http.Response, error)
-	PutTo(path string, body io.Reader) (*http.Response, error)
-	DeleteFrom(path string) (*http.Response, error)
+	Getter
+	Poster
+	Putter
+	Deleter
+	Executor
 
 	GetRequest(path string) (*http.Request, error)
 	PostRequest(path string, body io.Reader) (*http.Request, error)
 	PutRequest(path string, body io.Reader) (*http.Request, error)
 	DeleteRequest(path string) (*http.Request, error)
-	ExecuteRequest(r *http.Request) (*http.Response, error)
+}
+
+// Getter retrieves resources.
+type Getter interface {
+	Get(ctx context.Context, path string) (*http.Response, error)
+}
+
+// Poster creates resources.
+type Poster interface {
+	Post(ctx context.Context, path string, body io.Reader) (*http.Response, error)
+}
 
-	GetFromWithContext(ctx context.Context, path string) (*http.Response, error)
-	PostToWithContext(ctx context.Context, path string, body io.Reader) (*http.Response, error)
-	PutToWithContext(ctx context.Context, path string, body io.Reader) (*http.Response, error)
-	DeleteFromWithContext(ctx context.Context, path string) (*http.Response, error)
+// Putter replaces resources.
+type Putter interface {
+	Put(ctx context.Context, path string, body io.Reader) (*http.Response, error)
 }
 
+// Deleter deletes resources.
+type Deleter interface {
+	Delete(ctx context.Context, path string) (*http.Response, error)
+}
+
+// Executor executes prepared requests.
+type Executor interface {
+	ExecuteRequest(r *http.Request) (*http.Response, error)
+}
+
+var _ Client = (*HttpClient)(nil)
+
 // HttpConfig holds the base configuration for the HttpClient.
 type HttpConfig struct {
 	baseURL  string
 	username string
 	password string
 	accept   string
+	auth     AuthProvider
+	timeout  time.Duration
+	proxy    *url.URL
+	tls      *tls.Config
+
+	fallbackDelay  time.Duration
+	addressFamily  AddressFamily
+	resolve        map[string]string
+	hostHeader     string
+	socket         *localSocket
+	http2Fallback  bool
+	http2KeepAlive http2KeepAlive
+
+	watchdog      watchdogLimits
+	decompression decompressionLimits
+	compression   Compression
+	events        Events
+
+	redactedParams  []string
+	redactedHeaders []string
+	headerPolicy    *HeaderPolicy
+	messages        MessageCatalog
+	envelope        *Envelope
+	flags           Flags
+	endpoints       []endpointPattern
+	retry           *RetryPolicy
+	contentType     string
+	pagination      Pagination
+	idempotencyKeys bool
+	middleware      []Middleware
 }
 
 // HttpClient wraps the underlying http.Client and its HttpConfig.
+// It is safe for concurrent use by multiple goroutines, including changes through Reconfigure, Use and the other
+// setters while requests are in flight. Such changes apply to requests created afterwards.
 type HttpClient struct {
 	client *http.Client
 	config *HttpConfig
+
+	mu             sync.RWMutex
+	presets        map[string]Preset
+	environments   map[string]Environment
+	environment    string
+	tenantResolver TenantResolver
+	middleware     []Middleware
+	transformers   []ResponseTransformer
+
+	inflight         inflightRequests
+	latency          latencyEstimator
+	tlsTransports    sync.Map
+	http1Transports  sync.Map
+	pinnedTransports sync.Map
 }
 
 // NotFoundError allows to check for the not found url
@@ -80,48 +151,55 @@ func (e RemoteError) Error() string {
 	return e.err.Error()
 }
 
-func NewHttpConfig(baseURL string, username string, password string, accept string) *HttpConfig {
+func (e RemoteError) Unwrap() error {
+	return e.err
+}
+
+// ConfigError describes an invalid client configuration.
+type ConfigError struct {
+	Message string
+	Field   string
+}
+
+func (e ConfigError) Error() string {
+	return e.Message
+}
+
+func NewHttpConfig(baseURL string, username string, password string, accept string, opts ...Option) *HttpConfig {
 	config := &HttpConfig{
 		baseURL:  baseURL,
 		username: username,
 		password: password,
 		accept:   jsonType,
+		timeout:  defaultRequestTimeOut,
 	}
 
 	if accept != "" {
 		config.accept = accept
 	}
 
-	return config
+	return config.Apply(opts...)
 }
 
-func NewDefaultHttpConfig(baseURL string) *HttpConfig {
-	return NewHttpConfig(baseURL, "", "", jsonType)
+func NewDefaultHttpConfig(baseURL string, opts ...Option) *HttpConfig {
+	return NewHttpConfig(baseURL, "", "", jsonType, opts...)
+}
+
+// NewHttpConfigWithCredentials creates a HttpConfig using basic auth with credentials fetched lazily from source.
+func NewHttpConfigWithCredentials(baseURL string, source CredentialSource, accept string) *HttpConfig {
+	config := NewHttpConfig(baseURL, "", "", accept)
+	config.auth = BasicAuth(source)
+	return config
 }
 
 // Create a new default HttpClient with a custom transport for clean resource usage
 func NewDefaultHttpClient(baseURL string) *HttpClient {
-	client := &http.Client{
-		Transport: &http.Transport{
-			Proxy: http.ProxyFromEnvironment,
-			DialContext: (&net.Dialer{
-				Timeout:   defaultRequestTimeOut,
-				KeepAlive: defaultRequestTimeOut,
-			}).DialContext,
-			MaxIdleConns:          100,
-			IdleConnTimeout:       90 * time.Second,
-			TLSHandshakeTimeout:   10 * time.Second,
-			ExpectContinueTimeout: 1 * time.Second,
-		},
-		Timeout: defaultRequestTimeOut,
-	}
-
-	config := NewDefaultHttpConfig(baseURL)
+	return NewHttpClientWithConfig(NewDefaultHttpConfig(baseURL))
+}
 
-	return &HttpClient{
-		client: client,
-		config: config,
-	}
+// NewDefaultHttpClientE is like NewDefaultHttpClient but validates baseURL and returns an error if it is invalid.
+func NewDefaultHttpClientE(baseURL string) (*HttpClient, error) {
+	return NewHttpClientWithConfigE(NewDefaultHttpConfig(baseURL))
 }
 
 // NewHttpClientWithConfig creates a new HttpClient with given HttpConfig and a custom transport for clean resource usage
@@ -130,25 +208,25 @@ func NewHttpClientWithConfig(config *HttpConfig) *HttpClient {
 		panic("config is nil")
 	}
 
-	client := &http.Client{
-		Transport: &http.Transport{
-			Proxy: http.ProxyFromEnvironment,
-			DialContext: (&net.Dialer{
-				Timeout:   defaultRequestTimeOut,
-				KeepAlive: defaultRequestTimeOut,
-			}).DialContext,
-			MaxIdleConns:          100,
-			IdleConnTimeout:       90 * time.Second,
-			TLSHandshakeTimeout:   10 * time.Second,
-			ExpectContinueTimeout: 1 * time.Second,
-		},
-		Timeout: defaultRequestTimeOut,
+	client := &HttpClient{
+		client: newDefaultClient(config),
+		config: config,
 	}
+	client.trackConnections()
+	return client
+}
 
-	return &HttpClient{
-		client: client,
-		config: config,
+// NewHttpClientWithConfigE is like NewHttpClientWithConfig but validates the config and returns an error instead of panicking.
+func NewHttpClientWithConfigE(config *HttpConfig) (*HttpClient, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	client, err := NewHttpClientWithConfigAndClientE(config, newDefaultClient(config))
+	if err != nil {
+		return nil, err
 	}
+	client.trackConnections()
+	return client, nil
 }
 
 // NewHttpClientWithConfigAndClient creates a new HttpClient with given HttpConfig and a custom http.Client.
@@ -166,75 +244,182 @@ func NewHttpClientWithConfigAndClient(config *HttpConfig, client *http.Client) *
 	}
 }
 
+// NewHttpClientWithConfigAndClientE is like NewHttpClientWithConfigAndClient but validates its arguments and returns an error instead of panicking.
+func NewHttpClientWithConfigAndClientE(config *HttpConfig, client *http.Client) (*HttpClient, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	if client == nil {
+		return nil, &ConfigError{Message: "http.Client must not be nil.", Field: "client"}
+	}
+
+	return &HttpClient{
+		client: client,
+		config: config,
+	}, nil
+}
+
+// Validate checks the config for a usable base URL and consistent credentials.
+func (c *HttpConfig) Validate() error {
+	if c == nil {
+		return &ConfigError{Message: "HttpConfig must not be nil.", Field: "config"}
+	}
+
+	u, err := url.Parse(c.baseURL)
+	if err != nil {
+		return &ConfigError{Message: fmt.Sprintf("Base URL %q is invalid: %v.", c.baseURL, err), Field: "baseURL"}
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return &ConfigError{Message: fmt.Sprintf("Base URL %q must use http or https.", c.baseURL), Field: "baseURL"}
+	}
+	if u.Host == "" {
+		return &ConfigError{Message: fmt.Sprintf("Base URL %q has no host.", c.baseURL), Field: "baseURL"}
+	}
+
+	if (c.username == "") != (c.password == "") {
+		return &ConfigError{Message: "Username and password must be set together.", Field: "username"}
+	}
+	if c.username != "" && c.auth != nil {
+		return &ConfigError{Message: "Username and password conflict with the configured AuthProvider.", Field: "auth"}
+	}
+	if c.timeout < 0 {
+		return &ConfigError{Message: fmt.Sprintf("Timeout %s must not be negative.", c.timeout), Field: "timeout"}
+	}
+	if c.addressFamily < AddressFamilyAny || c.addressFamily > AddressFamilyIPv6Only {
+		return &ConfigError{Message: fmt.Sprintf("Address family %d is unknown.", c.addressFamily), Field: "addressFamily"}
+	}
+	if c.http2KeepAlive.readIdleTimeout < 0 || c.http2KeepAlive.pingTimeout < 0 {
+		return &ConfigError{Message: "HTTP/2 keepalive timeouts must not be negative.", Field: "http2KeepAlive"}
+	}
+	if c.retry != nil && c.retry.maxAttempts < 1 {
+		return &ConfigError{Message: fmt.Sprintf("Retry policy must allow at least 1 attempt, not %d.", c.retry.maxAttempts), Field: "retry"}
+	}
+	if c.proxy != nil && c.proxy.Host == "" {
+		return &ConfigError{Message: fmt.Sprintf("Proxy URL %q has no host.", c.proxy), Field: "proxy"}
+	}
+
+	return nil
+}
+
+// newDefaultClient creates a http.Client with a custom transport honoring the timeout, proxy and TLS settings of config.
+func newDefaultClient(config *HttpConfig) *http.Client {
+	transport := &http.Transport{
+		Proxy:                 proxyFunc(config),
+		DialContext:           newDialContext(config),
+		TLSClientConfig:       hostTLSConfig(config),
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+	configureHTTP2(transport, config)
+
+	return &http.Client{
+		Transport: transport,
+		Timeout:   config.timeout,
+	}
+}
+
+// proxyFunc returns the proxy selection of config, local sockets are never proxied.
+func proxyFunc(config *HttpConfig) func(*http.Request) (*url.URL, error) {
+	if config.socket != nil {
+		return nil
+	}
+	if config.proxy != nil {
+		return http.ProxyURL(config.proxy)
+	}
+	return http.ProxyFromEnvironment
+}
+
 //
 // Interface implementations
 //
+func (h *HttpClient) Get(ctx context.Context, path string) (*http.Response, error) {
+	return h.send(ctx, http.MethodGet, path, nil)
+}
+
+func (h *HttpClient) Post(ctx context.Context, path string, body io.Reader) (*http.Response, error) {
+	return h.send(ctx, http.MethodPost, path, body)
+}
+
+func (h *HttpClient) Put(ctx context.Context, path string, body io.Reader) (*http.Response, error) {
+	return h.send(ctx, http.MethodPut, path, body)
+}
+
+func (h *HttpClient) Delete(ctx context.Context, path string) (*http.Response, error) {
+	return h.send(ctx, http.MethodDelete, path, nil)
+}
+
+// GetFrom retrieves path without a context.
+//
+// Deprecated: Use Get.
 func (h *HttpClient) GetFrom(path string) (*http.Response, error) {
-	return h.GetFromWithContext(context.Background(), path)
+	return h.Get(context.Background(), path)
 }
 
+// PostTo posts body to path without a context.
+//
+// Deprecated: Use Post.
 func (h *HttpClient) PostTo(path string, body io.Reader) (*http.Response, error) {
-	return h.PostToWithContext(context.Background(), path, body)
+	return h.Post(context.Background(), path, body)
 }
 
+// PutTo puts body to path without a context.
+//
+// Deprecated: Use Put.
 func (h *HttpClient) PutTo(path string, body io.Reader) (*http.Response, error) {
-	return h.PutToWithContext(context.Background(), path, body)
+	return h.Put(context.Background(), path, body)
 }
 
+// DeleteFrom deletes path without a context.
+//
+// Deprecated: Use Delete.
 func (h *HttpClient) DeleteFrom(path string) (*http.Response, error) {
-	return h.DeleteFromWithContext(context.Background(), path)
+	return h.Delete(context.Background(), path)
 }
 
+// GetFromWithContext retrieves path.
+//
+// Deprecated: Use Get.
 func (h *HttpClient) GetFromWithContext(ctx context.Context, path string) (*http.Response, error) {
-	request, err := createRequest(ctx, h.config.baseURL, path, http.MethodGet, nil, h.config.username, h.config.password)
-	if err != nil {
-		return nil, err
-	}
-	requestWithCtx := request.WithContext(ctx)
-	return h.ExecuteRequest(requestWithCtx)
+	return h.Get(ctx, path)
 }
 
+// PostToWithContext posts body to path.
+//
+// Deprecated: Use Post.
 func (h *HttpClient) PostToWithContext(ctx context.Context, path string, body io.Reader) (*http.Response, error) {
-	request, err := createRequest(ctx, h.config.baseURL, path, http.MethodPost, body, h.config.username, h.config.password)
-	if err != nil {
-		return nil, err
-	}
-	requestWithCtx := request.WithContext(ctx)
-	return h.ExecuteRequest(requestWithCtx)
+	return h.Post(ctx, path, body)
 }
 
+// PutToWithContext puts body to path.
+//
+// Deprecated: Use Put.
 func (h *HttpClient) PutToWithContext(ctx context.Context, path string, body io.Reader) (*http.Response, error) {
-	request, err := createRequest(ctx, h.config.baseURL, path, http.MethodPut, body, h.config.username, h.config.password)
-	if err != nil {
-		return nil, err
-	}
-	requestWithCtx := request.WithContext(ctx)
-	return h.ExecuteRequest(requestWithCtx)
+	return h.Put(ctx, path, body)
 }
 
+// DeleteFromWithContext deletes path.
+//
+// Deprecated: Use Delete.
 func (h *HttpClient) DeleteFromWithContext(ctx context.Context, path string) (*http.Response, error) {
-	request, err := createRequest(ctx, h.config.baseURL, path, http.MethodDelete, nil, h.config.username, h.config.password)
-	if err != nil {
-		return nil, err
-	}
-	requestWithCtx := request.WithContext(ctx)
-	return h.ExecuteRequest(requestWithCtx)
+	return h.Delete(ctx, path)
 }
 
 func (h *HttpClient) GetRequest(path string) (*http.Request, error) {
-	return createRequest(nil, h.config.baseURL, path, http.MethodGet, nil, h.config.username, h.config.password)
+	return h.newRequest(nil, http.MethodGet, path, nil)
 }
 
 func (h *HttpClient) PostRequest(path string, body io.Reader) (*http.Request, error) {
-	return createRequest(nil, h.config.baseURL, path, http.MethodPost, body, h.config.username, h.config.password)
+	return h.newRequest(nil, http.MethodPost, path, body)
 }
 
 func (h *HttpClient) PutRequest(path string, body io.Reader) (*http.Request, error) {
-	return createRequest(nil, h.config.baseURL, path, http.MethodPut, body, h.config.username, h.config.password)
+	return h.newRequest(nil, http.MethodPut, path, body)
 }
 
 func (h *HttpClient) DeleteRequest(path string) (*http.Request, error) {
-	return createRequest(nil, h.config.baseURL, path, http.MethodDelete, nil, h.config.username, h.config.password)
+	return h.newRequest(nil, http.MethodDelete, path, nil)
 }
 
 //
@@ -247,6 +432,54 @@ func createDefaultContext(ctx context.Context) (context.Context, context.CancelF
 	return context.WithTimeout(context.Background(), defaultRequestTimeOut)
 }
 
+// send creates a request for path and executes it with ctx.
+func (h *HttpClient) send(ctx context.Context, method string, path string, body io.Reader) (*http.Response, error) {
+	request, err := h.newRequest(ctx, method, path, body)
+	if err != nil {
+		return nil, err
+	}
+	requestWithCtx := request.WithContext(ctx)
+	return h.ExecuteRequest(requestWithCtx)
+}
+
+// newRequest creates a request against the base URL and credentials in effect for ctx.
+func (h *HttpClient) newRequest(ctx context.Context, method string, path string, body io.Reader) (*http.Request, error) {
+	baseURL, username, password, err := h.resolveTarget(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if options := requestOptionsFrom(ctx); options != nil && options.authOverride {
+		username, password = "", ""
+	}
+
+	request, err := createRequest(ctx, baseURL, path, method, body, username, password)
+	if err != nil {
+		return request, err
+	}
+	if host := h.hostHeader(); host != "" {
+		request.Host = host
+	}
+	h.negotiate(request)
+	return request, nil
+}
+
+// negotiate sets the Accept and Content-Type headers of request to the content types of the config.
+func (h *HttpClient) negotiate(request *http.Request) {
+	h.mu.RLock()
+	accept := h.config.accept
+	h.mu.RUnlock()
+	if accept != "" {
+		request.Header.Set("Accept", accept)
+	}
+	request.Header.Set("Content-Type", h.contentType())
+}
+
+func (h *HttpClient) hostHeader() string {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.config.hostHeader
+}
+
 func createRequest(ctx context.Context, baseURL string, endpoint string, method string, body io.Reader, username string, password string) (*http.Request, error) {
 	// construct url by appending endpoint to base url
 	baseURL = strings.TrimSuffix(baseURL, "/")
@@ -268,33 +501,109 @@ func createRequest(ctx context.Context, baseURL string, endpoint string, method
 }
 
 func (h *HttpClient) ExecuteRequest(r *http.Request) (*http.Response, error) {
-	if r.Context() == context.Background() {
-		// TODO: handle Context's cancel function
-		ctx, _ := createDefaultContext(r.Context())
-		r = r.WithContext(ctx)
+	start := time.Now()
+	prepared, done, err := h.begin(r)
+	if err != nil {
+		return nil, h.newRequestError(r, start, err)
+	}
+	r = prepared
+
+	h.emit(Event{Type: EventRequestStarted, Time: start, Request: r})
+	resp, err := h.execute(r)
+	if err != nil {
+		err = h.newRequestError(r, start, err)
 	}
+	h.emit(Event{Type: EventRequestFinished, Request: r, Response: resp, Err: err, Duration: time.Since(start)})
 
-	resp, err := h.client.Do(r)
+	if resp == nil || resp.Body == nil {
+		done()
+		return resp, err
+	}
+	if _, buffered := resp.Body.(*bufferedBody); buffered {
+		done()
+		return resp, err
+	}
+	// the request stays in flight until its body is closed
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: done}
+	return resp, err
+}
 
+func (h *HttpClient) execute(r *http.Request) (*http.Response, error) {
+	resp, err := h.dispatch(r)
 	if err != nil {
-		return handleError(resp, err)
+		return handleError(r, resp, err)
+	}
+	if options := requestOptionsFrom(r.Context()); options != nil && options.bufferLimit > 0 && resp.Body != nil {
+		if err := bufferResponse(resp, options.bufferLimit); err != nil {
+			return nil, err
+		}
 	}
 
 	return resp, nil
 }
 
-func handleError(resp *http.Response, error error) (*http.Response, error) {
-	log.Fatal(error)
+// dispatch sends r with the client's idempotency keys and RetryPolicy, without mapping statuses to errors.
+func (h *HttpClient) dispatch(r *http.Request) (*http.Response, error) {
+	h.mu.RLock()
+	policy := h.config.retry
+	idempotencyKeys := h.config.idempotencyKeys
+	flags := h.config.flags
+	h.mu.RUnlock()
+	if options := requestOptionsFrom(r.Context()); options != nil && options.noRetry {
+		policy = nil
+	}
+	if policy != nil && !flagEnabled(r.Context(), flags, FlagRetries, true) {
+		policy = nil
+	}
+	if idempotencyKeys {
+		keyed, err := applyIdempotencyKey(r)
+		if err != nil {
+			return nil, err
+		}
+		r = keyed
+	}
+
+	if policy != nil {
+		return h.retryRoundTrip(r, policy)
+	}
+	return h.roundTrip(r)
+}
+
+// roundTrip authenticates r and sends it through the middleware chain, without mapping statuses to errors.
+func (h *HttpClient) roundTrip(r *http.Request) (*http.Response, error) {
+	r = applyContextValues(r)
+	r, provider := h.selectAuthProvider(r)
+	r, err := authenticate(r, provider)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := h.do(r)
+	if err == nil && provider != nil {
+		resp, err = h.answerChallenges(r, resp, provider)
+	}
+	return resp, err
+}
 
+func handleError(r *http.Request, resp *http.Response, err error) (*http.Response, error) {
+	if resp == nil {
+		return nil, &RemoteError{r.URL.Host, classifyError(err)}
+	}
+
+	return resp, statusError(resp)
+}
+
+// statusError maps the status code of an unsuccessful response to an error.
+func statusError(resp *http.Response) error {
 	if resp.StatusCode == http.StatusUnauthorized {
-		return resp, &UnauthorizedError{Message: "Authentication required.", URL: resp.Request.URL.String()}
+		return &UnauthorizedError{Message: "Authentication required.", URL: resp.Request.URL.String(), Status: resp.StatusCode}
 	}
 
 	if resp.StatusCode == http.StatusNotFound {
-		return resp, &NotFoundError{Message: "Resource not found.", URL: resp.Request.URL.String()}
+		return &NotFoundError{Message: "Resource not found.", URL: resp.Request.URL.String()}
 	}
 
-	return resp, &RemoteError{resp.Request.URL.Host, fmt.Errorf("%d: (%s)", resp.StatusCode, resp.Request.URL.String())}
+	return &RemoteError{resp.Request.URL.Host, fmt.Errorf("%d: (%s)", resp.StatusCode, resp.Request.URL.String())}
 }
 
 type RequestBuilder interface {
@@ -304,8 +613,10 @@ type RequestBuilder interface {
 	Delete() RequestBuilder
 	Path(path string) RequestBuilder
 	QueryParam(key string, value string) RequestBuilder
+	Header(key string, value string) RequestBuilder
 	WithContent(body io.Reader) RequestBuilder
 	AsJson() RequestBuilder
+	AsXml() RequestBuilder
 	Build() (*http.Request, error)
 }
 
@@ -313,6 +624,7 @@ type requestBuilder struct {
 	method      string
 	path        string
 	queryParams map[string]interface{}
+	header      http.Header
 	body        io.Reader
 	request     *http.Request
 	accept      string
@@ -352,11 +664,18 @@ func (rb *requestBuilder) WithContent(body io.Reader) RequestBuilder {
 	return rb
 }
 
+// AsJson accepts JSON and sends the content as JSON.
 func (rb *requestBuilder) AsJson() RequestBuilder {
 	rb.accept = jsonType
 	return rb
 }
 
+// AsXml accepts XML and sends the content as XML.
+func (rb *requestBuilder) AsXml() RequestBuilder {
+	rb.accept = xmlType
+	return rb
+}
+
 func (rb *requestBuilder) QueryParam(key string, value string) RequestBuilder {
 	if rb.queryParams == nil {
 		rb.queryParams = make(map[string]interface{})
@@ -365,8 +684,33 @@ func (rb *requestBuilder) QueryParam(key string, value string) RequestBuilder {
 	return rb
 }
 
+// Header adds a header to the request, cased as DefaultHeaderPolicy demands.
+func (rb *requestBuilder) Header(key string, value string) RequestBuilder {
+	if rb.header == nil {
+		rb.header = http.Header{}
+	}
+	name := DefaultHeaderPolicy.Canonical(key)
+	rb.header[name] = append(rb.header[name], value)
+	return rb
+}
+
 func (rb *requestBuilder) Build() (*http.Request, error) {
+	if err := DefaultHeaderPolicy.validate(rb.header); err != nil {
+		return nil, err
+	}
 	request, err := http.NewRequest(rb.method, rb.path, rb.body)
+	if err != nil {
+		return nil, err
+	}
+	if rb.accept != "" {
+		request.Header.Set("Accept", rb.accept)
+		if rb.body != nil {
+			request.Header.Set("Content-Type", rb.accept)
+		}
+	}
+	for key, values := range rb.header {
+		request.Header[key] = append(request.Header[key], values...)
+	}
 
 	if rb.queryParams != nil {
 		queryValues := request.URL.Query()
@@ -378,8 +722,5 @@ func (rb *requestBuilder) Build() (*http.Request, error) {
 		request.URL.RawQuery = queryValues.Encode()
 	}
 
-	if err != nil {
-		return request, err
-	}
 	return request, nil
 }
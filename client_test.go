@@ -2,8 +2,10 @@ package http
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
-	"io/ioutil"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
@@ -75,6 +77,41 @@ func TestNewHttpClient(t *testing.T) {
 	}
 }
 
+func TestNewHttpClientWithConfigE(t *testing.T) {
+	client, err := NewHttpClientWithConfigE(NewHttpConfig(fixtureBaseURL, "foo", "bar", contentTypeJSON))
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if client.config.baseURL != fixtureBaseURL {
+		t.Errorf("Expected %s but got %s", fixtureBaseURL, client.config.baseURL)
+	}
+}
+
+func TestNewHttpClientWithConfigE_InvalidConfig(t *testing.T) {
+	cases := map[string]*HttpConfig{
+		"config":   nil,
+		"baseURL":  NewDefaultHttpConfig("github.com/hawky-4s-"),
+		"username": NewHttpConfig(fixtureBaseURL, "foo", "", contentTypeJSON),
+		"auth":     NewHttpConfigWithCredentials(fixtureBaseURL, StaticCredentials("foo", "bar"), contentTypeJSON),
+	}
+	cases["auth"].username, cases["auth"].password = "foo", "bar"
+
+	for field, config := range cases {
+		_, err := NewHttpClientWithConfigE(config)
+
+		var configErr *ConfigError
+		if !errors.As(err, &configErr) || configErr.Field != field {
+			t.Errorf("Expected ConfigError for %s but got %v", field, err)
+		}
+	}
+}
+
+func TestNewHttpClientWithConfigAndClientE_NilClient(t *testing.T) {
+	if _, err := NewHttpClientWithConfigAndClientE(NewDefaultHttpConfig(fixtureBaseURL), nil); err == nil {
+		t.Error("Expected error for nil client")
+	}
+}
+
 func TestHttpClient_GetFrom(t *testing.T) {
 	server := mockServer(http.StatusOK, contentTypeJSON, fixtureBasicJSON)
 	defer server.Close()
@@ -150,7 +187,7 @@ func mockEchoServer(statusCode int) *httptest.Server {
 		body := ""
 		if r.Body != nil {
 			defer r.Body.Close()
-			bodyAsBytes, _ := ioutil.ReadAll(r.Body)
+			bodyAsBytes, _ := io.ReadAll(r.Body)
 			body = string(bodyAsBytes)
 		}
 		fmt.Fprint(w, body)
@@ -195,7 +232,7 @@ func assertResponseHasStatus(resp *http.Response, statusCode int, t *testing.T)
 
 func assertResponseBodyIs(resp *http.Response, expectedValue string, t *testing.T) {
 	defer resp.Body.Close()
-	body, err := ioutil.ReadAll(resp.Body)
+	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		t.Error("Unexpected error")
 	}
@@ -219,3 +256,61 @@ func assertURLIs(actual *url.URL, expectedV interface{}, t *testing.T) {
 		t.Errorf("Type not supported %v", expected)
 	}
 }
+
+type stubGetter struct {
+	paths []string
+}
+
+func (g *stubGetter) Get(ctx context.Context, path string) (*http.Response, error) {
+	g.paths = append(g.paths, path)
+	return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+}
+
+func TestGetter(t *testing.T) {
+	fetch := func(getter Getter) error {
+		resp, err := getter.Get(context.Background(), "/users")
+		if err == nil {
+			resp.Body.Close()
+		}
+		return err
+	}
+
+	stub := &stubGetter{}
+	if err := fetch(stub); err != nil || len(stub.paths) != 1 {
+		t.Errorf("Expected the stub to be called but got %v, %v", stub.paths, err)
+	}
+
+	server := mockServer(http.StatusOK, contentTypeJSON, "")
+	defer server.Close()
+	if err := fetch(createTestHTTPClient(server.URL)); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestHttpClient_ContextFirst(t *testing.T) {
+	var methods []string
+	server := mockServerWith(func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method)
+	})
+	defer server.Close()
+
+	client := createTestHTTPClient(server.URL)
+	ctx := context.Background()
+	calls := []func() (*http.Response, error){
+		func() (*http.Response, error) { return client.Get(ctx, "/") },
+		func() (*http.Response, error) { return client.Post(ctx, "/", strings.NewReader("{}")) },
+		func() (*http.Response, error) { return client.Put(ctx, "/", strings.NewReader("{}")) },
+		func() (*http.Response, error) { return client.Delete(ctx, "/") },
+	}
+	for _, call := range calls {
+		resp, err := call()
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+
+	if strings.Join(methods, ",") != "GET,POST,PUT,DELETE" {
+		t.Errorf("Unexpected methods %v", methods)
+	}
+}
@@ -0,0 +1,168 @@
+package http
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Clock is the source of time for components which schedule work, like the DurableQueue's redeliveries.
+// Tests inject a FakeClock to assert scheduled delays and fast-forward time without real sleeps.
+type Clock interface {
+	Now() time.Time
+	// After returns a channel receiving the current time once d has elapsed.
+	After(d time.Duration) <-chan time.Time
+	// Sleep waits for d or until ctx is done, in which case it returns ctx's error.
+	Sleep(ctx context.Context, d time.Duration) error
+}
+
+// SystemClock returns the Clock backed by the time package.
+func SystemClock() Clock {
+	return systemClock{}
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+func (systemClock) Sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// FakeClock is a Clock which only moves when advanced. It is safe for concurrent use.
+type FakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []fakeWaiter
+	changed chan struct{}
+}
+
+type fakeWaiter struct {
+	at time.Time
+	ch chan time.Time
+}
+
+// NewFakeClock creates a FakeClock starting at now.
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now, changed: make(chan struct{})}
+}
+
+// Now implements Clock.
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// After implements Clock.
+func (c *FakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ch := make(chan time.Time, 1)
+	if d <= 0 {
+		ch <- c.now
+		return ch
+	}
+	c.waiters = append(c.waiters, fakeWaiter{at: c.now.Add(d), ch: ch})
+	c.notify()
+	return ch
+}
+
+// Sleep implements Clock.
+func (c *FakeClock) Sleep(ctx context.Context, d time.Duration) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-c.After(d):
+		return nil
+	}
+}
+
+// Advance moves the clock forward by d and fires the waiters which became due.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+	pending := c.waiters[:0]
+	for _, w := range c.waiters {
+		if w.at.After(c.now) {
+			pending = append(pending, w)
+		} else {
+			w.ch <- c.now
+		}
+	}
+	c.waiters = pending
+	c.notify()
+}
+
+// Pending returns the delays until the scheduled waiters fire, shortest first.
+func (c *FakeClock) Pending() []time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delays := make([]time.Duration, 0, len(c.waiters))
+	for _, w := range c.waiters {
+		delays = append(delays, w.at.Sub(c.now))
+	}
+	sort.Slice(delays, func(i, j int) bool { return delays[i] < delays[j] })
+	return delays
+}
+
+// WaitForWaiters blocks until at least n waiters are scheduled or ctx is done. It lets tests advance the
+// clock only once the code under test has started waiting.
+func (c *FakeClock) WaitForWaiters(ctx context.Context, n int) error {
+	for {
+		c.mu.Lock()
+		count, changed := len(c.waiters), c.changed
+		c.mu.Unlock()
+
+		if count >= n {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-changed:
+		}
+	}
+}
+
+// notify wakes up WaitForWaiters. It must be called with c.mu held.
+func (c *FakeClock) notify() {
+	close(c.changed)
+	c.changed = make(chan struct{})
+}
+
+// Backoff returns the delay before the given attempt is repeated, starting with attempt 1.
+type Backoff func(attempt int) time.Duration
+
+// ExponentialBackoff returns a Backoff starting at base and doubling with every attempt, capped at max.
+func ExponentialBackoff(base time.Duration, max time.Duration) Backoff {
+	return func(attempt int) time.Duration {
+		backoff := base
+		for i := 1; i < attempt; i++ {
+			backoff *= 2
+			if backoff >= max {
+				return max
+			}
+		}
+		return backoff
+	}
+}
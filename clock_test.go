@@ -0,0 +1,98 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFakeClock(t *testing.T) {
+	start := time.Unix(1000, 0)
+	clock := NewFakeClock(start)
+
+	first := clock.After(2 * time.Second)
+	second := clock.After(5 * time.Second)
+	if pending := clock.Pending(); !reflect.DeepEqual(pending, []time.Duration{2 * time.Second, 5 * time.Second}) {
+		t.Errorf("Unexpected pending delays %v", pending)
+	}
+
+	clock.Advance(3 * time.Second)
+	select {
+	case now := <-first:
+		if !now.Equal(start.Add(3 * time.Second)) {
+			t.Errorf("Unexpected time %s", now)
+		}
+	default:
+		t.Error("Expected first waiter to fire")
+	}
+	select {
+	case <-second:
+		t.Error("Expected second waiter to be pending")
+	default:
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := clock.Sleep(ctx, time.Second); err != context.Canceled {
+		t.Errorf("Expected canceled sleep but got %v", err)
+	}
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	backoff := ExponentialBackoff(time.Second, 5*time.Second)
+
+	var delays []time.Duration
+	for attempt := 1; attempt <= 5; attempt++ {
+		delays = append(delays, backoff(attempt))
+	}
+	expected := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	if !reflect.DeepEqual(delays, expected) {
+		t.Errorf("Expected %v but got %v", expected, delays)
+	}
+}
+
+func TestDurableQueue_Clock(t *testing.T) {
+	server := mockServer(http.StatusServiceUnavailable, contentTypeJSON, "")
+	defer server.Close()
+
+	clock := NewFakeClock(time.Unix(1000, 0))
+	store := NewMemoryQueueStore()
+	queue := NewDurableQueue(createTestHTTPClient(server.URL), store).
+		WithClock(clock).
+		WithRedeliveryInterval(time.Minute).
+		WithBackoff(func(attempt int) time.Duration { return time.Duration(attempt) * time.Hour })
+
+	req, _ := http.NewRequest(http.MethodPost, server.URL, nil)
+	queue.Submit(req)
+
+	items, _ := store.Load()
+	if len(items) != 1 || items[0].NextAttempt.Sub(clock.Now()) != time.Hour {
+		t.Fatalf("Expected redelivery scheduled in an hour but got %+v", items)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	queue.Start(ctx)
+	defer queue.Stop()
+
+	// the worker checks every minute, the request becomes due after an hour
+	for i := 0; i < 60; i++ {
+		if err := clock.WaitForWaiters(ctx, 1); err != nil {
+			t.Fatal(err)
+		}
+		clock.Advance(time.Minute)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		items, _ = store.Load()
+		if len(items) == 1 && items[0].Attempts == 2 {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if items[0].Attempts != 2 || items[0].NextAttempt.Sub(clock.Now()) != 2*time.Hour {
+		t.Errorf("Expected second delivery with a backoff of two hours but got %+v", items[0])
+	}
+}
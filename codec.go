@@ -0,0 +1,119 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"strings"
+	"sync"
+)
+
+// Codec encodes request and decodes response bodies of the content types it handles.
+type Codec interface {
+	// ContentTypes returns the media types the codec handles, the first is used when encoding.
+	ContentTypes() []string
+	Encode(v interface{}) ([]byte, error)
+	Decode(data []byte, v interface{}) error
+}
+
+// CodecError is returned when no Codec is registered for a content type.
+type CodecError struct {
+	Message     string
+	ContentType string
+}
+
+func (e CodecError) Error() string {
+	return e.Message
+}
+
+var codecs = struct {
+	sync.RWMutex
+	byType map[string]Codec
+}{byType: map[string]Codec{}}
+
+func init() {
+	RegisterCodec(JSONCodec())
+	RegisterCodec(FormCodec())
+	RegisterCodec(XMLCodec())
+}
+
+// RegisterCodec makes codec available to EncodeBody and DecodeResponse for its content types, replacing codecs
+// registered before for them. JSON, XML and forms are registered by default; optional codecs like YAMLCodec are registered by
+// the application.
+func RegisterCodec(codec Codec) {
+	if codec == nil {
+		panic("codec is nil")
+	}
+	codecs.Lock()
+	defer codecs.Unlock()
+	for _, contentType := range codec.ContentTypes() {
+		codecs.byType[strings.ToLower(contentType)] = codec
+	}
+}
+
+// CodecFor returns the Codec registered for the media type of contentType, if any. Parameters like charset
+// are ignored.
+func CodecFor(contentType string) (Codec, bool) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = strings.TrimSpace(strings.SplitN(contentType, ";", 2)[0])
+	}
+
+	codecs.RLock()
+	defer codecs.RUnlock()
+	codec, ok := codecs.byType[strings.ToLower(mediaType)]
+	return codec, ok
+}
+
+// EncodeBody encodes v with the Codec registered for contentType, e.g. as body of a PostTo.
+func EncodeBody(contentType string, v interface{}) (io.Reader, error) {
+	codec, ok := CodecFor(contentType)
+	if !ok {
+		return nil, &CodecError{Message: fmt.Sprintf("No codec registered for %s.", contentType), ContentType: contentType}
+	}
+	data, err := codec.Encode(v)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.NewReader(data), nil
+}
+
+// WithContentType sends request bodies as contentType instead of JSON, e.g. as XML or form for APIs which only
+// accept those. Call and the API facade encode bodies with the Codec registered for it, and it is the Content-Type
+// of the requests created by the client. Use WithAccept to negotiate the response content type.
+func WithContentType(contentType string) Option {
+	return func(c *HttpConfig) {
+		c.contentType = contentType
+	}
+}
+
+// contentType returns the content type request bodies are encoded with.
+func (h *HttpClient) contentType() string {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	if h.config.contentType == "" {
+		return jsonType
+	}
+	return h.config.contentType
+}
+
+// JSONCodec returns the Codec for application/json.
+func JSONCodec() Codec {
+	return jsonCodec{}
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) ContentTypes() []string {
+	return []string{jsonType}
+}
+
+func (jsonCodec) Encode(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Decode(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
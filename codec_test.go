@@ -0,0 +1,31 @@
+package http
+
+import (
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestCodecFor(t *testing.T) {
+	codec, ok := CodecFor("application/json; charset=utf-8")
+	if !ok || codec.ContentTypes()[0] != jsonType {
+		t.Error("Expected the JSON codec to be registered by default")
+	}
+	if _, ok := CodecFor("application/x-unknown"); ok {
+		t.Error("Expected no codec for an unknown content type")
+	}
+
+	body, err := EncodeBody("Application/JSON", map[string]int{"a": 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if encoded, _ := io.ReadAll(body); string(encoded) != `{"a":1}` {
+		t.Errorf("Unexpected body %s", encoded)
+	}
+
+	_, err = EncodeBody("application/x-unknown", nil)
+	var codecErr *CodecError
+	if !errors.As(err, &codecErr) || codecErr.ContentType != "application/x-unknown" {
+		t.Errorf("Expected a CodecError but got %v", err)
+	}
+}
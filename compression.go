@@ -0,0 +1,86 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Compression controls how the client negotiates and decodes compressed response bodies.
+type Compression int
+
+const (
+	// CompressionAuto advertises gzip and decodes compressed bodies transparently, the transport's default.
+	CompressionAuto Compression = iota
+	// CompressionIdentity asks the server for uncompressed bodies.
+	CompressionIdentity
+	// CompressionRaw advertises gzip but returns compressed bodies untouched, with their Content-Encoding and
+	// Content-Length, e.g. to pass them through a proxy without recompressing.
+	CompressionRaw
+)
+
+func (c Compression) String() string {
+	switch c {
+	case CompressionAuto:
+		return "auto"
+	case CompressionIdentity:
+		return "identity"
+	case CompressionRaw:
+		return "raw"
+	}
+	return "unknown"
+}
+
+// WithCompression sets how all requests of the client negotiate compression.
+func WithCompression(mode Compression) Option {
+	return func(c *HttpConfig) {
+		c.compression = mode
+	}
+}
+
+// WithRequestCompression overrides the client's Compression for a single request.
+func WithRequestCompression(mode Compression) RequestOption {
+	return func(o *requestOptions) {
+		o.compression = &mode
+		o.acceptEncoding = ""
+	}
+}
+
+// WithAcceptEncoding advertises the given encodings, e.g. "br", "gzip", instead of letting the transport
+// choose. Bodies are returned as the server encoded them, see ContentEncoding.
+func WithAcceptEncoding(encodings ...string) RequestOption {
+	return func(o *requestOptions) {
+		raw := CompressionRaw
+		o.compression = &raw
+		o.acceptEncoding = strings.Join(encodings, ", ")
+	}
+}
+
+// ContentEncoding returns the encodings applied to the body of resp, e.g. "gzip", or an empty string if the
+// body is not compressed or was decoded by the client.
+func ContentEncoding(resp *http.Response) string {
+	if resp == nil || resp.Uncompressed {
+		return ""
+	}
+	return resp.Header.Get("Content-Encoding")
+}
+
+// negotiateCompression wraps next to set the Accept-Encoding of mode, unless the request sets one already.
+func negotiateCompression(next RoundTripperFunc, mode Compression, acceptEncoding string) RoundTripperFunc {
+	if mode == CompressionAuto {
+		return next
+	}
+	if acceptEncoding == "" {
+		acceptEncoding = "gzip"
+		if mode == CompressionIdentity {
+			acceptEncoding = "identity"
+		}
+	}
+	return func(r *http.Request) (*http.Response, error) {
+		if r.Header.Get("Accept-Encoding") != "" {
+			return next(r)
+		}
+		r = r.Clone(r.Context())
+		r.Header.Set("Accept-Encoding", acceptEncoding)
+		return next(r)
+	}
+}
@@ -0,0 +1,62 @@
+package http
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+)
+
+func TestCompression(t *testing.T) {
+	body := gzipped("hello")
+	server := mockServerWith(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Accept-Encoding", r.Header.Get("Accept-Encoding"))
+		if r.Header.Get("Accept-Encoding") == "identity" {
+			w.Write([]byte("hello"))
+			return
+		}
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Write(body)
+	})
+	defer server.Close()
+
+	tests := []struct {
+		name           string
+		mode           Compression
+		options        []RequestOption
+		acceptEncoding string
+		encoding       string
+	}{
+		{name: "auto", mode: CompressionAuto, acceptEncoding: "gzip", encoding: ""},
+		{name: "identity", mode: CompressionIdentity, acceptEncoding: "identity", encoding: ""},
+		{name: "raw", mode: CompressionRaw, acceptEncoding: "gzip", encoding: "gzip"},
+		{name: "request override", mode: CompressionRaw, options: []RequestOption{WithRequestCompression(CompressionIdentity)},
+			acceptEncoding: "identity", encoding: ""},
+		{name: "manual", mode: CompressionAuto, options: []RequestOption{WithAcceptEncoding("br", "gzip")},
+			acceptEncoding: "br, gzip", encoding: "gzip"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client := NewHttpClientWithConfig(NewDefaultHttpConfig(server.URL, WithCompression(test.mode)))
+			resp, err := client.Get(WithRequestOptions(context.Background(), test.options...), "/")
+			if err != nil {
+				t.Fatal(err)
+			}
+			data, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+
+			if got := resp.Header.Get("X-Accept-Encoding"); got != test.acceptEncoding {
+				t.Errorf("Expected Accept-Encoding %q but got %q", test.acceptEncoding, got)
+			}
+			if got := ContentEncoding(resp); got != test.encoding {
+				t.Errorf("Expected content encoding %q but got %q", test.encoding, got)
+			}
+			if test.encoding == "" && string(data) != "hello" {
+				t.Errorf("Expected decoded body but got %q", data)
+			}
+			if test.encoding != "" && string(data) != string(body) {
+				t.Errorf("Expected the raw compressed body but got %q", data)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// The tests in this file exercise concurrent use of a single HttpClient. They pass without the race detector,
+// but are meant to be run with "go test -race" to verify the client's synchronization.
+
+const concurrentWorkers = 8
+
+func runConcurrently(t *testing.T, workers ...func(i int)) {
+	t.Helper()
+
+	var wg sync.WaitGroup
+	for i := 0; i < concurrentWorkers; i++ {
+		for _, worker := range workers {
+			wg.Add(1)
+			go func(worker func(i int), i int) {
+				defer wg.Done()
+				worker(i)
+			}(worker, i)
+		}
+	}
+	wg.Wait()
+}
+
+func TestConcurrency_RequestsAndReconfigure(t *testing.T) {
+	server := mockEchoServer(http.StatusOK)
+	defer server.Close()
+
+	client := createTestHTTPClient(server.URL)
+
+	runConcurrently(t,
+		func(i int) {
+			if resp, err := client.GetFrom("/"); err == nil {
+				resp.Body.Close()
+			}
+		},
+		func(i int) {
+			if resp, err := client.PostTo("/", strings.NewReader(fixtureBasicJSON)); err == nil {
+				resp.Body.Close()
+			}
+		},
+		func(i int) {
+			client.Reconfigure(WithBaseURL(server.URL), WithTimeout(time.Duration(i+1)*time.Second))
+		},
+		func(i int) {
+			client.Config().Redacted()
+		},
+	)
+}
+
+func TestConcurrency_Setters(t *testing.T) {
+	server := mockEchoServer(http.StatusOK)
+	defer server.Close()
+
+	client := createTestHTTPClient(server.URL)
+	client.AddEnvironment(Environment{Name: EnvironmentStaging, BaseURL: server.URL})
+
+	runConcurrently(t,
+		func(i int) {
+			ctx := WithEnvironment(context.Background(), EnvironmentStaging)
+			if resp, err := client.GetFromWithContext(ctx, "/"); err == nil {
+				client.DecodeResponse(resp, &map[string]interface{}{})
+			}
+		},
+		func(i int) {
+			client.Use(func(next RoundTripperFunc) RoundTripperFunc { return next })
+		},
+		func(i int) {
+			client.SetAuthProvider(BasicAuth(StaticCredentials("user", "secret")))
+		},
+		func(i int) {
+			client.AddEnvironment(Environment{Name: EnvironmentSandbox, BaseURL: server.URL})
+			client.UseEnvironment("")
+		},
+		func(i int) {
+			client.AddResponseTransformer(StripXSSIPrefix())
+		},
+		func(i int) {
+			client.DefinePreset("echo", Preset{Method: http.MethodGet, Path: "/"})
+		},
+		func(i int) {
+			client.UsePAC(NewPACProxy("proxy.pac", PACEvaluatorFunc(func(string, string, string) (string, error) {
+				return "DIRECT", nil
+			})))
+		},
+	)
+}
@@ -0,0 +1,206 @@
+package http
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const redactedSecret = "xxxxx"
+
+// BaseURL returns the base URL requests are resolved against.
+func (c *HttpConfig) BaseURL() string {
+	return c.baseURL
+}
+
+// Username returns the username sent as basic auth.
+func (c *HttpConfig) Username() string {
+	return c.username
+}
+
+// Password returns the password sent as basic auth.
+func (c *HttpConfig) Password() string {
+	return c.password
+}
+
+// Accept returns the accepted content type.
+func (c *HttpConfig) Accept() string {
+	return c.accept
+}
+
+// AuthProvider returns the AuthProvider authenticating requests, if any.
+func (c *HttpConfig) AuthProvider() AuthProvider {
+	return c.auth
+}
+
+// Timeout returns the overall timeout of a request.
+func (c *HttpConfig) Timeout() time.Duration {
+	return c.timeout
+}
+
+// Proxy returns the proxy all requests are routed through, or nil if the environment decides.
+func (c *HttpConfig) Proxy() *url.URL {
+	return c.proxy
+}
+
+// TLSConfig returns the TLS configuration of the transport.
+func (c *HttpConfig) TLSConfig() *tls.Config {
+	return c.tls
+}
+
+// FallbackDelay returns the delay before racing a connection over the other address family.
+func (c *HttpConfig) FallbackDelay() time.Duration {
+	return c.fallbackDelay
+}
+
+// AddressFamily returns which IP address families are used to connect.
+func (c *HttpConfig) AddressFamily() AddressFamily {
+	return c.addressFamily
+}
+
+// Resolve returns a copy of the host to address pinning, see WithResolve.
+func (c *HttpConfig) Resolve() map[string]string {
+	resolve := make(map[string]string, len(c.resolve))
+	for k, v := range c.resolve {
+		resolve[k] = v
+	}
+	return resolve
+}
+
+// HostHeader returns the Host header sent instead of the host of the base URL, if any.
+func (c *HttpConfig) HostHeader() string {
+	return c.hostHeader
+}
+
+// Events returns the consumer of the client's events, if any.
+func (c *HttpConfig) Events() Events {
+	return c.events
+}
+
+// RedactedParams returns the query parameters masked in addition to DefaultRedactedParams.
+func (c *HttpConfig) RedactedParams() []string {
+	return append([]string(nil), c.redactedParams...)
+}
+
+// RedactedHeaders returns the headers masked in addition to DefaultRedactedHeaders.
+func (c *HttpConfig) RedactedHeaders() []string {
+	return append([]string(nil), c.redactedHeaders...)
+}
+
+// MessageCatalog returns the catalog localizing error messages, if any.
+func (c *HttpConfig) MessageCatalog() MessageCatalog {
+	return c.messages
+}
+
+// Envelope returns the response envelope decoded values are extracted from, if any.
+func (c *HttpConfig) Envelope() *Envelope {
+	return c.envelope
+}
+
+// Flags returns the feature flags consulted at request time, if any.
+func (c *HttpConfig) Flags() Flags {
+	return c.flags
+}
+
+// DecompressionLimits returns the maximum decompressed size and compression ratio of bodies, zero if unlimited.
+func (c *HttpConfig) DecompressionLimits() (maxSize int64, maxRatio float64) {
+	return c.decompression.maxSize, c.decompression.maxRatio
+}
+
+// Compression returns how requests negotiate compression.
+func (c *HttpConfig) Compression() Compression {
+	return c.compression
+}
+
+// HTTP2Fallback reports whether requests failing with an HTTP/2 stream error are retried over HTTP/1.1.
+func (c *HttpConfig) HTTP2Fallback() bool {
+	return c.http2Fallback
+}
+
+// HTTP2KeepAlive returns the read idle timeout after which HTTP/2 connections are pinged and the timeout of the
+// ping, zero if connections are not pinged.
+func (c *HttpConfig) HTTP2KeepAlive() (readIdleTimeout time.Duration, pingTimeout time.Duration) {
+	return c.http2KeepAlive.readIdleTimeout, c.http2KeepAlive.pingTimeout
+}
+
+// HeaderPolicy returns the policy applied to request headers, DefaultHeaderPolicy unless set.
+func (c *HttpConfig) HeaderPolicy() *HeaderPolicy {
+	if c.headerPolicy == nil {
+		return DefaultHeaderPolicy
+	}
+	return c.headerPolicy
+}
+
+// RetryPolicy returns the policy failed requests are retried with, if any.
+func (c *HttpConfig) RetryPolicy() *RetryPolicy {
+	return c.retry
+}
+
+// ContentType returns the content type the bodies of Call and the API facade are encoded with, JSON if empty.
+func (c *HttpConfig) ContentType() string {
+	return c.contentType
+}
+
+// Pagination returns how Pages finds the following page, nil for LinkPagination.
+func (c *HttpConfig) Pagination() Pagination {
+	return c.pagination
+}
+
+// IdempotencyKeys reports whether POST and PATCH requests get an Idempotency-Key header.
+func (c *HttpConfig) IdempotencyKeys() bool {
+	return c.idempotencyKeys
+}
+
+// Middleware returns the middleware set with WithMiddleware, without the middleware added to clients with Use.
+func (c *HttpConfig) Middleware() []Middleware {
+	return append([]Middleware(nil), c.middleware...)
+}
+
+// String returns the redacted representation of the config, so printing it never leaks secrets.
+func (c *HttpConfig) String() string {
+	return c.Redacted()
+}
+
+// Redacted returns a representation of the config with passwords masked.
+func (c *HttpConfig) Redacted() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "HttpConfig{baseURL: %s", redactURL(c.baseURL))
+	if c.username != "" {
+		fmt.Fprintf(&b, ", username: %s, password: %s", c.username, redactedSecret)
+	}
+	fmt.Fprintf(&b, ", accept: %s, timeout: %s", c.accept, c.timeout)
+	if c.auth != nil {
+		fmt.Fprintf(&b, ", auth: %T", c.auth)
+	}
+	if c.addressFamily != AddressFamilyAny {
+		fmt.Fprintf(&b, ", addressFamily: %s", c.addressFamily)
+	}
+	if c.proxy != nil {
+		fmt.Fprintf(&b, ", proxy: %s", c.proxy.Redacted())
+	}
+	if c.tls != nil {
+		fmt.Fprintf(&b, ", tls: {serverName: %s, insecureSkipVerify: %t}", c.tls.ServerName, c.tls.InsecureSkipVerify)
+	}
+	b.WriteString("}")
+
+	return b.String()
+}
+
+// Config returns a copy of the config currently in use by the client.
+func (h *HttpClient) Config() *HttpConfig {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	config := *h.config
+	return &config
+}
+
+func redactURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	return u.Redacted()
+}
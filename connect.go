@@ -0,0 +1,220 @@
+package http
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Connect opens a TCP tunnel to address ("host:port"), through the proxy the client would use for https
+// requests to address if there is one. The returned connection is raw, use ConnectTLS for a TLS tunnel.
+func (h *HttpClient) Connect(ctx context.Context, address string) (net.Conn, error) {
+	transport, err := h.transport()
+	if err != nil {
+		return nil, err
+	}
+
+	target := &url.URL{Scheme: "https", Host: address}
+	var proxy *url.URL
+	if transport.Proxy != nil {
+		if proxy, err = transport.Proxy(&http.Request{Method: http.MethodConnect, URL: target, Host: address}); err != nil {
+			return nil, err
+		}
+	}
+
+	dial := transport.DialContext
+	if dial == nil {
+		dial = (&net.Dialer{}).DialContext
+	}
+	if proxy == nil {
+		conn, err := dial(ctx, "tcp", address)
+		if err != nil {
+			return nil, &RemoteError{address, classifyError(err)}
+		}
+		return conn, nil
+	}
+
+	conn, err := dial(ctx, "tcp", proxyAddress(proxy))
+	if err != nil {
+		return nil, &RemoteError{proxy.Host, classifyError(err)}
+	}
+	if proxy.Scheme == "https" {
+		tlsConn := tls.Client(conn, &tls.Config{ServerName: proxy.Hostname()})
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			return nil, &RemoteError{proxy.Host, classifyError(err)}
+		}
+		conn = tlsConn
+	}
+
+	tunnel, err := proxyConnect(ctx, conn, proxy, address)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return tunnel, nil
+}
+
+// ConnectTLS opens a tunnel like Connect and performs a TLS handshake with address using the client's TLS config.
+func (h *HttpClient) ConnectTLS(ctx context.Context, address string) (net.Conn, error) {
+	transport, err := h.transport()
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := h.Connect(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &tls.Config{}
+	if transport.TLSClientConfig != nil {
+		config = transport.TLSClientConfig.Clone()
+	}
+	if config.ServerName == "" {
+		host, _, err := net.SplitHostPort(address)
+		if err != nil {
+			host = address
+		}
+		config.ServerName = host
+	}
+
+	tlsConn := tls.Client(conn, config)
+	if err := tlsConn.HandshakeContext(ctx); err != nil {
+		conn.Close()
+		return nil, &RemoteError{address, classifyError(err)}
+	}
+	return tlsConn, nil
+}
+
+// Upgrade sends a request for path asking the server to switch to protocol, e.g. "websocket", and returns the
+// upgraded connection. The request passes the client's auth and middleware like any other request, the
+// response is returned for inspecting headers. Any status but 101 Switching Protocols is an error.
+func (h *HttpClient) Upgrade(ctx context.Context, path string, protocol string) (io.ReadWriteCloser, *http.Response, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	// the connection is long-lived, neither the watchdog nor the client's timeout may abort it
+	ctx = WithRequestOptions(ctx, WithWatchdog(0, 0), func(o *requestOptions) {
+		o.untimed = true
+	})
+
+	request, err := h.newRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	request.Header.Set("Connection", "Upgrade")
+	request.Header.Set("Upgrade", protocol)
+
+	request, done, err := h.begin(request.WithContext(ctx))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resp, err := h.execute(request)
+	if err != nil {
+		done()
+		return nil, resp, err
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		drainAndClose(resp.Body)
+		done()
+		return nil, resp, &RemoteError{request.URL.Host, fmt.Errorf("upgrade to %s refused: %s", protocol, resp.Status)}
+	}
+
+	conn, ok := resp.Body.(io.ReadWriteCloser)
+	if !ok {
+		resp.Body.Close()
+		done()
+		return nil, resp, errors.New("upgraded connection is not writable")
+	}
+	return &upgradedConn{ReadWriteCloser: conn, done: done}, resp, nil
+}
+
+// upgradedConn keeps its request in flight until the connection is closed.
+type upgradedConn struct {
+	io.ReadWriteCloser
+	done func()
+}
+
+func (c *upgradedConn) Close() error {
+	err := c.ReadWriteCloser.Close()
+	c.done()
+	return err
+}
+
+// transport returns the *http.Transport of the client.
+func (h *HttpClient) transport() (*http.Transport, error) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	transport, ok := h.client.Transport.(*http.Transport)
+	if !ok {
+		return nil, errors.New("tunnels require an *http.Transport")
+	}
+	return transport, nil
+}
+
+// proxyConnect asks the proxy on conn to open a tunnel to address and returns the tunnel.
+func proxyConnect(ctx context.Context, conn net.Conn, proxy *url.URL, address string) (net.Conn, error) {
+	request := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: address},
+		Host:   address,
+		Header: make(http.Header),
+	}
+	if proxy.User != nil {
+		password, _ := proxy.User.Password()
+		credentials := base64.StdEncoding.EncodeToString([]byte(proxy.User.Username() + ":" + password))
+		request.Header.Set("Proxy-Authorization", "Basic "+credentials)
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+		defer conn.SetDeadline(time.Time{})
+	}
+
+	if err := request.Write(conn); err != nil {
+		return nil, &RemoteError{proxy.Host, err}
+	}
+
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, request)
+	if err != nil {
+		return nil, &RemoteError{proxy.Host, err}
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &RemoteError{proxy.Host, fmt.Errorf("proxy refused CONNECT to %s: %s", address, resp.Status)}
+	}
+	// the reader may already hold data sent through the tunnel right after the response
+	return &bufferedConn{Conn: conn, reader: reader}, nil
+}
+
+type bufferedConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+func (c *bufferedConn) Read(p []byte) (int, error) {
+	return c.reader.Read(p)
+}
+
+func proxyAddress(proxy *url.URL) string {
+	if proxy.Port() != "" {
+		return proxy.Host
+	}
+	if proxy.Scheme == "https" {
+		return net.JoinHostPort(proxy.Hostname(), "443")
+	}
+	return net.JoinHostPort(proxy.Hostname(), "80")
+}
@@ -0,0 +1,136 @@
+package http
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+// echoListener accepts connections and echoes everything written to them.
+func echoListener(t *testing.T) net.Listener {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				io.Copy(conn, conn)
+			}()
+		}
+	}()
+	return listener
+}
+
+func assertEcho(t *testing.T, conn io.ReadWriter) {
+	t.Helper()
+
+	if _, err := conn.Write([]byte("ping\n")); err != nil {
+		t.Fatal(err)
+	}
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil || line != "ping\n" {
+		t.Errorf("Expected echo but got %q, %v", line, err)
+	}
+}
+
+func TestHttpClient_ConnectDirect(t *testing.T) {
+	listener := echoListener(t)
+	defer listener.Close()
+
+	client := createTestHTTPClient(fixtureBaseURL)
+	// bypass any proxy configured in the environment
+	client.client.Transport.(*http.Transport).Proxy = nil
+
+	conn, err := client.Connect(context.Background(), listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	assertEcho(t, conn)
+}
+
+func TestHttpClient_ConnectThroughProxy(t *testing.T) {
+	listener := echoListener(t)
+	defer listener.Close()
+
+	var authorization string
+	proxy := mockServerWith(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Proxy-Authorization")
+		if r.Method != http.MethodConnect || r.Host != listener.Addr().String() {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		upstream, err := net.Dial("tcp", r.Host)
+		if err != nil {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		conn, buffered, _ := w.(http.Hijacker).Hijack()
+		conn.Write([]byte("HTTP/1.1 200 Connection established\r\n\r\n"))
+		go func() {
+			defer upstream.Close()
+			io.Copy(upstream, buffered)
+		}()
+		io.Copy(conn, upstream)
+		conn.Close()
+	})
+	defer proxy.Close()
+
+	proxyURL, _ := url.Parse(proxy.URL)
+	proxyURL.User = url.UserPassword("user", "secret")
+	client := NewHttpClientWithConfig(NewDefaultHttpConfig(fixtureBaseURL, WithProxy(proxyURL)))
+
+	conn, err := client.Connect(context.Background(), listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	assertEcho(t, conn)
+	if authorization != "Basic dXNlcjpzZWNyZXQ=" {
+		t.Errorf("Expected proxy credentials but got %q", authorization)
+	}
+}
+
+func TestHttpClient_Upgrade(t *testing.T) {
+	server := mockServerWith(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Upgrade") != "echo" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		conn, buffered, _ := w.(http.Hijacker).Hijack()
+		defer conn.Close()
+		buffered.WriteString("HTTP/1.1 101 Switching Protocols\r\nConnection: Upgrade\r\nUpgrade: echo\r\n\r\n")
+		buffered.Flush()
+		io.Copy(conn, buffered)
+	})
+	defer server.Close()
+
+	client := createTestHTTPClient(server.URL)
+
+	conn, resp, err := client.Upgrade(context.Background(), "/stream", "echo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	if resp.Header.Get("Upgrade") != "echo" {
+		t.Errorf("Expected upgrade response but got %v", resp.Header)
+	}
+	assertEcho(t, conn)
+
+	if _, _, err := client.Upgrade(context.Background(), "/stream", "other"); err == nil {
+		t.Error("Expected refused upgrade to fail")
+	}
+}
@@ -0,0 +1,217 @@
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/textproto"
+	"strconv"
+	"strings"
+)
+
+const (
+	connectProtocolVersion = "1"
+	grpcWebContentType     = "application/grpc-web+proto"
+	grpcWebTrailerFlag     = 0x80
+)
+
+// ConnectError is the error of a failed Connect unary call, see https://connectrpc.com/docs/protocol.
+type ConnectError struct {
+	Message string
+	Code    string
+	Status  int
+	Details json.RawMessage
+}
+
+func (e ConnectError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+// GRPCError is the non-OK status of a gRPC-Web call.
+type GRPCError struct {
+	Message string
+	Code    int
+}
+
+func (e GRPCError) Error() string {
+	return fmt.Sprintf("grpc status %d: %s", e.Code, e.Message)
+}
+
+// ConnectUnary issues a Connect unary call of procedure ("/package.Service/Method") through the client's
+// authentication, middleware and events. message is encoded according to contentType, e.g. application/proto
+// for a message marshalled by the caller's protobuf library or application/json.
+func (h *HttpClient) ConnectUnary(ctx context.Context, procedure string, contentType string, message []byte) ([]byte, error) {
+	resp, err := h.rpcRequest(ctx, procedure, message, http.Header{
+		"Content-Type":             {contentType},
+		"Accept":                   {contentType},
+		"Connect-Protocol-Version": {connectProtocolVersion},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		connectErr := &ConnectError{Status: resp.StatusCode}
+		if json.Unmarshal(body, connectErr) != nil || connectErr.Code == "" {
+			connectErr.Code = connectCodeOf(resp.StatusCode)
+			connectErr.Message = strings.TrimSpace(string(body))
+		}
+		return nil, connectErr
+	}
+	return body, nil
+}
+
+// UnmarshalJSON reads the JSON error body of the Connect protocol.
+func (e *ConnectError) UnmarshalJSON(data []byte) error {
+	var body struct {
+		Code    string          `json:"code"`
+		Message string          `json:"message"`
+		Details json.RawMessage `json:"details"`
+	}
+	if err := json.Unmarshal(data, &body); err != nil {
+		return err
+	}
+	e.Code, e.Message, e.Details = body.Code, body.Message, body.Details
+	return nil
+}
+
+// ConnectJSON issues a Connect unary call of procedure with req and resp encoded as JSON.
+func (h *HttpClient) ConnectJSON(ctx context.Context, procedure string, req interface{}, resp interface{}) error {
+	message, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	body, err := h.ConnectUnary(ctx, procedure, jsonType, message)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(body, resp)
+}
+
+// GRPCWebUnary issues a gRPC-Web unary call of procedure with a protobuf message marshalled by the caller. It
+// returns the response message and the trailers, which gRPC-Web sends in the body. A non-OK grpc-status is
+// returned as GRPCError.
+func (h *HttpClient) GRPCWebUnary(ctx context.Context, procedure string, message []byte) ([]byte, http.Header, error) {
+	frame := make([]byte, 5, 5+len(message))
+	binary.BigEndian.PutUint32(frame[1:], uint32(len(message)))
+	resp, err := h.rpcRequest(ctx, procedure, append(frame, message...), http.Header{
+		"Content-Type": {grpcWebContentType},
+		"Accept":       {grpcWebContentType},
+		"X-Grpc-Web":   {"1"},
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, &GRPCError{Message: fmt.Sprintf("HTTP status %d.", resp.StatusCode), Code: grpcCodeOf(resp.StatusCode)}
+	}
+	if strings.HasSuffix(resp.Header.Get("Content-Type"), "-text") {
+		if body, err = base64.StdEncoding.DecodeString(string(body)); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var data []byte
+	trailers := http.Header{}
+	for len(body) > 0 {
+		if len(body) < 5 {
+			return nil, nil, &GRPCError{Message: "Truncated gRPC-Web frame.", Code: 13}
+		}
+		flag, length := body[0], binary.BigEndian.Uint32(body[1:5])
+		if uint64(len(body)-5) < uint64(length) {
+			return nil, nil, &GRPCError{Message: "Truncated gRPC-Web frame.", Code: 13}
+		}
+		payload := body[5 : 5+length]
+		body = body[5+length:]
+
+		if flag&grpcWebTrailerFlag != 0 {
+			parsed, err := textproto.NewReader(bufio.NewReader(bytes.NewReader(append(payload, "\r\n"...)))).ReadMIMEHeader()
+			if err != nil && len(parsed) == 0 {
+				return nil, nil, &GRPCError{Message: "Invalid gRPC-Web trailers.", Code: 13}
+			}
+			for key, values := range parsed {
+				trailers[key] = values
+			}
+			continue
+		}
+		data = append(data, payload...)
+	}
+
+	// a trailers-only response carries the status in the headers
+	status, statusMessage := trailers.Get("Grpc-Status"), trailers.Get("Grpc-Message")
+	if status == "" {
+		status, statusMessage = resp.Header.Get("Grpc-Status"), resp.Header.Get("Grpc-Message")
+	}
+	if code, _ := strconv.Atoi(status); status == "" || code != 0 {
+		if status == "" {
+			code, statusMessage = 2, "Response carries no grpc-status."
+		}
+		return nil, trailers, &GRPCError{Message: statusMessage, Code: code}
+	}
+	return data, trailers, nil
+}
+
+func (h *HttpClient) rpcRequest(ctx context.Context, procedure string, body []byte, header http.Header) (*http.Response, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	request, err := h.newRequest(ctx, http.MethodPost, procedure, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range header {
+		request.Header[key] = values
+	}
+	return h.ExecuteRequest(request.WithContext(ctx))
+}
+
+// connectCodeOf maps an HTTP status to the Connect code of errors without JSON body.
+func connectCodeOf(status int) string {
+	switch status {
+	case http.StatusBadRequest:
+		return "internal"
+	case http.StatusUnauthorized:
+		return "unauthenticated"
+	case http.StatusForbidden:
+		return "permission_denied"
+	case http.StatusNotFound:
+		return "unimplemented"
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return "unavailable"
+	}
+	return "unknown"
+}
+
+// grpcCodeOf maps an HTTP status to a gRPC code as the gRPC HTTP mapping does.
+func grpcCodeOf(status int) int {
+	switch status {
+	case http.StatusBadRequest:
+		return 13
+	case http.StatusUnauthorized:
+		return 16
+	case http.StatusForbidden:
+		return 7
+	case http.StatusNotFound:
+		return 12
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return 14
+	}
+	return 2
+}
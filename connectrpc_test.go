@@ -0,0 +1,77 @@
+package http
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+)
+
+func TestHttpClient_ConnectJSON(t *testing.T) {
+	server := mockServerWith(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Connect-Protocol-Version") != "1" || r.Header.Get("Content-Type") != jsonType {
+			w.WriteHeader(http.StatusUnsupportedMediaType)
+			return
+		}
+		if r.URL.Path == "/users.v1.UserService/Delete" {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"code": "permission_denied", "message": "Not allowed."}`))
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", jsonType)
+		w.Write(body)
+	})
+	defer server.Close()
+
+	client := createTestHTTPClient(server.URL)
+	var user callUser
+	if err := client.ConnectJSON(context.Background(), "/users.v1.UserService/Get", callUser{ID: 3}, &user); err != nil || user.ID != 3 {
+		t.Errorf("Expected the echoed user but got %+v, %v", user, err)
+	}
+
+	err := client.ConnectJSON(context.Background(), "/users.v1.UserService/Delete", callUser{ID: 3}, &user)
+	var connectErr *ConnectError
+	if !errors.As(err, &connectErr) || connectErr.Code != "permission_denied" || connectErr.Status != http.StatusForbidden {
+		t.Errorf("Expected a ConnectError but got %v", err)
+	}
+}
+
+func grpcWebFrame(flag byte, payload string) []byte {
+	frame := make([]byte, 5)
+	frame[0] = flag
+	binary.BigEndian.PutUint32(frame[1:], uint32(len(payload)))
+	return append(frame, payload...)
+}
+
+func TestHttpClient_GRPCWebUnary(t *testing.T) {
+	server := mockServerWith(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", grpcWebContentType)
+		if r.URL.Path == "/svc/Missing" {
+			w.Header().Set("Grpc-Status", "5")
+			w.Header().Set("Grpc-Message", "not found")
+			return
+		}
+		w.Write(grpcWebFrame(0, "reply:"+string(body[5:])))
+		w.Write(grpcWebFrame(grpcWebTrailerFlag, "grpc-status: 0\r\ngrpc-message: \r\nx-trace: abc\r\n"))
+	})
+	defer server.Close()
+
+	client := createTestHTTPClient(server.URL)
+	message, trailers, err := client.GRPCWebUnary(context.Background(), "/svc/Echo", []byte("ping"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(message) != "reply:ping" || trailers.Get("X-Trace") != "abc" {
+		t.Errorf("Unexpected message %q and trailers %v", message, trailers)
+	}
+
+	_, _, err = client.GRPCWebUnary(context.Background(), "/svc/Missing", nil)
+	var grpcErr *GRPCError
+	if !errors.As(err, &grpcErr) || grpcErr.Code != 5 || grpcErr.Message != "not found" {
+		t.Errorf("Expected the trailers-only status as GRPCError but got %v", err)
+	}
+}
@@ -0,0 +1,65 @@
+package http
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// trackConnections makes the client's own transport emit EventConnectionOpened and EventConnectionClosed.
+// Transports of http.Clients passed in by the caller are left untouched.
+func (h *HttpClient) trackConnections() {
+	if transport, ok := h.client.Transport.(*http.Transport); ok && transport.DialContext != nil {
+		transport.DialContext = h.trackedDial(transport.DialContext)
+	}
+}
+
+// trackedDial wraps dial to emit events when connections are opened and closed.
+func (h *HttpClient) trackedDial(dial dialFunc) dialFunc {
+	return func(ctx context.Context, network string, address string) (net.Conn, error) {
+		start := time.Now()
+		conn, err := dial(ctx, network, address)
+		if err != nil {
+			return nil, err
+		}
+		h.emit(Event{Type: EventConnectionOpened, Host: address, Duration: time.Since(start)})
+		return &trackedConn{Conn: conn, client: h, address: address, opened: time.Now()}, nil
+	}
+}
+
+// trackedConn emits EventConnectionClosed when it is closed the first time.
+type trackedConn struct {
+	net.Conn
+	client  *HttpClient
+	address string
+	opened  time.Time
+	once    sync.Once
+}
+
+func (c *trackedConn) Close() error {
+	err := c.Conn.Close()
+	c.once.Do(func() {
+		c.client.emit(Event{Type: EventConnectionClosed, Host: c.address, Duration: time.Since(c.opened)})
+	})
+	return err
+}
+
+// connectionTrace emits EventConnectionReused and EventTLSResumed for the attempt r.
+func (h *HttpClient) connectionTrace(r *http.Request) *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			if info.Reused {
+				h.emit(Event{Type: EventConnectionReused, Request: r, ConnReused: true, Duration: info.IdleTime})
+			}
+		},
+		TLSHandshakeDone: func(state tls.ConnectionState, err error) {
+			if err == nil && state.DidResume {
+				h.emit(Event{Type: EventTLSResumed, Request: r})
+			}
+		},
+	}
+}
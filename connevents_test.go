@@ -0,0 +1,52 @@
+package http
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestConnectionEvents(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	var mu sync.Mutex
+	counts := map[EventType]int{}
+	events := EventsFunc(func(e Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		counts[e.Type]++
+	})
+
+	trusted := server.Client().Transport.(*http.Transport).TLSClientConfig.Clone()
+	trusted.ClientSessionCache = tls.NewLRUClientSessionCache(8)
+	client := NewHttpClientWithConfig(NewDefaultHttpConfig(server.URL, WithTLSConfig(trusted), WithEvents(events)))
+
+	get := func() {
+		resp, err := client.Get(context.Background(), "/")
+		if err != nil {
+			t.Fatal(err)
+		}
+		drainAndClose(resp.Body)
+	}
+	get()
+	get()
+	client.client.CloseIdleConnections()
+	get()
+	client.client.CloseIdleConnections()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if counts[EventConnectionOpened] != 2 || counts[EventConnectionClosed] != 2 {
+		t.Errorf("Expected 2 connections to be opened and closed but got %v", counts)
+	}
+	if counts[EventConnectionReused] != 1 {
+		t.Errorf("Expected 1 reused connection but got %v", counts)
+	}
+	if counts[EventTLSResumed] != 1 {
+		t.Errorf("Expected the second connection to resume the TLS session but got %v", counts)
+	}
+}
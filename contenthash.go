@@ -0,0 +1,100 @@
+package http
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Response headers set by a ChangeDetector.
+const (
+	ContentHashHeader      = "X-Content-Sha256"
+	ContentUnchangedHeader = "X-Content-Unchanged"
+)
+
+// ChangeDetector is a Middleware hashing the bodies of successful GET responses, so callers polling an endpoint
+// can skip processing content that did not change, even if the server sends no ETag. The hash of the previous
+// response of each URL is kept; the detector should not be shared by clients whose credentials see different
+// content.
+type ChangeDetector struct {
+	mu     sync.Mutex
+	hashes map[string]string
+}
+
+// NewChangeDetector creates a ChangeDetector.
+func NewChangeDetector() *ChangeDetector {
+	return &ChangeDetector{hashes: make(map[string]string)}
+}
+
+// Forget removes the hash of rawURL, so its next response counts as changed.
+func (d *ChangeDetector) Forget(rawURL string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.hashes, rawURL)
+}
+
+// ForgetPrefix removes the hashes of all URLs starting with prefix.
+func (d *ChangeDetector) ForgetPrefix(prefix string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for rawURL := range d.hashes {
+		if strings.HasPrefix(rawURL, prefix) {
+			delete(d.hashes, rawURL)
+		}
+	}
+}
+
+// Middleware returns the Middleware hashing response bodies. It buffers the bodies it hashes.
+func (d *ChangeDetector) Middleware() Middleware {
+	return func(next RoundTripperFunc) RoundTripperFunc {
+		return func(r *http.Request) (*http.Response, error) {
+			resp, err := next(r)
+			if err != nil || r.Method != http.MethodGet || ClassifyStatus(resp.StatusCode) != StatusClassSuccess {
+				return resp, err
+			}
+
+			body, err := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			if err != nil {
+				return nil, err
+			}
+			resp.Body = io.NopCloser(bytes.NewReader(body))
+
+			sum := sha256.Sum256(body)
+			hash := hex.EncodeToString(sum[:])
+			rawURL := r.URL.String()
+
+			d.mu.Lock()
+			unchanged := d.hashes[rawURL] == hash
+			d.hashes[rawURL] = hash
+			d.mu.Unlock()
+
+			if resp.Header == nil {
+				resp.Header = http.Header{}
+			}
+			resp.Header.Set(ContentHashHeader, hash)
+			if unchanged {
+				resp.Header.Set(ContentUnchangedHeader, "true")
+			}
+			return resp, nil
+		}
+	}
+}
+
+// ContentUnchanged reports whether a ChangeDetector found the body of resp equal to the previous response of its
+// URL.
+func ContentUnchanged(resp *http.Response) bool {
+	return resp != nil && resp.Header.Get(ContentUnchangedHeader) == "true"
+}
+
+// ContentHash returns the hex encoded SHA-256 of the body of resp computed by a ChangeDetector, if any.
+func ContentHash(resp *http.Response) string {
+	if resp == nil {
+		return ""
+	}
+	return resp.Header.Get(ContentHashHeader)
+}
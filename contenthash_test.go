@@ -0,0 +1,50 @@
+package http
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+)
+
+func TestChangeDetector(t *testing.T) {
+	content := "v1"
+	server := mockServerWith(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(content))
+	})
+	defer server.Close()
+
+	detector := NewChangeDetector()
+	client := createTestHTTPClient(server.URL)
+	client.Use(detector.Middleware())
+
+	poll := func() (bool, string) {
+		resp, err := client.Get(context.Background(), "/feed")
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if string(body) != content {
+			t.Errorf("Expected the body to stay readable but got %q", body)
+		}
+		return ContentUnchanged(resp), ContentHash(resp)
+	}
+
+	if unchanged, hash := poll(); unchanged || len(hash) != 64 {
+		t.Errorf("Expected the first response to be new with a hash but got %t, %q", unchanged, hash)
+	}
+	if unchanged, _ := poll(); !unchanged {
+		t.Error("Expected the repeated content to be unchanged")
+	}
+
+	content = "v2"
+	if unchanged, _ := poll(); unchanged {
+		t.Error("Expected new content to be changed")
+	}
+
+	detector.Forget(server.URL + "/feed")
+	if unchanged, _ := poll(); unchanged {
+		t.Error("Expected a forgotten URL to count as changed")
+	}
+}
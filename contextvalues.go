@@ -0,0 +1,104 @@
+package http
+
+import (
+	"context"
+	"net/http"
+)
+
+// TraceIDHeader is the header the trace ID of a request's context is sent in.
+const TraceIDHeader = "X-Request-ID"
+
+// Priority ranks requests for middleware deciding which requests to delay or shed under load.
+type Priority int
+
+// Request priorities, PriorityNormal is used if none is set.
+const (
+	PriorityLow Priority = iota - 1
+	PriorityNormal
+	PriorityHigh
+)
+
+func (p Priority) String() string {
+	switch p {
+	case PriorityLow:
+		return "low"
+	case PriorityNormal:
+		return "normal"
+	case PriorityHigh:
+		return "high"
+	}
+	return "unknown"
+}
+
+type traceIDKey struct{}
+
+type priorityKey struct{}
+
+// WithTraceID returns a context carrying the trace ID sent in the TraceIDHeader of requests executed with it.
+func WithTraceID(ctx context.Context, traceID string) context.Context {
+	return context.WithValue(ctx, traceIDKey{}, traceID)
+}
+
+// TraceIDFromContext returns the trace ID stored in ctx, if any.
+func TraceIDFromContext(ctx context.Context) (string, bool) {
+	if ctx == nil {
+		return "", false
+	}
+	traceID, ok := ctx.Value(traceIDKey{}).(string)
+	return traceID, ok
+}
+
+// WithPriority returns a context carrying the priority of requests executed with it.
+func WithPriority(ctx context.Context, priority Priority) context.Context {
+	return context.WithValue(ctx, priorityKey{}, priority)
+}
+
+// PriorityFromContext returns the priority stored in ctx, PriorityNormal if there is none.
+func PriorityFromContext(ctx context.Context) Priority {
+	if ctx == nil {
+		return PriorityNormal
+	}
+	if priority, ok := ctx.Value(priorityKey{}).(Priority); ok {
+		return priority
+	}
+	return PriorityNormal
+}
+
+// EnvironmentFromContext returns the environment name selected by ctx, if any.
+func EnvironmentFromContext(ctx context.Context) (string, bool) {
+	if ctx == nil {
+		return "", false
+	}
+	name, ok := ctx.Value(environmentKey{}).(string)
+	return name, ok
+}
+
+// WithAuthOverride returns a context authenticating requests with provider instead of the client's credentials.
+// A nil provider sends requests without credentials, see WithoutAuth.
+func WithAuthOverride(ctx context.Context, provider AuthProvider) context.Context {
+	if provider == nil {
+		return WithRequestOptions(ctx, WithoutAuth())
+	}
+	return WithRequestOptions(ctx, WithAuth(provider))
+}
+
+// AuthOverrideFromContext returns the AuthProvider overriding the client's credentials for ctx, if any.
+func AuthOverrideFromContext(ctx context.Context) (AuthProvider, bool) {
+	options := requestOptionsFrom(ctx)
+	if options == nil || !options.authOverride {
+		return nil, false
+	}
+	return options.auth, true
+}
+
+// applyContextValues adds the headers derived from the values of r's context, keeping headers set explicitly.
+func applyContextValues(r *http.Request) *http.Request {
+	traceID, ok := TraceIDFromContext(r.Context())
+	if !ok || traceID == "" || r.Header.Get(TraceIDHeader) != "" {
+		return r
+	}
+
+	r = r.Clone(r.Context())
+	r.Header.Set(TraceIDHeader, traceID)
+	return r
+}
@@ -0,0 +1,63 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestContextValues(t *testing.T) {
+	ctx := context.Background()
+	if _, ok := TraceIDFromContext(ctx); ok {
+		t.Error("Expected no trace ID")
+	}
+	if PriorityFromContext(ctx) != PriorityNormal {
+		t.Error("Expected normal priority by default")
+	}
+
+	ctx = WithTraceID(ctx, "trace-1")
+	ctx = WithPriority(ctx, PriorityHigh)
+	ctx = WithEnvironment(ctx, EnvironmentStaging)
+	ctx = WithAuthOverride(ctx, nil)
+
+	if traceID, _ := TraceIDFromContext(ctx); traceID != "trace-1" {
+		t.Errorf("Expected trace-1 but got %s", traceID)
+	}
+	if priority := PriorityFromContext(ctx); priority != PriorityHigh {
+		t.Errorf("Expected high priority but got %s", priority)
+	}
+	if name, _ := EnvironmentFromContext(ctx); name != EnvironmentStaging {
+		t.Errorf("Expected %s but got %s", EnvironmentStaging, name)
+	}
+	if provider, ok := AuthOverrideFromContext(ctx); !ok || provider != nil {
+		t.Error("Expected auth to be overridden without provider")
+	}
+}
+
+func TestHttpClient_SendsTraceID(t *testing.T) {
+	var received []string
+	server := mockServerWith(func(w http.ResponseWriter, r *http.Request) {
+		received = append(received, r.Header.Get(TraceIDHeader))
+	})
+	defer server.Close()
+
+	client := createTestHTTPClient(server.URL)
+
+	resp, err := client.GetFromWithContext(WithTraceID(context.Background(), "trace-1"), "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	request, _ := client.GetRequest("/")
+	request.Header.Set(TraceIDHeader, "explicit")
+	resp, err = client.ExecuteRequest(request.WithContext(WithTraceID(context.Background(), "trace-2")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if len(received) != 2 || received[0] != "trace-1" || received[1] != "explicit" {
+		t.Errorf("Unexpected trace IDs %v", received)
+	}
+}
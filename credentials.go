@@ -0,0 +1,159 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// Credentials are the secrets used to authenticate requests.
+// Expiry is optional and tells caching sources when to fetch new credentials.
+type Credentials struct {
+	Username string    `json:"username,omitempty"`
+	Password string    `json:"password,omitempty"`
+	Token    string    `json:"token,omitempty"`
+	Expiry   time.Time `json:"expiry,omitempty"`
+}
+
+// CredentialSource provides credentials lazily at request time.
+type CredentialSource interface {
+	Credentials(ctx context.Context) (Credentials, error)
+}
+
+// CredentialSourceFunc adapts a function, e.g. a Vault or KMS lookup, to the CredentialSource interface.
+type CredentialSourceFunc func(ctx context.Context) (Credentials, error)
+
+func (f CredentialSourceFunc) Credentials(ctx context.Context) (Credentials, error) {
+	return f(ctx)
+}
+
+// CredentialError is returned when a CredentialSource fails to provide credentials.
+type CredentialError struct {
+	Message string
+	err     error
+}
+
+func (e CredentialError) Error() string {
+	return e.Message
+}
+
+func (e CredentialError) Unwrap() error {
+	return e.err
+}
+
+// StaticCredentials returns a CredentialSource which always provides the given username and password.
+func StaticCredentials(username string, password string) CredentialSource {
+	return CredentialSourceFunc(func(ctx context.Context) (Credentials, error) {
+		return Credentials{Username: username, Password: password}, nil
+	})
+}
+
+// EnvCredentials returns a CredentialSource reading username and password from the given environment variables.
+func EnvCredentials(usernameVar string, passwordVar string) CredentialSource {
+	return CredentialSourceFunc(func(ctx context.Context) (Credentials, error) {
+		username, ok := os.LookupEnv(usernameVar)
+		if !ok {
+			return Credentials{}, &CredentialError{Message: fmt.Sprintf("Environment variable %s not set.", usernameVar)}
+		}
+		password, ok := os.LookupEnv(passwordVar)
+		if !ok {
+			return Credentials{}, &CredentialError{Message: fmt.Sprintf("Environment variable %s not set.", passwordVar)}
+		}
+		return Credentials{Username: username, Password: password}, nil
+	})
+}
+
+// FileCredentials returns a CredentialSource reading JSON encoded Credentials from path on every call.
+func FileCredentials(path string) CredentialSource {
+	return CredentialSourceFunc(func(ctx context.Context) (Credentials, error) {
+		credentials := Credentials{}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return credentials, &CredentialError{Message: fmt.Sprintf("Credentials file %s not readable.", path), err: err}
+		}
+		if err := json.Unmarshal(data, &credentials); err != nil {
+			return credentials, &CredentialError{Message: fmt.Sprintf("Credentials file %s is invalid.", path), err: err}
+		}
+		return credentials, nil
+	})
+}
+
+// CachingCredentialSource caches the credentials of another source for a TTL or until they expire.
+type CachingCredentialSource struct {
+	source    CredentialSource
+	ttl       time.Duration
+	onRefresh func(credentials Credentials, err error)
+
+	mu          sync.Mutex
+	credentials Credentials
+	fetchedAt   time.Time
+	valid       bool
+}
+
+// CachedCredentials wraps source with a cache holding credentials for ttl.
+// A ttl of zero caches until the credentials' Expiry, or forever if none is set.
+func CachedCredentials(source CredentialSource, ttl time.Duration) *CachingCredentialSource {
+	if source == nil {
+		panic("source is nil")
+	}
+	return &CachingCredentialSource{source: source, ttl: ttl}
+}
+
+// OnRefresh registers a hook called after every fetch from the underlying source.
+func (c *CachingCredentialSource) OnRefresh(f func(credentials Credentials, err error)) *CachingCredentialSource {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.onRefresh = f
+	return c
+}
+
+// Credentials returns the cached credentials, fetching new ones if they are missing or stale.
+func (c *CachingCredentialSource) Credentials(ctx context.Context) (Credentials, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.valid && !c.stale(time.Now()) {
+		return c.credentials, nil
+	}
+	return c.fetch(ctx)
+}
+
+// Refresh fetches new credentials from the underlying source regardless of the cache state.
+func (c *CachingCredentialSource) Refresh(ctx context.Context) (Credentials, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.fetch(ctx)
+}
+
+// Invalidate drops the cached credentials, e.g. after the server rejected them.
+func (c *CachingCredentialSource) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.valid = false
+}
+
+func (c *CachingCredentialSource) fetch(ctx context.Context) (Credentials, error) {
+	credentials, err := c.source.Credentials(ctx)
+	if c.onRefresh != nil {
+		c.onRefresh(credentials, err)
+	}
+	if err != nil {
+		return credentials, err
+	}
+
+	c.credentials = credentials
+	c.fetchedAt = time.Now()
+	c.valid = true
+	return credentials, nil
+}
+
+func (c *CachingCredentialSource) stale(now time.Time) bool {
+	if c.ttl > 0 && now.Sub(c.fetchedAt) >= c.ttl {
+		return true
+	}
+	return !c.credentials.Expiry.IsZero() && !now.Before(c.credentials.Expiry)
+}
@@ -0,0 +1,80 @@
+package http
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestEnvCredentials(t *testing.T) {
+	t.Setenv("TEST_HTTP_USERNAME", "foo")
+	t.Setenv("TEST_HTTP_PASSWORD", "bar")
+
+	credentials, err := EnvCredentials("TEST_HTTP_USERNAME", "TEST_HTTP_PASSWORD").Credentials(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if credentials.Username != "foo" || credentials.Password != "bar" {
+		t.Errorf("Expected foo:bar but got %s:%s", credentials.Username, credentials.Password)
+	}
+
+	if _, err := EnvCredentials("TEST_HTTP_UNSET", "TEST_HTTP_PASSWORD").Credentials(context.Background()); err == nil {
+		t.Error("Expected error for unset variable")
+	}
+}
+
+func TestFileCredentials(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "credentials.json")
+	os.WriteFile(path, []byte(`{"username": "foo", "password": "bar"}`), 0600)
+
+	credentials, err := FileCredentials(path).Credentials(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if credentials.Username != "foo" || credentials.Password != "bar" {
+		t.Errorf("Expected foo:bar but got %s:%s", credentials.Username, credentials.Password)
+	}
+}
+
+func TestCachedCredentials(t *testing.T) {
+	fetches := 0
+	source := CredentialSourceFunc(func(ctx context.Context) (Credentials, error) {
+		fetches++
+		return Credentials{Token: "token"}, nil
+	})
+
+	refreshes := 0
+	cached := CachedCredentials(source, time.Hour).OnRefresh(func(credentials Credentials, err error) {
+		refreshes++
+	})
+
+	cached.Credentials(context.Background())
+	cached.Credentials(context.Background())
+	if fetches != 1 {
+		t.Errorf("Expected 1 fetch but got %d", fetches)
+	}
+
+	cached.Invalidate()
+	cached.Credentials(context.Background())
+	if fetches != 2 || refreshes != 2 {
+		t.Errorf("Expected 2 fetches and refreshes but got %d and %d", fetches, refreshes)
+	}
+}
+
+func TestCachedCredentialsExpiry(t *testing.T) {
+	fetches := 0
+	source := CredentialSourceFunc(func(ctx context.Context) (Credentials, error) {
+		fetches++
+		return Credentials{Token: "token", Expiry: time.Now().Add(-time.Second)}, nil
+	})
+
+	cached := CachedCredentials(source, 0)
+	cached.Credentials(context.Background())
+	cached.Credentials(context.Background())
+
+	if fetches != 2 {
+		t.Errorf("Expected expired credentials to be fetched again, got %d fetches", fetches)
+	}
+}
@@ -0,0 +1,237 @@
+package http
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// CSVError is returned when a value can not be encoded as or decoded from CSV.
+type CSVError struct {
+	Message string
+	Line    int
+	Column  string
+}
+
+func (e CSVError) Error() string {
+	return e.Message
+}
+
+// EncodeCSV encodes a slice of structs as CSV with a header row, e.g. as body of a PostTo to a data import API.
+// Columns are named by the csv tag of the exported fields or their name; fields tagged "-" are skipped. Values
+// are formatted with their encoding.TextMarshaler or as string, number or bool.
+func EncodeCSV(records interface{}) (io.Reader, error) {
+	value := reflect.ValueOf(records)
+	if value.Kind() != reflect.Slice {
+		return nil, &CSVError{Message: fmt.Sprintf("Can not encode %T as CSV, expected a slice of structs.", records)}
+	}
+	fields, err := csvFields(value.Type().Elem())
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	header := make([]string, len(fields))
+	for i, field := range fields {
+		header[i] = field.name
+	}
+	writer.Write(header)
+
+	for i := 0; i < value.Len(); i++ {
+		record := reflect.Indirect(value.Index(i))
+		row := make([]string, len(fields))
+		for j, field := range fields {
+			if row[j], err = formatCSVValue(record.FieldByIndex(field.index)); err != nil {
+				return nil, &CSVError{Message: err.Error(), Line: i + 2, Column: field.name}
+			}
+		}
+		writer.Write(row)
+	}
+	writer.Flush()
+	return &buf, writer.Error()
+}
+
+// DecodeCSV decodes CSV with a header row into out, a pointer to a slice of structs. Columns are matched to
+// fields like EncodeCSV names them; columns without field are ignored.
+func DecodeCSV(r io.Reader, out interface{}) error {
+	target := reflect.ValueOf(out)
+	if target.Kind() != reflect.Ptr || target.Elem().Kind() != reflect.Slice {
+		return &CSVError{Message: fmt.Sprintf("Can not decode CSV into %T, expected a pointer to a slice of structs.", out)}
+	}
+	slice := target.Elem()
+	elem := slice.Type().Elem()
+	pointers := elem.Kind() == reflect.Ptr
+	if pointers {
+		elem = elem.Elem()
+	}
+	fields, err := csvFields(elem)
+	if err != nil {
+		return err
+	}
+
+	reader := csv.NewReader(r)
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	columns := make([]*csvField, len(header))
+	for i, name := range header {
+		for j := range fields {
+			if fields[j].name == strings.TrimSpace(name) {
+				columns[i] = &fields[j]
+			}
+		}
+	}
+
+	for line := 2; ; line++ {
+		row, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		record := reflect.New(elem)
+		for i, text := range row {
+			if i >= len(columns) || columns[i] == nil {
+				continue
+			}
+			if err := parseCSVValue(record.Elem().FieldByIndex(columns[i].index), text); err != nil {
+				return &CSVError{Message: err.Error(), Line: line, Column: columns[i].name}
+			}
+		}
+		if pointers {
+			slice.Set(reflect.Append(slice, record))
+		} else {
+			slice.Set(reflect.Append(slice, record.Elem()))
+		}
+	}
+}
+
+// DecodeCSVResponse reads and closes the body of resp, runs the response transformers and decodes the result as
+// CSV into out, see DecodeCSV.
+func (h *HttpClient) DecodeCSVResponse(resp *http.Response, out interface{}) error {
+	body, err := h.transformedBody(resp)
+	if err != nil {
+		return err
+	}
+	return DecodeCSV(bytes.NewReader(body), out)
+}
+
+type csvField struct {
+	name  string
+	index []int
+}
+
+func csvFields(t reflect.Type) ([]csvField, error) {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, &CSVError{Message: fmt.Sprintf("Can not map %s to CSV columns, expected a struct.", t)}
+	}
+
+	var fields []csvField
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		name := field.Tag.Get("csv")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields = append(fields, csvField{name: name, index: field.Index})
+	}
+	return fields, nil
+}
+
+var textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+
+func formatCSVValue(v reflect.Value) (string, error) {
+	if v.Type().Implements(textMarshalerType) {
+		if v.Kind() == reflect.Ptr && v.IsNil() {
+			return "", nil
+		}
+		text, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+		return string(text), err
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return "", nil
+		}
+		return formatCSVValue(v.Elem())
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits()), nil
+	}
+	return "", fmt.Errorf("Can not encode %s as CSV.", v.Type())
+}
+
+func parseCSVValue(v reflect.Value, text string) error {
+	if unmarshaler, ok := v.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return unmarshaler.UnmarshalText([]byte(text))
+	}
+
+	var err error
+	switch v.Kind() {
+	case reflect.Ptr:
+		if text == "" {
+			return nil
+		}
+		v.Set(reflect.New(v.Type().Elem()))
+		return parseCSVValue(v.Elem(), text)
+	case reflect.String:
+		v.SetString(text)
+		return nil
+	}
+
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.Bool:
+		var b bool
+		b, err = strconv.ParseBool(text)
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var i int64
+		i, err = strconv.ParseInt(text, 10, v.Type().Bits())
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var u uint64
+		u, err = strconv.ParseUint(text, 10, v.Type().Bits())
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		var f float64
+		f, err = strconv.ParseFloat(text, v.Type().Bits())
+		v.SetFloat(f)
+	default:
+		return fmt.Errorf("Can not decode CSV into %s.", v.Type())
+	}
+	return err
+}
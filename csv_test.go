@@ -0,0 +1,86 @@
+package http
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+type csvRecord struct {
+	ID      int       `csv:"id"`
+	Name    string    `csv:"name"`
+	Score   float64   `csv:"score"`
+	Active  bool      `csv:"active"`
+	Created time.Time `csv:"created"`
+	Note    *string   `csv:"note"`
+	secret  string
+	Ignored string `csv:"-"`
+}
+
+func TestCSV_RoundTrip(t *testing.T) {
+	created := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	note := "needs, quoting"
+	records := []csvRecord{
+		{ID: 1, Name: "jane", Score: 1.5, Active: true, Created: created, Note: &note, Ignored: "x"},
+		{ID: 2, Name: "john", Created: created},
+	}
+
+	encoded, err := EncodeCSV(records)
+	if err != nil {
+		t.Fatal(err)
+	}
+	text, _ := io.ReadAll(encoded)
+	expected := "id,name,score,active,created,note\n" +
+		"1,jane,1.5,true,2020-01-02T03:04:05Z,\"needs, quoting\"\n" +
+		"2,john,0,false,2020-01-02T03:04:05Z,\n"
+	if string(text) != expected {
+		t.Errorf("Expected %q but got %q", expected, text)
+	}
+
+	var decoded []*csvRecord
+	if err := DecodeCSV(strings.NewReader(string(text)), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded) != 2 || *decoded[0].Note != note || decoded[1].Note != nil || !decoded[0].Created.Equal(created) {
+		t.Errorf("Unexpected records %+v", decoded)
+	}
+}
+
+func TestDecodeCSV_Errors(t *testing.T) {
+	var records []csvRecord
+	err := DecodeCSV(strings.NewReader("name,id,extra\njane,1,x\njohn,two,y\n"), &records)
+
+	var csvErr *CSVError
+	if !errors.As(err, &csvErr) || csvErr.Line != 3 || csvErr.Column != "id" {
+		t.Errorf("Expected an error in line 3, column id but got %v", err)
+	}
+	if len(records) != 1 || records[0].Name != "jane" {
+		t.Errorf("Expected columns to be matched by header but got %+v", records)
+	}
+
+	if err := DecodeCSV(strings.NewReader(""), records); err == nil {
+		t.Error("Expected an error for a non-pointer target")
+	}
+}
+
+func TestHttpClient_DecodeCSVResponse(t *testing.T) {
+	server := mockServer(http.StatusOK, "text/csv", "id,name\n1,jane\n")
+	defer server.Close()
+
+	client := createTestHTTPClient(server.URL)
+	resp, err := client.GetFrom("/export")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var records []csvRecord
+	if err := client.DecodeCSVResponse(resp, &records); err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 || records[0].ID != 1 {
+		t.Errorf("Unexpected records %+v", records)
+	}
+}
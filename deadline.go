@@ -0,0 +1,71 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// latencySmoothing weighs a new sample in the typical latency.
+const latencySmoothing = 0.2
+
+// AttemptDeadlineError is returned instead of starting an attempt which can not finish before the deadline of
+// its context, given the backoff before it and the typical latency of previous attempts.
+type AttemptDeadlineError struct {
+	Message   string
+	Remaining time.Duration
+	Needed    time.Duration
+}
+
+func (e AttemptDeadlineError) Error() string {
+	return e.Message
+}
+
+// Unwrap makes errors.Is(err, context.DeadlineExceeded) hold, the attempt would have exceeded it.
+func (e AttemptDeadlineError) Unwrap() error {
+	return context.DeadlineExceeded
+}
+
+// checkAttemptDeadline returns an AttemptDeadlineError if an attempt started after backoff and taking the typical
+// latency would end after the deadline of ctx.
+func checkAttemptDeadline(ctx context.Context, backoff time.Duration, typical time.Duration) error {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return nil
+	}
+
+	remaining := time.Until(deadline)
+	needed := backoff + typical
+	if needed < remaining {
+		return nil
+	}
+	return &AttemptDeadlineError{
+		Message:   fmt.Sprintf("Attempt needs about %s but only %s remain until the deadline.", needed, remaining),
+		Remaining: remaining,
+		Needed:    needed,
+	}
+}
+
+// latencyEstimator tracks the typical latency of attempts as exponentially weighted moving average.
+type latencyEstimator struct {
+	mu      sync.Mutex
+	average time.Duration
+}
+
+func (e *latencyEstimator) observe(latency time.Duration) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.average == 0 {
+		e.average = latency
+		return
+	}
+	e.average = time.Duration((1-latencySmoothing)*float64(e.average) + latencySmoothing*float64(latency))
+}
+
+func (e *latencyEstimator) typical() time.Duration {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.average
+}
@@ -0,0 +1,62 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestCheckAttemptDeadline(t *testing.T) {
+	if err := checkAttemptDeadline(context.Background(), time.Hour, time.Hour); err != nil {
+		t.Errorf("Expected no error without deadline but got %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if err := checkAttemptDeadline(ctx, 100*time.Millisecond, 100*time.Millisecond); err != nil {
+		t.Errorf("Expected attempt to fit the deadline but got %v", err)
+	}
+
+	err := checkAttemptDeadline(ctx, 500*time.Millisecond, time.Second)
+	var deadlineErr *AttemptDeadlineError
+	if !errors.As(err, &deadlineErr) || deadlineErr.Needed != 1500*time.Millisecond {
+		t.Errorf("Expected AttemptDeadlineError but got %v", err)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Error("Expected AttemptDeadlineError to match context.DeadlineExceeded")
+	}
+}
+
+func TestLatencyEstimator(t *testing.T) {
+	estimator := latencyEstimator{}
+	estimator.observe(100 * time.Millisecond)
+	estimator.observe(200 * time.Millisecond)
+
+	if typical := estimator.typical(); typical != 120*time.Millisecond {
+		t.Errorf("Expected typical latency of 120ms but got %s", typical)
+	}
+}
+
+func TestDurableQueue_FlushRespectsDeadline(t *testing.T) {
+	server := mockServer(http.StatusServiceUnavailable, contentTypeJSON, "")
+	defer server.Close()
+
+	store := NewMemoryQueueStore()
+	queue := NewDurableQueue(createTestHTTPClient(server.URL), store).WithRedeliveryInterval(time.Millisecond)
+	queue.latency.observe(time.Hour)
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	queue.Submit(req)
+	time.Sleep(5 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	var deadlineErr *AttemptDeadlineError
+	if err := queue.Flush(ctx); !errors.As(err, &deadlineErr) {
+		t.Errorf("Expected AttemptDeadlineError but got %v", err)
+	}
+}
@@ -0,0 +1,141 @@
+package http
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// decompressionRatioGrace is the decompressed size below which the compression ratio is not checked, so small,
+// highly compressible bodies are not mistaken for bombs.
+const decompressionRatioGrace = 1 << 20
+
+// DecompressionBombError is returned when reading a compressed body exceeds the decompressed size or
+// compression ratio limit.
+type DecompressionBombError struct {
+	Message      string
+	Decompressed int64
+	Compressed   int64
+	MaxSize      int64
+	MaxRatio     float64
+}
+
+func (e DecompressionBombError) Error() string {
+	return e.Message
+}
+
+// decompressionLimits bound the decompressed size and compression ratio of bodies, zero disables a limit.
+type decompressionLimits struct {
+	maxSize  int64
+	maxRatio float64
+}
+
+func (l decompressionLimits) enabled() bool {
+	return l.maxSize > 0 || l.maxRatio > 0
+}
+
+// WithDecompressionLimits protects against decompression bombs from untrusted origins. The client decodes gzip
+// and deflate bodies itself and fails reading them with a DecompressionBombError once more than maxSize bytes
+// were decompressed or the compression ratio exceeds maxRatio. Zero disables a limit.
+func WithDecompressionLimits(maxSize int64, maxRatio float64) Option {
+	return func(c *HttpConfig) {
+		c.decompression = decompressionLimits{maxSize: maxSize, maxRatio: maxRatio}
+	}
+}
+
+// decompress wraps next to request compressed responses and decode them within limits.
+func decompress(next RoundTripperFunc, limits decompressionLimits) RoundTripperFunc {
+	return func(r *http.Request) (*http.Response, error) {
+		if r.Header.Get("Accept-Encoding") != "" || r.Header.Get("Range") != "" || r.Method == http.MethodHead {
+			return next(r)
+		}
+		r = r.Clone(r.Context())
+		r.Header.Set("Accept-Encoding", "gzip, deflate")
+
+		resp, err := next(r)
+		if err != nil || resp.Body == nil {
+			return resp, err
+		}
+
+		encoding := strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding")))
+		if encoding != "gzip" && encoding != "deflate" {
+			return resp, nil
+		}
+
+		compressed := &countingReader{reader: resp.Body}
+		var decoder io.ReadCloser
+		if encoding == "gzip" {
+			if decoder, err = gzip.NewReader(compressed); err != nil {
+				resp.Body.Close()
+				return nil, err
+			}
+		} else {
+			decoder = flate.NewReader(compressed)
+		}
+
+		resp.Body = &limitedDecompressor{decoder: decoder, body: resp.Body, compressed: compressed, limits: limits}
+		resp.Header.Del("Content-Encoding")
+		resp.Header.Del("Content-Length")
+		resp.ContentLength = -1
+		resp.Uncompressed = true
+		return resp, nil
+	}
+}
+
+type countingReader struct {
+	reader io.Reader
+	n      int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.reader.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// limitedDecompressor decodes a body, failing once the decompressed size or ratio exceed the limits.
+type limitedDecompressor struct {
+	decoder      io.ReadCloser
+	body         io.ReadCloser
+	compressed   *countingReader
+	limits       decompressionLimits
+	decompressed int64
+	err          error
+}
+
+func (d *limitedDecompressor) Read(p []byte) (int, error) {
+	if d.err != nil {
+		return 0, d.err
+	}
+
+	n, err := d.decoder.Read(p)
+	d.decompressed += int64(n)
+
+	exceeded := ""
+	switch {
+	case d.limits.maxSize > 0 && d.decompressed > d.limits.maxSize:
+		exceeded = fmt.Sprintf("Decompressed body exceeds %d bytes.", d.limits.maxSize)
+	case d.limits.maxRatio > 0 && d.decompressed > decompressionRatioGrace && d.compressed.n > 0 &&
+		float64(d.decompressed)/float64(d.compressed.n) > d.limits.maxRatio:
+		exceeded = fmt.Sprintf("Compression ratio of body exceeds %g.", d.limits.maxRatio)
+	}
+	if exceeded != "" {
+		d.err = &DecompressionBombError{
+			Message:      exceeded,
+			Decompressed: d.decompressed,
+			Compressed:   d.compressed.n,
+			MaxSize:      d.limits.maxSize,
+			MaxRatio:     d.limits.maxRatio,
+		}
+		return 0, d.err
+	}
+	return n, err
+}
+
+func (d *limitedDecompressor) Close() error {
+	d.decoder.Close()
+	return d.body.Close()
+}
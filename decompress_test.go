@@ -0,0 +1,64 @@
+package http
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func gzipped(content string) []byte {
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	writer.Write([]byte(content))
+	writer.Close()
+	return buf.Bytes()
+}
+
+func TestWithDecompressionLimits(t *testing.T) {
+	bomb := gzipped(strings.Repeat("0", 4<<20))
+	small := gzipped(`{"name": "jane"}`)
+	server := mockServerWith(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept-Encoding") != "gzip, deflate" {
+			t.Errorf("Expected compressed responses to be requested but got %q", r.Header.Get("Accept-Encoding"))
+		}
+		w.Header().Set("Content-Encoding", "gzip")
+		if r.URL.Path == "/bomb" {
+			w.Write(bomb)
+			return
+		}
+		w.Write(small)
+	})
+	defer server.Close()
+
+	client := NewHttpClientWithConfig(NewDefaultHttpConfig(server.URL, WithDecompressionLimits(0, 100)))
+	resp, err := client.Get(context.Background(), "/small")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var user callUser
+	if err := client.DecodeResponse(resp, &user); err != nil || user.Name != "jane" {
+		t.Errorf("Expected the small body to be decoded but got %+v, %v", user, err)
+	}
+
+	for _, config := range []*HttpConfig{
+		NewDefaultHttpConfig(server.URL, WithDecompressionLimits(0, 100)),
+		NewDefaultHttpConfig(server.URL, WithDecompressionLimits(1<<20, 0)),
+	} {
+		resp, err := NewHttpClientWithConfig(config).Get(context.Background(), "/bomb")
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		var bombErr *DecompressionBombError
+		if !errors.As(err, &bombErr) || bombErr.Decompressed > 2<<20 {
+			t.Errorf("Expected reading to stop with DecompressionBombError but got %v", err)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package http
+
+import (
+	"context"
+	"net"
+	"time"
+)
+
+// AddressFamily selects which IP families are used to connect to dual-stack hosts.
+type AddressFamily int
+
+const (
+	// AddressFamilyAny connects using the address order returned by the resolver.
+	AddressFamilyAny AddressFamily = iota
+	// AddressFamilyPreferIPv4 tries IPv4 first and falls back to IPv6 after the fallback delay.
+	AddressFamilyPreferIPv4
+	// AddressFamilyPreferIPv6 tries IPv6 first and falls back to IPv4 after the fallback delay.
+	AddressFamilyPreferIPv6
+	// AddressFamilyIPv4Only never connects over IPv6.
+	AddressFamilyIPv4Only
+	// AddressFamilyIPv6Only never connects over IPv4.
+	AddressFamilyIPv6Only
+)
+
+func (f AddressFamily) String() string {
+	switch f {
+	case AddressFamilyAny:
+		return "any"
+	case AddressFamilyPreferIPv4:
+		return "prefer-ipv4"
+	case AddressFamilyPreferIPv6:
+		return "prefer-ipv6"
+	case AddressFamilyIPv4Only:
+		return "ipv4-only"
+	case AddressFamilyIPv6Only:
+		return "ipv6-only"
+	}
+	return "unknown"
+}
+
+// WithFallbackDelay sets how long to wait for a connection over the preferred address family before racing a
+// connection over the other one ("Happy Eyeballs"). Zero uses the default of 300ms, a negative delay disables
+// the fallback race so the other family is only tried after the preferred one failed.
+func WithFallbackDelay(delay time.Duration) Option {
+	return func(c *HttpConfig) {
+		c.fallbackDelay = delay
+	}
+}
+
+// WithAddressFamily restricts or orders the IP address families used to connect.
+func WithAddressFamily(family AddressFamily) Option {
+	return func(c *HttpConfig) {
+		c.addressFamily = family
+	}
+}
+
+// defaultFallbackDelay is the delay net.Dialer uses before racing the other address family.
+const defaultFallbackDelay = 300 * time.Millisecond
+
+type dialFunc func(ctx context.Context, network string, address string) (net.Conn, error)
+
+// newDialContext returns the DialContext of the transport honoring the socket, dual-stack and pinning settings of config.
+func newDialContext(config *HttpConfig) dialFunc {
+	dialer := &net.Dialer{
+		Timeout:       defaultRequestTimeOut,
+		KeepAlive:     defaultRequestTimeOut,
+		FallbackDelay: config.fallbackDelay,
+	}
+
+	if config.socket != nil {
+		return dialSocket(dialer, config.socket)
+	}
+
+	var dial dialFunc = dialer.DialContext
+	switch config.addressFamily {
+	case AddressFamilyIPv4Only:
+		dial = restrictFamily(dialer.DialContext, "4")
+	case AddressFamilyIPv6Only:
+		dial = restrictFamily(dialer.DialContext, "6")
+	case AddressFamilyPreferIPv4:
+		dial = preferFamily(dialer, "4", "6")
+	case AddressFamilyPreferIPv6:
+		dial = preferFamily(dialer, "6", "4")
+	}
+	return pinAddresses(dial, config.resolve)
+}
+
+// restrictFamily dials tcp networks only over the given family, "4" or "6".
+func restrictFamily(dial dialFunc, family string) dialFunc {
+	return func(ctx context.Context, network string, address string) (net.Conn, error) {
+		if network == "tcp" {
+			network += family
+		}
+		return dial(ctx, network, address)
+	}
+}
+
+type dialResult struct {
+	conn    net.Conn
+	err     error
+	primary bool
+}
+
+// preferFamily dials over the primary family and races the fallback family once the fallback delay passed
+// or the primary attempt failed, returning the first connection established.
+func preferFamily(dialer *net.Dialer, primary string, fallback string) dialFunc {
+	return func(ctx context.Context, network string, address string) (net.Conn, error) {
+		if network != "tcp" {
+			return dialer.DialContext(ctx, network, address)
+		}
+
+		var fallbackTimer <-chan time.Time
+		if delay := dialer.FallbackDelay; delay >= 0 {
+			if delay == 0 {
+				delay = defaultFallbackDelay
+			}
+			timer := time.NewTimer(delay)
+			defer timer.Stop()
+			fallbackTimer = timer.C
+		}
+
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		results := make(chan dialResult, 2)
+		dial := func(family string, primary bool) {
+			conn, err := dialer.DialContext(ctx, network+family, address)
+			results <- dialResult{conn, err, primary}
+		}
+
+		go dial(primary, true)
+		pending, fallbackStarted := 1, false
+		startFallback := func() {
+			if !fallbackStarted {
+				fallbackStarted = true
+				pending++
+				go dial(fallback, false)
+			}
+		}
+
+		var primaryErr error
+		for pending > 0 {
+			select {
+			case <-fallbackTimer:
+				startFallback()
+			case result := <-results:
+				pending--
+				if result.err == nil {
+					go closeLateConnections(results, pending)
+					return result.conn, nil
+				}
+				if result.primary {
+					primaryErr = result.err
+				} else if primaryErr == nil {
+					primaryErr = result.err
+				}
+				startFallback()
+			}
+		}
+		return nil, primaryErr
+	}
+}
+
+// closeLateConnections closes the connections of dial attempts finishing after another attempt won the race.
+func closeLateConnections(results <-chan dialResult, pending int) {
+	for ; pending > 0; pending-- {
+		if result := <-results; result.conn != nil {
+			result.conn.Close()
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package http
+
+import (
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// localhostURL points url of a server listening on 127.0.0.1 to localhost, which may also resolve to ::1.
+func localhostURL(url string) string {
+	return strings.Replace(url, "127.0.0.1", "localhost", 1)
+}
+
+func TestAddressFamily_IPv4(t *testing.T) {
+	server := mockServer(http.StatusOK, contentTypeJSON, fixtureBasicJSON)
+	defer server.Close()
+
+	for _, family := range []AddressFamily{AddressFamilyIPv4Only, AddressFamilyPreferIPv4, AddressFamilyPreferIPv6} {
+		client := NewHttpClientWithConfig(NewDefaultHttpConfig(localhostURL(server.URL),
+			WithAddressFamily(family), WithFallbackDelay(50*time.Millisecond)))
+
+		resp, err := client.GetFrom("/")
+		if err != nil {
+			t.Errorf("Expected %s to connect over IPv4 but got %v", family, err)
+			continue
+		}
+		assertResponseBodyIs(resp, fixtureBasicJSON, t)
+	}
+}
+
+func TestAddressFamily_IPv6Only(t *testing.T) {
+	server := mockServer(http.StatusOK, contentTypeJSON, fixtureBasicJSON)
+	defer server.Close()
+
+	client := NewHttpClientWithConfig(NewDefaultHttpConfig(localhostURL(server.URL), WithAddressFamily(AddressFamilyIPv6Only)))
+
+	if _, err := client.GetFrom("/"); err == nil {
+		t.Error("Expected IPv6 only client not to reach an IPv4 server")
+	}
+}
+
+func TestAddressFamily_Validate(t *testing.T) {
+	if err := NewDefaultHttpConfig(fixtureBaseURL, WithAddressFamily(AddressFamily(42))).Validate(); err == nil {
+		t.Error("Expected error for unknown address family")
+	}
+}
+
+func TestCloseLateConnections(t *testing.T) {
+	client, server := net.Pipe()
+	defer server.Close()
+
+	results := make(chan dialResult, 1)
+	results <- dialResult{conn: client}
+	closeLateConnections(results, 1)
+
+	if _, err := client.Write([]byte("x")); err == nil {
+		t.Error("Expected late connection to be closed")
+	}
+}
@@ -0,0 +1,132 @@
+package http
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"sync"
+)
+
+type dnsPinKey struct{}
+
+// dnsPin holds the addresses the hosts of an operation were resolved to.
+type dnsPin struct {
+	mu        sync.Mutex
+	addresses map[string]string
+}
+
+// WithPinnedDNS returns a context resolving each host once for all requests executed with it, e.g. the attempts,
+// retries and follow-up calls of a logical operation. All of them connect to the address resolved first, so a
+// DNS change in the middle of the operation cannot move it to another backend, which breaks sticky sessions.
+// Hosts pinned with WithResolve, IP addresses and requests sent through a proxy are not affected.
+func WithPinnedDNS(ctx context.Context) context.Context {
+	return context.WithValue(ctx, dnsPinKey{}, &dnsPin{addresses: map[string]string{}})
+}
+
+// PinnedAddresses returns the addresses the hosts of the operation of ctx were resolved to, by host.
+func PinnedAddresses(ctx context.Context) map[string]string {
+	pin, ok := ctx.Value(dnsPinKey{}).(*dnsPin)
+	if !ok {
+		return nil
+	}
+	pin.mu.Lock()
+	defer pin.mu.Unlock()
+
+	addresses := make(map[string]string, len(pin.addresses))
+	for host, address := range pin.addresses {
+		addresses[host] = address
+	}
+	return addresses
+}
+
+// resolve returns the address host is pinned to, resolving it on first use. Addresses of the preferred family
+// come first.
+func (p *dnsPin) resolve(ctx context.Context, host string, family AddressFamily) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if address, ok := p.addresses[host]; ok {
+		return address, nil
+	}
+
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return "", err
+	}
+	preferIPv6 := family == AddressFamilyPreferIPv6 || family == AddressFamilyIPv6Only
+	address, fallback := "", ""
+	for _, addr := range addrs {
+		isIPv6 := addr.IP.To4() == nil
+		if family == AddressFamilyIPv4Only && isIPv6 || family == AddressFamilyIPv6Only && !isIPv6 {
+			continue
+		}
+		if isIPv6 == preferIPv6 {
+			address = addr.IP.String()
+			break
+		}
+		if fallback == "" {
+			fallback = addr.IP.String()
+		}
+	}
+	if address == "" {
+		address = fallback
+	}
+	if address == "" {
+		return "", &net.DNSError{Err: "no suitable address", Name: host, IsNotFound: true}
+	}
+
+	p.addresses[host] = address
+	return address, nil
+}
+
+// pinnedTransportKey identifies the transport dialing host at address, connections are only shared between
+// operations pinned to the same address.
+type pinnedTransportKey struct {
+	base    *http.Transport
+	host    string
+	address string
+}
+
+// pinDNS returns client connecting to the pinned address of r's host, or client itself if r's context pins
+// nothing.
+func (h *HttpClient) pinDNS(client *http.Client, r *http.Request) (*http.Client, error) {
+	pin, ok := r.Context().Value(dnsPinKey{}).(*dnsPin)
+	if !ok {
+		return client, nil
+	}
+	base, ok := client.Transport.(*http.Transport)
+	if !ok {
+		return nil, &ConfigError{Message: "Pinning DNS requires a *http.Transport.", Field: "transport"}
+	}
+
+	host := r.URL.Hostname()
+	h.mu.RLock()
+	_, resolved := h.config.resolve[host]
+	_, resolvedWithPort := h.config.resolve[r.URL.Host]
+	family := h.config.addressFamily
+	h.mu.RUnlock()
+	if resolved || resolvedWithPort || net.ParseIP(host) != nil {
+		return client, nil
+	}
+
+	address, err := pin.resolve(r.Context(), host, family)
+	if err != nil {
+		return nil, err
+	}
+
+	key := pinnedTransportKey{base: base, host: host, address: address}
+	transport, ok := h.pinnedTransports.Load(key)
+	if !ok {
+		updated := base.Clone()
+		dial := updated.DialContext
+		if dial == nil {
+			dial = (&net.Dialer{}).DialContext
+		}
+		updated.DialContext = pinAddresses(dial, map[string]string{host: address})
+		transport, _ = h.pinnedTransports.LoadOrStore(key, updated)
+	}
+
+	copied := *client
+	copied.Transport = transport.(*http.Transport)
+	return &copied, nil
+}
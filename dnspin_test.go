@@ -0,0 +1,61 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestWithPinnedDNS(t *testing.T) {
+	server := mockServer(http.StatusOK, contentTypeJSON, "{}")
+	defer server.Close()
+
+	// localhost resolves without a DNS server, the mock server only listens on IPv4
+	baseURL := strings.Replace(server.URL, "127.0.0.1", "localhost", 1)
+	client := NewHttpClientWithConfig(NewDefaultHttpConfig(baseURL, WithAddressFamily(AddressFamilyPreferIPv4)))
+
+	ctx := WithPinnedDNS(context.Background())
+	for i := 0; i < 3; i++ {
+		resp, err := client.Get(ctx, "/")
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+
+	pinned := PinnedAddresses(ctx)
+	if pinned["localhost"] != "127.0.0.1" || len(pinned) != 1 {
+		t.Errorf("Expected localhost to be pinned to 127.0.0.1 but got %v", pinned)
+	}
+
+	count := 0
+	client.pinnedTransports.Range(func(key, _ interface{}) bool {
+		count++
+		return true
+	})
+	if count != 1 {
+		t.Errorf("Expected a single pinned transport but got %d", count)
+	}
+
+	if PinnedAddresses(context.Background()) != nil {
+		t.Error("Expected no pinned addresses without WithPinnedDNS")
+	}
+}
+
+func TestWithPinnedDNSSkipsIPs(t *testing.T) {
+	server := mockServer(http.StatusOK, contentTypeJSON, "{}")
+	defer server.Close()
+
+	client := NewHttpClientWithConfig(NewDefaultHttpConfig(server.URL))
+	ctx := WithPinnedDNS(context.Background())
+	resp, err := client.Get(ctx, "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if len(PinnedAddresses(ctx)) != 0 {
+		t.Errorf("Expected IP addresses not to be pinned but got %v", PinnedAddresses(ctx))
+	}
+}
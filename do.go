@@ -0,0 +1,36 @@
+package http
+
+import (
+	"context"
+	"net/http"
+)
+
+// Do executes req with ctx and decodes a successful response into T with the Codec registered for its
+// Content-Type, applying the client's response transformers and envelope like DecodeResponse:
+//
+//	req, _ := client.GetRequest("/users/7")
+//	user, resp, err := http.Do[User](ctx, client, req)
+//
+// The response is returned for its status and headers, its body is already read and closed. Unsuccessful
+// statuses are reported as error together with the response, like UnauthorizedError or NotFoundError.
+// Responses are not decoded into Empty.
+func Do[T any](ctx context.Context, client *HttpClient, req *http.Request) (T, *http.Response, error) {
+	var value T
+	if ctx != nil {
+		req = req.WithContext(ctx)
+	}
+
+	resp, err := client.ExecuteRequest(req)
+	if err != nil {
+		return value, resp, err
+	}
+	if ClassifyStatus(resp.StatusCode) != StatusClassSuccess {
+		return value, resp, client.responseError(resp)
+	}
+	if _, empty := interface{}(value).(Empty); empty {
+		drainAndClose(resp.Body)
+		return value, resp, nil
+	}
+	err = client.DecodeResponse(resp, &value)
+	return value, resp, err
+}
@@ -0,0 +1,55 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestDo(t *testing.T) {
+	server := mockServerWith(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/yaml")
+		w.Header().Set("X-Request-Id", "7")
+		w.Write([]byte("name: gopher\n"))
+	})
+	defer server.Close()
+	RegisterCodec(YAMLCodec())
+
+	client := NewDefaultHttpClient(server.URL)
+	req, _ := client.GetRequest("/")
+	user, resp, err := Do[struct {
+		Name string `json:"name"`
+	}](context.Background(), client, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.Name != "gopher" || resp.Header.Get("X-Request-Id") != "7" {
+		t.Errorf("Unexpected result %+v with headers %v", user, resp.Header)
+	}
+}
+
+func TestDo_Status(t *testing.T) {
+	server := mockServer(http.StatusNotFound, contentTypeJSON, "{}")
+	defer server.Close()
+
+	client := NewDefaultHttpClient(server.URL)
+	req, _ := client.GetRequest("/")
+	_, resp, err := Do[map[string]interface{}](context.Background(), client, req)
+	if _, ok := err.(*NotFoundError); !ok {
+		t.Errorf("Expected NotFoundError but got %v", err)
+	}
+	if resp == nil || resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected the response with the error but got %v", resp)
+	}
+}
+
+func TestDo_Empty(t *testing.T) {
+	server := mockServer(http.StatusNoContent, contentTypeJSON, "")
+	defer server.Close()
+
+	client := NewDefaultHttpClient(server.URL)
+	req, _ := client.DeleteRequest("/")
+	if _, resp, err := Do[Empty](context.Background(), client, req); err != nil || resp.StatusCode != http.StatusNoContent {
+		t.Errorf("Unexpected result %v, %v", resp, err)
+	}
+}
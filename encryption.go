@@ -0,0 +1,228 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const joseType = "application/jose"
+
+// KeyProvider supplies the symmetric keys for payload encryption, e.g. backed by a KMS.
+// Keys must be 16, 24 or 32 bytes long for A128GCM, A192GCM or A256GCM.
+type KeyProvider interface {
+	// EncryptionKey returns the ID and key to encrypt the next request with.
+	EncryptionKey(ctx context.Context) (string, []byte, error)
+	// DecryptionKey returns the key with the given ID.
+	DecryptionKey(ctx context.Context, keyID string) ([]byte, error)
+}
+
+type staticKeyProvider struct {
+	keyID string
+	key   []byte
+}
+
+// StaticKey returns a KeyProvider with a single key.
+func StaticKey(keyID string, key []byte) KeyProvider {
+	return &staticKeyProvider{keyID: keyID, key: key}
+}
+
+func (p *staticKeyProvider) EncryptionKey(ctx context.Context) (string, []byte, error) {
+	return p.keyID, p.key, nil
+}
+
+func (p *staticKeyProvider) DecryptionKey(ctx context.Context, keyID string) ([]byte, error) {
+	if keyID != p.keyID {
+		return nil, fmt.Errorf("unknown key %q", keyID)
+	}
+	return p.key, nil
+}
+
+type joseHeader struct {
+	Alg string `json:"alg"`
+	Enc string `json:"enc"`
+	Kid string `json:"kid,omitempty"`
+	Cty string `json:"cty,omitempty"`
+}
+
+// EncryptionMiddleware encrypts request bodies as compact JWE (direct encryption with AES-GCM) and
+// decrypts application/jose responses, restoring the original Content-Type of both.
+func EncryptionMiddleware(keys KeyProvider) Middleware {
+	if keys == nil {
+		panic("keys is nil")
+	}
+
+	return func(next RoundTripperFunc) RoundTripperFunc {
+		return func(r *http.Request) (*http.Response, error) {
+			r, err := encryptRequest(r, keys)
+			if err != nil {
+				return nil, err
+			}
+
+			resp, err := next(r)
+			if err != nil {
+				return resp, err
+			}
+			return decryptResponse(resp, keys)
+		}
+	}
+}
+
+func encryptRequest(r *http.Request, keys KeyProvider) (*http.Request, error) {
+	if r.Body == nil || r.Body == http.NoBody {
+		return r, nil
+	}
+
+	plaintext, err := io.ReadAll(r.Body)
+	r.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	keyID, key, err := keys.EncryptionKey(r.Context())
+	if err != nil {
+		return nil, err
+	}
+	jwe, err := encryptJWE(plaintext, keyID, key, r.Header.Get("Content-Type"))
+	if err != nil {
+		return nil, err
+	}
+
+	encrypted := r.Clone(r.Context())
+	encrypted.Body = io.NopCloser(strings.NewReader(jwe))
+	encrypted.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(strings.NewReader(jwe)), nil
+	}
+	encrypted.ContentLength = int64(len(jwe))
+	encrypted.Header.Set("Content-Type", joseType)
+	return encrypted, nil
+}
+
+func decryptResponse(resp *http.Response, keys KeyProvider) (*http.Response, error) {
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType != joseType {
+		return resp, nil
+	}
+
+	jwe, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, contentType, err := decryptJWE(resp.Request.Context(), string(jwe), keys)
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(plaintext))
+	resp.ContentLength = int64(len(plaintext))
+	resp.Header.Set("Content-Length", strconv.Itoa(len(plaintext)))
+	if contentType != "" {
+		resp.Header.Set("Content-Type", contentType)
+	} else {
+		resp.Header.Del("Content-Type")
+	}
+	return resp, nil
+}
+
+func encryptJWE(plaintext []byte, keyID string, key []byte, contentType string) (string, error) {
+	gcm, enc, err := newJWECipher(key)
+	if err != nil {
+		return "", err
+	}
+
+	header, err := json.Marshal(joseHeader{Alg: "dir", Enc: enc, Kid: keyID, Cty: contentType})
+	if err != nil {
+		return "", err
+	}
+	encodedHeader := base64.RawURLEncoding.EncodeToString(header)
+
+	iv := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(iv); err != nil {
+		return "", err
+	}
+
+	// the encoded header is the additional authenticated data, the tag is appended by Seal
+	sealed := gcm.Seal(nil, iv, plaintext, []byte(encodedHeader))
+	ciphertext, tag := sealed[:len(sealed)-gcm.Overhead()], sealed[len(sealed)-gcm.Overhead():]
+
+	return strings.Join([]string{
+		encodedHeader,
+		"",
+		base64.RawURLEncoding.EncodeToString(iv),
+		base64.RawURLEncoding.EncodeToString(ciphertext),
+		base64.RawURLEncoding.EncodeToString(tag),
+	}, "."), nil
+}
+
+func decryptJWE(ctx context.Context, jwe string, keys KeyProvider) ([]byte, string, error) {
+	parts := strings.Split(strings.TrimSpace(jwe), ".")
+	if len(parts) != 5 || parts[1] != "" {
+		return nil, "", errors.New("invalid compact JWE")
+	}
+
+	rawHeader, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, "", err
+	}
+	header := joseHeader{}
+	if err := json.Unmarshal(rawHeader, &header); err != nil {
+		return nil, "", err
+	}
+	if header.Alg != "dir" {
+		return nil, "", fmt.Errorf("unsupported JWE algorithm %q", header.Alg)
+	}
+
+	key, err := keys.DecryptionKey(ctx, header.Kid)
+	if err != nil {
+		return nil, "", err
+	}
+	gcm, enc, err := newJWECipher(key)
+	if err != nil {
+		return nil, "", err
+	}
+	if header.Enc != enc {
+		return nil, "", fmt.Errorf("JWE encryption %q does not match key size", header.Enc)
+	}
+
+	var decoded [3][]byte
+	for i, part := range parts[2:] {
+		if decoded[i], err = base64.RawURLEncoding.DecodeString(part); err != nil {
+			return nil, "", err
+		}
+	}
+	iv, ciphertext, tag := decoded[0], decoded[1], decoded[2]
+	if len(iv) != gcm.NonceSize() {
+		return nil, "", errors.New("invalid JWE initialization vector")
+	}
+
+	plaintext, err := gcm.Open(nil, iv, append(ciphertext, tag...), []byte(parts[0]))
+	if err != nil {
+		return nil, "", err
+	}
+	return plaintext, header.Cty, nil
+}
+
+func newJWECipher(key []byte) (cipher.AEAD, string, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, "", err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, "", err
+	}
+	return gcm, fmt.Sprintf("A%dGCM", len(key)*8), nil
+}
@@ -0,0 +1,61 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+var fixtureEncryptionKey = bytes.Repeat([]byte{0x42}, 32)
+
+func TestEncryptionMiddleware(t *testing.T) {
+	keys := StaticKey("key-1", fixtureEncryptionKey)
+
+	var received string
+	f := func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received = string(body)
+
+		plaintext, contentType, err := decryptJWE(r.Context(), received, keys)
+		if err != nil || contentType != contentTypeJSON {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		jwe, _ := encryptJWE(plaintext, "key-1", fixtureEncryptionKey, contentTypeJSON)
+		w.Header().Set("Content-Type", joseType)
+		w.Write([]byte(jwe))
+	}
+	server := mockServerWith(http.HandlerFunc(f))
+	defer server.Close()
+
+	client := createTestHTTPClient(server.URL)
+	client.Use(EncryptionMiddleware(keys))
+
+	resp, err := client.PostTo("", strings.NewReader(fixtureBasicJSON))
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	if strings.Contains(received, fixtureBasicJSON) || strings.Count(received, ".") != 4 {
+		t.Errorf("Expected compact JWE on the wire but got %s", received)
+	}
+	assertResponseHasStatus(resp, http.StatusOK, t)
+	if resp.Header.Get("Content-Type") != contentTypeJSON {
+		t.Errorf("Expected restored Content-Type but got %s", resp.Header.Get("Content-Type"))
+	}
+	assertResponseBodyIs(resp, fixtureBasicJSON, t)
+}
+
+func TestDecryptJWE_Tampered(t *testing.T) {
+	keys := StaticKey("key-1", fixtureEncryptionKey)
+	jwe, _ := encryptJWE([]byte(fixtureBasicJSON), "key-1", fixtureEncryptionKey, "")
+
+	parts := strings.Split(jwe, ".")
+	parts[3] = parts[3][:len(parts[3])-2] + "AA"
+	if _, _, err := decryptJWE(context.Background(), strings.Join(parts, "."), keys); err == nil {
+		t.Error("Expected tampered ciphertext to be rejected")
+	}
+}
@@ -0,0 +1,93 @@
+package http
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// endpointPattern maps requests matching a method and path pattern to an endpoint name.
+type endpointPattern struct {
+	pattern  string
+	method   string
+	segments []string
+	name     string
+}
+
+// WithEndpointPattern names the endpoint of requests whose path matches pattern, e.g. "/users/{id}" or
+// "GET /users/{id}", as name, e.g. "get_user", so metrics, logs, traces and profiles aggregate by endpoint
+// instead of exploding the cardinality with raw URLs. A "{param}" segment matches any single path segment, a
+// trailing "{param...}" the rest of the path. Paths are matched relative to the base URL's path, the first
+// matching pattern wins; an endpoint name set with WithEndpointName takes precedence.
+func WithEndpointPattern(pattern string, name string) Option {
+	parsed := endpointPattern{pattern: pattern, name: name}
+	path := pattern
+	if method, rest, ok := strings.Cut(pattern, " "); ok {
+		parsed.method = strings.ToUpper(method)
+		path = strings.TrimSpace(rest)
+	}
+	parsed.segments = strings.Split(strings.Trim(path, "/"), "/")
+
+	return func(c *HttpConfig) {
+		c.endpoints = append(append([]endpointPattern(nil), c.endpoints...), parsed)
+	}
+}
+
+// EndpointPatterns returns the endpoint names by pattern, see WithEndpointPattern.
+func (c *HttpConfig) EndpointPatterns() map[string]string {
+	patterns := make(map[string]string, len(c.endpoints))
+	for _, p := range c.endpoints {
+		patterns[p.pattern] = p.name
+	}
+	return patterns
+}
+
+// EndpointName returns the name of the endpoint r targets, from r's context or the client's endpoint patterns.
+func (h *HttpClient) EndpointName(r *http.Request) (string, bool) {
+	if name, ok := EndpointNameFromContext(r.Context()); ok {
+		return name, true
+	}
+
+	h.mu.RLock()
+	endpoints, baseURL := h.config.endpoints, h.config.baseURL
+	h.mu.RUnlock()
+	if len(endpoints) == 0 || r.URL == nil {
+		return "", false
+	}
+
+	path := r.URL.Path
+	if base, err := url.Parse(baseURL); err == nil && base.Host == r.URL.Host {
+		if prefix := strings.TrimSuffix(base.Path, "/"); prefix != "" && strings.HasPrefix(path, prefix+"/") {
+			path = strings.TrimPrefix(path, prefix)
+		}
+	}
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+
+	for _, p := range endpoints {
+		if (p.method == "" || p.method == r.Method) && p.matches(segments) {
+			return p.name, true
+		}
+	}
+	return "", false
+}
+
+func (p endpointPattern) matches(segments []string) bool {
+	for i, segment := range p.segments {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "...}") {
+			return i < len(segments)
+		}
+		if i >= len(segments) {
+			return false
+		}
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			if segments[i] == "" {
+				return false
+			}
+			continue
+		}
+		if segment != segments[i] {
+			return false
+		}
+	}
+	return len(segments) == len(p.segments)
+}
@@ -0,0 +1,68 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestWithEndpointPattern(t *testing.T) {
+	client := NewHttpClientWithConfig(NewDefaultHttpConfig("http://api.test/v1",
+		WithEndpointPattern("GET /users/{id}", "get_user"),
+		WithEndpointPattern("/users/{id}", "change_user"),
+		WithEndpointPattern("/files/{path...}", "file"),
+		WithEndpointPattern("/users", "users"),
+	))
+
+	tests := []struct {
+		method string
+		url    string
+		name   string
+	}{
+		{http.MethodGet, "http://api.test/v1/users/42", "get_user"},
+		{http.MethodPut, "http://api.test/v1/users/42", "change_user"},
+		{http.MethodGet, "http://api.test/v1/users", "users"},
+		{http.MethodGet, "http://api.test/v1/files/a/b/c.txt", "file"},
+		{http.MethodGet, "http://api.test/v1/files", ""},
+		{http.MethodGet, "http://api.test/v1/users/42/posts", ""},
+		{http.MethodGet, "http://other.test/users/42", "get_user"},
+	}
+	for _, test := range tests {
+		request, _ := http.NewRequest(test.method, test.url, nil)
+		if name, _ := client.EndpointName(request); name != test.name {
+			t.Errorf("Expected %s %s to be named %q but got %q", test.method, test.url, test.name, name)
+		}
+	}
+
+	request, _ := http.NewRequestWithContext(WithEndpointName(context.Background(), "explicit"), http.MethodGet,
+		"http://api.test/v1/users/42", nil)
+	if name, _ := client.EndpointName(request); name != "explicit" {
+		t.Errorf("Expected the explicit endpoint name to win but got %q", name)
+	}
+}
+
+func TestWithEndpointPatternMetrics(t *testing.T) {
+	server := mockServer(http.StatusOK, "application/json", "{}")
+	defer server.Close()
+
+	metrics := NewMetrics()
+	client := NewHttpClientWithConfig(NewDefaultHttpConfig(server.URL, WithEvents(metrics),
+		WithEndpointPattern("/users/{id}", "get_user")))
+	for _, path := range []string{"/users/1", "/users/2", "/users/3"} {
+		resp, err := client.Get(context.Background(), path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+
+	snapshot := metrics.Snapshot()
+	if len(snapshot) != 1 {
+		t.Fatalf("Expected a single series but got %v", snapshot)
+	}
+	for labels, histogram := range snapshot {
+		if labels.Endpoint != "get_user" || histogram.Count != 3 {
+			t.Errorf("Expected 3 requests to get_user but got %v: %d", labels, histogram.Count)
+		}
+	}
+}
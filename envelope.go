@@ -0,0 +1,146 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Envelope names the keys of a response envelope like {"data": ..., "error": ..., "meta": ...}.
+type Envelope struct {
+	DataKey  string
+	ErrorKey string
+	MetaKey  string
+}
+
+// DefaultEnvelope is the data/error/meta envelope used by many internal APIs.
+var DefaultEnvelope = Envelope{DataKey: "data", ErrorKey: "error", MetaKey: "meta"}
+
+// EnvelopeError is the error reported in the error key of a response envelope. Err is the error of the
+// response status, if it was unsuccessful, e.g. a NotFoundError.
+type EnvelopeError struct {
+	Message string
+	Code    string
+	Status  int
+	Details json.RawMessage
+	Err     error
+}
+
+func (e EnvelopeError) Error() string {
+	if e.Code != "" {
+		return fmt.Sprintf("%s (%s)", e.Message, e.Code)
+	}
+	return e.Message
+}
+
+func (e EnvelopeError) Unwrap() error {
+	return e.Err
+}
+
+// WithEnvelope makes DecodeResponse, Fetch and Call extract the data key of envelope into the decoded value and
+// report its error key as EnvelopeError.
+func WithEnvelope(envelope Envelope) Option {
+	return func(c *HttpConfig) {
+		c.envelope = &envelope
+	}
+}
+
+func (h *HttpClient) envelope() *Envelope {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.config.envelope
+}
+
+// DecodeEnvelope decodes resp like DecodeResponse and additionally the meta key of the client's envelope into
+// meta, e.g. pagination information.
+func (h *HttpClient) DecodeEnvelope(resp *http.Response, out interface{}, meta interface{}) error {
+	envelope := h.envelope()
+	if envelope == nil {
+		return h.DecodeResponse(resp, out)
+	}
+
+	body, err := h.transformedBody(resp)
+	if err != nil {
+		return err
+	}
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil
+	}
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return err
+	}
+
+	if err := envelope.error(fields, resp.StatusCode, nil); err != nil {
+		return err
+	}
+	if meta != nil && len(fields[envelope.MetaKey]) > 0 {
+		if err := json.Unmarshal(fields[envelope.MetaKey], meta); err != nil {
+			return err
+		}
+	}
+	if data, ok := fields[envelope.DataKey]; ok {
+		return json.Unmarshal(data, out)
+	}
+	return nil
+}
+
+// responseError returns the error of an unsuccessful response, the EnvelopeError in its body if the client is
+// configured with an envelope. The body is closed.
+func (h *HttpClient) responseError(resp *http.Response) error {
+	statusErr := statusError(resp)
+	envelope := h.envelope()
+	if envelope == nil || resp.Body == nil {
+		drainAndClose(resp.Body)
+		return statusErr
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	fields := map[string]json.RawMessage{}
+	if err != nil || json.Unmarshal(body, &fields) != nil {
+		return statusErr
+	}
+	if err := envelope.error(fields, resp.StatusCode, statusErr); err != nil {
+		return err
+	}
+	return statusErr
+}
+
+// error returns the EnvelopeError in the error key of fields, if any.
+func (e *Envelope) error(fields map[string]json.RawMessage, status int, statusErr error) error {
+	raw, ok := fields[e.ErrorKey]
+	if !ok || len(raw) == 0 || string(raw) == "null" {
+		return nil
+	}
+
+	envelopeErr := &EnvelopeError{Status: status, Details: raw, Err: statusErr}
+	var message string
+	if json.Unmarshal(raw, &message) == nil {
+		envelopeErr.Message = message
+		return envelopeErr
+	}
+
+	var described struct {
+		Message     string      `json:"message"`
+		Description string      `json:"description"`
+		Code        interface{} `json:"code"`
+	}
+	if err := json.Unmarshal(raw, &described); err != nil {
+		envelopeErr.Message = string(raw)
+		return envelopeErr
+	}
+	envelopeErr.Message = described.Message
+	if envelopeErr.Message == "" {
+		envelopeErr.Message = described.Description
+	}
+	if described.Code != nil {
+		envelopeErr.Code = fmt.Sprint(described.Code)
+	}
+	if envelopeErr.Message == "" {
+		envelopeErr.Message = fmt.Sprintf("Request failed with status %d.", status)
+	}
+	return envelopeErr
+}
@@ -0,0 +1,51 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestHttpClient_Envelope(t *testing.T) {
+	server := mockServerWith(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/users":
+			w.Write([]byte(`{"data": [{"id": 1, "name": "jane"}], "meta": {"total": 12}, "error": null}`))
+		case "/missing":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"data": null, "error": {"message": "No such user.", "code": 1042}}`))
+		case "/partial":
+			w.Write([]byte(`{"error": "Quota exceeded."}`))
+		}
+	})
+	defer server.Close()
+
+	client := NewHttpClientWithConfig(NewDefaultHttpConfig(server.URL, WithEnvelope(DefaultEnvelope)))
+
+	users := Fetch[[]callUser](context.Background(), client, http.MethodGet, "/users", nil)
+	if users.Err != nil || len(users.Value) != 1 || users.Value[0].Name != "jane" {
+		t.Errorf("Expected the data to be extracted but got %+v", users)
+	}
+
+	resp, err := client.Get(context.Background(), "/users")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var meta struct{ Total int }
+	if err := client.DecodeEnvelope(resp, &[]callUser{}, &meta); err != nil || meta.Total != 12 {
+		t.Errorf("Expected the meta to be decoded but got %+v, %v", meta, err)
+	}
+
+	_, err = Call[Empty, callUser](context.Background(), client, Spec{Method: http.MethodGet, Path: "/missing"}, Empty{})
+	var envelopeErr *EnvelopeError
+	var notFound *NotFoundError
+	if !errors.As(err, &envelopeErr) || envelopeErr.Code != "1042" || envelopeErr.Message != "No such user." || !errors.As(err, &notFound) {
+		t.Errorf("Expected an EnvelopeError wrapping NotFoundError but got %v", err)
+	}
+
+	partial := Fetch[callUser](context.Background(), client, http.MethodGet, "/partial", nil)
+	if !errors.As(partial.Err, &envelopeErr) || envelopeErr.Message != "Quota exceeded." || envelopeErr.Status != http.StatusOK {
+		t.Errorf("Expected the error of a successful response to be reported but got %v", partial.Err)
+	}
+}
@@ -0,0 +1,130 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// Well-known environment names.
+const (
+	EnvironmentProduction = "production"
+	EnvironmentSandbox    = "sandbox"
+	EnvironmentStaging    = "staging"
+)
+
+// Environment is a named profile with its own base URL and credentials.
+type Environment struct {
+	Name     string
+	BaseURL  string
+	Username string
+	Password string
+}
+
+// EnvironmentNotFoundError is returned when switching to an environment which was never added.
+type EnvironmentNotFoundError struct {
+	Message string
+	Name    string
+}
+
+func (e EnvironmentNotFoundError) Error() string {
+	return e.Message
+}
+
+type environmentKey struct{}
+
+// WithEnvironment returns a context selecting the named environment for requests created with it.
+func WithEnvironment(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, environmentKey{}, name)
+}
+
+// AddEnvironment registers an Environment on the client, replacing any environment with the same name.
+func (h *HttpClient) AddEnvironment(env Environment) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.environments == nil {
+		h.environments = make(map[string]Environment)
+	}
+	h.environments[env.Name] = env
+}
+
+// UseEnvironment switches the client to the named environment. An empty name switches back to the HttpConfig.
+func (h *HttpClient) UseEnvironment(name string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := h.environments[name]; !ok && name != "" {
+		return newEnvironmentNotFoundError(name)
+	}
+	h.environment = name
+	return nil
+}
+
+// Environment returns the name of the environment the client currently uses.
+func (h *HttpClient) Environment() string {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.environment
+}
+
+// resolveTarget returns the base URL and credentials for a request, preferring the tenant of ctx,
+// then the environment selected by ctx, then the client's environment and finally the HttpConfig.
+func (h *HttpClient) resolveTarget(ctx context.Context) (string, string, string, error) {
+	baseURL, username, password, err := h.resolveEnvironment(ctx)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	if tenantID, ok := TenantFromContext(ctx); ok {
+		return h.resolveTenant(ctx, tenantID, baseURL)
+	}
+	return baseURL, username, password, nil
+}
+
+// ownTarget reports whether r goes to the host of the HttpConfig's base URL rather than to a tenant, an
+// environment, which bring their own credentials, or any other host, like an identity provider or the
+// target of a Location header.
+func (h *HttpClient) ownTarget(r *http.Request) bool {
+	if _, ok := TenantFromContext(r.Context()); ok {
+		return false
+	}
+
+	h.mu.RLock()
+	environment, baseURL := h.environment, h.config.baseURL
+	h.mu.RUnlock()
+	if name, ok := EnvironmentFromContext(r.Context()); ok {
+		environment = name
+	}
+	if environment != "" {
+		return false
+	}
+
+	base, err := url.Parse(baseURL)
+	return err == nil && base.Host == r.URL.Host
+}
+
+func (h *HttpClient) resolveEnvironment(ctx context.Context) (string, string, string, error) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	name := h.environment
+	if selected, ok := EnvironmentFromContext(ctx); ok {
+		name = selected
+	}
+
+	if name == "" {
+		return h.config.baseURL, h.config.username, h.config.password, nil
+	}
+
+	env, ok := h.environments[name]
+	if !ok {
+		return "", "", "", newEnvironmentNotFoundError(name)
+	}
+	return env.BaseURL, env.Username, env.Password, nil
+}
+
+func newEnvironmentNotFoundError(name string) error {
+	return &EnvironmentNotFoundError{Message: fmt.Sprintf("Environment %q not defined.", name), Name: name}
+}
@@ -0,0 +1,52 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+const fixtureSandboxURL = "https://sandbox.github.com/hawky-4s-"
+
+func TestHttpClient_UseEnvironment(t *testing.T) {
+	client := createTestHTTPClient(fixtureBaseURL)
+	client.AddEnvironment(Environment{Name: EnvironmentSandbox, BaseURL: fixtureSandboxURL, Username: "foo", Password: "bar"})
+
+	if err := client.UseEnvironment(EnvironmentSandbox); err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	req, _ := client.GetRequest("path")
+
+	assertURLIs(req.URL, fixtureSandboxURL+"/path", t)
+	if username, password, _ := req.BasicAuth(); username != "foo" || password != "bar" {
+		t.Errorf("Expected sandbox credentials but got %s:%s", username, password)
+	}
+
+	client.UseEnvironment("")
+	req, _ = client.GetRequest("path")
+	assertURLIs(req.URL, fixtureBaseURL+"/path", t)
+}
+
+func TestHttpClient_UseUnknownEnvironment(t *testing.T) {
+	client := createTestHTTPClient(fixtureBaseURL)
+
+	if err := client.UseEnvironment(EnvironmentStaging); err == nil {
+		t.Error("Expected EnvironmentNotFoundError")
+	}
+}
+
+func TestHttpClient_EnvironmentFromContext(t *testing.T) {
+	server := mockServer(http.StatusOK, contentTypeJSON, fixtureBasicJSON)
+	defer server.Close()
+
+	client := createTestHTTPClient(fixtureBaseURL)
+	client.AddEnvironment(Environment{Name: EnvironmentSandbox, BaseURL: server.URL})
+
+	resp, err := client.GetFromWithContext(WithEnvironment(context.Background(), EnvironmentSandbox), "")
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	assertResponseHasStatus(resp, http.StatusOK, t)
+	assertResponseBodyIs(resp, fixtureBasicJSON, t)
+}
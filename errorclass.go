@@ -0,0 +1,157 @@
+package http
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net"
+	"syscall"
+)
+
+// TimeoutError is a request which did not complete in time, because its context's deadline expired or
+// a network operation timed out.
+type TimeoutError struct {
+	Message string
+	Err     error
+}
+
+func (e TimeoutError) Error() string {
+	return e.Message + ": " + e.Err.Error()
+}
+
+func (e TimeoutError) Unwrap() error {
+	return e.Err
+}
+
+// Timeout implements net.Error.
+func (e TimeoutError) Timeout() bool {
+	return true
+}
+
+// Temporary reports that sending the request again may succeed.
+func (e TimeoutError) Temporary() bool {
+	return true
+}
+
+// CanceledError is a request canceled by its context. It is neither a timeout nor temporary.
+type CanceledError struct {
+	Message string
+	Err     error
+}
+
+func (e CanceledError) Error() string {
+	return e.Message + ": " + e.Err.Error()
+}
+
+func (e CanceledError) Unwrap() error {
+	return e.Err
+}
+
+// DNSError is a failure to resolve the host of a request.
+type DNSError struct {
+	Message string
+	Host    string
+	Err     *net.DNSError
+}
+
+func (e DNSError) Error() string {
+	return e.Message + ": " + e.Err.Error()
+}
+
+func (e DNSError) Unwrap() error {
+	return e.Err
+}
+
+// Timeout reports whether the lookup timed out.
+func (e DNSError) Timeout() bool {
+	return e.Err.IsTimeout
+}
+
+// Temporary reports whether the lookup may succeed when repeated. Unknown hosts are permanent.
+func (e DNSError) Temporary() bool {
+	return e.Err.IsTimeout || e.Err.IsTemporary
+}
+
+// ConnectionRefusedError is a connection attempt rejected by the remote host, typically because nothing
+// listens on the port.
+type ConnectionRefusedError struct {
+	Message string
+	Address string
+	Err     error
+}
+
+func (e ConnectionRefusedError) Error() string {
+	return e.Message + ": " + e.Err.Error()
+}
+
+func (e ConnectionRefusedError) Unwrap() error {
+	return e.Err
+}
+
+// Timeout implements net.Error.
+func (e ConnectionRefusedError) Timeout() bool {
+	return false
+}
+
+// Temporary reports that the service may accept connections again later, e.g. after a restart.
+func (e ConnectionRefusedError) Temporary() bool {
+	return true
+}
+
+// TLSError is a failed TLS handshake, e.g. because the server's certificate is not trusted or does not
+// match the host. Repeating the request does not help.
+type TLSError struct {
+	Message string
+	Err     error
+}
+
+func (e TLSError) Error() string {
+	return e.Message + ": " + e.Err.Error()
+}
+
+func (e TLSError) Unwrap() error {
+	return e.Err
+}
+
+// classifyError maps a transport error to one of the typed errors above, or returns it unchanged if it
+// falls into none of the categories.
+func classifyError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var netErr net.Error
+	var dnsErr *net.DNSError
+	var opErr *net.OpError
+	switch {
+	case errors.Is(err, context.Canceled):
+		return &CanceledError{Message: "Request canceled", Err: err}
+	case errors.As(err, &dnsErr):
+		return &DNSError{Message: "Host lookup failed", Host: dnsErr.Name, Err: dnsErr}
+	case errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()):
+		return &TimeoutError{Message: "Request timed out", Err: err}
+	case errors.Is(err, syscall.ECONNREFUSED):
+		address := ""
+		if errors.As(err, &opErr) && opErr.Addr != nil {
+			address = opErr.Addr.String()
+		}
+		return &ConnectionRefusedError{Message: "Connection refused", Address: address, Err: err}
+	case isTLSError(err):
+		return &TLSError{Message: "TLS handshake failed", Err: err}
+	}
+	return err
+}
+
+func isTLSError(err error) bool {
+	var verificationErr *tls.CertificateVerificationError
+	var recordErr tls.RecordHeaderError
+	var alertErr tls.AlertError
+	var authorityErr x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var invalidErr x509.CertificateInvalidError
+	var mismatchErr *CertificateMismatchError
+	return errors.As(err, &verificationErr) || errors.As(err, &recordErr) || errors.As(err, &alertErr) ||
+		errors.As(err, &authorityErr) || errors.As(err, &hostnameErr) || errors.As(err, &invalidErr) ||
+		errors.As(err, &mismatchErr)
+}
@@ -0,0 +1,70 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestClassifyError_ExecuteRequest(t *testing.T) {
+	slow := mockServerWith(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	})
+	defer slow.Close()
+	untrusted := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	untrusted.Config.ErrorLog = log.New(io.Discard, "", 0)
+	untrusted.StartTLS()
+	defer untrusted.Close()
+	closed := mockServerWith(nil)
+	closed.Close()
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	expired, cancelExpired := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancelExpired()
+
+	cases := map[string]struct {
+		ctx    context.Context
+		url    string
+		target interface{}
+	}{
+		"canceled": {canceled, slow.URL, new(*CanceledError)},
+		"timeout":  {expired, slow.URL, new(*TimeoutError)},
+		"refused":  {context.Background(), closed.URL, new(*ConnectionRefusedError)},
+		"tls":      {context.Background(), untrusted.URL, new(*TLSError)},
+		"dns":      {context.Background(), "http://unknown.invalid", new(*DNSError)},
+	}
+
+	for name, c := range cases {
+		client := createTestHTTPClient(c.url)
+		_, err := client.GetFromWithContext(c.ctx, "/")
+		if !errors.As(err, c.target) {
+			t.Errorf("%s: expected %T but got %v", name, c.target, err)
+		}
+	}
+}
+
+func TestClassifyError_TimeoutTemporary(t *testing.T) {
+	var timeout net.Error = &TimeoutError{Message: "Request timed out", Err: context.DeadlineExceeded}
+	if !timeout.Timeout() || !errors.Is(timeout, context.DeadlineExceeded) {
+		t.Error("Expected timeout to implement net.Error and wrap the cause")
+	}
+
+	notFound := &DNSError{Message: "Host lookup failed", Err: &net.DNSError{Name: "unknown.invalid", IsNotFound: true}}
+	if notFound.Temporary() || notFound.Timeout() {
+		t.Error("Expected unknown host to be permanent")
+	}
+
+	if classifyError(errors.New("other")).Error() != "other" {
+		t.Error("Expected unclassified errors to be returned unchanged")
+	}
+}
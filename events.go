@@ -0,0 +1,244 @@
+package http
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// EventType identifies what happened in an Event.
+type EventType string
+
+// Event types emitted by the client and its components.
+const (
+	// EventRequestStarted is emitted when ExecuteRequest starts a request.
+	EventRequestStarted EventType = "request_started"
+	// EventRequestFinished is emitted when ExecuteRequest returns, after all attempts.
+	EventRequestFinished EventType = "request_finished"
+	// EventAttemptFinished is emitted for every round trip to the server, including authentication rounds.
+	EventAttemptFinished EventType = "attempt_finished"
+	// EventRetryScheduled is emitted when a failed request is scheduled to be sent again after Delay.
+	EventRetryScheduled EventType = "retry_scheduled"
+	// EventCacheHit is emitted when a response is served from a cache, with CacheStatus HIT or STALE.
+	EventCacheHit EventType = "cache_hit"
+	// EventCacheMiss is emitted when a cache has no entry for a request.
+	EventCacheMiss EventType = "cache_miss"
+	// EventCacheRevalidated is emitted when the server confirmed an expired cache entry to be current.
+	EventCacheRevalidated EventType = "cache_revalidated"
+	// EventCacheStored is emitted when a response is added to a cache.
+	EventCacheStored EventType = "cache_stored"
+	// EventCacheEvicted is emitted when an entry is removed from a cache.
+	EventCacheEvicted EventType = "cache_evicted"
+	// EventCircuitOpened is emitted when a circuit breaker stops sending requests to Host.
+	EventCircuitOpened EventType = "circuit_opened"
+	// EventTokenRefreshed is emitted when a TokenSource obtained a new token.
+	EventTokenRefreshed EventType = "token_refreshed"
+	// EventConnectionOpened is emitted when the client's transport established a connection to Host, the
+	// dialed address, with the Duration of the dial.
+	EventConnectionOpened EventType = "connection_opened"
+	// EventConnectionReused is emitted when an attempt reuses a pooled connection, with the Duration it was idle.
+	EventConnectionReused EventType = "connection_reused"
+	// EventConnectionClosed is emitted when a connection of the client's transport to Host is closed, with the
+	// Duration it was open.
+	EventConnectionClosed EventType = "connection_closed"
+	// EventTLSResumed is emitted when an attempt resumed a previous TLS session instead of a full handshake.
+	EventTLSResumed EventType = "tls_resumed"
+)
+
+// Event describes something which happened while executing requests. Fields not applying to the Type are zero.
+type Event struct {
+	Type     EventType
+	Time     time.Time
+	Host     string
+	Request  *http.Request
+	Response *http.Response
+	Err      error
+	// Duration of the request or attempt.
+	Duration time.Duration
+	// Attempt is the number of the attempt, starting at 1.
+	Attempt int
+	// Delay until a scheduled retry.
+	Delay time.Duration
+	// ConnReused tells whether an attempt reused a pooled connection.
+	ConnReused bool
+	// Timings of the phases of an attempt.
+	Timings AttemptTimings
+	// CacheStatus of a cache hit.
+	CacheStatus CacheStatus
+}
+
+// AttemptTimings are the durations of the phases of an attempt, zero for phases skipped, e.g. on a reused
+// connection.
+type AttemptTimings struct {
+	DNS     time.Duration
+	Connect time.Duration
+	TLS     time.Duration
+	// TimeToFirstByte is measured from the start of the attempt.
+	TimeToFirstByte time.Duration
+}
+
+// Events consumes the event stream of a client, e.g. to derive metrics, logs and traces from it.
+// Implementations are called synchronously on the request's goroutine and must be safe for concurrent use.
+type Events interface {
+	Emit(e Event)
+}
+
+// EventsFunc adapts a function to the Events interface.
+type EventsFunc func(e Event)
+
+func (f EventsFunc) Emit(e Event) {
+	f(e)
+}
+
+// MultiEvents returns Events passing every event to all of events in order.
+func MultiEvents(events ...Events) Events {
+	return EventsFunc(func(e Event) {
+		for _, consumer := range events {
+			consumer.Emit(e)
+		}
+	})
+}
+
+// WithEvents sets the consumer of the client's events.
+func WithEvents(events Events) Option {
+	return func(c *HttpConfig) {
+		c.events = events
+	}
+}
+
+// observeAttempts wraps next to emit an EventAttemptFinished for every round trip.
+func (h *HttpClient) observeAttempts(next RoundTripperFunc) RoundTripperFunc {
+	return func(r *http.Request) (*http.Response, error) {
+		attempt := nextAttempt(r.Context())
+
+		start := time.Now()
+		timer := &attemptTimer{start: start}
+		ctx := httptrace.WithClientTrace(r.Context(), timer.trace())
+		r = r.WithContext(httptrace.WithClientTrace(ctx, h.connectionTrace(r)))
+
+		resp, err := next(r)
+		reused, timings := timer.result()
+		h.emit(Event{
+			Type:       EventAttemptFinished,
+			Request:    r,
+			Response:   resp,
+			Err:        err,
+			Duration:   time.Since(start),
+			Attempt:    attempt,
+			ConnReused: reused,
+			Timings:    timings,
+		})
+		return resp, err
+	}
+}
+
+// attemptTimer measures the phases of an attempt. Its callbacks may run on the transport's dial goroutines.
+type attemptTimer struct {
+	mu                               sync.Mutex
+	start                            time.Time
+	dnsStart, connectStart, tlsStart time.Time
+	reused                           bool
+	timings                          AttemptTimings
+}
+
+func (t *attemptTimer) trace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		DNSStart:          func(httptrace.DNSStartInfo) { t.begin(&t.dnsStart) },
+		DNSDone:           func(httptrace.DNSDoneInfo) { t.measure(&t.dnsStart, &t.timings.DNS) },
+		ConnectStart:      func(string, string) { t.begin(&t.connectStart) },
+		ConnectDone:       func(string, string, error) { t.measure(&t.connectStart, &t.timings.Connect) },
+		TLSHandshakeStart: func() { t.begin(&t.tlsStart) },
+		TLSHandshakeDone:  func(tls.ConnectionState, error) { t.measure(&t.tlsStart, &t.timings.TLS) },
+		GotConn: func(info httptrace.GotConnInfo) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.reused = info.Reused
+		},
+		GotFirstResponseByte: func() { t.measure(&t.start, &t.timings.TimeToFirstByte) },
+	}
+}
+
+func (t *attemptTimer) begin(at *time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	*at = time.Now()
+}
+
+func (t *attemptTimer) measure(since *time.Time, phase *time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !since.IsZero() {
+		*phase = time.Since(*since)
+	}
+}
+
+func (t *attemptTimer) result() (bool, AttemptTimings) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.reused, t.timings
+}
+
+// emit sends e to the client's events, filling in the time and host if missing.
+func (h *HttpClient) emit(e Event) {
+	h.mu.RLock()
+	events := h.config.events
+	h.mu.RUnlock()
+
+	if events == nil {
+		return
+	}
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	if e.Host == "" && e.Request != nil {
+		e.Host = e.Request.URL.Host
+	}
+	events.Emit(e)
+}
+
+// hostOf returns the host of rawURL, or an empty string if it can not be parsed.
+func hostOf(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return u.Host
+}
+
+type attemptCounterKey struct{}
+
+// withAttemptCounter returns a context counting the attempts of the request executed with it.
+func withAttemptCounter(ctx context.Context) context.Context {
+	return context.WithValue(ctx, attemptCounterKey{}, new(int32))
+}
+
+// countAttempts wraps next to count its round trips when no events are observed.
+func countAttempts(next RoundTripperFunc) RoundTripperFunc {
+	return func(r *http.Request) (*http.Response, error) {
+		nextAttempt(r.Context())
+		return next(r)
+	}
+}
+
+// attemptsFrom returns the number of attempts sent with ctx so far.
+func attemptsFrom(ctx context.Context) int {
+	counter, ok := ctx.Value(attemptCounterKey{}).(*int32)
+	if !ok {
+		return 0
+	}
+	return int(atomic.LoadInt32(counter))
+}
+
+// nextAttempt returns the number of the attempt about to be sent with ctx.
+func nextAttempt(ctx context.Context) int {
+	counter, ok := ctx.Value(attemptCounterKey{}).(*int32)
+	if !ok {
+		return 1
+	}
+	return int(atomic.AddInt32(counter, 1))
+}
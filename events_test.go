@@ -0,0 +1,104 @@
+package http
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+type recordedEvents struct {
+	mu     sync.Mutex
+	events []Event
+}
+
+func (r *recordedEvents) Emit(e Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, e)
+}
+
+func (r *recordedEvents) snapshot() []Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Event(nil), r.events...)
+}
+
+func (r *recordedEvents) types() []EventType {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	types := make([]EventType, len(r.events))
+	for i, e := range r.events {
+		types[i] = e.Type
+	}
+	return types
+}
+
+func TestEvents_Request(t *testing.T) {
+	server := mockServer(http.StatusOK, contentTypeJSON, fixtureBasicJSON)
+	defer server.Close()
+
+	recorded := &recordedEvents{}
+	client := NewHttpClientWithConfig(NewDefaultHttpConfig(server.URL, WithEvents(recorded)))
+
+	resp, err := client.GetFrom("/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	types := recorded.types()
+	expected := []EventType{EventRequestStarted, EventConnectionOpened, EventAttemptFinished, EventRequestFinished}
+	if len(types) != len(expected) {
+		t.Fatalf("Expected events %v but got %v", expected, types)
+	}
+	for i := range expected {
+		if types[i] != expected[i] {
+			t.Errorf("Expected events %v but got %v", expected, types)
+		}
+	}
+
+	attempt := recorded.snapshot()[2]
+	if attempt.Attempt != 1 || attempt.Response == nil || attempt.Host != client.Config().BaseURL()[len("http://"):] {
+		t.Errorf("Unexpected attempt event %+v", attempt)
+	}
+}
+
+func TestEvents_AttemptsAcrossAuthRounds(t *testing.T) {
+	server := mockServerWith(func(w http.ResponseWriter, r *http.Request) {
+		if user, _, _ := r.BasicAuth(); user != "old" {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	})
+	defer server.Close()
+
+	recorded := &recordedEvents{}
+	source := NewRotatingCredentials(Credentials{Username: "old", Password: "secret"})
+	source.Rotate(Credentials{Username: "new", Password: "secret"}, time.Minute)
+	client := NewHttpClientWithConfig(NewDefaultHttpConfig(server.URL, WithEvents(recorded), WithAuthProvider(BasicAuth(source))))
+
+	resp, err := client.GetFrom("/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	var attempts []int
+	for _, e := range recorded.snapshot() {
+		if e.Type == EventAttemptFinished {
+			attempts = append(attempts, e.Attempt)
+		}
+	}
+	if len(attempts) != 2 || attempts[1] != 2 {
+		t.Errorf("Expected two numbered attempts but got %v", attempts)
+	}
+}
+
+func TestMultiEvents(t *testing.T) {
+	first, second := &recordedEvents{}, &recordedEvents{}
+	MultiEvents(first, second).Emit(Event{Type: EventCacheHit})
+
+	if len(first.events) != 1 || len(second.events) != 1 {
+		t.Error("Expected event to reach all consumers")
+	}
+}
@@ -0,0 +1,104 @@
+package http
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Flags provides feature flag values at request time, so client behavior can be toggled without redeploys.
+// Implementations adapt flag systems by returning a flag's current value for the request's context, e.g. its
+// tenant or user. Values are booleans ("true", "off", ...) or percentages ("25%") of requests.
+type Flags interface {
+	Flag(ctx context.Context, name string) (string, bool)
+}
+
+// FlagRetries toggles the client's RetryPolicy per request. Requests are retried unless the flag is disabled.
+const FlagRetries = "retries"
+
+// FlagsFunc adapts a function to the Flags interface.
+type FlagsFunc func(ctx context.Context, name string) (string, bool)
+
+func (f FlagsFunc) Flag(ctx context.Context, name string) (string, bool) {
+	return f(ctx, name)
+}
+
+// StaticFlags are fixed flag values, e.g. for tests or configuration files.
+type StaticFlags map[string]string
+
+func (f StaticFlags) Flag(ctx context.Context, name string) (string, bool) {
+	value, ok := f[name]
+	return value, ok
+}
+
+// WithFlags consults flags at request time for the behaviors toggled by WhenFlag, FlagEnabled and FlagRetries.
+func WithFlags(flags Flags) Option {
+	return func(c *HttpConfig) {
+		c.flags = flags
+	}
+}
+
+type flagsKey struct{}
+
+func (h *HttpClient) flags() Flags {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.config.flags
+}
+
+// FlagEnabled evaluates the flag name for ctx with the client's Flags, fallback if the flag is not set.
+func (h *HttpClient) FlagEnabled(ctx context.Context, name string, fallback bool) bool {
+	return flagEnabled(ctx, h.flags(), name, fallback)
+}
+
+// WhenFlag applies middleware only to requests for which the flag name of the client's Flags is enabled, e.g.
+// to roll out a new codec or mirror a percentage of traffic. Requests of clients without Flags, or for which
+// the flag is not set, skip the middleware.
+func WhenFlag(name string, middleware Middleware) Middleware {
+	return func(next RoundTripperFunc) RoundTripperFunc {
+		flagged := middleware(next)
+		return func(r *http.Request) (*http.Response, error) {
+			flags, _ := r.Context().Value(flagsKey{}).(Flags)
+			if flagEnabled(r.Context(), flags, name, false) {
+				return flagged(r)
+			}
+			return next(r)
+		}
+	}
+}
+
+// withFlags makes flags available to the middleware of requests executed with ctx.
+func withFlags(ctx context.Context, flags Flags) context.Context {
+	return context.WithValue(ctx, flagsKey{}, flags)
+}
+
+func flagEnabled(ctx context.Context, flags Flags, name string, fallback bool) bool {
+	if flags == nil {
+		return fallback
+	}
+	value, ok := flags.Flag(ctx, name)
+	if !ok {
+		return fallback
+	}
+
+	value = strings.TrimSpace(value)
+	if strings.HasSuffix(value, "%") {
+		percentage, err := strconv.ParseFloat(strings.TrimSpace(strings.TrimSuffix(value, "%")), 64)
+		if err != nil {
+			return fallback
+		}
+		return rand.Float64()*100 < percentage
+	}
+	switch strings.ToLower(value) {
+	case "on", "yes", "enabled":
+		return true
+	case "off", "no", "disabled":
+		return false
+	}
+	if enabled, err := strconv.ParseBool(value); err == nil {
+		return enabled
+	}
+	return fallback
+}
@@ -0,0 +1,85 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+)
+
+func TestWhenFlag(t *testing.T) {
+	server := mockServerWith(func(w http.ResponseWriter, r *http.Request) {})
+	defer server.Close()
+
+	flags := StaticFlags{"tagging": "on", "mirror": "0%", "broken": "maybe"}
+	client := NewHttpClientWithConfig(NewDefaultHttpConfig(server.URL, WithFlags(flags)))
+
+	applied := map[string]int{}
+	counting := func(name string) Middleware {
+		return WhenFlag(name, func(next RoundTripperFunc) RoundTripperFunc {
+			return func(r *http.Request) (*http.Response, error) {
+				applied[name]++
+				return next(r)
+			}
+		})
+	}
+	client.Use(counting("tagging"), counting("mirror"), counting("broken"), counting("unset"))
+
+	for i := 0; i < 3; i++ {
+		flags["mirror"] = map[bool]string{true: "100%", false: "0%"}[i == 2]
+		resp, err := client.Get(context.Background(), "/")
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+
+	if applied["tagging"] != 3 || applied["mirror"] != 1 || applied["broken"] != 0 || applied["unset"] != 0 {
+		t.Errorf("Unexpected flagged middleware calls %v", applied)
+	}
+}
+
+func TestHttpClient_FlagEnabled(t *testing.T) {
+	client := NewHttpClientWithConfig(NewDefaultHttpConfig(fixtureBaseURL, WithFlags(FlagsFunc(func(ctx context.Context, name string) (string, bool) {
+		tenant, _ := TenantFromContext(ctx)
+		return "true", tenant == "beta"
+	}))))
+
+	if client.FlagEnabled(context.Background(), "retries", false) {
+		t.Error("Expected the fallback for unset flags")
+	}
+	if !client.FlagEnabled(WithTenant(context.Background(), "beta"), "retries", false) {
+		t.Error("Expected the flag to be evaluated for the request's context")
+	}
+	if !createTestHTTPClient(fixtureBaseURL).FlagEnabled(context.Background(), "retries", true) {
+		t.Error("Expected the fallback for clients without flags")
+	}
+}
+
+func TestFlagRetries(t *testing.T) {
+	var calls int32
+	server := mockServerWith(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	defer server.Close()
+
+	flags := StaticFlags{FlagRetries: "off"}
+	policy := NewRetryPolicy(3).WithBackoff(noBackoff)
+	client := NewHttpClientWithConfig(NewDefaultHttpConfig(server.URL, WithRetryPolicy(policy), WithFlags(flags)))
+
+	for _, value := range []string{"off", "on"} {
+		flags[FlagRetries] = value
+		atomic.StoreInt32(&calls, 0)
+		resp, err := client.Get(context.Background(), "/")
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+
+		expected := map[string]int32{"off": 1, "on": 3}[value]
+		if calls != expected {
+			t.Errorf("Expected %d attempts with retries %s but got %d", expected, value, calls)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+const defaultFollowInterval = time.Second
+
+// FollowOptions configure FollowLocation.
+type FollowOptions struct {
+	// Poll fetches the resource until Done reports it complete, instead of once.
+	Poll bool
+	// Done reports whether a polled resource is complete, or an error to stop polling. By default a resource is
+	// complete once it is no longer answered with 202 Accepted. Status resources redirecting to the created
+	// resource with 303 See Other are followed before Done is asked.
+	Done func(resp *Response) (bool, error)
+	// Interval between polls, 1 second if zero. A longer Retry-After of the status resource is honored.
+	Interval time.Duration
+	// Clock the interval is waited with, the system clock if nil.
+	Clock Clock
+}
+
+// FollowLocation covers the asynchronous creation pattern of REST APIs: if resp is a 201 Created or 202 Accepted
+// with a Location header, it closes resp and gets the resource at the location, resolved against the request URL,
+// with the headers of the original request. With opts.Poll it keeps getting the resource until opts.Done
+// reports it complete or ctx is done:
+//
+//	resp, err := client.Post(ctx, "/exports", body)
+//	...
+//	export, err := client.FollowLocation(ctx, resp, http.FollowOptions{Poll: true, Interval: 5 * time.Second})
+//
+// Other responses are returned buffered but otherwise unchanged. Unsuccessful statuses of the resource are
+// reported as error, like UnauthorizedError or NotFoundError. Locations on another host are requested without
+// the headers the HeaderPolicy treats as sensitive and without the client's credentials.
+func (h *HttpClient) FollowLocation(ctx context.Context, resp *http.Response, opts FollowOptions) (*Response, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	location, err := resp.Location()
+	if (resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusAccepted) || err != nil {
+		return NewResponse(resp)
+	}
+	drainAndClose(resp.Body)
+
+	request, err := h.locationRequest(ctx, resp.Request, location.String())
+	if err != nil {
+		return nil, err
+	}
+
+	done := opts.Done
+	if done == nil {
+		done = func(resp *Response) (bool, error) {
+			return resp.StatusCode != http.StatusAccepted, nil
+		}
+	}
+	interval := opts.Interval
+	if interval <= 0 {
+		interval = defaultFollowInterval
+	}
+	clock := opts.Clock
+	if clock == nil {
+		clock = SystemClock()
+	}
+
+	for {
+		polled, err := h.ExecuteRequest(request)
+		if err != nil {
+			return nil, err
+		}
+		if ClassifyStatus(polled.StatusCode) != StatusClassSuccess {
+			return nil, h.responseError(polled)
+		}
+		current, err := NewResponse(polled)
+		if err != nil {
+			return nil, err
+		}
+		if !opts.Poll {
+			return current, nil
+		}
+		if complete, err := done(current); err != nil || complete {
+			return current, err
+		}
+
+		delay := interval
+		if after, ok := current.RetryAfter(); ok && after > delay {
+			delay = after
+		}
+		if err := clock.Sleep(ctx, delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// locationRequest creates the GET request of location with the headers of the request which created it.
+func (h *HttpClient) locationRequest(ctx context.Context, created *http.Request, location string) (*http.Request, error) {
+	request, err := http.NewRequest(http.MethodGet, location, nil)
+	if err != nil {
+		return nil, err
+	}
+	if created == nil {
+		return request.WithContext(ctx), nil
+	}
+
+	request.Header = created.Header.Clone()
+	request.Header.Del("Content-Type")
+	request.Header.Del(IdempotencyKeyHeader)
+	if request.URL.Host != created.URL.Host {
+		h.mu.RLock()
+		policy := h.config.effectiveHeaderPolicy()
+		h.mu.RUnlock()
+		for _, name := range policy.Sensitive() {
+			request.Header.Del(name)
+		}
+	} else {
+		request.Host = created.Host
+	}
+	return request.WithContext(ctx), nil
+}
@@ -0,0 +1,163 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFollowLocation_Created(t *testing.T) {
+	server := mockServerWith(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			w.Header().Set("Location", "/users/7")
+			w.WriteHeader(http.StatusCreated)
+			return
+		}
+		w.Write([]byte(`{"id": 7, "user": "` + r.Header.Get("X-Tenant") + `"}`))
+	})
+	defer server.Close()
+
+	client := NewDefaultHttpClient(server.URL)
+	request, _ := client.PostRequest("/users", strings.NewReader("{}"))
+	request.Header.Set("X-Tenant", "acme")
+	created, err := client.ExecuteRequest(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := client.FollowLocation(context.Background(), created, FollowOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Request.Method != http.MethodGet || resp.String() != `{"id": 7, "user": "acme"}` {
+		t.Errorf("Expected the created resource but got %s %s", resp.Request.Method, resp.String())
+	}
+}
+
+func TestFollowLocation_Poll(t *testing.T) {
+	var polls int32
+	server := mockServerWith(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/exports":
+			w.Header().Set("Location", "/operations/1")
+			w.WriteHeader(http.StatusAccepted)
+		case "/operations/1":
+			if atomic.AddInt32(&polls, 1) < 3 {
+				w.WriteHeader(http.StatusAccepted)
+				return
+			}
+			http.Redirect(w, r, "/exports/1", http.StatusSeeOther)
+		case "/exports/1":
+			w.Write([]byte("done"))
+		}
+	})
+	defer server.Close()
+
+	client := NewDefaultHttpClient(server.URL)
+	accepted, err := client.Post(context.Background(), "/exports", strings.NewReader("{}"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clock := NewFakeClock(time.Now())
+	result := make(chan *Response, 1)
+	go func() {
+		resp, err := client.FollowLocation(context.Background(), accepted, FollowOptions{Poll: true, Interval: time.Minute, Clock: clock})
+		if err != nil {
+			t.Error(err)
+		}
+		result <- resp
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	for i := 0; i < 2; i++ {
+		if err := clock.WaitForWaiters(ctx, 1); err != nil {
+			t.Fatal(err)
+		}
+		clock.Advance(time.Minute)
+	}
+
+	resp := <-result
+	if resp == nil || resp.String() != "done" || polls != 3 {
+		t.Errorf("Expected the finished export after 3 polls but got %v after %d", resp, polls)
+	}
+}
+
+func TestFollowLocation_Done(t *testing.T) {
+	var polls int32
+	server := mockServerWith(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&polls, 1) < 3 {
+			w.Write([]byte(`{"status": "running"}`))
+			return
+		}
+		w.Write([]byte(`{"status": "succeeded"}`))
+	})
+	defer server.Close()
+
+	client := NewDefaultHttpClient(server.URL)
+	accepted := &http.Response{StatusCode: http.StatusAccepted, Header: http.Header{"Location": {server.URL + "/jobs/1"}}, Body: http.NoBody}
+	done := func(resp *Response) (bool, error) {
+		status, err := resp.JSONPath("$.status")
+		return status == "succeeded", err
+	}
+
+	resp, err := client.FollowLocation(context.Background(), accepted, FollowOptions{Poll: true, Done: done, Interval: time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if polls != 3 || !strings.Contains(resp.String(), "succeeded") {
+		t.Errorf("Expected polling until succeeded but got %s after %d", resp.String(), polls)
+	}
+}
+
+func TestFollowLocation_OtherResponses(t *testing.T) {
+	server := mockServer(http.StatusOK, contentTypeJSON, `{"ok": true}`)
+	defer server.Close()
+
+	client := NewDefaultHttpClient(server.URL)
+	ok, err := client.Get(context.Background(), "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := client.FollowLocation(context.Background(), ok, FollowOptions{Poll: true})
+	if err != nil || resp.String() != `{"ok": true}` {
+		t.Errorf("Expected the response unchanged but got %v, %v", resp, err)
+	}
+}
+
+func TestFollowLocation_Failure(t *testing.T) {
+	server := mockServerWith(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	defer server.Close()
+
+	client := NewDefaultHttpClient(server.URL)
+	created := &http.Response{StatusCode: http.StatusCreated, Header: http.Header{"Location": {server.URL + "/gone"}}, Body: http.NoBody}
+	if _, err := client.FollowLocation(context.Background(), created, FollowOptions{}); err == nil {
+		t.Error("Expected NotFoundError")
+	} else if _, ok := err.(*NotFoundError); !ok {
+		t.Errorf("Expected NotFoundError but got %T", err)
+	}
+}
+
+func TestFollowLocation_OtherHostWithoutCredentials(t *testing.T) {
+	var authorization string
+	other := mockServerWith(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+	})
+	defer other.Close()
+
+	client := NewHttpClientWithConfig(NewDefaultHttpConfig(fixtureBaseURL, WithAuthProvider(BasicAuth(StaticCredentials("u", "secret")))))
+	created := &http.Response{StatusCode: http.StatusCreated, Header: http.Header{"Location": {other.URL + "/x"}}, Body: http.NoBody}
+	if _, err := client.FollowLocation(context.Background(), created, FollowOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if authorization != "" {
+		t.Errorf("Expected no credentials for another host but got %q", authorization)
+	}
+}
@@ -0,0 +1,127 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+)
+
+// FormCodec returns the Codec for application/x-www-form-urlencoded, which is registered by default.
+//
+// url.Values, map[string][]string and map[string]string are encoded as they are. Other values are converted
+// through JSON, so json tags apply, and nested objects and arrays are encoded in bracket notation like
+// card[number]=4242 and items[0][price]=7, as expected by Rails-style APIs. Decoding fills url.Values, or other
+// values through JSON with a string, or a list of strings for repeated keys, per key.
+func FormCodec() Codec {
+	return formCodec{}
+}
+
+type formCodec struct{}
+
+func (formCodec) ContentTypes() []string {
+	return []string{formType}
+}
+
+func (formCodec) Encode(v interface{}) ([]byte, error) {
+	switch values := v.(type) {
+	case url.Values:
+		return []byte(values.Encode()), nil
+	case map[string][]string:
+		return []byte(url.Values(values).Encode()), nil
+	case map[string]string:
+		form := url.Values{}
+		for key, value := range values {
+			form.Set(key, value)
+		}
+		return []byte(form.Encode()), nil
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	fields, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, &CodecError{Message: fmt.Sprintf("Form bodies must be objects, not %T.", v), ContentType: formType}
+	}
+
+	var pairs []string
+	for _, key := range sortedFieldNames(fields) {
+		pairs = appendFormPairs(pairs, key, fields[key])
+	}
+	var b bytes.Buffer
+	for i := 0; i < len(pairs); i += 2 {
+		if b.Len() > 0 {
+			b.WriteByte('&')
+		}
+		b.WriteString(url.QueryEscape(pairs[i]))
+		b.WriteByte('=')
+		b.WriteString(url.QueryEscape(pairs[i+1]))
+	}
+	return b.Bytes(), nil
+}
+
+// appendFormPairs appends the key and value pairs of value in bracket notation, keeping the order of arrays.
+func appendFormPairs(pairs []string, key string, value interface{}) []string {
+	switch v := value.(type) {
+	case nil:
+		return append(pairs, key, "")
+	case map[string]interface{}:
+		for _, k := range sortedFieldNames(v) {
+			pairs = appendFormPairs(pairs, key+"["+k+"]", v[k])
+		}
+		return pairs
+	case []interface{}:
+		for i, item := range v {
+			pairs = appendFormPairs(pairs, key+"["+strconv.Itoa(i)+"]", item)
+		}
+		return pairs
+	case string:
+		return append(pairs, key, v)
+	default:
+		return append(pairs, key, fmt.Sprint(v))
+	}
+}
+
+func sortedFieldNames(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (formCodec) Decode(data []byte, v interface{}) error {
+	values, err := url.ParseQuery(string(data))
+	if err != nil {
+		return err
+	}
+	if target, ok := v.(*url.Values); ok {
+		*target = values
+		return nil
+	}
+
+	fields := make(map[string]interface{}, len(values))
+	for key, list := range values {
+		if len(list) == 1 {
+			fields[key] = list[0]
+		} else {
+			fields[key] = list
+		}
+	}
+	converted, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(converted, v)
+}
@@ -0,0 +1,67 @@
+package http
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestFormCodec_Encode(t *testing.T) {
+	type card struct {
+		Number string `json:"number"`
+		CVC    int    `json:"cvc"`
+	}
+	body := struct {
+		Amount   int               `json:"amount"`
+		Card     card              `json:"card"`
+		Tags     []string          `json:"tags"`
+		Metadata map[string]string `json:"metadata,omitempty"`
+	}{Amount: 2000, Card: card{Number: "4242", CVC: 123}, Tags: []string{"a b", "c"}}
+
+	encoded, err := FormCodec().Encode(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "amount=2000&card%5Bcvc%5D=123&card%5Bnumber%5D=4242&tags%5B0%5D=a+b&tags%5B1%5D=c"
+	if string(encoded) != expected {
+		t.Errorf("Expected %s but got %s", expected, encoded)
+	}
+}
+
+func TestFormCodec_EncodeValues(t *testing.T) {
+	encoded, err := FormCodec().Encode(map[string]string{"b": "2", "a": "1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(encoded) != "a=1&b=2" {
+		t.Errorf("Unexpected form %s", encoded)
+	}
+
+	if _, err := FormCodec().Encode([]int{1}); err == nil {
+		t.Error("Expected arrays to be rejected")
+	}
+}
+
+func TestFormCodec_Decode(t *testing.T) {
+	var out struct {
+		Name string   `json:"name"`
+		Tags []string `json:"tags"`
+	}
+	if err := FormCodec().Decode([]byte("name=gopher&tags=a&tags=b"), &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Name != "gopher" || len(out.Tags) != 2 {
+		t.Errorf("Unexpected decoded form %+v", out)
+	}
+
+	var values url.Values
+	if err := FormCodec().Decode([]byte("a=1"), &values); err != nil || values.Get("a") != "1" {
+		t.Errorf("Expected url.Values to be filled but got %v, %v", values, err)
+	}
+}
+
+func TestFormCodec_Registered(t *testing.T) {
+	if _, ok := CodecFor("application/x-www-form-urlencoded; charset=utf-8"); !ok {
+		t.Error("Expected the form codec to be registered by default")
+	}
+}
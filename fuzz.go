@@ -0,0 +1,328 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// fuzzValues are the replacement values tried for query parameters, headers and JSON fields.
+// JSON fields additionally receive values of the wrong type.
+var fuzzValues = []string{
+	"",
+	"0",
+	"-1",
+	"99999999999999999999999999",
+	"1e309",
+	"NaN",
+	"true",
+	"null",
+	"' OR '1'='1",
+	"../../../../etc/passwd",
+	"<script>alert(1)</script>",
+	"%00",
+	"\u202e\u0000\uffff",
+	"{{7*7}}",
+	strings.Repeat("A", 64*1024),
+}
+
+var fuzzJSONValues = []interface{}{nil, true, -1, 1e308, []interface{}{}, map[string]interface{}{}}
+
+// FuzzFinding is a mutated request the server answered with a 5xx status, or not at all in time.
+type FuzzFinding struct {
+	// Mutation describes the change made to the template, e.g. `query "page" = "-1"`.
+	Mutation   string
+	Method     string
+	URL        string
+	Header     http.Header
+	Body       []byte
+	StatusCode int
+	Err        error
+}
+
+// Fuzzer sends randomly mutated variants of a template request to find inputs an API does not handle
+// gracefully. Each iteration changes one query parameter, header or JSON body field of the template:
+// it is replaced by an unusual value, a value of the wrong type, or removed.
+type Fuzzer struct {
+	client     *HttpClient
+	template   *http.Request
+	body       []byte
+	iterations int
+	seed       int64
+	timeout    time.Duration
+}
+
+// NewFuzzer creates a Fuzzer for template, sending 100 mutations with a 5 second timeout each.
+// The template's body is read and closed.
+func NewFuzzer(client *HttpClient, template *http.Request) (*Fuzzer, error) {
+	if client == nil {
+		panic("client is nil")
+	}
+	if template == nil {
+		panic("template is nil")
+	}
+
+	var body []byte
+	if template.Body != nil {
+		var err error
+		body, err = io.ReadAll(template.Body)
+		template.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("reading template body: %w", err)
+		}
+	}
+
+	return &Fuzzer{
+		client:     client,
+		template:   template,
+		body:       body,
+		iterations: 100,
+		seed:       time.Now().UnixNano(),
+		timeout:    5 * time.Second,
+	}, nil
+}
+
+// WithIterations sets the number of mutated requests to send.
+func (f *Fuzzer) WithIterations(iterations int) *Fuzzer {
+	f.iterations = iterations
+	return f
+}
+
+// WithSeed makes the sequence of mutations reproducible.
+func (f *Fuzzer) WithSeed(seed int64) *Fuzzer {
+	f.seed = seed
+	return f
+}
+
+// WithTimeout sets how long a single request may take before it is reported as a finding.
+func (f *Fuzzer) WithTimeout(timeout time.Duration) *Fuzzer {
+	f.timeout = timeout
+	return f
+}
+
+// Run sends the mutated requests one after another and returns the findings.
+func (f *Fuzzer) Run(ctx context.Context) ([]FuzzFinding, error) {
+	random := rand.New(rand.NewSource(f.seed))
+	targets := f.targets()
+	if len(targets) == 0 {
+		return nil, errors.New("template has no query parameters, headers or JSON fields to mutate")
+	}
+
+	var findings []FuzzFinding
+	for i := 0; i < f.iterations; i++ {
+		if err := ctx.Err(); err != nil {
+			return findings, err
+		}
+
+		finding, err := f.mutate(targets[random.Intn(len(targets))], random)
+		if err != nil {
+			return findings, err
+		}
+		if f.send(ctx, &finding) {
+			findings = append(findings, finding)
+		}
+	}
+	return findings, nil
+}
+
+// fuzzTarget is a mutable part of the template.
+type fuzzTarget struct {
+	kind string // "query", "header" or "json"
+	name string
+	path []interface{}
+}
+
+func (f *Fuzzer) targets() []fuzzTarget {
+	var targets []fuzzTarget
+
+	query := f.template.URL.Query()
+	for _, name := range sortedKeys(query) {
+		targets = append(targets, fuzzTarget{kind: "query", name: name})
+	}
+	for _, name := range sortedKeys(f.template.Header) {
+		targets = append(targets, fuzzTarget{kind: "header", name: name})
+	}
+
+	var document interface{}
+	if json.Unmarshal(f.body, &document) == nil {
+		collectJSONPaths(document, nil, func(path []interface{}) {
+			targets = append(targets, fuzzTarget{kind: "json", name: formatJSONPath(path), path: path})
+		})
+	}
+	return targets
+}
+
+func (f *Fuzzer) mutate(target fuzzTarget, random *rand.Rand) (FuzzFinding, error) {
+	u := *f.template.URL
+	header := f.template.Header.Clone()
+	body := f.body
+	remove := random.Intn(8) == 0
+	value := fuzzValues[random.Intn(len(fuzzValues))]
+
+	var mutation string
+	switch target.kind {
+	case "query":
+		query := u.Query()
+		if remove {
+			query.Del(target.name)
+		} else {
+			query.Set(target.name, value)
+		}
+		u.RawQuery = query.Encode()
+	case "header":
+		if remove {
+			header.Del(target.name)
+		} else {
+			// header values must not contain control characters
+			header.Set(target.name, strings.Map(dropControl, value))
+		}
+	case "json":
+		var document interface{}
+		json.Unmarshal(f.body, &document)
+
+		var replacement interface{} = value
+		if random.Intn(2) == 0 {
+			replacement = fuzzJSONValues[random.Intn(len(fuzzJSONValues))]
+		}
+		document = setJSONPath(document, target.path, replacement, remove)
+
+		var err error
+		if body, err = json.Marshal(document); err != nil {
+			return FuzzFinding{}, err
+		}
+		if !remove {
+			encoded, _ := json.Marshal(replacement)
+			mutation = fmt.Sprintf("json %q = %s", target.name, truncate(string(encoded)))
+		}
+	}
+	if mutation == "" {
+		if remove {
+			mutation = fmt.Sprintf("%s %q removed", target.kind, target.name)
+		} else {
+			mutation = fmt.Sprintf("%s %q = %q", target.kind, target.name, truncate(value))
+		}
+	}
+
+	return FuzzFinding{Mutation: mutation, Method: f.template.Method, URL: u.String(), Header: header, Body: body}, nil
+}
+
+// send executes the mutated request and reports whether the outcome is a finding.
+func (f *Fuzzer) send(ctx context.Context, finding *FuzzFinding) bool {
+	ctx, cancel := context.WithTimeout(ctx, f.timeout)
+	defer cancel()
+
+	request, err := http.NewRequest(finding.Method, finding.URL, bytes.NewReader(finding.Body))
+	if err != nil {
+		finding.Err = err
+		return false
+	}
+	request.Header = finding.Header.Clone()
+	request.Host = f.template.Host
+
+	resp, err := f.client.ExecuteRequest(request.WithContext(ctx))
+	if resp != nil {
+		drainAndClose(resp.Body)
+		finding.StatusCode = resp.StatusCode
+		return resp.StatusCode >= 500
+	}
+	finding.Err = err
+	return isTimeout(err)
+}
+
+func isTimeout(err error) bool {
+	var netErr net.Error
+	return errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout())
+}
+
+func collectJSONPaths(value interface{}, path []interface{}, visit func([]interface{})) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			child := append(append([]interface{}{}, path...), key)
+			visit(child)
+			collectJSONPaths(v[key], child, visit)
+		}
+	case []interface{}:
+		for i, element := range v {
+			child := append(append([]interface{}{}, path...), i)
+			visit(child)
+			collectJSONPaths(element, child, visit)
+		}
+	}
+}
+
+// setJSONPath replaces or removes the value at path and returns the modified document.
+func setJSONPath(document interface{}, path []interface{}, value interface{}, remove bool) interface{} {
+	if len(path) == 0 {
+		return value
+	}
+	switch v := document.(type) {
+	case map[string]interface{}:
+		key := path[0].(string)
+		if len(path) == 1 && remove {
+			delete(v, key)
+		} else {
+			v[key] = setJSONPath(v[key], path[1:], value, remove)
+		}
+	case []interface{}:
+		i := path[0].(int)
+		if len(path) == 1 && remove {
+			return append(v[:i], v[i+1:]...)
+		}
+		v[i] = setJSONPath(v[i], path[1:], value, remove)
+	}
+	return document
+}
+
+func formatJSONPath(path []interface{}) string {
+	var b strings.Builder
+	for _, element := range path {
+		switch e := element.(type) {
+		case string:
+			if b.Len() > 0 {
+				b.WriteByte('.')
+			}
+			b.WriteString(e)
+		case int:
+			fmt.Fprintf(&b, "[%d]", e)
+		}
+	}
+	return b.String()
+}
+
+func sortedKeys(values map[string][]string) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func dropControl(r rune) rune {
+	if r < 0x20 || r == 0x7f {
+		return -1
+	}
+	return r
+}
+
+func truncate(s string) string {
+	if len(s) > 40 {
+		return s[:40] + "..."
+	}
+	return s
+}
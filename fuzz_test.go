@@ -0,0 +1,103 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFuzzer_Run(t *testing.T) {
+	server := mockServerWith(func(w http.ResponseWriter, r *http.Request) {
+		var user struct {
+			Name string   `json:"name"`
+			Tags []string `json:"tags"`
+		}
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &user); err != nil {
+			// a robust API answers malformed input with 400
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if r.URL.Query().Get("page") == "-1" || len(user.Tags) == 0 {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	})
+	defer server.Close()
+
+	client := createTestHTTPClient(server.URL)
+	template, _ := client.PostRequest("/users?page=1", strings.NewReader(`{"name": "bob", "tags": ["admin"]}`))
+	template.Header.Set("X-Tenant", "acme")
+
+	fuzzer, err := NewFuzzer(client, template)
+	if err != nil {
+		t.Fatal(err)
+	}
+	findings, err := fuzzer.WithSeed(1).WithIterations(200).Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(findings) == 0 {
+		t.Fatal("Expected findings")
+	}
+	kinds := map[string]bool{}
+	for _, finding := range findings {
+		if finding.StatusCode != http.StatusInternalServerError {
+			t.Errorf("Unexpected finding %+v", finding)
+		}
+		kinds[strings.Fields(finding.Mutation)[0]] = true
+	}
+	if !kinds["query"] || !kinds["json"] {
+		t.Errorf("Expected query and json findings but got %v", kinds)
+	}
+}
+
+func TestFuzzer_ReportsTimeouts(t *testing.T) {
+	server := mockServerWith(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Tenant") == "" {
+			time.Sleep(200 * time.Millisecond)
+		}
+	})
+	defer server.Close()
+
+	client := createTestHTTPClient(server.URL)
+	template, _ := client.GetRequest("/")
+	template.Header.Set("X-Tenant", "acme")
+
+	fuzzer, _ := NewFuzzer(client, template)
+	findings, _ := fuzzer.WithSeed(1).WithIterations(40).WithTimeout(50 * time.Millisecond).Run(context.Background())
+
+	for _, finding := range findings {
+		if !isTimeout(finding.Err) || finding.Header.Get("X-Tenant") != "" {
+			t.Errorf("Unexpected finding %+v", finding)
+		}
+	}
+}
+
+func TestFuzzer_NothingToMutate(t *testing.T) {
+	template, _ := http.NewRequest(http.MethodGet, fixtureBaseURL, nil)
+	fuzzer, _ := NewFuzzer(createTestHTTPClient(fixtureBaseURL), template)
+
+	if _, err := fuzzer.Run(context.Background()); err == nil {
+		t.Error("Expected error for template without mutable parts")
+	}
+}
+
+func TestSetJSONPath(t *testing.T) {
+	var document interface{}
+	json.Unmarshal([]byte(`{"user": {"tags": ["a", "b"]}}`), &document)
+
+	document = setJSONPath(document, []interface{}{"user", "tags", 0}, nil, true)
+	encoded, _ := json.Marshal(document)
+
+	if string(encoded) != `{"user":{"tags":["b"]}}` {
+		t.Errorf("Unexpected document %s", encoded)
+	}
+	if formatJSONPath([]interface{}{"user", "tags", 0}) != "user.tags[0]" {
+		t.Errorf("Unexpected path %s", formatJSONPath([]interface{}{"user", "tags", 0}))
+	}
+}
@@ -0,0 +1,61 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"sync"
+)
+
+const defaultGetManyConcurrency = 8
+
+// GetManyOptions configure GetMany.
+type GetManyOptions struct {
+	// Concurrency bounds the requests in flight, 8 if zero.
+	Concurrency int
+	// FailFast cancels the remaining requests after the first error.
+	FailFast bool
+}
+
+// GetMany fetches paths concurrently and decodes each successful response into T. The results are in the order
+// of paths and carry their own errors, so callers can use the resources which could be fetched.
+func GetMany[T any](ctx context.Context, client *HttpClient, paths []string, opts GetManyOptions) []Result[T] {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultGetManyConcurrency
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]Result[T], len(paths))
+	slots := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, path := range paths {
+		if err := ctx.Err(); err != nil {
+			results[i].Err = err
+			continue
+		}
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			results[i].Err = ctx.Err()
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, path string) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			results[i] = Fetch[T](ctx, client, http.MethodGet, path, nil)
+			if results[i].Err != nil && opts.FailFast {
+				cancel()
+			}
+		}(i, path)
+	}
+	wg.Wait()
+	return results
+}
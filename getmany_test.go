@@ -0,0 +1,70 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGetMany(t *testing.T) {
+	var inflight, peak int32
+	server := mockServerWith(func(w http.ResponseWriter, r *http.Request) {
+		current := atomic.AddInt32(&inflight, 1)
+		defer atomic.AddInt32(&inflight, -1)
+		for {
+			seen := atomic.LoadInt32(&peak)
+			if current <= seen || atomic.CompareAndSwapInt32(&peak, seen, current) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+
+		if r.URL.Path == "/users/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"name": "` + r.URL.Path[len("/users/"):] + `"}`))
+	})
+	defer server.Close()
+
+	client := createTestHTTPClient(server.URL)
+	paths := []string{"/users/a", "/users/missing", "/users/b", "/users/c", "/users/d"}
+	results := GetMany[callUser](context.Background(), client, paths, GetManyOptions{Concurrency: 2})
+
+	var notFound *NotFoundError
+	if !errors.As(results[1].Err, &notFound) {
+		t.Errorf("Expected NotFoundError for the missing user but got %v", results[1].Err)
+	}
+	for i, name := range []string{"a", "", "b", "c", "d"} {
+		if name != "" && (results[i].Err != nil || results[i].Value.Name != name) {
+			t.Errorf("Expected user %s at %d but got %+v", name, i, results[i])
+		}
+	}
+	if peak > 2 {
+		t.Errorf("Expected at most 2 concurrent requests but got %d", peak)
+	}
+}
+
+func TestGetMany_FailFast(t *testing.T) {
+	server := mockServerWith(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		time.Sleep(20 * time.Millisecond)
+		w.Write([]byte(`{}`))
+	})
+	defer server.Close()
+
+	client := createTestHTTPClient(server.URL)
+	results := GetMany[callUser](context.Background(), client, []string{"/fail", "/a", "/b", "/c"}, GetManyOptions{Concurrency: 1, FailFast: true})
+
+	for _, result := range results[1:] {
+		if !errors.Is(result.Err, context.Canceled) {
+			t.Errorf("Expected the remaining requests to be canceled but got %v", result.Err)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package http
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// HandlerTransport returns a http.RoundTripper serving requests in-process by handler instead of sending them
+// over the network. Response bodies are streamed, so handlers flushing partial responses behave as over a socket.
+func HandlerTransport(handler http.Handler) http.RoundTripper {
+	if handler == nil {
+		panic("handler is nil")
+	}
+	return &handlerTransport{handler: handler}
+}
+
+// NewHandlerClient creates a HttpClient whose requests to baseURL are served by handler, e.g. to exercise the
+// full client pipeline against real handler code in tests without opening sockets.
+func NewHandlerClient(baseURL string, handler http.Handler) *HttpClient {
+	return NewHttpClientWithConfigAndClient(NewDefaultHttpConfig(baseURL), &http.Client{Transport: HandlerTransport(handler)})
+}
+
+type handlerTransport struct {
+	handler http.Handler
+}
+
+func (t *handlerTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	request := r.Clone(r.Context())
+	request.RequestURI = r.URL.RequestURI()
+	request.RemoteAddr = "127.0.0.1:0"
+	request.Proto, request.ProtoMajor, request.ProtoMinor = "HTTP/1.1", 1, 1
+	if request.Host == "" {
+		request.Host = r.URL.Host
+	}
+	if request.Body == nil {
+		request.Body = http.NoBody
+	}
+
+	body, bodyWriter := io.Pipe()
+	w := &pipeResponseWriter{header: make(http.Header), body: bodyWriter, ready: make(chan struct{})}
+
+	go func() {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				w.fail(fmt.Errorf("handler panicked: %v", recovered))
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+			bodyWriter.Close()
+		}()
+		t.handler.ServeHTTP(w, request)
+	}()
+
+	select {
+	case <-w.ready:
+	case <-r.Context().Done():
+		body.CloseWithError(r.Context().Err())
+		return nil, r.Context().Err()
+	}
+	if w.err != nil {
+		return nil, w.err
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", w.status, http.StatusText(w.status)),
+		StatusCode:    w.status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        w.sent,
+		Body:          body,
+		ContentLength: -1,
+		Request:       r,
+	}, nil
+}
+
+// pipeResponseWriter streams the response written by a handler through a pipe.
+type pipeResponseWriter struct {
+	header http.Header
+	body   *io.PipeWriter
+
+	once   sync.Once
+	ready  chan struct{}
+	status int
+	sent   http.Header
+	err    error
+}
+
+func (w *pipeResponseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *pipeResponseWriter) WriteHeader(status int) {
+	w.once.Do(func() {
+		w.status = status
+		w.sent = w.header.Clone()
+		close(w.ready)
+	})
+}
+
+func (w *pipeResponseWriter) Write(p []byte) (int, error) {
+	w.WriteHeader(http.StatusOK)
+	return w.body.Write(p)
+}
+
+// Flush implements http.Flusher, the written data is passed on right away anyway.
+func (w *pipeResponseWriter) Flush() {
+	w.WriteHeader(http.StatusOK)
+}
+
+func (w *pipeResponseWriter) fail(err error) {
+	w.once.Do(func() {
+		w.err = err
+		close(w.ready)
+	})
+	w.body.CloseWithError(err)
+}
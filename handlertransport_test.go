@@ -0,0 +1,77 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestHandlerClient(t *testing.T) {
+	handler := http.NewServeMux()
+	handler.HandleFunc("/echo", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", contentTypeJSON)
+		w.Header().Set("X-Host", r.Host)
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, string(body))
+	})
+
+	client := NewHandlerClient("http://api.example.test", handler)
+
+	resp, err := client.PostTo("/echo", strings.NewReader(fixtureBasicJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertResponseHasStatus(resp, http.StatusCreated, t)
+	if resp.Header.Get("X-Host") != "api.example.test" {
+		t.Errorf("Expected Host header of the base URL but got %s", resp.Header.Get("X-Host"))
+	}
+	assertResponseBodyIs(resp, fixtureBasicJSON, t)
+
+	resp, err = client.GetFrom("/missing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertResponseHasStatus(resp, http.StatusNotFound, t)
+	resp.Body.Close()
+}
+
+func TestHandlerTransport_Streaming(t *testing.T) {
+	proceed := make(chan struct{})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "first")
+		w.(http.Flusher).Flush()
+		<-proceed
+		fmt.Fprint(w, "second")
+	})
+
+	client := NewHandlerClient("http://api.example.test", handler)
+
+	resp, err := client.GetFrom("/")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first := make([]byte, 5)
+	if _, err := resp.Body.Read(first); err != nil || string(first) != "first" {
+		t.Errorf("Expected first chunk before the handler finished but got %q, %v", first, err)
+	}
+	close(proceed)
+	assertResponseBodyIs(resp, "second", t)
+}
+
+func TestHandlerTransport_Panic(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})
+
+	client := NewHandlerClient("http://api.example.test", handler)
+
+	if _, err := client.GetFromWithContext(context.Background(), "/"); err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Errorf("Expected handler panic to surface as error but got %v", err)
+	}
+}
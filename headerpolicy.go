@@ -0,0 +1,201 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// DefaultHopByHopHeaders are the headers that only concern a single connection and are removed from requests
+// before they are sent, see RFC 9110 section 7.6.1.
+var DefaultHopByHopHeaders = []string{
+	"Connection", "Keep-Alive", "Proxy-Connection", "Te", "Trailer", "Transfer-Encoding", "Upgrade",
+}
+
+// DefaultHeaderPolicy canonicalizes headers, removes DefaultHopByHopHeaders and treats DefaultRedactedHeaders
+// as sensitive.
+var DefaultHeaderPolicy = NewHeaderPolicy()
+
+// HeaderPolicyError is returned when a request sets a header forbidden by the HeaderPolicy.
+type HeaderPolicyError struct {
+	Message string
+	Header  string
+}
+
+func (e HeaderPolicyError) Error() string {
+	return e.Message
+}
+
+// HeaderPolicy controls how headers are cased, which are never sent and which are sensitive. Sensitive headers
+// are masked in errors, events and logs and removed from requests redirected to another host. The same policy
+// is applied by the RequestBuilder, the client's request execution and its logs.
+type HeaderPolicy struct {
+	casing    map[string]string
+	forbidden map[string]bool
+	sensitive map[string]bool
+}
+
+// NewHeaderPolicy creates a HeaderPolicy forbidding DefaultHopByHopHeaders and marking DefaultRedactedHeaders
+// as sensitive.
+func NewHeaderPolicy() *HeaderPolicy {
+	p := &HeaderPolicy{casing: map[string]string{}, forbidden: map[string]bool{}, sensitive: map[string]bool{}}
+	return p.WithForbidden(DefaultHopByHopHeaders...).WithSensitive(DefaultRedactedHeaders...)
+}
+
+// WithCasing sends the given headers with exactly the given casing, e.g. "X-API-Key" for servers that do not
+// treat header names case-insensitively. All other headers use the canonical MIME casing.
+func (p *HeaderPolicy) WithCasing(names ...string) *HeaderPolicy {
+	p = p.clone()
+	for _, name := range names {
+		p.casing[http.CanonicalHeaderKey(name)] = name
+	}
+	return p
+}
+
+// WithForbidden removes the given headers from every request.
+func (p *HeaderPolicy) WithForbidden(names ...string) *HeaderPolicy {
+	p = p.clone()
+	for _, name := range names {
+		p.forbidden[http.CanonicalHeaderKey(name)] = true
+	}
+	return p
+}
+
+// WithSensitive marks the given headers as sensitive.
+func (p *HeaderPolicy) WithSensitive(names ...string) *HeaderPolicy {
+	p = p.clone()
+	for _, name := range names {
+		p.sensitive[http.CanonicalHeaderKey(name)] = true
+	}
+	return p
+}
+
+// Canonical returns the name header is sent with.
+func (p *HeaderPolicy) Canonical(name string) string {
+	canonical := http.CanonicalHeaderKey(name)
+	if cased, ok := p.casing[canonical]; ok {
+		return cased
+	}
+	return canonical
+}
+
+// IsForbidden reports whether header is never sent.
+func (p *HeaderPolicy) IsForbidden(name string) bool {
+	return p.forbidden[http.CanonicalHeaderKey(name)]
+}
+
+// IsSensitive reports whether header is sensitive.
+func (p *HeaderPolicy) IsSensitive(name string) bool {
+	return p.sensitive[http.CanonicalHeaderKey(name)]
+}
+
+// Sensitive returns the names of the sensitive headers in canonical form.
+func (p *HeaderPolicy) Sensitive() []string {
+	names := make([]string, 0, len(p.sensitive))
+	for name := range p.sensitive {
+		names = append(names, name)
+	}
+	return names
+}
+
+// Apply rewrites header in place: names get their policy casing and forbidden headers are removed. The
+// Connection and Upgrade headers of a protocol upgrade are kept.
+func (p *HeaderPolicy) Apply(header http.Header) {
+	upgrade := header.Get("Upgrade") != "" && strings.EqualFold(header.Get("Connection"), "Upgrade")
+	for key, values := range header {
+		canonical := http.CanonicalHeaderKey(key)
+		if p.forbidden[canonical] && !(upgrade && (canonical == "Connection" || canonical == "Upgrade")) {
+			delete(header, key)
+			continue
+		}
+		if name := p.Canonical(key); name != key {
+			delete(header, key)
+			header[name] = append(header[name], values...)
+		}
+	}
+}
+
+// checkRedirect removes the sensitive headers from requests redirected to another host before deferring to
+// next, or to the default limit of 10 redirects if next is nil.
+func (p *HeaderPolicy) checkRedirect(next func(*http.Request, []*http.Request) error) func(*http.Request, []*http.Request) error {
+	return func(r *http.Request, via []*http.Request) error {
+		if len(via) > 0 && !strings.EqualFold(r.URL.Host, via[0].URL.Host) {
+			for key := range r.Header {
+				if p.IsSensitive(key) {
+					r.Header.Del(key)
+				}
+			}
+		}
+		if next != nil {
+			return next(r, via)
+		}
+		if len(via) >= 10 {
+			return errors.New("stopped after 10 redirects")
+		}
+		return nil
+	}
+}
+
+// validate returns a HeaderPolicyError for the first forbidden header of header.
+func (p *HeaderPolicy) validate(header http.Header) error {
+	for key := range header {
+		if p.IsForbidden(key) {
+			return &HeaderPolicyError{Message: fmt.Sprintf("Header %s must not be set.", p.Canonical(key)), Header: p.Canonical(key)}
+		}
+	}
+	return nil
+}
+
+func (p *HeaderPolicy) clone() *HeaderPolicy {
+	clone := &HeaderPolicy{
+		casing:    make(map[string]string, len(p.casing)),
+		forbidden: make(map[string]bool, len(p.forbidden)),
+		sensitive: make(map[string]bool, len(p.sensitive)),
+	}
+	for k, v := range p.casing {
+		clone.casing[k] = v
+	}
+	for k, v := range p.forbidden {
+		clone.forbidden[k] = v
+	}
+	for k, v := range p.sensitive {
+		clone.sensitive[k] = v
+	}
+	return clone
+}
+
+// WithHeaderPolicy applies policy to all requests of the client instead of DefaultHeaderPolicy. Headers passed
+// to WithRedactedHeaders are sensitive in addition to those of policy.
+func WithHeaderPolicy(policy *HeaderPolicy) Option {
+	if policy == nil {
+		panic("policy is nil")
+	}
+	return func(c *HttpConfig) {
+		c.headerPolicy = policy
+	}
+}
+
+// effectiveHeaderPolicy returns the effective policy of config, including the headers passed to WithRedactedHeaders.
+func (c *HttpConfig) effectiveHeaderPolicy() *HeaderPolicy {
+	policy := c.headerPolicy
+	if policy == nil {
+		policy = DefaultHeaderPolicy
+	}
+	if len(c.redactedHeaders) > 0 {
+		policy = policy.WithSensitive(c.redactedHeaders...)
+	}
+	return policy
+}
+
+// applyHeaderPolicy wraps next to send the headers of requests as policy demands, leaving the caller's request
+// untouched.
+func applyHeaderPolicy(next RoundTripperFunc, policy *HeaderPolicy) RoundTripperFunc {
+	return func(r *http.Request) (*http.Response, error) {
+		header := r.Header.Clone()
+		policy.Apply(header)
+		r = r.WithContext(r.Context())
+		r.Header = header
+		return next(r)
+	}
+}
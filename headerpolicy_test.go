@@ -0,0 +1,110 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestHeaderPolicyApply(t *testing.T) {
+	policy := NewHeaderPolicy().WithCasing("X-API-Key").WithForbidden("X-Debug")
+	header := http.Header{
+		"x-api-key":         {"secret"},
+		"content-type":      {"text/plain"},
+		"Connection":        {"keep-alive"},
+		"Transfer-Encoding": {"chunked"},
+		"X-Debug":           {"1"},
+	}
+	policy.Apply(header)
+
+	if len(header) != 2 || header["X-API-Key"][0] != "secret" || header["Content-Type"][0] != "text/plain" {
+		t.Errorf("Expected canonical headers without forbidden ones but got %v", header)
+	}
+
+	upgrade := http.Header{"Connection": {"Upgrade"}, "Upgrade": {"websocket"}}
+	policy.Apply(upgrade)
+	if len(upgrade) != 2 {
+		t.Errorf("Expected the headers of an upgrade to be kept but got %v", upgrade)
+	}
+
+	if DefaultHeaderPolicy.IsForbidden("X-Debug") {
+		t.Error("Expected WithForbidden not to change DefaultHeaderPolicy")
+	}
+}
+
+func TestHeaderPolicyRequestBuilder(t *testing.T) {
+	request, err := NewRequestBuilder().Get().Path("http://localhost/users").Header("x-request-id", "42").Build()
+	if err != nil || request.Header["X-Request-Id"][0] != "42" {
+		t.Errorf("Expected the canonical header to be set but got %v, %v", request, err)
+	}
+
+	_, err = NewRequestBuilder().Get().Path("http://localhost/users").Header("keep-alive", "timeout=5").Build()
+	var policyErr *HeaderPolicyError
+	if !errors.As(err, &policyErr) || policyErr.Header != "Keep-Alive" {
+		t.Errorf("Expected HeaderPolicyError but got %v", err)
+	}
+}
+
+func TestHeaderPolicyRedirect(t *testing.T) {
+	other := mockServerWith(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Tenant-Secret") != "" || r.Header.Get("Authorization") != "" {
+			t.Errorf("Expected sensitive headers to be removed on a cross-host redirect but got %v", r.Header)
+		}
+		if r.Header.Get("X-Request-Id") != "42" {
+			t.Errorf("Expected other headers to be kept but got %v", r.Header)
+		}
+	})
+	defer other.Close()
+
+	var sameHost http.Header
+	server := mockServerWith(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/away":
+			http.Redirect(w, r, other.URL+"/landing", http.StatusFound)
+		case "/here":
+			http.Redirect(w, r, "/landing", http.StatusFound)
+		default:
+			sameHost = r.Header
+		}
+	})
+	defer server.Close()
+
+	client := NewHttpClientWithConfig(NewDefaultHttpConfig(server.URL,
+		WithHeaderPolicy(NewHeaderPolicy().WithSensitive("X-Tenant-Secret"))))
+	client.Use(func(next RoundTripperFunc) RoundTripperFunc {
+		return func(r *http.Request) (*http.Response, error) {
+			r.Header.Set("X-Tenant-Secret", "s3cr3t")
+			r.Header.Set("X-Request-Id", "42")
+			r.Header.Set("Connection", "close")
+			return next(r)
+		}
+	})
+
+	for _, path := range []string{"/away", "/here"} {
+		resp, err := client.Get(context.Background(), path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+	if sameHost.Get("X-Tenant-Secret") != "s3cr3t" {
+		t.Errorf("Expected sensitive headers to be kept on a same-host redirect but got %v", sameHost)
+	}
+	if sameHost.Get("Connection") != "" {
+		t.Errorf("Expected hop-by-hop headers to be removed but got %v", sameHost)
+	}
+}
+
+func TestHeaderPolicyRedaction(t *testing.T) {
+	client := NewHttpClientWithConfig(NewDefaultHttpConfig("http://localhost",
+		WithHeaderPolicy(NewHeaderPolicy().WithSensitive("X-Tenant-Secret"))))
+
+	if value := client.redaction().headerValue("x-tenant-secret", "s3cr3t"); strings.Contains(value, "s3cr3t") {
+		t.Errorf("Expected sensitive header to be masked but got %q", value)
+	}
+	if !client.Config().HeaderPolicy().IsSensitive("Authorization") {
+		t.Error("Expected the default sensitive headers to be kept")
+	}
+}
@@ -0,0 +1,117 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// http2FallbackErrors are the HTTP/2 error codes after which a request is retried over HTTP/1.1. The bundled
+// HTTP/2 implementation does not export its error types, so they are recognized by their message.
+var http2FallbackErrors = []string{"REFUSED_STREAM", "GOAWAY", "HTTP_1_1_REQUIRED"}
+
+// WithHTTP2Fallback negotiates HTTP/2 with servers supporting it and retries requests failing with an HTTP/2
+// stream error, like REFUSED_STREAM or GOAWAY, once on a fresh HTTP/1.1 connection. Requests whose body cannot
+// be replayed, i.e. without GetBody, are not retried.
+func WithHTTP2Fallback() Option {
+	return func(c *HttpConfig) {
+		c.http2Fallback = true
+	}
+}
+
+// http2KeepAlive configures the health check pings of HTTP/2 connections.
+type http2KeepAlive struct {
+	readIdleTimeout time.Duration
+	pingTimeout     time.Duration
+}
+
+// WithHTTP2KeepAlive negotiates HTTP/2 with servers supporting it and pings connections no frame was received
+// on for readIdleTimeout, closing them if the ping is not answered within pingTimeout, 15 seconds if zero. This
+// detects connections silently dropped by NAT gateways or firewalls before a request fails on them.
+func WithHTTP2KeepAlive(readIdleTimeout time.Duration, pingTimeout time.Duration) Option {
+	return func(c *HttpConfig) {
+		c.http2KeepAlive = http2KeepAlive{readIdleTimeout: readIdleTimeout, pingTimeout: pingTimeout}
+	}
+}
+
+// http2Enabled reports whether the transport attempts HTTP/2 despite its custom dialer and TLS config.
+func (c *HttpConfig) http2Enabled() bool {
+	return c.http2Fallback || c.http2KeepAlive.readIdleTimeout > 0
+}
+
+// configureHTTP2 applies the HTTP/2 settings of config to t.
+func configureHTTP2(t *http.Transport, config *HttpConfig) {
+	t.ForceAttemptHTTP2 = config.http2Enabled()
+	if config.http2KeepAlive.readIdleTimeout <= 0 {
+		t.HTTP2 = nil
+		return
+	}
+	t.HTTP2 = &http.HTTP2Config{
+		SendPingTimeout: config.http2KeepAlive.readIdleTimeout,
+		PingTimeout:     config.http2KeepAlive.pingTimeout,
+	}
+}
+
+// http1Client returns a copy of client restricted to HTTP/1.1. Transports are cached per base transport, so
+// fallback connections are pooled as well.
+func (h *HttpClient) http1Client(client *http.Client) *http.Client {
+	base, ok := client.Transport.(*http.Transport)
+	if !ok {
+		return client
+	}
+
+	transport, ok := h.http1Transports.Load(base)
+	if !ok {
+		updated := base.Clone()
+		protocols := new(http.Protocols)
+		protocols.SetHTTP1(true)
+		updated.Protocols = protocols
+		updated.ForceAttemptHTTP2 = false
+		if updated.TLSClientConfig != nil {
+			// a TLS config offering h2 would still negotiate it
+			updated.TLSClientConfig = updated.TLSClientConfig.Clone()
+			updated.TLSClientConfig.NextProtos = []string{"http/1.1"}
+		}
+		transport, _ = h.http1Transports.LoadOrStore(base, updated)
+	}
+
+	copied := *client
+	copied.Transport = transport.(*http.Transport)
+	return &copied
+}
+
+// fallbackToHTTP1 wraps next to send requests failing with an HTTP/2 stream error again through http1.
+func fallbackToHTTP1(next RoundTripperFunc, http1 RoundTripperFunc) RoundTripperFunc {
+	return func(r *http.Request) (*http.Response, error) {
+		resp, err := next(r)
+		if err == nil || !isHTTP2StreamError(err) || r.Context().Err() != nil {
+			return resp, err
+		}
+
+		retry := r.Clone(r.Context())
+		if r.Body != nil && r.Body != http.NoBody {
+			if r.GetBody == nil {
+				return resp, err
+			}
+			body, bodyErr := r.GetBody()
+			if bodyErr != nil {
+				return resp, err
+			}
+			retry.Body = body
+		}
+		return http1(retry)
+	}
+}
+
+func isHTTP2StreamError(err error) bool {
+	message := err.Error()
+	if !strings.Contains(message, "http2") {
+		return false
+	}
+	for _, code := range http2FallbackErrors {
+		if strings.Contains(message, code) {
+			return true
+		}
+	}
+	return false
+}
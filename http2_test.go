@@ -0,0 +1,133 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestFallbackToHTTP1(t *testing.T) {
+	goAway := errors.New("http2: server sent GOAWAY and closed the connection; LastStreamID=1, ErrCode=NO_ERROR")
+
+	tests := []struct {
+		name     string
+		err      error
+		body     bool
+		replay   bool
+		fallback bool
+	}{
+		{name: "goaway", err: goAway, fallback: true},
+		{name: "refused stream", err: errors.New("stream error: stream ID 3; REFUSED_STREAM; http2"), fallback: true},
+		{name: "replayable body", err: goAway, body: true, replay: true, fallback: true},
+		{name: "unreplayable body", err: goAway, body: true},
+		{name: "other error", err: errors.New("connection refused")},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var fellBack bool
+			var sentBody string
+			next := fallbackToHTTP1(
+				func(r *http.Request) (*http.Response, error) { return nil, test.err },
+				func(r *http.Request) (*http.Response, error) {
+					fellBack = true
+					if r.Body != nil {
+						data, _ := io.ReadAll(r.Body)
+						sentBody = string(data)
+					}
+					return &http.Response{StatusCode: http.StatusOK}, nil
+				},
+			)
+
+			request, _ := http.NewRequest(http.MethodPost, "https://api.test/users", nil)
+			if test.body {
+				request.Body = io.NopCloser(bytes.NewReader([]byte("payload")))
+				if test.replay {
+					request.GetBody = func() (io.ReadCloser, error) {
+						return io.NopCloser(bytes.NewReader([]byte("payload"))), nil
+					}
+				}
+			}
+			_, err := next(request)
+
+			if fellBack != test.fallback {
+				t.Errorf("Expected fallback %t but got %t (%v)", test.fallback, fellBack, err)
+			}
+			if test.replay && sentBody != "payload" {
+				t.Errorf("Expected the body to be replayed but got %q", sentBody)
+			}
+		})
+	}
+}
+
+func TestWithHTTP2Fallback(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Proto", r.Proto)
+	}))
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	defer server.Close()
+
+	trusted := server.Client().Transport.(*http.Transport).TLSClientConfig
+	client := NewHttpClientWithConfig(NewDefaultHttpConfig(server.URL, WithTLSConfig(trusted), WithHTTP2Fallback()))
+
+	resp, err := client.Get(context.Background(), "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.Header.Get("X-Proto") != "HTTP/2.0" {
+		t.Errorf("Expected HTTP/2 to be negotiated but got %s", resp.Header.Get("X-Proto"))
+	}
+
+	resp, err = client.http1Client(client.client).Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.Header.Get("X-Proto") != "HTTP/1.1" {
+		t.Errorf("Expected the fallback to use HTTP/1.1 but got %s", resp.Header.Get("X-Proto"))
+	}
+}
+
+func TestWithHTTP2KeepAlive(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Proto", r.Proto)
+	}))
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	defer server.Close()
+
+	trusted := server.Client().Transport.(*http.Transport).TLSClientConfig
+	client := NewHttpClientWithConfig(NewDefaultHttpConfig(server.URL, WithTLSConfig(trusted),
+		WithHTTP2KeepAlive(30*time.Second, 5*time.Second)))
+
+	transport := client.client.Transport.(*http.Transport)
+	if transport.HTTP2 == nil || transport.HTTP2.SendPingTimeout != 30*time.Second || transport.HTTP2.PingTimeout != 5*time.Second {
+		t.Errorf("Expected the ping settings on the transport but got %+v", transport.HTTP2)
+	}
+
+	resp, err := client.Get(context.Background(), "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.Header.Get("X-Proto") != "HTTP/2.0" {
+		t.Errorf("Expected HTTP/2 to be negotiated but got %s", resp.Header.Get("X-Proto"))
+	}
+
+	if err := client.Reconfigure(WithHTTP2KeepAlive(0, 0)); err != nil {
+		t.Fatal(err)
+	}
+	if transport := client.client.Transport.(*http.Transport); transport.HTTP2 != nil || transport.ForceAttemptHTTP2 {
+		t.Errorf("Expected the ping settings to be removed but got %+v", transport.HTTP2)
+	}
+
+	if err := NewDefaultHttpConfig(server.URL, WithHTTP2KeepAlive(-time.Second, 0)).Validate(); err == nil {
+		t.Error("Expected negative timeouts to be rejected")
+	}
+}
@@ -0,0 +1,49 @@
+package http
+
+import (
+	"crypto/rand"
+	"fmt"
+	"net/http"
+)
+
+// IdempotencyKeyHeader is the header carrying the key the server recognizes repeated requests by.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// WithIdempotencyKeys sends POST and PATCH requests with a random Idempotency-Key header unless they have one,
+// so the server performs them only once even if they are sent again. The key is kept for all attempts of a
+// request, which makes them safe to retry with the client's RetryPolicy.
+func WithIdempotencyKeys() Option {
+	return func(c *HttpConfig) {
+		c.idempotencyKeys = true
+	}
+}
+
+// NewIdempotencyKey returns a random version 4 UUID to be used as idempotency key.
+func NewIdempotencyKey() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
+
+// applyIdempotencyKey returns a copy of r with a new idempotency key if it needs one.
+func applyIdempotencyKey(r *http.Request) (*http.Request, error) {
+	if r.Method != http.MethodPost && r.Method != http.MethodPatch {
+		return r, nil
+	}
+	if r.Header.Get(IdempotencyKeyHeader) != "" {
+		return r, nil
+	}
+
+	key, err := NewIdempotencyKey()
+	if err != nil {
+		return nil, err
+	}
+	r = r.WithContext(r.Context())
+	r.Header = r.Header.Clone()
+	r.Header.Set(IdempotencyKeyHeader, key)
+	return r, nil
+}
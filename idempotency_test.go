@@ -0,0 +1,78 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestNewIdempotencyKey(t *testing.T) {
+	key, err := NewIdempotencyKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`).MatchString(key) {
+		t.Errorf("Expected a version 4 UUID but got %s", key)
+	}
+}
+
+func TestWithIdempotencyKeys(t *testing.T) {
+	var mu sync.Mutex
+	keys := map[string][]string{}
+	server := mockServerWith(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		key := r.Header.Get(IdempotencyKeyHeader)
+		keys[r.Method] = append(keys[r.Method], key)
+		if r.Method == http.MethodPost && len(keys[r.Method]) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	})
+	defer server.Close()
+
+	policy := NewRetryPolicy(2).WithBackoff(noBackoff)
+	client := NewHttpClientWithConfig(NewDefaultHttpConfig(server.URL, WithIdempotencyKeys(), WithRetryPolicy(policy)))
+
+	resp, err := client.Post(context.Background(), "/", strings.NewReader("{}"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	resp, err = client.Get(context.Background(), "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	posts := keys[http.MethodPost]
+	if len(posts) != 2 || posts[0] == "" || posts[0] != posts[1] {
+		t.Errorf("Expected both attempts of the POST to share a key but got %q", posts)
+	}
+	if gets := keys[http.MethodGet]; len(gets) != 1 || gets[0] != "" {
+		t.Errorf("Expected GET without key but got %q", gets)
+	}
+}
+
+func TestWithIdempotencyKeys_KeepsKey(t *testing.T) {
+	var received string
+	server := mockServerWith(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Get(IdempotencyKeyHeader)
+	})
+	defer server.Close()
+
+	client := NewHttpClientWithConfig(NewDefaultHttpConfig(server.URL, WithIdempotencyKeys()))
+	request, _ := client.PostRequest("/", strings.NewReader("{}"))
+	request.Header.Set(IdempotencyKeyHeader, "order-1")
+	resp, err := client.ExecuteRequest(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if received != "order-1" {
+		t.Errorf("Expected the caller's key but got %q", received)
+	}
+}
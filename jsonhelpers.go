@@ -0,0 +1,60 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+)
+
+// GetJSON gets path and decodes the JSON response into out, applying the client's response transformers and
+// envelope like DecodeResponse. The body is closed in any case, and discarded if out is nil. Unsuccessful
+// statuses are reported as error, like UnauthorizedError or NotFoundError.
+func (h *HttpClient) GetJSON(ctx context.Context, path string, out interface{}) error {
+	return h.sendEncoded(ctx, http.MethodGet, path, JSONCodec(), nil, out)
+}
+
+// PostJSON posts in encoded as JSON to path and decodes the JSON response into out like GetJSON. A nil in is
+// sent without body.
+func (h *HttpClient) PostJSON(ctx context.Context, path string, in interface{}, out interface{}) error {
+	return h.sendEncoded(ctx, http.MethodPost, path, JSONCodec(), in, out)
+}
+
+// sendEncoded sends in encoded with codec and decodes the response into out with codec, regardless of the
+// content types the client negotiates.
+func (h *HttpClient) sendEncoded(ctx context.Context, method string, path string, codec Codec, in interface{}, out interface{}) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	contentType := codec.ContentTypes()[0]
+	spec := Spec{Method: method, Path: path, Headers: http.Header{"Accept": {contentType}}}
+	var body io.Reader
+	if in != nil {
+		encoded, err := codec.Encode(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(encoded)
+		spec.Headers.Set("Content-Type", contentType)
+	}
+
+	resp, err := h.sendSpec(ctx, spec, body)
+	if err != nil {
+		return err
+	}
+	if out == nil {
+		drainAndClose(resp.Body)
+		return nil
+	}
+	if contentType == jsonType {
+		// JSON responses may be wrapped in the client's envelope
+		return h.DecodeResponse(resp, out)
+	}
+
+	data, err := h.transformedBody(resp)
+	if err != nil || len(bytes.TrimSpace(data)) == 0 {
+		return err
+	}
+	return codec.Decode(data, out)
+}
@@ -0,0 +1,62 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestHttpClient_GetJSON(t *testing.T) {
+	server := mockServer(http.StatusOK, contentTypeJSON, `{"name": "gopher"}`)
+	defer server.Close()
+
+	var out struct {
+		Name string `json:"name"`
+	}
+	if err := NewDefaultHttpClient(server.URL).GetJSON(context.Background(), "/", &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Name != "gopher" {
+		t.Errorf("Unexpected value %+v", out)
+	}
+}
+
+func TestHttpClient_GetJSON_Status(t *testing.T) {
+	server := mockServer(http.StatusNotFound, contentTypeJSON, `{}`)
+	defer server.Close()
+
+	var out map[string]interface{}
+	err := NewDefaultHttpClient(server.URL).GetJSON(context.Background(), "/", &out)
+	if _, ok := err.(*NotFoundError); !ok {
+		t.Errorf("Expected NotFoundError but got %v", err)
+	}
+}
+
+func TestHttpClient_PostJSON(t *testing.T) {
+	var contentType string
+	server := mockServerWith(func(w http.ResponseWriter, r *http.Request) {
+		contentType = r.Header.Get("Content-Type")
+		var in map[string]int
+		json.NewDecoder(r.Body).Decode(&in)
+		w.Header().Set("Content-Type", contentTypeJSON)
+		json.NewEncoder(w).Encode(map[string]int{"sum": in["a"] + in["b"]})
+	})
+	defer server.Close()
+
+	// the form encoding of the profile does not apply to PostJSON
+	client := NewHttpClientWithConfig(NewDefaultHttpConfig(server.URL, WithProfile(ProfileStripeStyle)))
+	var out struct {
+		Sum int `json:"sum"`
+	}
+	if err := client.PostJSON(context.Background(), "/", map[string]int{"a": 1, "b": 2}, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Sum != 3 || contentType != jsonType {
+		t.Errorf("Expected sum 3 of JSON body but got %d of %s", out.Sum, contentType)
+	}
+
+	if err := client.PostJSON(context.Background(), "/", nil, nil); err != nil {
+		t.Errorf("Expected response to be discarded but got %v", err)
+	}
+}
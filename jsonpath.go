@@ -0,0 +1,146 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// JSONPathError is returned when a JSONPath expression is invalid or does not match the document.
+type JSONPathError struct {
+	Message string
+	Path    string
+}
+
+func (e JSONPathError) Error() string {
+	return e.Message
+}
+
+type jsonPathStep struct {
+	key      string
+	index    int
+	isIndex  bool
+	wildcard bool
+}
+
+// ExtractJSONPath returns the value at path in the JSON document data. Supported are the root $,
+// child access by .name or ['name'], array indexes including negative ones ([0], [-1]) and
+// wildcards (.* or [*]). Paths with wildcards return a slice of all matches.
+func ExtractJSONPath(data []byte, path string) (interface{}, error) {
+	steps, err := parseJSONPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var document interface{}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, err
+	}
+
+	nodes := []interface{}{document}
+	wildcard := false
+	for _, step := range steps {
+		wildcard = wildcard || step.wildcard
+
+		var next []interface{}
+		for _, node := range nodes {
+			next = append(next, step.apply(node)...)
+		}
+		if len(next) == 0 && !wildcard {
+			return nil, &JSONPathError{Message: fmt.Sprintf("JSONPath %s does not match.", path), Path: path}
+		}
+		nodes = next
+	}
+
+	if wildcard {
+		if nodes == nil {
+			nodes = []interface{}{}
+		}
+		return nodes, nil
+	}
+	return nodes[0], nil
+}
+
+func (s jsonPathStep) apply(node interface{}) []interface{} {
+	switch v := node.(type) {
+	case map[string]interface{}:
+		if s.wildcard {
+			values := make([]interface{}, 0, len(v))
+			for _, value := range v {
+				values = append(values, value)
+			}
+			return values
+		}
+		if value, ok := v[s.key]; ok && !s.isIndex {
+			return []interface{}{value}
+		}
+	case []interface{}:
+		if s.wildcard {
+			return v
+		}
+		if s.isIndex {
+			index := s.index
+			if index < 0 {
+				index += len(v)
+			}
+			if index >= 0 && index < len(v) {
+				return []interface{}{v[index]}
+			}
+		}
+	}
+	return nil
+}
+
+func parseJSONPath(path string) ([]jsonPathStep, error) {
+	invalid := func() error {
+		return &JSONPathError{Message: fmt.Sprintf("Invalid JSONPath %s.", path), Path: path}
+	}
+
+	if !strings.HasPrefix(path, "$") {
+		return nil, invalid()
+	}
+
+	var steps []jsonPathStep
+	rest := path[1:]
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			name := rest[:end]
+			if name == "" {
+				return nil, invalid()
+			}
+			steps = append(steps, jsonPathStep{key: name, wildcard: name == "*"})
+			rest = rest[end:]
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, invalid()
+			}
+			selector := strings.TrimSpace(rest[1:end])
+			rest = rest[end+1:]
+
+			switch {
+			case selector == "*":
+				steps = append(steps, jsonPathStep{wildcard: true})
+			case len(selector) >= 2 && (selector[0] == '\'' || selector[0] == '"') && selector[len(selector)-1] == selector[0]:
+				steps = append(steps, jsonPathStep{key: selector[1 : len(selector)-1]})
+			default:
+				index, err := strconv.Atoi(selector)
+				if err != nil {
+					return nil, invalid()
+				}
+				steps = append(steps, jsonPathStep{index: index, isIndex: true})
+			}
+		default:
+			return nil, invalid()
+		}
+	}
+
+	return steps, nil
+}
@@ -0,0 +1,54 @@
+package http
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+const fixtureItemsJSON = `{"items": [{"id": 1, "name": "a"}, {"id": 2, "name": "b"}], "meta": {"total-count": 2}}`
+
+func TestExtractJSONPath(t *testing.T) {
+	cases := map[string]interface{}{
+		"$.items[0].id":            float64(1),
+		"$.items[-1].name":         "b",
+		"$['meta']['total-count']": float64(2),
+		"$.items[*].id":            []interface{}{float64(1), float64(2)},
+	}
+
+	for path, expected := range cases {
+		actual, err := ExtractJSONPath([]byte(fixtureItemsJSON), path)
+		if err != nil {
+			t.Errorf("Unexpected error %v for %s", err, path)
+			continue
+		}
+		if !reflect.DeepEqual(actual, expected) {
+			t.Errorf("Expected %v but got %v for %s", expected, actual, path)
+		}
+	}
+}
+
+func TestExtractJSONPath_Errors(t *testing.T) {
+	for _, path := range []string{"items", "$.items[x]", "$.items[5]", "$.missing"} {
+		if _, err := ExtractJSONPath([]byte(fixtureItemsJSON), path); err == nil {
+			t.Errorf("Expected error for %s", path)
+		}
+	}
+}
+
+func TestResponse_JSONPath(t *testing.T) {
+	server := mockServer(http.StatusOK, contentTypeJSON, fixtureItemsJSON)
+	defer server.Close()
+
+	resp, _ := createTestHTTPClient(server.URL).GetFrom("")
+	wrapped, err := NewResponse(resp)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	id, err := wrapped.JSONPath("$.items[1].id")
+	if err != nil || id != float64(2) {
+		t.Errorf("Expected 2 but got %v, %v", id, err)
+	}
+	assertResponseBodyIs(wrapped.Response, fixtureItemsJSON, t)
+}
@@ -0,0 +1,240 @@
+package http
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	defaultAssertionLifetime = 5 * time.Minute
+
+	jwtBearerGrantType     = "urn:ietf:params:oauth:grant-type:jwt-bearer"
+	jwtClientAssertionType = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+	serviceAccountKeyType  = "service_account"
+)
+
+// JWTConfig describes the JWT assertions signed for a token endpoint.
+// Key must be an *rsa.PrivateKey (RS256) or an *ecdsa.PrivateKey on P-256 (ES256).
+type JWTConfig struct {
+	Issuer   string
+	Subject  string
+	Audience string
+	Scopes   []string
+	KeyID    string
+	Key      crypto.Signer
+	Lifetime time.Duration
+}
+
+// NewJWTBearerSource creates a TokenSource using the JWT bearer grant (RFC 7523), as used by
+// Google service accounts. The audience defaults to the token endpoint.
+func NewJWTBearerSource(client *HttpClient, tokenEndpoint string, config JWTConfig) *TokenSource {
+	source := newTokenSource(client, tokenEndpoint, "", "")
+	source.params = func() (url.Values, error) {
+		claims := config.claims(tokenEndpoint)
+		if len(config.Scopes) > 0 {
+			claims["scope"] = strings.Join(config.Scopes, " ")
+		}
+
+		assertion, err := SignJWT(claims, config.Key, config.KeyID)
+		if err != nil {
+			return nil, err
+		}
+		return url.Values{"grant_type": {jwtBearerGrantType}, "assertion": {assertion}}, nil
+	}
+	return source
+}
+
+// NewPrivateKeyJWTSource creates a TokenSource using the client credentials grant, authenticating
+// the client with a signed assertion (private_key_jwt) instead of a secret. The issuer and subject
+// default to clientID.
+func NewPrivateKeyJWTSource(client *HttpClient, tokenEndpoint string, clientID string, config JWTConfig) *TokenSource {
+	if config.Issuer == "" {
+		config.Issuer = clientID
+	}
+	if config.Subject == "" {
+		config.Subject = clientID
+	}
+
+	source := newTokenSource(client, tokenEndpoint, clientID, "")
+	source.params = func() (url.Values, error) {
+		assertion, err := SignJWT(config.claims(tokenEndpoint), config.Key, config.KeyID)
+		if err != nil {
+			return nil, err
+		}
+
+		params := url.Values{
+			"grant_type":            {"client_credentials"},
+			"client_assertion_type": {jwtClientAssertionType},
+			"client_assertion":      {assertion},
+		}
+		if len(config.Scopes) > 0 {
+			params.Set("scope", strings.Join(config.Scopes, " "))
+		}
+		return params, nil
+	}
+	return source
+}
+
+// NewServiceAccountSource creates a JWT bearer TokenSource from a Google service account key file.
+func NewServiceAccountSource(client *HttpClient, keyJSON []byte, scopes ...string) (*TokenSource, error) {
+	var key struct {
+		Type         string `json:"type"`
+		ClientEmail  string `json:"client_email"`
+		PrivateKey   string `json:"private_key"`
+		PrivateKeyID string `json:"private_key_id"`
+		TokenURI     string `json:"token_uri"`
+	}
+	if err := json.Unmarshal(keyJSON, &key); err != nil {
+		return nil, err
+	}
+	if key.Type != serviceAccountKeyType {
+		return nil, fmt.Errorf("unsupported key type %q", key.Type)
+	}
+
+	signer, err := ParsePrivateKeyPEM([]byte(key.PrivateKey))
+	if err != nil {
+		return nil, err
+	}
+
+	return NewJWTBearerSource(client, key.TokenURI, JWTConfig{
+		Issuer: key.ClientEmail,
+		Scopes: scopes,
+		KeyID:  key.PrivateKeyID,
+		Key:    signer,
+	}), nil
+}
+
+// JWTAuth returns an AuthProvider sending the tokens of source as bearer tokens, requesting a new
+// token only when the cached one expired.
+func JWTAuth(source *TokenSource) AuthProvider {
+	return BearerAuth(CachedCredentials(source, 0))
+}
+
+// SignJWT signs claims with key as a compact JWT, using RS256 for RSA and ES256 for ECDSA keys.
+func SignJWT(claims map[string]interface{}, key crypto.Signer, keyID string) (string, error) {
+	var alg string
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		alg = "RS256"
+	case *ecdsa.PrivateKey:
+		if k.Curve.Params().BitSize != 256 {
+			return "", errors.New("ES256 requires a P-256 key")
+		}
+		alg = "ES256"
+	default:
+		return "", fmt.Errorf("unsupported key type %T", key)
+	}
+
+	header := map[string]string{"alg": alg, "typ": "JWT"}
+	if keyID != "" {
+		header["kid"] = keyID
+	}
+
+	encodedHeader, err := encodeJWTPart(header)
+	if err != nil {
+		return "", err
+	}
+	encodedClaims, err := encodeJWTPart(claims)
+	if err != nil {
+		return "", err
+	}
+
+	signingInput := encodedHeader + "." + encodedClaims
+	digest := sha256.Sum256([]byte(signingInput))
+
+	var signature []byte
+	if ecKey, ok := key.(*ecdsa.PrivateKey); ok {
+		// JWS uses the fixed size r || s encoding instead of ASN.1
+		r, s, err := ecdsa.Sign(rand.Reader, ecKey, digest[:])
+		if err != nil {
+			return "", err
+		}
+		signature = append(padBigInt(r, 32), padBigInt(s, 32)...)
+	} else {
+		signature, err = key.Sign(rand.Reader, digest[:], crypto.SHA256)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// ParsePrivateKeyPEM parses a PKCS#1, PKCS#8 or SEC 1 PEM encoded RSA or ECDSA private key.
+func ParsePrivateKeyPEM(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		if signer, ok := key.(crypto.Signer); ok {
+			return signer, nil
+		}
+		return nil, fmt.Errorf("unsupported key type %T", key)
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	return x509.ParseECPrivateKey(block.Bytes)
+}
+
+func (c JWTConfig) claims(tokenEndpoint string) map[string]interface{} {
+	lifetime := c.Lifetime
+	if lifetime <= 0 {
+		lifetime = defaultAssertionLifetime
+	}
+	audience := c.Audience
+	if audience == "" {
+		audience = tokenEndpoint
+	}
+
+	now := time.Now()
+	claims := map[string]interface{}{
+		"iss": c.Issuer,
+		"aud": audience,
+		"iat": now.Unix(),
+		"exp": now.Add(lifetime).Unix(),
+		"jti": newJWTID(),
+	}
+	if c.Subject != "" {
+		claims["sub"] = c.Subject
+	}
+	return claims
+}
+
+func encodeJWTPart(v interface{}) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func padBigInt(i *big.Int, size int) []byte {
+	b := i.Bytes()
+	if len(b) >= size {
+		return b
+	}
+	return append(make([]byte, size-len(b)), b...)
+}
+
+func newJWTID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,118 @@
+package http
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestSignJWT_RS256(t *testing.T) {
+	key, _ := rsa.GenerateKey(rand.Reader, 2048)
+
+	token, err := SignJWT(map[string]interface{}{"iss": "client"}, key, "key-1")
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	parts := strings.Split(token, ".")
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	signature, _ := base64.RawURLEncoding.DecodeString(parts[2])
+	if err := rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest[:], signature); err != nil {
+		t.Errorf("Expected valid RS256 signature but got %v", err)
+	}
+
+	header, _ := base64.RawURLEncoding.DecodeString(parts[0])
+	if !strings.Contains(string(header), `"kid":"key-1"`) {
+		t.Errorf("Expected kid in header but got %s", header)
+	}
+}
+
+func TestSignJWT_ES256(t *testing.T) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	token, err := SignJWT(map[string]interface{}{"iss": "client"}, key, "")
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	parts := strings.Split(token, ".")
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	signature, _ := base64.RawURLEncoding.DecodeString(parts[2])
+	r, s := new(big.Int).SetBytes(signature[:32]), new(big.Int).SetBytes(signature[32:])
+	if len(signature) != 64 || !ecdsa.Verify(&key.PublicKey, digest[:], r, s) {
+		t.Error("Expected valid ES256 signature")
+	}
+}
+
+func TestNewServiceAccountSource(t *testing.T) {
+	var grantType string
+	var claims map[string]interface{}
+	f := func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		grantType = r.Form.Get("grant_type")
+		payload, _ := base64.RawURLEncoding.DecodeString(strings.Split(r.Form.Get("assertion"), ".")[1])
+		json.Unmarshal(payload, &claims)
+		fmt.Fprint(w, `{"access_token": "token", "expires_in": 3600}`)
+	}
+	server := mockServerWith(http.HandlerFunc(f))
+	defer server.Close()
+
+	key, _ := rsa.GenerateKey(rand.Reader, 2048)
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	keyJSON, _ := json.Marshal(map[string]string{
+		"type":         "service_account",
+		"client_email": "robot@project.iam.gserviceaccount.com",
+		"private_key":  string(keyPEM),
+		"token_uri":    server.URL,
+	})
+
+	source, err := NewServiceAccountSource(createTestHTTPClient(server.URL), keyJSON, "scope-a", "scope-b")
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	credentials, err := source.Credentials(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	if credentials.Token != "token" || grantType != jwtBearerGrantType {
+		t.Errorf("Expected token from jwt-bearer grant but got %s with %s", credentials.Token, grantType)
+	}
+	if claims["iss"] != "robot@project.iam.gserviceaccount.com" || claims["aud"] != server.URL || claims["scope"] != "scope-a scope-b" {
+		t.Errorf("Unexpected assertion claims %v", claims)
+	}
+}
+
+func TestNewPrivateKeyJWTSource(t *testing.T) {
+	var assertionType, clientID string
+	f := func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		assertionType, clientID = r.Form.Get("client_assertion_type"), r.Form.Get("client_id")
+		fmt.Fprint(w, `{"access_token": "token"}`)
+	}
+	server := mockServerWith(http.HandlerFunc(f))
+	defer server.Close()
+
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	source := NewPrivateKeyJWTSource(createTestHTTPClient(server.URL), server.URL, "client", JWTConfig{Key: key})
+
+	if _, err := source.Credentials(context.Background()); err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if assertionType != jwtClientAssertionType || clientID != "client" {
+		t.Errorf("Expected private_key_jwt client authentication but got %s for %s", assertionType, clientID)
+	}
+}
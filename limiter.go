@@ -0,0 +1,162 @@
+package http
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	defaultLimiterBackoffRatio = 0.9
+	defaultLimiterTolerance    = 2.0
+	// limiterSmoothing weighs a new latency sample in the long-term baseline
+	limiterSmoothing = 0.05
+	// limiterSlowSmoothing weighs a slow sample, so the baseline follows a lasting latency step more slowly
+	limiterSlowSmoothing = 0.02
+	defaultLimiterMin    = 1
+	defaultLimiterMax    = 1000
+)
+
+// OverloadError is returned when the AdaptiveLimiter sheds a request because the concurrency limit is reached.
+type OverloadError struct {
+	Message string
+	Limit   int
+}
+
+func (e OverloadError) Error() string {
+	return e.Message
+}
+
+// AdaptiveLimiter limits the number of requests in flight, adapting the limit to the observed latency with
+// AIMD: the limit grows by one per round trip while latencies are normal and shrinks by the backoff ratio
+// when a request fails or takes longer than tolerance times the long-term average latency. Slow requests
+// still move the average, slower than normal ones, so the limit recovers once latency settles at a higher level.
+type AdaptiveLimiter struct {
+	mu        sync.Mutex
+	limit     float64
+	minLimit  float64
+	maxLimit  float64
+	backoff   float64
+	tolerance float64
+	baseline  time.Duration
+	inflight  int
+}
+
+// NewAdaptiveLimiter creates an AdaptiveLimiter starting with the given limit, which may range from 1 to 1000.
+// An initial limit outside of that range is clamped to it.
+func NewAdaptiveLimiter(initial int) *AdaptiveLimiter {
+	return &AdaptiveLimiter{
+		limit:     math.Max(defaultLimiterMin, math.Min(defaultLimiterMax, float64(initial))),
+		minLimit:  defaultLimiterMin,
+		maxLimit:  defaultLimiterMax,
+		backoff:   defaultLimiterBackoffRatio,
+		tolerance: defaultLimiterTolerance,
+	}
+}
+
+// WithLimits sets the range the limit adapts in.
+func (l *AdaptiveLimiter) WithLimits(min int, max int) *AdaptiveLimiter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.minLimit, l.maxLimit = float64(min), float64(max)
+	l.limit = math.Max(l.minLimit, math.Min(l.maxLimit, l.limit))
+	return l
+}
+
+// WithBackoffRatio sets the factor the limit is multiplied with when latencies rise or requests fail.
+func (l *AdaptiveLimiter) WithBackoffRatio(ratio float64) *AdaptiveLimiter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.backoff = ratio
+	return l
+}
+
+// WithTolerance sets how many times slower than the long-term average a request may be before the limit shrinks.
+func (l *AdaptiveLimiter) WithTolerance(tolerance float64) *AdaptiveLimiter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.tolerance = tolerance
+	return l
+}
+
+// Limit returns the current concurrency limit.
+func (l *AdaptiveLimiter) Limit() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return int(l.limit)
+}
+
+// InFlight returns the number of requests currently in flight.
+func (l *AdaptiveLimiter) InFlight() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.inflight
+}
+
+// Middleware returns a Middleware rejecting requests with an OverloadError while the limit is reached.
+// Requests with PriorityHigh are never shed, but count against the limit.
+func (l *AdaptiveLimiter) Middleware() Middleware {
+	return func(next RoundTripperFunc) RoundTripperFunc {
+		return func(r *http.Request) (*http.Response, error) {
+			if !l.acquire(PriorityFromContext(r.Context()) == PriorityHigh) {
+				return nil, &OverloadError{Message: fmt.Sprintf("Concurrency limit of %d reached.", l.Limit()), Limit: l.Limit()}
+			}
+
+			start := time.Now()
+			resp, err := next(r)
+			l.release(time.Since(start), err != nil || isOverloadStatus(resp.StatusCode))
+			return resp, err
+		}
+	}
+}
+
+func (l *AdaptiveLimiter) acquire(force bool) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !force && l.inflight >= int(l.limit) {
+		return false
+	}
+	l.inflight++
+	return true
+}
+
+func (l *AdaptiveLimiter) release(latency time.Duration, failed bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	inflight := l.inflight
+	l.inflight--
+
+	if failed {
+		// the latency of failures, often fast errors, says nothing about the upstream's normal latency
+		l.limit = math.Max(l.minLimit, l.limit*l.backoff)
+		return
+	}
+	if l.baseline == 0 {
+		l.baseline = latency
+	}
+
+	slow := float64(latency) > l.tolerance*float64(l.baseline)
+	smoothing := limiterSmoothing
+	if slow {
+		smoothing = limiterSlowSmoothing
+	}
+	l.baseline = time.Duration((1-smoothing)*float64(l.baseline) + smoothing*float64(latency))
+	if slow {
+		l.limit = math.Max(l.minLimit, l.limit*l.backoff)
+		return
+	}
+	// only grow while the limit is actually used, an idle client must not drift to the maximum
+	if float64(inflight)*2 >= l.limit {
+		l.limit = math.Min(l.maxLimit, l.limit+1/l.limit)
+	}
+}
+
+// isOverloadStatus reports whether the upstream signals overload with the status code.
+func isOverloadStatus(statusCode int) bool {
+	return statusCode == http.StatusTooManyRequests || statusCode == http.StatusServiceUnavailable ||
+		statusCode == http.StatusGatewayTimeout
+}
@@ -0,0 +1,105 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestAdaptiveLimiter_ShedsAtLimit(t *testing.T) {
+	release := make(chan struct{})
+	server := mockServerWith(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	})
+	defer server.Close()
+
+	limiter := NewAdaptiveLimiter(1)
+	client := createTestHTTPClient(server.URL)
+	client.Use(limiter.Middleware())
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if resp, err := client.GetFrom("/"); err == nil {
+			resp.Body.Close()
+		}
+	}()
+	for limiter.InFlight() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	_, err := client.GetFrom("/")
+	var overloadErr *OverloadError
+	if !errors.As(err, &overloadErr) || overloadErr.Limit != 1 {
+		t.Errorf("Expected OverloadError but got %v", err)
+	}
+
+	if limiter.InFlight() != 1 {
+		t.Errorf("Expected shed request not to count as in flight but got %d", limiter.InFlight())
+	}
+
+	close(release)
+	<-done
+
+	resp, err := client.GetFromWithContext(WithPriority(context.Background(), PriorityHigh), "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+}
+
+func TestAdaptiveLimiter_Adapts(t *testing.T) {
+	limiter := NewAdaptiveLimiter(10).WithLimits(2, 20)
+
+	for i := 0; i < 10; i++ {
+		limiter.acquire(false)
+	}
+	for i := 0; i < 10; i++ {
+		limiter.release(10*time.Millisecond, false)
+	}
+	if limiter.Limit() != 10 || limiter.limit <= 10 {
+		t.Errorf("Expected limit to grow while in use but got %f", limiter.limit)
+	}
+
+	limiter.acquire(false)
+	limiter.release(time.Second, false)
+	if limiter.limit >= 10 {
+		t.Errorf("Expected slow request to shrink the limit but got %f", limiter.limit)
+	}
+
+	for i := 0; i < 50; i++ {
+		limiter.acquire(false)
+		limiter.release(10*time.Millisecond, true)
+	}
+	if limiter.Limit() != 2 {
+		t.Errorf("Expected failures to shrink the limit to its minimum but got %d", limiter.Limit())
+	}
+}
+
+func TestAdaptiveLimiter_RecoversAfterLatencyStep(t *testing.T) {
+	limiter := NewAdaptiveLimiter(10).WithLimits(2, 20)
+	for i := 0; i < 20; i++ {
+		limiter.acquire(false)
+		limiter.release(10*time.Millisecond, false)
+	}
+
+	// the upstream settles at a latency above the tolerance
+	for i := 0; i < 200; i++ {
+		limiter.acquire(false)
+		limiter.release(50*time.Millisecond, false)
+	}
+	if limiter.limit <= 2 {
+		t.Errorf("Expected the limit to grow again after the latency settled but got %f", limiter.limit)
+	}
+}
+
+func TestNewAdaptiveLimiter_ClampsInitialLimit(t *testing.T) {
+	if limit := NewAdaptiveLimiter(0).Limit(); limit != 1 {
+		t.Errorf("Expected initial limit 0 to be clamped to 1 but got %d", limit)
+	}
+	if limit := NewAdaptiveLimiter(5000).Limit(); limit != 1000 {
+		t.Errorf("Expected initial limit 5000 to be clamped to 1000 but got %d", limit)
+	}
+}
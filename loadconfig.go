@@ -0,0 +1,228 @@
+package http
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ConfigFile is the schema of configuration files and environment variables loaded by LoadConfig and ConfigFromEnv.
+type ConfigFile struct {
+	BaseURL  string          `json:"baseURL"`
+	Username string          `json:"username"`
+	Password string          `json:"password"`
+	Accept   string          `json:"accept"`
+	Timeout  string          `json:"timeout"`
+	Proxy    string          `json:"proxy"`
+	TLS      TLSConfigFile   `json:"tls"`
+	Retry    RetryConfigFile `json:"retry"`
+}
+
+// TLSConfigFile is the TLS section of a ConfigFile.
+type TLSConfigFile struct {
+	CAFile             string `json:"caFile"`
+	CertFile           string `json:"certFile"`
+	KeyFile            string `json:"keyFile"`
+	ServerName         string `json:"serverName"`
+	InsecureSkipVerify bool   `json:"insecureSkipVerify"`
+	MinVersion         string `json:"minVersion"`
+}
+
+// RetryConfigFile is the retry section of a ConfigFile, mapped to WithRetryPolicy. Requests are retried if
+// MaxAttempts is greater than one, with an exponential backoff from BaseDelay up to MaxDelay, 100ms and 10s by
+// default.
+type RetryConfigFile struct {
+	MaxAttempts int    `json:"maxAttempts"`
+	BaseDelay   string `json:"baseDelay"`
+	MaxDelay    string `json:"maxDelay"`
+}
+
+// LoadConfig reads a HttpConfig from a JSON (.json) or YAML (.yaml, .yml) file. YAML files are decoded with
+// YAMLCodec.
+func LoadConfig(path string) (*HttpConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	file := ConfigFile{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		err = json.Unmarshal(data, &file)
+	case ".yaml", ".yml":
+		err = YAMLCodec().Decode(data, &file)
+	default:
+		return nil, &ConfigError{Message: fmt.Sprintf("Config file %s has an unsupported format.", path), Field: "path"}
+	}
+	if err != nil {
+		return nil, &ConfigError{Message: fmt.Sprintf("Config file %s is invalid: %v.", path, err), Field: "path"}
+	}
+
+	return file.HttpConfig()
+}
+
+// ConfigFromEnv reads a HttpConfig from environment variables named <prefix>_BASE_URL, _USERNAME, _PASSWORD,
+// _ACCEPT, _TIMEOUT, _PROXY, _TLS_CA_FILE, _TLS_CERT_FILE, _TLS_KEY_FILE, _TLS_SERVER_NAME,
+// _TLS_INSECURE_SKIP_VERIFY, _TLS_MIN_VERSION, _RETRY_MAX_ATTEMPTS, _RETRY_BASE_DELAY and _RETRY_MAX_DELAY.
+func ConfigFromEnv(prefix string) (*HttpConfig, error) {
+	env := func(name string) string {
+		return os.Getenv(prefix + "_" + name)
+	}
+
+	file := ConfigFile{
+		BaseURL:  env("BASE_URL"),
+		Username: env("USERNAME"),
+		Password: env("PASSWORD"),
+		Accept:   env("ACCEPT"),
+		Timeout:  env("TIMEOUT"),
+		Proxy:    env("PROXY"),
+		TLS: TLSConfigFile{
+			CAFile:     env("TLS_CA_FILE"),
+			CertFile:   env("TLS_CERT_FILE"),
+			KeyFile:    env("TLS_KEY_FILE"),
+			ServerName: env("TLS_SERVER_NAME"),
+			MinVersion: env("TLS_MIN_VERSION"),
+		},
+		Retry: RetryConfigFile{
+			BaseDelay: env("RETRY_BASE_DELAY"),
+			MaxDelay:  env("RETRY_MAX_DELAY"),
+		},
+	}
+
+	if insecure := env("TLS_INSECURE_SKIP_VERIFY"); insecure != "" {
+		skip, err := strconv.ParseBool(insecure)
+		if err != nil {
+			return nil, &ConfigError{Message: fmt.Sprintf("%s_TLS_INSECURE_SKIP_VERIFY is no boolean.", prefix), Field: "tls.insecureSkipVerify"}
+		}
+		file.TLS.InsecureSkipVerify = skip
+	}
+	if maxAttempts := env("RETRY_MAX_ATTEMPTS"); maxAttempts != "" {
+		attempts, err := strconv.Atoi(maxAttempts)
+		if err != nil {
+			return nil, &ConfigError{Message: fmt.Sprintf("%s_RETRY_MAX_ATTEMPTS is no number.", prefix), Field: "retry.maxAttempts"}
+		}
+		file.Retry.MaxAttempts = attempts
+	}
+
+	return file.HttpConfig()
+}
+
+// HttpConfig converts the file contents to a validated HttpConfig.
+func (f ConfigFile) HttpConfig() (*HttpConfig, error) {
+	config := NewHttpConfig(f.BaseURL, f.Username, f.Password, f.Accept)
+
+	if f.Timeout != "" {
+		timeout, err := time.ParseDuration(f.Timeout)
+		if err != nil {
+			return nil, &ConfigError{Message: fmt.Sprintf("Timeout %q is no duration.", f.Timeout), Field: "timeout"}
+		}
+		config.timeout = timeout
+	}
+
+	if f.Proxy != "" {
+		proxy, err := url.Parse(f.Proxy)
+		if err != nil {
+			return nil, &ConfigError{Message: fmt.Sprintf("Proxy URL %q is invalid: %v.", f.Proxy, err), Field: "proxy"}
+		}
+		config.proxy = proxy
+	}
+
+	tlsConfig, err := f.TLS.tlsConfig()
+	if err != nil {
+		return nil, err
+	}
+	config.tls = tlsConfig
+
+	policy, err := f.Retry.retryPolicy()
+	if err != nil {
+		return nil, err
+	}
+	if policy != nil {
+		config.Apply(WithRetryPolicy(policy))
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+func (r RetryConfigFile) retryPolicy() (*RetryPolicy, error) {
+	if r.MaxAttempts == 0 && r.BaseDelay == "" && r.MaxDelay == "" {
+		return nil, nil
+	}
+	if r.MaxAttempts < 1 {
+		return nil, &ConfigError{Message: "Retry needs maxAttempts of at least 1.", Field: "retry.maxAttempts"}
+	}
+
+	base, err := parseRetryDelay(r.BaseDelay, "retry.baseDelay", defaultRetryBaseDelay)
+	if err != nil {
+		return nil, err
+	}
+	max, err := parseRetryDelay(r.MaxDelay, "retry.maxDelay", defaultRetryMaxDelay)
+	if err != nil {
+		return nil, err
+	}
+	return NewRetryPolicy(r.MaxAttempts).WithBackoff(ExponentialBackoff(base, max)), nil
+}
+
+func parseRetryDelay(value string, field string, fallback time.Duration) (time.Duration, error) {
+	if value == "" {
+		return fallback, nil
+	}
+	delay, err := time.ParseDuration(value)
+	if err != nil || delay < 0 {
+		return 0, &ConfigError{Message: fmt.Sprintf("Retry delay %q is no duration.", value), Field: field}
+	}
+	return delay, nil
+}
+
+func (t TLSConfigFile) tlsConfig() (*tls.Config, error) {
+	if t == (TLSConfigFile{}) {
+		return nil, nil
+	}
+
+	config := &tls.Config{ServerName: t.ServerName, InsecureSkipVerify: t.InsecureSkipVerify}
+
+	switch t.MinVersion {
+	case "":
+	case "1.2":
+		config.MinVersion = tls.VersionTLS12
+	case "1.3":
+		config.MinVersion = tls.VersionTLS13
+	default:
+		return nil, &ConfigError{Message: fmt.Sprintf("TLS version %q is not supported.", t.MinVersion), Field: "tls.minVersion"}
+	}
+
+	if t.CAFile != "" {
+		pem, err := os.ReadFile(t.CAFile)
+		if err != nil {
+			return nil, &ConfigError{Message: fmt.Sprintf("CA file %s not readable: %v.", t.CAFile, err), Field: "tls.caFile"}
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, &ConfigError{Message: fmt.Sprintf("CA file %s contains no certificates.", t.CAFile), Field: "tls.caFile"}
+		}
+		config.RootCAs = pool
+	}
+
+	if (t.CertFile == "") != (t.KeyFile == "") {
+		return nil, &ConfigError{Message: "TLS certificate and key file must be set together.", Field: "tls.certFile"}
+	}
+	if t.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(t.CertFile, t.KeyFile)
+		if err != nil {
+			return nil, &ConfigError{Message: fmt.Sprintf("TLS key pair not loadable: %v.", err), Field: "tls.certFile"}
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	return config, nil
+}
@@ -0,0 +1,199 @@
+package http
+
+import (
+	"crypto/tls"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeConfigFile(t *testing.T, name string, content string) string {
+	dir, err := os.MkdirTemp("", "config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadConfig_JSON(t *testing.T) {
+	path := writeConfigFile(t, "config.json", `{
+		"baseURL": "https://api.example.com",
+		"username": "user",
+		"password": "secret",
+		"timeout": "5s",
+		"proxy": "http://proxy:3128",
+		"tls": {"serverName": "api", "minVersion": "1.2"}
+	}`)
+
+	config, err := LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if config.baseURL != "https://api.example.com" || config.username != "user" || config.password != "secret" {
+		t.Errorf("Unexpected config %+v", config)
+	}
+	if config.timeout != 5*time.Second {
+		t.Errorf("Expected timeout 5s but got %s", config.timeout)
+	}
+	if config.proxy == nil || config.proxy.Host != "proxy:3128" {
+		t.Errorf("Unexpected proxy %v", config.proxy)
+	}
+	if config.tls == nil || config.tls.ServerName != "api" {
+		t.Errorf("Unexpected TLS config %+v", config.tls)
+	}
+}
+
+func TestLoadConfig_YAML(t *testing.T) {
+	path := writeConfigFile(t, "config.yaml", `
+# service config
+baseURL: "https://api.example.com"
+username: user
+password: 'it''s # secret'
+timeout: 1m
+tls:
+  insecureSkipVerify: true
+  serverName: api # inline comment
+accept: application/xml
+`)
+
+	config, err := LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if config.baseURL != "https://api.example.com" || config.accept != "application/xml" {
+		t.Errorf("Unexpected config %+v", config)
+	}
+	if config.password != "it's # secret" {
+		t.Errorf("Expected quoted password but got %q", config.password)
+	}
+	if config.timeout != time.Minute {
+		t.Errorf("Expected timeout 1m but got %s", config.timeout)
+	}
+	if config.tls == nil || !config.tls.InsecureSkipVerify || config.tls.ServerName != "api" {
+		t.Errorf("Unexpected TLS config %+v", config.tls)
+	}
+}
+
+func TestLoadConfig_Invalid(t *testing.T) {
+	cases := map[string]string{
+		"timeout":    writeConfigFile(t, "config.json", `{"baseURL": "https://api.example.com", "timeout": "soon"}`),
+		"baseURL":    writeConfigFile(t, "config.yml", "username: user\n"),
+		"path":       writeConfigFile(t, "config.toml", `baseURL = "https://api.example.com"`),
+		"tls.caFile": writeConfigFile(t, "ca.json", `{"baseURL": "https://api.example.com", "tls": {"caFile": "/does/not/exist"}}`),
+	}
+
+	for field, path := range cases {
+		_, err := LoadConfig(path)
+		var configErr *ConfigError
+		if !errors.As(err, &configErr) || configErr.Field != field {
+			t.Errorf("Expected ConfigError for %s but got %v", field, err)
+		}
+	}
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	os.Setenv("TEST_API_BASE_URL", "https://api.example.com")
+	os.Setenv("TEST_API_TIMEOUT", "250ms")
+	os.Setenv("TEST_API_TLS_INSECURE_SKIP_VERIFY", "true")
+	defer os.Unsetenv("TEST_API_BASE_URL")
+	defer os.Unsetenv("TEST_API_TIMEOUT")
+	defer os.Unsetenv("TEST_API_TLS_INSECURE_SKIP_VERIFY")
+
+	config, err := ConfigFromEnv("TEST_API")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if config.baseURL != "https://api.example.com" || config.timeout != 250*time.Millisecond {
+		t.Errorf("Unexpected config %+v", config)
+	}
+	if config.tls == nil || !config.tls.InsecureSkipVerify {
+		t.Errorf("Unexpected TLS config %+v", config.tls)
+	}
+
+	os.Setenv("TEST_API_TLS_INSECURE_SKIP_VERIFY", "maybe")
+	if _, err := ConfigFromEnv("TEST_API"); err == nil {
+		t.Error("Expected error for invalid boolean")
+	}
+}
+
+func TestLoadConfig_YAMLNumbersAsStrings(t *testing.T) {
+	path := writeConfigFile(t, "config.yaml", "baseURL: https://api.example.com\nusername: 42\npassword: 1234\ntls:\n  minVersion: 1.2\n")
+
+	config, err := LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.username != "42" || config.password != "1234" || config.tls == nil || config.tls.MinVersion != tls.VersionTLS12 {
+		t.Errorf("Expected numbers to be read as strings but got %q, %+v", config.password, config.tls)
+	}
+}
+
+func TestLoadConfig_InvalidYAML(t *testing.T) {
+	_, err := LoadConfig(writeConfigFile(t, "config.yaml", "baseURL: https://api.example.com\ntls: {serverName: api\n"))
+	var configErr *ConfigError
+	if !errors.As(err, &configErr) || configErr.Field != "path" {
+		t.Errorf("Expected ConfigError for the file but got %v", err)
+	}
+}
+
+func TestLoadConfig_Retry(t *testing.T) {
+	path := writeConfigFile(t, "config.yaml", `
+baseURL: https://api.example.com
+retry:
+  maxAttempts: 4
+  baseDelay: 50ms
+  maxDelay: 2s
+`)
+
+	config, err := LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	policy := config.RetryPolicy()
+	if policy == nil || policy.MaxAttempts() != 4 {
+		t.Fatalf("Expected a retry policy with 4 attempts but got %+v", policy)
+	}
+	if delay := policy.Delay(2, nil); delay != 50*time.Millisecond {
+		t.Errorf("Expected the first retry after 50ms but got %s", delay)
+	}
+	if delay := policy.Delay(10, nil); delay != 2*time.Second {
+		t.Errorf("Expected retries to be delayed at most 2s but got %s", delay)
+	}
+
+	invalid := writeConfigFile(t, "config.json", `{"baseURL": "https://api.example.com", "retry": {"maxAttempts": 2, "maxDelay": "later"}}`)
+	var configErr *ConfigError
+	if _, err := LoadConfig(invalid); !errors.As(err, &configErr) || configErr.Field != "retry.maxDelay" {
+		t.Errorf("Expected ConfigError for retry.maxDelay but got %v", err)
+	}
+}
+
+func TestConfigFromEnv_Retry(t *testing.T) {
+	t.Setenv("TEST_RETRY_BASE_URL", "https://api.example.com")
+	t.Setenv("TEST_RETRY_RETRY_MAX_ATTEMPTS", "3")
+	t.Setenv("TEST_RETRY_RETRY_BASE_DELAY", "10ms")
+
+	config, err := ConfigFromEnv("TEST_RETRY")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if policy := config.RetryPolicy(); policy == nil || policy.MaxAttempts() != 3 || policy.Delay(2, nil) != 10*time.Millisecond {
+		t.Errorf("Unexpected retry policy %+v", policy)
+	}
+
+	t.Setenv("TEST_RETRY_RETRY_MAX_ATTEMPTS", "often")
+	var configErr *ConfigError
+	if _, err := ConfigFromEnv("TEST_RETRY"); !errors.As(err, &configErr) || configErr.Field != "retry.maxAttempts" {
+		t.Errorf("Expected ConfigError for retry.maxAttempts but got %v", err)
+	}
+}
@@ -0,0 +1,147 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// LoadReport summarizes a LoadTest run.
+type LoadReport struct {
+	// Requests is the number of requests sent.
+	Requests int
+	// Errors counts requests which failed without a response or with a 4xx or 5xx status.
+	Errors      int
+	StatusCodes map[int]int
+	Duration    time.Duration
+	Latency     LatencySummary
+}
+
+// LatencySummary describes the latency distribution of a LoadTest run.
+type LatencySummary struct {
+	Min  time.Duration
+	Mean time.Duration
+	P50  time.Duration
+	P90  time.Duration
+	P99  time.Duration
+	Max  time.Duration
+}
+
+// Throughput returns the completed requests per second.
+func (r LoadReport) Throughput() float64 {
+	if r.Duration <= 0 {
+		return 0
+	}
+	return float64(r.Requests) / r.Duration.Seconds()
+}
+
+func (r LoadReport) String() string {
+	return fmt.Sprintf("%d requests in %s (%.1f/s), %d errors, latency min %s mean %s p50 %s p90 %s p99 %s max %s",
+		r.Requests, r.Duration.Round(time.Millisecond), r.Throughput(), r.Errors,
+		r.Latency.Min, r.Latency.Mean, r.Latency.P50, r.Latency.P90, r.Latency.P99, r.Latency.Max)
+}
+
+// LoadTest sends copies of req at rps requests per second for duration, or until ctx is done, and reports
+// the latencies and errors observed. Requests are started on schedule regardless of how many are still in
+// flight, so a slow server shows up as growing latency rather than a lower rate. The body of req must be
+// replayable, i.e. req must have been created with a GetBody function like http.NewRequest does for
+// in-memory bodies.
+func (h *HttpClient) LoadTest(ctx context.Context, req *http.Request, rps float64, duration time.Duration) (LoadReport, error) {
+	if rps <= 0 {
+		return LoadReport{}, errors.New("rps must be positive")
+	}
+	if duration <= 0 {
+		return LoadReport{}, errors.New("duration must be positive")
+	}
+	if _, ok := replayableClone(req); !ok {
+		return LoadReport{}, errors.New("request body can not be replayed")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, duration)
+	defer cancel()
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	report := LoadReport{StatusCodes: map[int]int{}}
+	var latencies []time.Duration
+
+	send := func() {
+		defer wg.Done()
+
+		clone, _ := replayableClone(req)
+		// in-flight requests finish after the test ends, so they don't inherit its deadline
+		clone = clone.WithContext(req.Context())
+		start := time.Now()
+		resp, _ := h.ExecuteRequest(clone)
+		latency := time.Since(start)
+
+		mu.Lock()
+		defer mu.Unlock()
+		report.Requests++
+		latencies = append(latencies, latency)
+		if resp == nil {
+			report.Errors++
+			return
+		}
+		drainAndClose(resp.Body)
+		report.StatusCodes[resp.StatusCode]++
+		if resp.StatusCode >= 400 {
+			report.Errors++
+		}
+	}
+
+	ticker := time.NewTicker(time.Duration(float64(time.Second) / rps))
+	defer ticker.Stop()
+
+	start := time.Now()
+	wg.Add(1)
+	go send()
+	for done := false; !done; {
+		select {
+		case <-ctx.Done():
+			done = true
+		case <-ticker.C:
+			wg.Add(1)
+			go send()
+		}
+	}
+	wg.Wait()
+
+	report.Duration = time.Since(start)
+	report.Latency = summarizeLatencies(latencies)
+	return report, nil
+}
+
+func summarizeLatencies(latencies []time.Duration) LatencySummary {
+	if len(latencies) == 0 {
+		return LatencySummary{}
+	}
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+
+	var total time.Duration
+	for _, latency := range latencies {
+		total += latency
+	}
+	return LatencySummary{
+		Min:  latencies[0],
+		Mean: total / time.Duration(len(latencies)),
+		P50:  percentile(latencies, 0.5),
+		P90:  percentile(latencies, 0.9),
+		P99:  percentile(latencies, 0.99),
+		Max:  latencies[len(latencies)-1],
+	}
+}
+
+// percentile returns the nearest-rank percentile p of the sorted latencies.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return sorted[rank]
+}
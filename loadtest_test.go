@@ -0,0 +1,68 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestHttpClient_LoadTest(t *testing.T) {
+	var count int32
+	server := mockServerWith(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&count, 1)%5 == 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	})
+	defer server.Close()
+
+	client := createTestHTTPClient(server.URL)
+	req, _ := client.PostRequest("/", strings.NewReader(fixtureBasicJSON))
+
+	report, err := client.LoadTest(context.Background(), req, 100, 200*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if report.Requests < 10 || report.Requests > 25 {
+		t.Errorf("Expected about 20 requests but got %d", report.Requests)
+	}
+	if report.Errors != report.StatusCodes[http.StatusServiceUnavailable] || report.Errors == 0 {
+		t.Errorf("Expected 503s to be counted as errors but got %+v", report)
+	}
+	if report.Latency.Min > report.Latency.P50 || report.Latency.P50 > report.Latency.P99 || report.Latency.P99 > report.Latency.Max {
+		t.Errorf("Expected ordered percentiles but got %+v", report.Latency)
+	}
+	if !strings.Contains(report.String(), "requests in") {
+		t.Errorf("Unexpected summary %s", report)
+	}
+}
+
+func TestHttpClient_LoadTest_Invalid(t *testing.T) {
+	client := createTestHTTPClient(fixtureBaseURL)
+	req, _ := client.GetRequest("/")
+
+	if _, err := client.LoadTest(context.Background(), req, 0, time.Second); err == nil {
+		t.Error("Expected error for zero rps")
+	}
+
+	req, _ = http.NewRequest(http.MethodPost, fixtureBaseURL, strings.NewReader("body"))
+	req.GetBody = nil
+	if _, err := client.LoadTest(context.Background(), req, 1, time.Second); err == nil {
+		t.Error("Expected error for body which can not be replayed")
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	latencies := make([]time.Duration, 100)
+	for i := range latencies {
+		latencies[i] = time.Duration(i+1) * time.Millisecond
+	}
+	summary := summarizeLatencies(latencies)
+
+	if summary.P50 != 50*time.Millisecond || summary.P99 != 99*time.Millisecond || summary.Mean != 50500*time.Microsecond {
+		t.Errorf("Unexpected summary %+v", summary)
+	}
+}
@@ -0,0 +1,99 @@
+package http
+
+import (
+	"log/slog"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// LogPolicy decides which requests the logging middleware logs and at which level. Failed requests (transport
+// errors and status 400 and above) and requests slower than SlowThreshold are always logged, successful
+// requests only with probability SuccessSampleRate.
+type LogPolicy struct {
+	// SuccessSampleRate is the fraction of successful requests logged, from 0 to 1.
+	SuccessSampleRate float64
+	// SlowThreshold makes slower requests always logged, zero disables it.
+	SlowThreshold time.Duration
+
+	SuccessLevel slog.Level
+	SlowLevel    slog.Level
+	ErrorLevel   slog.Level
+}
+
+// DefaultLogPolicy logs every request: successes at info, requests slower than a second at warn and failures
+// at error level.
+func DefaultLogPolicy() LogPolicy {
+	return LogPolicy{
+		SuccessSampleRate: 1,
+		SlowThreshold:     time.Second,
+		SuccessLevel:      slog.LevelInfo,
+		SlowLevel:         slog.LevelWarn,
+		ErrorLevel:        slog.LevelError,
+	}
+}
+
+// SampledLogPolicy logs all failures and slow requests but only rate (e.g. 0.01) of the successful requests.
+func SampledLogPolicy(rate float64, slowThreshold time.Duration) LogPolicy {
+	policy := DefaultLogPolicy()
+	policy.SuccessSampleRate = rate
+	policy.SlowThreshold = slowThreshold
+	return policy
+}
+
+// level returns the level to log a request at, or false if it is not logged.
+func (p LogPolicy) level(resp *http.Response, err error, duration time.Duration, sample float64) (slog.Level, bool) {
+	if err != nil || (resp != nil && resp.StatusCode >= http.StatusBadRequest) {
+		return p.ErrorLevel, true
+	}
+	if p.SlowThreshold > 0 && duration >= p.SlowThreshold {
+		return p.SlowLevel, true
+	}
+	return p.SuccessLevel, sample < p.SuccessSampleRate
+}
+
+// LoggingMiddleware returns a Middleware logging requests to logger as selected by policy. The logger's handler
+// decides about the minimum level, e.g. through a slog.LevelVar adjustable at runtime.
+func LoggingMiddleware(logger *slog.Logger, policy LogPolicy) Middleware {
+	return loggingMiddleware(logger, policy, rand.Float64)
+}
+
+func loggingMiddleware(logger *slog.Logger, policy LogPolicy, sample func() float64) Middleware {
+	if logger == nil {
+		panic("logger is nil")
+	}
+
+	return func(next RoundTripperFunc) RoundTripperFunc {
+		return func(r *http.Request) (*http.Response, error) {
+			start := time.Now()
+			resp, err := next(r)
+			duration := time.Since(start)
+
+			level, ok := policy.level(resp, err, duration, sample())
+			if !ok || !logger.Enabled(r.Context(), level) {
+				return resp, err
+			}
+
+			attrs := []slog.Attr{
+				slog.String("method", r.Method),
+				slog.String("url", redactionFrom(r.Context()).url(r.URL)),
+				slog.Duration("duration", duration),
+			}
+			if resp != nil {
+				attrs = append(attrs, slog.Int("status", resp.StatusCode))
+			}
+			if err != nil {
+				attrs = append(attrs, slog.String("error", err.Error()))
+			}
+			if traceID, ok := TraceIDFromContext(r.Context()); ok {
+				attrs = append(attrs, slog.String("trace_id", traceID))
+			}
+			if endpoint, ok := EndpointNameFromContext(r.Context()); ok {
+				attrs = append(attrs, slog.String("endpoint", endpoint))
+			}
+			logger.LogAttrs(r.Context(), level, "http request", attrs...)
+
+			return resp, err
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLogPolicy_Level(t *testing.T) {
+	policy := SampledLogPolicy(0.1, time.Second)
+	ok := &http.Response{StatusCode: http.StatusOK}
+
+	if level, logged := policy.level(&http.Response{StatusCode: http.StatusBadGateway}, nil, 0, 0.9); !logged || level != slog.LevelError {
+		t.Error("Expected failures to be logged at error level")
+	}
+	if level, logged := policy.level(ok, nil, 2*time.Second, 0.9); !logged || level != slog.LevelWarn {
+		t.Error("Expected slow requests to be logged at warn level")
+	}
+	if _, logged := policy.level(ok, nil, time.Millisecond, 0.9); logged {
+		t.Error("Expected successes above the sample rate not to be logged")
+	}
+	if level, logged := policy.level(ok, nil, time.Millisecond, 0.05); !logged || level != slog.LevelInfo {
+		t.Error("Expected sampled successes to be logged at info level")
+	}
+}
+
+func TestLoggingMiddleware(t *testing.T) {
+	server := mockServerWith(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	})
+	defer server.Close()
+
+	var output bytes.Buffer
+	level := &slog.LevelVar{}
+	logger := slog.New(slog.NewTextHandler(&output, &slog.HandlerOptions{Level: level}))
+
+	client := createTestHTTPClient(server.URL)
+	client.Use(loggingMiddleware(logger, SampledLogPolicy(0.5, time.Hour), func() float64 { return 0.9 }))
+
+	for _, path := range []string{"/", "/fail"} {
+		resp, err := client.GetFrom(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+
+	if strings.Count(output.String(), "http request") != 1 || !strings.Contains(output.String(), "status=500") {
+		t.Errorf("Expected only the failure to be logged but got %s", output.String())
+	}
+
+	output.Reset()
+	level.Set(slog.LevelError + 1)
+	resp, _ := client.GetFrom("/fail")
+	resp.Body.Close()
+	if output.Len() != 0 {
+		t.Errorf("Expected logging to honor the level at runtime but got %s", output.String())
+	}
+}
+
+func TestLoggingMiddleware_RedactsClientParams(t *testing.T) {
+	server := mockServerWith(func(w http.ResponseWriter, r *http.Request) {})
+	defer server.Close()
+
+	var output bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&output, nil))
+	client := NewHttpClientWithConfig(NewDefaultHttpConfig(server.URL, WithRedactedParams("tenant_secret")))
+	client.Use(LoggingMiddleware(logger, DefaultLogPolicy()))
+
+	resp, err := client.Get(context.Background(), "/items?tenant_secret=hunter2&page=2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if strings.Contains(output.String(), "hunter2") || !strings.Contains(output.String(), "page=2") {
+		t.Errorf("Expected the client's redacted parameter to be masked but got %s", output.String())
+	}
+}
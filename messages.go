@@ -0,0 +1,123 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+)
+
+// MessageKey identifies the human-readable message of a client error.
+type MessageKey string
+
+// The message keys and the arguments passed to MessageCatalog.Message for them.
+const (
+	MessageUnauthorized      MessageKey = "unauthorized"       // no arguments
+	MessageNotFound          MessageKey = "not_found"          // no arguments
+	MessageClientClosed      MessageKey = "client_closed"      // no arguments
+	MessageCanceled          MessageKey = "canceled"           // no arguments
+	MessageTimeout           MessageKey = "timeout"            // no arguments
+	MessageDNS               MessageKey = "dns"                // host
+	MessageConnectionRefused MessageKey = "connection_refused" // address
+	MessageTLS               MessageKey = "tls"                // no arguments
+	MessageOverload          MessageKey = "overload"           // limit
+	MessageSlowHeaders       MessageKey = "slow_headers"       // timeout
+	MessageAttemptDeadline   MessageKey = "attempt_deadline"   // needed, remaining
+	MessageSLOBudget         MessageKey = "slo_budget"         // remaining, reserve
+)
+
+// MessageCatalog localizes the human-readable part of client errors, e.g. for products showing them to end
+// users. The typed data of the errors, like URLs and status codes, is not affected.
+type MessageCatalog interface {
+	// Message returns the message for key, or false to keep the default English one.
+	Message(key MessageKey, args ...interface{}) (string, bool)
+}
+
+// MessageMap is a MessageCatalog of fmt format strings, which receive the arguments of their key in order:
+//
+//	MessageMap{MessageOverload: "Zu viele gleichzeitige Anfragen (Limit %d)."}
+type MessageMap map[MessageKey]string
+
+// Message implements MessageCatalog.
+func (m MessageMap) Message(key MessageKey, args ...interface{}) (string, bool) {
+	format, ok := m[key]
+	if !ok {
+		return "", false
+	}
+	if len(args) == 0 {
+		return format, true
+	}
+	return fmt.Sprintf(format, args...), true
+}
+
+// WithMessageCatalog localizes the messages of the errors returned by ExecuteRequest with catalog.
+func WithMessageCatalog(catalog MessageCatalog) Option {
+	return func(c *HttpConfig) {
+		c.messages = catalog
+	}
+}
+
+func (h *HttpClient) messageCatalog() MessageCatalog {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.config.messages
+}
+
+// localizeError replaces the messages of the typed errors in err's chain with those of catalog.
+func localizeError(catalog MessageCatalog, err error) {
+	if catalog == nil {
+		return
+	}
+	localize := func(message *string, key MessageKey, args ...interface{}) {
+		if localized, ok := catalog.Message(key, args...); ok {
+			*message = localized
+		}
+	}
+
+	var unauthorized *UnauthorizedError
+	if errors.As(err, &unauthorized) {
+		localize(&unauthorized.Message, MessageUnauthorized)
+	}
+	var notFound *NotFoundError
+	if errors.As(err, &notFound) {
+		localize(&notFound.Message, MessageNotFound)
+	}
+	var closed *ClientClosedError
+	if errors.As(err, &closed) {
+		localize(&closed.Message, MessageClientClosed)
+	}
+	var canceled *CanceledError
+	if errors.As(err, &canceled) {
+		localize(&canceled.Message, MessageCanceled)
+	}
+	var timeout *TimeoutError
+	if errors.As(err, &timeout) {
+		localize(&timeout.Message, MessageTimeout)
+	}
+	var dns *DNSError
+	if errors.As(err, &dns) {
+		localize(&dns.Message, MessageDNS, dns.Host)
+	}
+	var refused *ConnectionRefusedError
+	if errors.As(err, &refused) {
+		localize(&refused.Message, MessageConnectionRefused, refused.Address)
+	}
+	var tlsErr *TLSError
+	if errors.As(err, &tlsErr) {
+		localize(&tlsErr.Message, MessageTLS)
+	}
+	var overload *OverloadError
+	if errors.As(err, &overload) {
+		localize(&overload.Message, MessageOverload, overload.Limit)
+	}
+	var slow *SlowResponseError
+	if errors.As(err, &slow) && slow.Phase == SlowResponseHeaders {
+		localize(&slow.Message, MessageSlowHeaders, slow.Timeout)
+	}
+	var deadline *AttemptDeadlineError
+	if errors.As(err, &deadline) {
+		localize(&deadline.Message, MessageAttemptDeadline, deadline.Needed, deadline.Remaining)
+	}
+	var exhausted *SLOBudgetExhaustedError
+	if errors.As(err, &exhausted) {
+		localize(&exhausted.Message, MessageSLOBudget, exhausted.Remaining, exhausted.Reserve)
+	}
+}
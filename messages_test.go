@@ -0,0 +1,52 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestMessageCatalog(t *testing.T) {
+	server := mockServerWith(nil)
+	url := server.URL
+	server.Close()
+
+	catalog := MessageMap{
+		MessageConnectionRefused: "Verbindung zu %s abgelehnt",
+		MessageClientClosed:      "Client wurde beendet.",
+	}
+	client := NewHttpClientWithConfig(NewHttpConfig(url, "", "", contentTypeJSON, WithMessageCatalog(catalog)))
+
+	_, err := client.GetFrom("/")
+	var refused *ConnectionRefusedError
+	if !errors.As(err, &refused) || !strings.HasPrefix(refused.Message, "Verbindung zu 127.0.0.1:") {
+		t.Errorf("Expected localized message but got %v", err)
+	}
+	if !strings.Contains(err.Error(), "Verbindung zu") {
+		t.Errorf("Expected localized message in %s", err)
+	}
+
+	client.Shutdown(context.Background())
+	_, err = client.GetFrom("/")
+	var closed *ClientClosedError
+	if !errors.As(err, &closed) || closed.Message != "Client wurde beendet." {
+		t.Errorf("Expected localized message but got %v", err)
+	}
+}
+
+func TestMessageMap_KeepsDefault(t *testing.T) {
+	err := &NotFoundError{Message: "Resource not found.", URL: "https://example.com"}
+	localizeError(MessageMap{MessageUnauthorized: "Nicht angemeldet."}, err)
+
+	if err.Message != "Resource not found." {
+		t.Errorf("Expected default message but got %s", err.Message)
+	}
+
+	err401 := &UnauthorizedError{Message: "Authentication required.", Status: http.StatusUnauthorized}
+	localizeError(MessageMap{MessageUnauthorized: "Nicht angemeldet."}, err401)
+	if err401.Message != "Nicht angemeldet." {
+		t.Errorf("Expected localized message but got %s", err401.Message)
+	}
+}
@@ -0,0 +1,161 @@
+package http
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultLatencyBuckets are the upper bounds of the latency histogram buckets used by NewMetrics.
+var DefaultLatencyBuckets = []time.Duration{
+	5 * time.Millisecond, 10 * time.Millisecond, 25 * time.Millisecond, 50 * time.Millisecond,
+	100 * time.Millisecond, 250 * time.Millisecond, 500 * time.Millisecond,
+	1 * time.Second, 2500 * time.Millisecond, 5 * time.Second, 10 * time.Second,
+}
+
+// Metrics is an Events consumer counting requests and recording their latency per method, host, endpoint and
+// status class ("2xx", "5xx", "error" for transport failures). The latest request with a trace ID in each
+// histogram bucket is kept as exemplar, linking a slow bucket to an actual trace.
+type Metrics struct {
+	bounds []time.Duration
+
+	mu     sync.Mutex
+	series map[MetricLabels]*Histogram
+}
+
+// MetricLabels identify a series of Metrics.
+type MetricLabels struct {
+	Method      string
+	Host        string
+	Endpoint    string
+	StatusClass string
+}
+
+// Histogram is the latency distribution of a series. Counts are per bucket, not cumulative; the last bucket
+// counts requests slower than all bounds.
+type Histogram struct {
+	Bounds    []time.Duration
+	Counts    []int64
+	Exemplars []*Exemplar
+	Count     int64
+	Sum       time.Duration
+}
+
+// Exemplar is a sample request of a histogram bucket.
+type Exemplar struct {
+	TraceID string
+	Latency time.Duration
+	Time    time.Time
+}
+
+// NewMetrics creates Metrics using the given latency bucket bounds, DefaultLatencyBuckets if none are given.
+func NewMetrics(bounds ...time.Duration) *Metrics {
+	if len(bounds) == 0 {
+		bounds = DefaultLatencyBuckets
+	}
+	sorted := append([]time.Duration(nil), bounds...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	return &Metrics{bounds: sorted, series: make(map[MetricLabels]*Histogram)}
+}
+
+// Emit implements Events, recording every finished request.
+func (m *Metrics) Emit(e Event) {
+	if e.Type != EventRequestFinished || e.Request == nil {
+		return
+	}
+
+	labels := MetricLabels{Method: e.Request.Method, Host: e.Host, StatusClass: "error"}
+	if e.Response != nil {
+		labels.StatusClass = ClassifyStatus(e.Response.StatusCode).String()
+	}
+	labels.Endpoint, _ = EndpointNameFromContext(e.Request.Context())
+	traceID, _ := TraceIDFromContext(e.Request.Context())
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	histogram, ok := m.series[labels]
+	if !ok {
+		histogram = &Histogram{
+			Bounds:    m.bounds,
+			Counts:    make([]int64, len(m.bounds)+1),
+			Exemplars: make([]*Exemplar, len(m.bounds)+1),
+		}
+		m.series[labels] = histogram
+	}
+
+	bucket := sort.Search(len(m.bounds), func(i int) bool { return e.Duration <= m.bounds[i] })
+	histogram.Counts[bucket]++
+	histogram.Count++
+	histogram.Sum += e.Duration
+	if traceID != "" {
+		histogram.Exemplars[bucket] = &Exemplar{TraceID: traceID, Latency: e.Duration, Time: e.Time}
+	}
+}
+
+// Snapshot returns a copy of all series.
+func (m *Metrics) Snapshot() map[MetricLabels]Histogram {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	snapshot := make(map[MetricLabels]Histogram, len(m.series))
+	for labels, histogram := range m.series {
+		copied := *histogram
+		copied.Counts = append([]int64(nil), histogram.Counts...)
+		copied.Exemplars = make([]*Exemplar, len(histogram.Exemplars))
+		for i, exemplar := range histogram.Exemplars {
+			if exemplar != nil {
+				e := *exemplar
+				copied.Exemplars[i] = &e
+			}
+		}
+		snapshot[labels] = copied
+	}
+	return snapshot
+}
+
+// WriteOpenMetrics writes the series in the OpenMetrics text format, including exemplars, as
+// http_client_request_duration_seconds histogram.
+func (m *Metrics) WriteOpenMetrics(w io.Writer) error {
+	snapshot := m.Snapshot()
+	keys := make([]MetricLabels, 0, len(snapshot))
+	for labels := range snapshot {
+		keys = append(keys, labels)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+
+	var b strings.Builder
+	b.WriteString("# TYPE http_client_request_duration_seconds histogram\n")
+	for _, labels := range keys {
+		histogram := snapshot[labels]
+		cumulative := int64(0)
+		for i, count := range histogram.Counts {
+			cumulative += count
+			le := "+Inf"
+			if i < len(histogram.Bounds) {
+				le = fmt.Sprint(histogram.Bounds[i].Seconds())
+			}
+			fmt.Fprintf(&b, "http_client_request_duration_seconds_bucket{%s,le=\"%s\"} %d", labels, le, cumulative)
+			if exemplar := histogram.Exemplars[i]; exemplar != nil {
+				fmt.Fprintf(&b, " # {trace_id=\"%s\"} %g %.3f", exemplar.TraceID, exemplar.Latency.Seconds(),
+					float64(exemplar.Time.UnixNano())/1e9)
+			}
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "http_client_request_duration_seconds_count{%s} %d\n", labels, histogram.Count)
+		fmt.Fprintf(&b, "http_client_request_duration_seconds_sum{%s} %g\n", labels, histogram.Sum.Seconds())
+	}
+	b.WriteString("# EOF\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// String formats the labels for the OpenMetrics text format.
+func (l MetricLabels) String() string {
+	return fmt.Sprintf("method=%q,host=%q,endpoint=%q,status=%q", l.Method, l.Host, l.Endpoint, l.StatusClass)
+}
@@ -0,0 +1,59 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMetrics(t *testing.T) {
+	server := mockServerWith(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	})
+	defer server.Close()
+
+	metrics := NewMetrics(time.Millisecond, time.Hour)
+	client := NewHttpClientWithConfig(NewDefaultHttpConfig(server.URL, WithEvents(metrics)))
+
+	ctx := WithEndpointName(WithTraceID(context.Background(), "trace-1"), "fail")
+	for _, path := range []string{"/", "/fail"} {
+		resp, err := client.GetFromWithContext(ctx, path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+
+	snapshot := metrics.Snapshot()
+	if len(snapshot) != 2 {
+		t.Fatalf("Expected a series per status class but got %v", snapshot)
+	}
+
+	host := strings.TrimPrefix(server.URL, "http://")
+	failed, ok := snapshot[MetricLabels{Method: http.MethodGet, Host: host, Endpoint: "fail", StatusClass: "5xx"}]
+	if !ok || failed.Count != 1 {
+		t.Fatalf("Expected a 5xx series but got %v", snapshot)
+	}
+
+	exemplars := 0
+	for _, exemplar := range failed.Exemplars {
+		if exemplar != nil && exemplar.TraceID == "trace-1" {
+			exemplars++
+		}
+	}
+	if exemplars != 1 {
+		t.Errorf("Expected one exemplar with the trace ID but got %d", exemplars)
+	}
+
+	var text strings.Builder
+	if err := metrics.WriteOpenMetrics(&text); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(text.String(), `status="5xx",le="+Inf"} 1`) || !strings.Contains(text.String(), `# {trace_id="trace-1"}`) {
+		t.Errorf("Unexpected OpenMetrics output\n%s", text.String())
+	}
+}
@@ -0,0 +1,141 @@
+package http
+
+import (
+	"net/http"
+)
+
+// RoundTripperFunc executes a single HTTP request.
+type RoundTripperFunc func(r *http.Request) (*http.Response, error)
+
+// RoundTrip implements http.RoundTripper.
+func (f RoundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+// Middleware wraps the execution of requests, e.g. to add logging, metrics or payload encryption.
+type Middleware func(next RoundTripperFunc) RoundTripperFunc
+
+// Chain composes middleware into a single Middleware. The first one is the outermost.
+func Chain(middleware ...Middleware) Middleware {
+	return func(next RoundTripperFunc) RoundTripperFunc {
+		for i := len(middleware) - 1; i >= 0; i-- {
+			next = middleware[i](next)
+		}
+		return next
+	}
+}
+
+// WithMiddleware sets the middleware of the config, e.g. to bundle it in a profile. It wraps the middleware
+// added with Use, the first one given is the outermost. Every request of the client flows through it, from
+// ExecuteRequest to the convenience methods like GetFrom and PostTo.
+func WithMiddleware(middleware ...Middleware) Option {
+	return func(c *HttpConfig) {
+		c.middleware = append(append([]Middleware(nil), c.middleware...), middleware...)
+	}
+}
+
+// Use appends middleware to the client. The first middleware added is the outermost one. Middleware set with
+// WithMiddleware wraps it.
+func (h *HttpClient) Use(middleware ...Middleware) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.middleware = append(h.middleware, middleware...)
+}
+
+// do sends r through the middleware chain, the response watchdog, the decompression limits and the header policy
+// to the underlying http.Client.
+func (h *HttpClient) do(r *http.Request) (*http.Response, error) {
+	h.mu.RLock()
+	middleware := append(append([]Middleware(nil), h.config.middleware...), h.middleware...)
+	client := h.client
+	limits := h.config.watchdog
+	decompression := h.config.decompression
+	compression := h.config.compression
+	observed := h.config.events != nil
+	flags := h.config.flags
+	http2Fallback := h.config.http2Fallback
+	policy := h.config.effectiveHeaderPolicy()
+	h.mu.RUnlock()
+
+	if flags != nil {
+		r = r.WithContext(withFlags(r.Context(), flags))
+	}
+
+	acceptEncoding := ""
+	var forward *passthrough
+	if options := requestOptionsFrom(r.Context()); options != nil {
+		if options.watchdog != nil {
+			limits = *options.watchdog
+		}
+		if options.compression != nil {
+			compression = *options.compression
+			acceptEncoding = options.acceptEncoding
+		}
+		if options.passthrough != nil {
+			forward = options.passthrough
+			decompression = decompressionLimits{}
+		}
+		if options.tls != nil {
+			overridden, err := h.tlsOverrideClient(client, *options.tls)
+			if err != nil {
+				return nil, err
+			}
+			client = overridden
+		}
+		if options.untimed && client.Timeout != 0 {
+			untimed := *client
+			untimed.Timeout = 0
+			client = &untimed
+		}
+	}
+
+	client, err := h.pinDNS(client, r)
+	if err != nil {
+		return nil, err
+	}
+
+	redirected := *client
+	redirected.CheckRedirect = policy.checkRedirect(client.CheckRedirect)
+	if forward != nil {
+		// redirects are passed on to the downstream client
+		redirected.CheckRedirect = func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		}
+	}
+	send := RoundTripperFunc(redirected.Do)
+	if http2Fallback {
+		send = fallbackToHTTP1(send, h.http1Client(&redirected).Do)
+	}
+	next := applyHeaderPolicy(send, policy)
+	if forward != nil {
+		next = applyPassthrough(send, forward)
+	}
+	if decompression.enabled() {
+		next = decompress(next, decompression)
+	}
+	next = negotiateCompression(next, compression, acceptEncoding)
+	if observed {
+		next = h.observeAttempts(next)
+	} else {
+		next = countAttempts(next)
+	}
+	if limits.enabled() {
+		next = watchdog(next, limits)
+	}
+	next = Chain(middleware...)(next)
+
+	r, cancel, err := applySLOBudget(r)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := next(r)
+	if cancel == nil {
+		return resp, err
+	}
+	if resp == nil || resp.Body == nil {
+		cancel()
+		return resp, err
+	}
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, err
+}
@@ -0,0 +1,98 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestHttpClient_UseMiddlewareOrder(t *testing.T) {
+	server := mockServer(http.StatusOK, contentTypeJSON, fixtureBasicJSON)
+	defer server.Close()
+
+	var calls []string
+	tracing := func(name string) Middleware {
+		return func(next RoundTripperFunc) RoundTripperFunc {
+			return func(r *http.Request) (*http.Response, error) {
+				calls = append(calls, name+">")
+				resp, err := next(r)
+				calls = append(calls, "<"+name)
+				return resp, err
+			}
+		}
+	}
+
+	client := createTestHTTPClient(server.URL)
+	client.Use(tracing("outer"), tracing("inner"))
+	resp, _ := client.GetFrom("")
+
+	assertResponseHasStatus(resp, http.StatusOK, t)
+	expected := []string{"outer>", "inner>", "<inner", "<outer"}
+	if len(calls) != len(expected) {
+		t.Fatalf("Expected %v but got %v", expected, calls)
+	}
+	for i := range expected {
+		if calls[i] != expected[i] {
+			t.Errorf("Expected %v but got %v", expected, calls)
+		}
+	}
+}
+
+func TestWithMiddleware(t *testing.T) {
+	server := mockServer(http.StatusOK, contentTypeJSON, fixtureBasicJSON)
+	defer server.Close()
+
+	var calls []string
+	recording := func(name string) Middleware {
+		return func(next RoundTripperFunc) RoundTripperFunc {
+			return func(r *http.Request) (*http.Response, error) {
+				calls = append(calls, name+" "+r.Method)
+				return next(r)
+			}
+		}
+	}
+
+	client := NewHttpClientWithConfig(NewDefaultHttpConfig(server.URL, WithMiddleware(recording("config"))))
+	client.Use(recording("used"))
+
+	requests := []func() (*http.Response, error){
+		func() (*http.Response, error) { return client.GetFrom("/") },
+		func() (*http.Response, error) { return client.PostTo("/", strings.NewReader("{}")) },
+		func() (*http.Response, error) { return client.PutTo("/", strings.NewReader("{}")) },
+		func() (*http.Response, error) { return client.DeleteFrom("/") },
+	}
+	for _, request := range requests {
+		resp, err := request()
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+
+	expected := "[config GET used GET config POST used POST config PUT used PUT config DELETE used DELETE]"
+	if fmt.Sprint(calls) != expected {
+		t.Errorf("Expected %s but got %v", expected, calls)
+	}
+}
+
+func TestChain(t *testing.T) {
+	var order string
+	named := func(name string) Middleware {
+		return func(next RoundTripperFunc) RoundTripperFunc {
+			return func(r *http.Request) (*http.Response, error) {
+				order += name
+				return next(r)
+			}
+		}
+	}
+
+	send := Chain(named("a"), named("b"), Chain(named("c")))(func(r *http.Request) (*http.Response, error) {
+		return nil, nil
+	})
+	send(nil)
+
+	if order != "abc" {
+		t.Errorf("Expected middleware in order abc but got %s", order)
+	}
+}
@@ -0,0 +1,150 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// checksumAlgorithms are the algorithms a checksum of DownloadFromMirrors may use, by prefix.
+var checksumAlgorithms = map[string]func() hash.Hash{
+	"sha256": sha256.New,
+	"sha384": sha512.New384,
+	"sha512": sha512.New,
+}
+
+// ChecksumError is an artifact whose checksum does not match the expected one, or an expected checksum which
+// can not be parsed.
+type ChecksumError struct {
+	Message  string
+	URL      string
+	Expected string
+	Actual   string
+}
+
+func (e ChecksumError) Error() string {
+	return e.Message
+}
+
+// MirrorResult tells which mirror an artifact was downloaded from.
+type MirrorResult struct {
+	// Mirror the verified artifact was downloaded from.
+	Mirror string
+	// Size of the artifact in bytes.
+	Size int64
+	// Errors of the mirrors tried before, in order.
+	Errors []error
+}
+
+// DownloadFromMirrors downloads the same artifact from the first of mirrors which serves it with the expected
+// checksum, like "sha256:9f86d0...", to the file dst. sha256, sha384 and sha512 are supported. Mirrors are tried in
+// order; a mirror failing, answering with an unsuccessful status or serving content with another checksum, which
+// is reported as ChecksumError, is skipped. The file is only replaced once the content has been verified.
+//
+// Relative mirror URLs are resolved against the base URL like the paths of Get. Like all requests, absolute ones
+// to other hosts are sent without the client's credentials, as mirrors are usually public. If all mirrors fail, a
+// ScatterError with the error of each is returned.
+func (h *HttpClient) DownloadFromMirrors(ctx context.Context, mirrors []string, checksum string, dst string) (*MirrorResult, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	newHash, expected, err := parseChecksum(checksum)
+	if err != nil {
+		return nil, err
+	}
+	if len(mirrors) == 0 {
+		return nil, &ScatterError{Message: "No mirrors to download from."}
+	}
+
+	result := &MirrorResult{}
+	for _, mirror := range mirrors {
+		size, err := h.downloadVerified(ctx, mirror, newHash(), expected, dst)
+		if err == nil {
+			result.Mirror = mirror
+			result.Size = size
+			return result, nil
+		}
+		result.Errors = append(result.Errors, err)
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return result, &ScatterError{Message: fmt.Sprintf("All %d mirrors failed.", len(result.Errors)), Errors: result.Errors}
+}
+
+// downloadVerified downloads mirror into a temporary file next to dst and moves it to dst if its digest is expected.
+func (h *HttpClient) downloadVerified(ctx context.Context, mirror string, digest hash.Hash, expected []byte, dst string) (int64, error) {
+	request, err := h.mirrorRequest(ctx, mirror)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := h.ExecuteRequest(request)
+	if err != nil {
+		return 0, err
+	}
+	defer drainAndClose(resp.Body)
+	if ClassifyStatus(resp.StatusCode) != StatusClassSuccess {
+		return 0, statusError(resp)
+	}
+
+	temp, err := os.CreateTemp(filepath.Dir(dst), "."+filepath.Base(dst)+".*.tmp")
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(temp.Name())
+
+	size, err := io.Copy(io.MultiWriter(temp, digest), resp.Body)
+	if closeErr := temp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	if actual := digest.Sum(nil); !bytes.Equal(actual, expected) {
+		return 0, &ChecksumError{
+			Message:  fmt.Sprintf("Checksum of %s does not match.", request.URL.Redacted()),
+			URL:      request.URL.Redacted(),
+			Expected: hex.EncodeToString(expected),
+			Actual:   hex.EncodeToString(actual),
+		}
+	}
+	return size, os.Rename(temp.Name(), dst)
+}
+
+func (h *HttpClient) mirrorRequest(ctx context.Context, mirror string) (*http.Request, error) {
+	if !strings.Contains(mirror, "://") {
+		request, err := h.newRequest(ctx, http.MethodGet, mirror, nil)
+		if err != nil {
+			return nil, err
+		}
+		request.Header.Set("Accept", "*/*")
+		request.Header.Del("Content-Type")
+		return request.WithContext(ctx), nil
+	}
+
+	return http.NewRequestWithContext(ctx, http.MethodGet, mirror, nil)
+}
+
+// parseChecksum parses a checksum like "sha256:<hex digest>".
+func parseChecksum(checksum string) (func() hash.Hash, []byte, error) {
+	algorithm, digest, ok := strings.Cut(checksum, ":")
+	newHash, known := checksumAlgorithms[strings.ToLower(algorithm)]
+	if !ok || !known {
+		return nil, nil, &ChecksumError{Message: fmt.Sprintf("Checksum %q must be given as sha256, sha384 or sha512 like sha256:<hex digest>.", checksum), Expected: checksum}
+	}
+	expected, err := hex.DecodeString(digest)
+	if err != nil || len(expected) != newHash().Size() {
+		return nil, nil, &ChecksumError{Message: fmt.Sprintf("Checksum %q is not a valid %s digest.", checksum, algorithm), Expected: checksum}
+	}
+	return newHash, expected, nil
+}
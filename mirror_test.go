@@ -0,0 +1,92 @@
+package http
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func sha256Checksum(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+func TestDownloadFromMirrors(t *testing.T) {
+	down := mockServerWith(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	defer down.Close()
+	tampered := mockServerWith(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("tampered"))
+	})
+	defer tampered.Close()
+	var authorization string
+	good := mockServerWith(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		w.Write([]byte("artifact"))
+	})
+	defer good.Close()
+
+	dst := filepath.Join(t.TempDir(), "artifact.bin")
+	client := NewHttpClientWithConfig(NewHttpConfig(good.URL, "user", "secret", ""))
+	mirrors := []string{down.URL + "/a.bin", tampered.URL + "/a.bin", good.URL + "/a.bin"}
+
+	result, err := client.DownloadFromMirrors(context.Background(), mirrors, sha256Checksum("artifact"), dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Mirror != good.URL+"/a.bin" || result.Size != int64(len("artifact")) || len(result.Errors) != 2 {
+		t.Errorf("Unexpected result %+v", result)
+	}
+	var checksumErr *ChecksumError
+	if !errors.As(result.Errors[1], &checksumErr) || checksumErr.Actual != sha256Checksum("tampered")[len("sha256:"):] {
+		t.Errorf("Expected checksum error of the tampered mirror but got %v", result.Errors[1])
+	}
+	if content, _ := os.ReadFile(dst); string(content) != "artifact" {
+		t.Errorf("Unexpected content %q", content)
+	}
+	if authorization != "" {
+		t.Errorf("Expected mirrors to be requested without credentials but got %q", authorization)
+	}
+}
+
+func TestDownloadFromMirrors_AllFail(t *testing.T) {
+	server := mockServerWith(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("wrong"))
+	})
+	defer server.Close()
+
+	dir := t.TempDir()
+	dst := filepath.Join(dir, "artifact.bin")
+	os.WriteFile(dst, []byte("previous"), 0o644)
+
+	client := NewDefaultHttpClient(server.URL)
+	_, err := client.DownloadFromMirrors(context.Background(), []string{"/one", "/two"}, sha256Checksum("right"), dst)
+	var scatterErr *ScatterError
+	if !errors.As(err, &scatterErr) || len(scatterErr.Errors) != 2 {
+		t.Fatalf("Expected ScatterError of both mirrors but got %v", err)
+	}
+
+	if content, _ := os.ReadFile(dst); string(content) != "previous" {
+		t.Errorf("Expected the previous file to be kept but got %q", content)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("Expected temporary files to be removed but got %d entries", len(entries))
+	}
+}
+
+func TestDownloadFromMirrors_InvalidChecksum(t *testing.T) {
+	client := NewDefaultHttpClient("http://localhost")
+	for _, checksum := range []string{"abc", "md5:d41d8cd98f00b204e9800998ecf8427e", "sha256:zz", "sha256:abcd"} {
+		_, err := client.DownloadFromMirrors(context.Background(), []string{"/a"}, checksum, filepath.Join(t.TempDir(), "a"))
+		if _, ok := err.(*ChecksumError); !ok {
+			t.Errorf("Expected ChecksumError for %q but got %v", checksum, err)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package http
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strconv"
+	"strings"
+)
+
+// MultipartError is returned for responses which are no multipart response or have malformed parts.
+type MultipartError struct {
+	Message     string
+	ContentType string
+}
+
+func (e MultipartError) Error() string {
+	return e.Message
+}
+
+// MultipartReader streams the parts of a multipart/mixed or multipart/byteranges response, e.g. of batch APIs or
+// range requests, without buffering the whole payload.
+type MultipartReader struct {
+	resp   *http.Response
+	reader *multipart.Reader
+}
+
+// Part is a part of a multipart response. Its body is only valid until the next call to NextPart.
+type Part struct {
+	Header textproto.MIMEHeader
+	// Range is the byte range of a multipart/byteranges part, nil for other parts.
+	Range *ContentRange
+	Body  io.Reader
+}
+
+// ContentRange is the Content-Range of a byte range, Total is -1 if unknown.
+type ContentRange struct {
+	Start int64
+	End   int64
+	Total int64
+}
+
+// NewMultipartReader returns a reader over the parts of resp. Its Close closes the response body.
+func NewMultipartReader(resp *http.Response) (*MultipartReader, error) {
+	contentType := resp.Header.Get("Content-Type")
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil || !strings.HasPrefix(mediaType, "multipart/") {
+		return nil, &MultipartError{Message: fmt.Sprintf("Response of type %q is no multipart response.", contentType), ContentType: contentType}
+	}
+	if params["boundary"] == "" {
+		return nil, &MultipartError{Message: "Multipart response has no boundary.", ContentType: contentType}
+	}
+	return &MultipartReader{resp: resp, reader: multipart.NewReader(resp.Body, params["boundary"])}, nil
+}
+
+// NextPart returns the next part, or io.EOF after the last one.
+func (m *MultipartReader) NextPart() (*Part, error) {
+	part, err := m.reader.NextRawPart()
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Part{Header: part.Header, Body: part}
+	if contentRange := part.Header.Get("Content-Range"); contentRange != "" {
+		if p.Range, err = parseContentRange(contentRange); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+// Close closes the response body.
+func (m *MultipartReader) Close() error {
+	return m.resp.Body.Close()
+}
+
+// parseContentRange parses "bytes start-end/total".
+func parseContentRange(value string) (*ContentRange, error) {
+	invalid := &MultipartError{Message: fmt.Sprintf("Invalid Content-Range %q.", value)}
+	spec := strings.TrimPrefix(strings.TrimSpace(value), "bytes ")
+	span, total, ok := strings.Cut(spec, "/")
+	startText, endText, found := strings.Cut(span, "-")
+	if !ok || !found {
+		return nil, invalid
+	}
+
+	r := &ContentRange{Total: -1}
+	var err error
+	if r.Start, err = strconv.ParseInt(startText, 10, 64); err != nil {
+		return nil, invalid
+	}
+	if r.End, err = strconv.ParseInt(endText, 10, 64); err != nil || r.End < r.Start {
+		return nil, invalid
+	}
+	if total != "*" {
+		if r.Total, err = strconv.ParseInt(total, 10, 64); err != nil {
+			return nil, invalid
+		}
+	}
+	return r, nil
+}
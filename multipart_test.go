@@ -0,0 +1,60 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+)
+
+func TestMultipartReader(t *testing.T) {
+	server := mockServerWith(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "multipart/byteranges; boundary=SEP")
+		io.WriteString(w, "--SEP\r\nContent-Type: text/plain\r\nContent-Range: bytes 0-4/20\r\n\r\nhello\r\n"+
+			"--SEP\r\nContent-Type: text/plain\r\nContent-Range: bytes 10-14/*\r\n\r\nworld\r\n--SEP--\r\n")
+	})
+	defer server.Close()
+
+	resp, err := createTestHTTPClient(server.URL).Get(context.Background(), "/file")
+	if err != nil {
+		t.Fatal(err)
+	}
+	reader, err := NewMultipartReader(resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reader.Close()
+
+	var bodies []string
+	var ranges []ContentRange
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(part.Body)
+		bodies = append(bodies, string(body))
+		ranges = append(ranges, *part.Range)
+	}
+
+	if len(bodies) != 2 || bodies[0] != "hello" || bodies[1] != "world" {
+		t.Errorf("Unexpected parts %q", bodies)
+	}
+	if ranges[0] != (ContentRange{Start: 0, End: 4, Total: 20}) || ranges[1] != (ContentRange{Start: 10, End: 14, Total: -1}) {
+		t.Errorf("Unexpected ranges %+v", ranges)
+	}
+}
+
+func TestNewMultipartReader_NoMultipart(t *testing.T) {
+	resp := &http.Response{Header: http.Header{"Content-Type": {"application/json"}}, Body: http.NoBody}
+
+	_, err := NewMultipartReader(resp)
+	var multipartErr *MultipartError
+	if !errors.As(err, &multipartErr) {
+		t.Errorf("Expected MultipartError but got %v", err)
+	}
+}
@@ -0,0 +1,378 @@
+package http
+
+import (
+	"bytes"
+	"container/list"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultNegativeCacheTTL      = 30 * time.Second
+	defaultNegativeCacheMaxStale = 5 * time.Minute
+)
+
+// NegativeCache is a Middleware caching 404 and 410 responses of GET and HEAD requests for a short time, so
+// read-heavy services don't repeatedly look up resources known to be missing. Entries are keyed by method and
+// URL; the cache should not be shared by clients whose credentials see different resources.
+//
+// A successful POST, PUT, PATCH or DELETE to a URL invalidates its entries, e.g. when the missing resource is
+// created through the same client. Resources created elsewhere can be invalidated explicitly.
+//
+// Expired entries with an ETag or Last-Modified header are revalidated with a conditional request. If the
+// server can not be reached, an entry expired for at most the max stale age is served as stale, unless the
+// request itself was canceled or timed out. Every response passing the cache carries its CacheStatus.
+//
+// The cache can be bounded by entries and bytes, evicting the least recently used entries first.
+type NegativeCache struct {
+	ttl        time.Duration
+	statuses   map[int]bool
+	clock      Clock
+	events     Events
+	maxEntries int
+	maxBytes   int64
+	maxStale   time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*negativeEntry
+	lru     *list.List
+	bytes   int64
+}
+
+type negativeEntry struct {
+	key     string
+	url     string
+	status  int
+	header  http.Header
+	body    []byte
+	expires time.Time
+	size    int64
+	element *list.Element
+}
+
+// NewNegativeCache creates a NegativeCache keeping responses for ttl. A ttl of zero uses 30 seconds.
+func NewNegativeCache(ttl time.Duration) *NegativeCache {
+	if ttl <= 0 {
+		ttl = defaultNegativeCacheTTL
+	}
+	return &NegativeCache{
+		ttl:      ttl,
+		statuses: map[int]bool{http.StatusNotFound: true, http.StatusGone: true},
+		clock:    SystemClock(),
+		maxStale: defaultNegativeCacheMaxStale,
+		entries:  make(map[string]*negativeEntry),
+		lru:      list.New(),
+	}
+}
+
+// WithMaxEntries limits the number of entries. Zero means unlimited.
+func (c *NegativeCache) WithMaxEntries(max int) *NegativeCache {
+	c.maxEntries = max
+	return c
+}
+
+// WithMaxBytes limits the total size of the entries, counting URL, headers and body. Responses larger than
+// max are not cached. Zero means unlimited.
+func (c *NegativeCache) WithMaxBytes(max int64) *NegativeCache {
+	c.maxBytes = max
+	return c
+}
+
+// WithMaxStale sets how long after expiry an entry may be served while the server can not be reached,
+// 5 minutes by default. Zero never serves stale entries.
+func (c *NegativeCache) WithMaxStale(maxStale time.Duration) *NegativeCache {
+	c.maxStale = maxStale
+	return c
+}
+
+// WithClock sets the clock entries expire by.
+func (c *NegativeCache) WithClock(clock Clock) *NegativeCache {
+	c.clock = clock
+	return c
+}
+
+// WithEvents reports hits, misses, revalidations, stores and evictions to events, e.g. the Stats or Events
+// the client is configured with.
+func (c *NegativeCache) WithEvents(events Events) *NegativeCache {
+	c.events = events
+	return c
+}
+
+// Invalidate removes the entries of rawURL.
+func (c *NegativeCache) Invalidate(rawURL string) {
+	c.invalidate(func(e *negativeEntry) bool { return e.url == rawURL })
+}
+
+// InvalidatePrefix removes the entries of all URLs starting with prefix, e.g. a collection URL.
+func (c *NegativeCache) InvalidatePrefix(prefix string) {
+	c.invalidate(func(e *negativeEntry) bool { return strings.HasPrefix(e.url, prefix) })
+}
+
+// Clear removes all entries.
+func (c *NegativeCache) Clear() {
+	c.invalidate(func(*negativeEntry) bool { return true })
+}
+
+// Len returns the number of entries, including expired ones not yet removed.
+func (c *NegativeCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+// Bytes returns the total size of the entries.
+func (c *NegativeCache) Bytes() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.bytes
+}
+
+func (c *NegativeCache) invalidate(match func(*negativeEntry) bool) {
+	c.mu.Lock()
+	var evicted []*negativeEntry
+	for _, e := range c.entries {
+		if match(e) {
+			c.unlink(e)
+			evicted = append(evicted, e)
+		}
+	}
+	c.mu.Unlock()
+
+	c.emitEvicted(evicted)
+}
+
+// unlink removes e from the cache. It must be called with c.mu held.
+func (c *NegativeCache) unlink(e *negativeEntry) {
+	delete(c.entries, e.key)
+	c.lru.Remove(e.element)
+	c.bytes -= e.size
+}
+
+// link adds e to the cache and returns the entries evicted to make room. It must be called with c.mu held.
+func (c *NegativeCache) link(e *negativeEntry) []*negativeEntry {
+	var evicted []*negativeEntry
+	if previous, ok := c.entries[e.key]; ok {
+		c.unlink(previous)
+	}
+
+	c.entries[e.key] = e
+	e.element = c.lru.PushFront(e)
+	c.bytes += e.size
+
+	for (c.maxEntries > 0 && len(c.entries) > c.maxEntries) || (c.maxBytes > 0 && c.bytes > c.maxBytes) {
+		oldest := c.lru.Back().Value.(*negativeEntry)
+		c.unlink(oldest)
+		evicted = append(evicted, oldest)
+	}
+	return evicted
+}
+
+func (c *NegativeCache) emitEvicted(evicted []*negativeEntry) {
+	for _, e := range evicted {
+		c.emit(Event{Type: EventCacheEvicted, Host: hostOf(e.url)})
+	}
+}
+
+// Middleware returns the Middleware answering from and filling the cache.
+func (c *NegativeCache) Middleware() Middleware {
+	return func(next RoundTripperFunc) RoundTripperFunc {
+		return func(r *http.Request) (*http.Response, error) {
+			rawURL := r.URL.String()
+			if r.Method != http.MethodGet && r.Method != http.MethodHead {
+				resp, err := next(r)
+				if err == nil && isInvalidatingMethod(r.Method) && resp.StatusCode < 400 {
+					c.Invalidate(rawURL)
+				}
+				return resp, err
+			}
+
+			key := r.Method + " " + rawURL
+			entry, fresh := c.lookup(key)
+			if fresh {
+				return c.serve(entry, r, CacheStatusHit), nil
+			}
+
+			sent := r
+			if entry != nil {
+				if validated := entry.conditional(r); validated != nil {
+					sent = validated
+				}
+			}
+			if sent == r {
+				c.emit(Event{Type: EventCacheMiss, Request: r})
+			}
+
+			resp, err := next(sent)
+			switch {
+			case err != nil && entry != nil && r.Context().Err() == nil && c.servableStale(entry):
+				return c.serve(entry, r, CacheStatusStale), nil
+			case err != nil:
+				return resp, err
+			case resp.StatusCode == http.StatusNotModified && entry != nil:
+				drainAndClose(resp.Body)
+				c.refresh(key, entry)
+				c.emit(Event{Type: EventCacheRevalidated, Request: r})
+				return c.serve(entry, r, CacheStatusRevalidated), nil
+			}
+
+			if entry != nil {
+				c.remove(key, entry)
+			}
+			if !c.statuses[resp.StatusCode] {
+				setCacheStatus(resp, CacheStatusMiss)
+				return resp, nil
+			}
+			return c.store(key, rawURL, resp)
+		}
+	}
+}
+
+// lookup returns the entry of key and whether it is still fresh.
+func (c *NegativeCache) lookup(key string) (*negativeEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.lru.MoveToFront(e.element)
+	return e, c.clock.Now().Before(e.expires)
+}
+
+// servableStale reports whether the expired entry e is young enough to be served as stale.
+func (c *NegativeCache) servableStale(e *negativeEntry) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.clock.Now().Before(e.expires.Add(c.maxStale))
+}
+
+func (c *NegativeCache) serve(e *negativeEntry, r *http.Request, status CacheStatus) *http.Response {
+	if status != CacheStatusRevalidated {
+		c.emit(Event{Type: EventCacheHit, Request: r, CacheStatus: status})
+	}
+	resp := e.response(r)
+	setCacheStatus(resp, status)
+	return resp
+}
+
+func (c *NegativeCache) refresh(key string, e *negativeEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.entries[key] == e {
+		e.expires = c.clock.Now().Add(c.ttl)
+	}
+}
+
+func (c *NegativeCache) remove(key string, e *negativeEntry) {
+	c.mu.Lock()
+	removed := c.entries[key] == e
+	if removed {
+		c.unlink(e)
+	}
+	c.mu.Unlock()
+
+	if removed {
+		c.emitEvicted([]*negativeEntry{e})
+	}
+}
+
+func (c *NegativeCache) store(key string, rawURL string, resp *http.Response) (*http.Response, error) {
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	setCacheStatus(resp, CacheStatusMiss)
+	e := &negativeEntry{
+		key:    key,
+		url:    rawURL,
+		status: resp.StatusCode,
+		header: resp.Header.Clone(),
+		body:   body,
+	}
+	e.header.Del(CacheStatusHeader)
+	e.size = e.sizeOf()
+	if c.maxBytes > 0 && e.size > c.maxBytes {
+		return resp, nil
+	}
+
+	c.mu.Lock()
+	e.expires = c.clock.Now().Add(c.ttl)
+	evicted := c.link(e)
+	c.mu.Unlock()
+
+	c.emit(Event{Type: EventCacheStored, Request: resp.Request, Response: resp})
+	c.emitEvicted(evicted)
+	return resp, nil
+}
+
+func (c *NegativeCache) emit(e Event) {
+	if c.events == nil {
+		return
+	}
+	e.Time = c.clock.Now()
+	if e.Host == "" && e.Request != nil {
+		e.Host = e.Request.URL.Host
+	}
+	c.events.Emit(e)
+}
+
+// sizeOf accounts the memory held by e.
+func (e *negativeEntry) sizeOf() int64 {
+	size := len(e.key) + len(e.url) + len(e.body)
+	for key, values := range e.header {
+		size += len(key)
+		for _, value := range values {
+			size += len(value)
+		}
+	}
+	return int64(size)
+}
+
+// conditional returns a copy of r asking the server whether e is still current, or nil if e has no
+// validators.
+func (e *negativeEntry) conditional(r *http.Request) *http.Request {
+	etag, lastModified := e.header.Get("ETag"), e.header.Get("Last-Modified")
+	if etag == "" && lastModified == "" {
+		return nil
+	}
+
+	validated := r.Clone(r.Context())
+	if etag != "" {
+		validated.Header.Set("If-None-Match", etag)
+	}
+	if lastModified != "" {
+		validated.Header.Set("If-Modified-Since", lastModified)
+	}
+	return validated
+}
+
+func (e *negativeEntry) response(r *http.Request) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", e.status, http.StatusText(e.status)),
+		StatusCode:    e.status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        e.header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(e.body)),
+		ContentLength: int64(len(e.body)),
+		Request:       r,
+	}
+}
+
+func isInvalidatingMethod(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,237 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestNegativeCache(t *testing.T) {
+	var lookups int32
+	var exists int32
+	server := mockServerWith(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			atomic.AddInt32(&lookups, 1)
+		}
+		if r.Method == http.MethodPut {
+			atomic.StoreInt32(&exists, 1)
+		}
+		if atomic.LoadInt32(&exists) == 0 {
+			http.Error(w, "missing", http.StatusNotFound)
+		}
+	})
+	defer server.Close()
+
+	clock := NewFakeClock(time.Unix(1000, 0))
+	cache := NewNegativeCache(time.Minute).WithClock(clock)
+	client := createTestHTTPClient(server.URL)
+	client.Use(cache.Middleware())
+
+	for i := 0; i < 3; i++ {
+		resp, _ := client.GetFrom("/users/1")
+		assertResponseHasStatus(resp, http.StatusNotFound, t)
+		assertResponseBodyIs(resp, "missing\n", t)
+	}
+	if atomic.LoadInt32(&lookups) != 1 || cache.Len() != 1 {
+		t.Errorf("Expected one lookup but got %d", lookups)
+	}
+
+	clock.Advance(time.Minute)
+	resp, _ := client.GetFrom("/users/1")
+	resp.Body.Close()
+	if atomic.LoadInt32(&lookups) != 2 {
+		t.Errorf("Expected lookup after expiry but got %d", lookups)
+	}
+
+	// creating the resource through the client invalidates the entry
+	resp, _ = client.PutTo("/users/1", strings.NewReader(fixtureBasicJSON))
+	resp.Body.Close()
+	resp, _ = client.GetFrom("/users/1")
+	resp.Body.Close()
+	assertResponseHasStatus(resp, http.StatusOK, t)
+	if cache.Len() != 0 {
+		t.Errorf("Expected no entries but got %d", cache.Len())
+	}
+}
+
+func TestNegativeCache_Invalidate(t *testing.T) {
+	server := mockServer(http.StatusNotFound, contentTypeJSON, "")
+	defer server.Close()
+
+	cache := NewNegativeCache(0)
+	client := createTestHTTPClient(server.URL)
+	client.Use(cache.Middleware())
+
+	for _, path := range []string{"/users/1", "/users/2", "/groups/1"} {
+		resp, _ := client.GetFrom(path)
+		resp.Body.Close()
+	}
+
+	cache.Invalidate(server.URL + "/groups/1")
+	if cache.Len() != 2 {
+		t.Errorf("Expected 2 entries but got %d", cache.Len())
+	}
+	cache.InvalidatePrefix(server.URL + "/users/")
+	if cache.Len() != 0 {
+		t.Errorf("Expected no entries but got %d", cache.Len())
+	}
+
+	resp, _ := client.GetFrom("/users/1")
+	resp.Body.Close()
+	cache.Clear()
+	if cache.Len() != 0 {
+		t.Errorf("Expected no entries after Clear but got %d", cache.Len())
+	}
+}
+
+func TestNegativeCache_Instrumentation(t *testing.T) {
+	var down int32
+	server := mockServerWith(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.WriteHeader(http.StatusNotFound)
+	})
+	defer server.Close()
+
+	clock := NewFakeClock(time.Unix(1000, 0))
+	stats := NewStats()
+	cache := NewNegativeCache(time.Minute).WithClock(clock).WithEvents(stats)
+	client := createTestHTTPClient(server.URL)
+	client.Use(cache.Middleware(), func(next RoundTripperFunc) RoundTripperFunc {
+		return func(r *http.Request) (*http.Response, error) {
+			if atomic.LoadInt32(&down) == 1 {
+				return nil, errors.New("unreachable")
+			}
+			return next(r)
+		}
+	})
+
+	get := func() CacheStatus {
+		resp, err := client.GetFrom("/users/1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		assertResponseHasStatus(resp, http.StatusNotFound, t)
+		return CacheStatusOf(resp)
+	}
+
+	statuses := []CacheStatus{get(), get()}
+	clock.Advance(2 * time.Minute)
+	statuses = append(statuses, get())
+	clock.Advance(2 * time.Minute)
+	atomic.StoreInt32(&down, 1)
+	statuses = append(statuses, get())
+	cache.Clear()
+
+	expected := []CacheStatus{CacheStatusMiss, CacheStatusHit, CacheStatusRevalidated, CacheStatusStale}
+	if !reflect.DeepEqual(statuses, expected) {
+		t.Errorf("Expected %v but got %v", expected, statuses)
+	}
+	snapshot := stats.Snapshot()
+	if snapshot.CacheMisses != 1 || snapshot.CacheHits != 2 || snapshot.CacheStaleHits != 1 ||
+		snapshot.CacheRevalidated != 1 || snapshot.CacheStores != 1 || snapshot.CacheEvictions != 1 {
+		t.Errorf("Unexpected cache counters %+v", snapshot)
+	}
+	if snapshot.CacheHitRatio != 0.75 {
+		t.Errorf("Expected hit ratio of 0.75 but got %f", snapshot.CacheHitRatio)
+	}
+}
+
+func TestNegativeCache_Limits(t *testing.T) {
+	server := mockServer(http.StatusNotFound, contentTypeJSON, strings.Repeat("x", 100))
+	defer server.Close()
+
+	stats := NewStats()
+	cache := NewNegativeCache(time.Minute).WithMaxEntries(2).WithEvents(stats)
+	client := createTestHTTPClient(server.URL)
+	client.Use(cache.Middleware())
+
+	get := func(path string) CacheStatus {
+		resp, _ := client.GetFrom(path)
+		resp.Body.Close()
+		return CacheStatusOf(resp)
+	}
+
+	get("/a")
+	get("/b")
+	get("/a") // a is now the most recently used
+	get("/c") // evicts b
+	if cache.Len() != 2 || stats.Snapshot().CacheEvictions != 1 {
+		t.Errorf("Expected one eviction but got %d entries, %+v", cache.Len(), stats.Snapshot())
+	}
+	if get("/a") != CacheStatusHit || get("/b") != CacheStatusMiss {
+		t.Error("Expected the least recently used entry to be evicted")
+	}
+
+	entrySize := cache.Bytes() / int64(cache.Len())
+	bounded := NewNegativeCache(time.Minute).WithMaxBytes(entrySize * 3 / 2)
+	client = createTestHTTPClient(server.URL)
+	client.Use(bounded.Middleware())
+	get("/a")
+	get("/b")
+	if bounded.Len() != 1 || bounded.Bytes() > entrySize*3/2 {
+		t.Errorf("Expected the size limit to hold one entry but got %d entries, %d bytes", bounded.Len(), bounded.Bytes())
+	}
+
+	tiny := NewNegativeCache(time.Minute).WithMaxBytes(10)
+	client = createTestHTTPClient(server.URL)
+	client.Use(tiny.Middleware())
+	get("/a")
+	if tiny.Len() != 0 {
+		t.Error("Expected responses larger than the limit not to be cached")
+	}
+}
+
+func TestNegativeCache_StaleLimits(t *testing.T) {
+	server := mockServer(http.StatusNotFound, contentTypeJSON, "")
+	defer server.Close()
+
+	clock := NewFakeClock(time.Unix(1000, 0))
+	cache := NewNegativeCache(time.Minute).WithClock(clock).WithMaxStale(time.Minute)
+	unreachable := errors.New("unreachable")
+	var down int32
+	middleware := cache.Middleware()(func(r *http.Request) (*http.Response, error) {
+		if atomic.LoadInt32(&down) == 1 {
+			return nil, unreachable
+		}
+		return http.DefaultTransport.RoundTrip(r)
+	})
+
+	get := func(ctx context.Context) error {
+		request, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/users/1", nil)
+		resp, err := middleware(request)
+		if resp != nil {
+			resp.Body.Close()
+		}
+		return err
+	}
+
+	if err := get(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	atomic.StoreInt32(&down, 1)
+	clock.Advance(90 * time.Second)
+	if err := get(context.Background()); err != nil {
+		t.Errorf("Expected a stale entry within the max stale age but got %v", err)
+	}
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := get(canceled); err != unreachable {
+		t.Errorf("Expected no stale entry for a canceled request but got %v", err)
+	}
+
+	clock.Advance(time.Minute)
+	if err := get(context.Background()); err != unreachable {
+		t.Errorf("Expected no stale entry beyond the max stale age but got %v", err)
+	}
+}
@@ -0,0 +1,333 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"math/bits"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf16"
+)
+
+const (
+	ntlmNegotiateUnicode          = 0x00000001
+	ntlmNegotiateOEM              = 0x00000002
+	ntlmRequestTarget             = 0x00000004
+	ntlmNegotiateNTLM             = 0x00000200
+	ntlmNegotiateAlwaysSign       = 0x00008000
+	ntlmNegotiateExtendedSecurity = 0x00080000
+	ntlmNegotiateTargetInfo       = 0x00800000
+	ntlmNegotiate128              = 0x20000000
+	ntlmNegotiate56               = 0x80000000
+
+	ntlmNegotiateFlags = ntlmNegotiateUnicode | ntlmNegotiateOEM | ntlmRequestTarget | ntlmNegotiateNTLM |
+		ntlmNegotiateAlwaysSign | ntlmNegotiateExtendedSecurity | ntlmNegotiate128 | ntlmNegotiate56
+
+	// windowsEpochOffset is the number of 100ns intervals between 1601-01-01 and 1970-01-01.
+	windowsEpochOffset = 116444736000000000
+)
+
+var ntlmSignature = []byte("NTLMSSP\x00")
+
+type ntlmAuth struct {
+	source CredentialSource
+	proxy  bool
+}
+
+// NTLMAuth returns a ChallengeAuthProvider performing the NTLMv2 handshake with the credentials of source.
+// The username may be qualified with a domain as DOMAIN\user.
+func NTLMAuth(source CredentialSource) ChallengeAuthProvider {
+	if source == nil {
+		panic("source is nil")
+	}
+	return &ntlmAuth{source: source}
+}
+
+// NTLMProxyAuth returns a ChallengeAuthProvider performing the NTLMv2 handshake with a proxy
+// answering 407 responses. CONNECT tunnels for https destinations are authenticated by the transport
+// and not covered.
+func NTLMProxyAuth(source CredentialSource) ChallengeAuthProvider {
+	if source == nil {
+		panic("source is nil")
+	}
+	return &ntlmAuth{source: source, proxy: true}
+}
+
+func (a *ntlmAuth) Authenticate(r *http.Request) error {
+	r.Header.Set(a.authorizationHeader(), "NTLM "+base64.StdEncoding.EncodeToString(ntlmNegotiateMessage()))
+	return nil
+}
+
+func (a *ntlmAuth) Challenge(r *http.Request, resp *http.Response) (bool, error) {
+	if a.proxy != (resp.StatusCode == http.StatusProxyAuthRequired) {
+		return false, nil
+	}
+
+	token, ok := authChallengeToken(resp.Header.Values(a.challengeHeader()), "NTLM")
+	if !ok {
+		return false, nil
+	}
+	if token == nil {
+		// the server asks for NTLM but did not receive our negotiate message
+		return true, a.Authenticate(r)
+	}
+
+	challenge, err := parseNTLMChallenge(token)
+	if err != nil {
+		return false, err
+	}
+	credentials, err := a.source.Credentials(r.Context())
+	if err != nil {
+		return false, err
+	}
+
+	domain, user := "", credentials.Username
+	if i := strings.IndexByte(user, '\\'); i >= 0 {
+		domain, user = user[:i], user[i+1:]
+	}
+
+	clientChallenge := make([]byte, 8)
+	if _, err := rand.Read(clientChallenge); err != nil {
+		return false, err
+	}
+
+	msg := ntlmAuthenticateMessage(challenge, domain, user, credentials.Password, clientChallenge, ntlmTimestamp(time.Now()))
+	r.Header.Set(a.authorizationHeader(), "NTLM "+base64.StdEncoding.EncodeToString(msg))
+	return true, nil
+}
+
+func (a *ntlmAuth) authorizationHeader() string {
+	if a.proxy {
+		return "Proxy-Authorization"
+	}
+	return "Authorization"
+}
+
+func (a *ntlmAuth) challengeHeader() string {
+	if a.proxy {
+		return "Proxy-Authenticate"
+	}
+	return "WWW-Authenticate"
+}
+
+// SPNEGOMechanism produces the tokens of a SPNEGO security context, e.g. backed by gokrb5 or Windows SSPI.
+type SPNEGOMechanism interface {
+	// InitSecContext returns the next token for the service principal spn. Input is nil for the
+	// initial token and the server's token for continuation steps.
+	InitSecContext(ctx context.Context, spn string, input []byte) ([]byte, error)
+}
+
+type negotiateAuth struct {
+	mechanism SPNEGOMechanism
+}
+
+// NegotiateAuth returns a ChallengeAuthProvider performing the SPNEGO (Kerberos) handshake
+// for the service principal HTTP/<host>.
+func NegotiateAuth(mechanism SPNEGOMechanism) ChallengeAuthProvider {
+	if mechanism == nil {
+		panic("mechanism is nil")
+	}
+	return &negotiateAuth{mechanism: mechanism}
+}
+
+func (a *negotiateAuth) Authenticate(r *http.Request) error {
+	return a.step(r, nil)
+}
+
+func (a *negotiateAuth) Challenge(r *http.Request, resp *http.Response) (bool, error) {
+	if resp.StatusCode != http.StatusUnauthorized {
+		return false, nil
+	}
+
+	token, ok := authChallengeToken(resp.Header.Values("WWW-Authenticate"), "Negotiate")
+	if !ok || token == nil {
+		// a bare Negotiate challenge after our initial token means it was rejected
+		return false, nil
+	}
+	return true, a.step(r, token)
+}
+
+func (a *negotiateAuth) step(r *http.Request, input []byte) error {
+	token, err := a.mechanism.InitSecContext(r.Context(), "HTTP/"+r.URL.Hostname(), input)
+	if err != nil {
+		return err
+	}
+	r.Header.Set("Authorization", "Negotiate "+base64.StdEncoding.EncodeToString(token))
+	return nil
+}
+
+// authChallengeToken finds the challenge of scheme in the given header values and decodes its token.
+// A challenge without token returns a nil token.
+func authChallengeToken(values []string, scheme string) ([]byte, bool) {
+	for _, value := range values {
+		fields := strings.Fields(value)
+		if len(fields) == 0 || !strings.EqualFold(fields[0], scheme) {
+			continue
+		}
+		if len(fields) == 1 {
+			return nil, true
+		}
+		token, err := base64.StdEncoding.DecodeString(fields[1])
+		if err != nil {
+			continue
+		}
+		return token, true
+	}
+	return nil, false
+}
+
+func ntlmNegotiateMessage() []byte {
+	msg := make([]byte, 32)
+	copy(msg, ntlmSignature)
+	binary.LittleEndian.PutUint32(msg[8:], 1)
+	binary.LittleEndian.PutUint32(msg[12:], ntlmNegotiateFlags)
+	// domain and workstation buffers stay empty
+	return msg
+}
+
+type ntlmChallenge struct {
+	flags           uint32
+	serverChallenge []byte
+	targetInfo      []byte
+}
+
+func parseNTLMChallenge(msg []byte) (*ntlmChallenge, error) {
+	if len(msg) < 32 || !bytes.Equal(msg[:8], ntlmSignature) || binary.LittleEndian.Uint32(msg[8:]) != 2 {
+		return nil, errors.New("invalid NTLM challenge message")
+	}
+
+	challenge := &ntlmChallenge{
+		flags:           binary.LittleEndian.Uint32(msg[20:]),
+		serverChallenge: msg[24:32],
+	}
+
+	if len(msg) >= 48 {
+		length := int(binary.LittleEndian.Uint16(msg[40:]))
+		offset := int(binary.LittleEndian.Uint32(msg[44:]))
+		if offset+length > len(msg) {
+			return nil, errors.New("invalid NTLM target info")
+		}
+		challenge.targetInfo = msg[offset : offset+length]
+	}
+
+	return challenge, nil
+}
+
+func ntlmAuthenticateMessage(c *ntlmChallenge, domain string, user string, password string, clientChallenge []byte, timestamp []byte) []byte {
+	ntHash := md4Sum(utf16le(password))
+	v2Hash := hmacMD5(ntHash[:], utf16le(strings.ToUpper(user)+domain))
+
+	var blob bytes.Buffer
+	blob.Write([]byte{1, 1, 0, 0, 0, 0, 0, 0})
+	blob.Write(timestamp)
+	blob.Write(clientChallenge)
+	blob.Write([]byte{0, 0, 0, 0})
+	blob.Write(c.targetInfo)
+	blob.Write([]byte{0, 0, 0, 0})
+
+	ntProof := hmacMD5(v2Hash, append(append([]byte(nil), c.serverChallenge...), blob.Bytes()...))
+	ntResponse := append(ntProof, blob.Bytes()...)
+	lmResponse := append(hmacMD5(v2Hash, append(append([]byte(nil), c.serverChallenge...), clientChallenge...)), clientChallenge...)
+
+	flags := uint32(ntlmNegotiateFlags)&c.flags | ntlmNegotiateUnicode
+	if c.flags&ntlmNegotiateTargetInfo != 0 {
+		flags |= ntlmNegotiateTargetInfo
+	}
+
+	payloads := [][]byte{lmResponse, ntResponse, utf16le(domain), utf16le(user), nil, nil}
+	msg := make([]byte, 64)
+	copy(msg, ntlmSignature)
+	binary.LittleEndian.PutUint32(msg[8:], 3)
+
+	// security buffers for LM, NT, domain, user, workstation and session key start at offset 12
+	offset := len(msg)
+	for i, payload := range payloads {
+		field := msg[12+8*i:]
+		binary.LittleEndian.PutUint16(field, uint16(len(payload)))
+		binary.LittleEndian.PutUint16(field[2:], uint16(len(payload)))
+		binary.LittleEndian.PutUint32(field[4:], uint32(offset))
+		offset += len(payload)
+	}
+	binary.LittleEndian.PutUint32(msg[60:], flags)
+
+	for _, payload := range payloads {
+		msg = append(msg, payload...)
+	}
+	return msg
+}
+
+// ntlmTimestamp encodes t as Windows FILETIME.
+func ntlmTimestamp(t time.Time) []byte {
+	timestamp := make([]byte, 8)
+	binary.LittleEndian.PutUint64(timestamp, uint64(t.UnixNano()/100+windowsEpochOffset))
+	return timestamp
+}
+
+func hmacMD5(key []byte, data []byte) []byte {
+	mac := hmac.New(md5.New, key)
+	mac.Write(data)
+	return mac.Sum(nil)
+}
+
+func utf16le(s string) []byte {
+	encoded := utf16.Encode([]rune(s))
+	b := make([]byte, 2*len(encoded))
+	for i, r := range encoded {
+		binary.LittleEndian.PutUint16(b[2*i:], r)
+	}
+	return b
+}
+
+// md4Sum implements RFC 1320, which NTLM requires for the NT hash but the standard library lacks.
+func md4Sum(data []byte) [16]byte {
+	a, b, c, d := uint32(0x67452301), uint32(0xefcdab89), uint32(0x98badcfe), uint32(0x10325476)
+
+	msg := append(append([]byte(nil), data...), 0x80)
+	for len(msg)%64 != 56 {
+		msg = append(msg, 0)
+	}
+	length := make([]byte, 8)
+	binary.LittleEndian.PutUint64(length, uint64(len(data))*8)
+	msg = append(msg, length...)
+
+	round1 := [16]int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}
+	round2 := [16]int{0, 4, 8, 12, 1, 5, 9, 13, 2, 6, 10, 14, 3, 7, 11, 15}
+	round3 := [16]int{0, 8, 4, 12, 2, 10, 6, 14, 1, 9, 5, 13, 3, 11, 7, 15}
+
+	for block := 0; block < len(msg); block += 64 {
+		var x [16]uint32
+		for i := range x {
+			x[i] = binary.LittleEndian.Uint32(msg[block+4*i:])
+		}
+		aa, bb, cc, dd := a, b, c, d
+
+		for i, k := range round1 {
+			t := bits.RotateLeft32(a+(b&c|^b&d)+x[k], [4]int{3, 7, 11, 19}[i%4])
+			a, b, c, d = d, t, b, c
+		}
+		for i, k := range round2 {
+			t := bits.RotateLeft32(a+(b&c|b&d|c&d)+x[k]+0x5a827999, [4]int{3, 5, 9, 13}[i%4])
+			a, b, c, d = d, t, b, c
+		}
+		for i, k := range round3 {
+			t := bits.RotateLeft32(a+(b^c^d)+x[k]+0x6ed9eba1, [4]int{3, 9, 11, 15}[i%4])
+			a, b, c, d = d, t, b, c
+		}
+
+		a, b, c, d = a+aa, b+bb, c+cc, d+dd
+	}
+
+	var sum [16]byte
+	binary.LittleEndian.PutUint32(sum[0:], a)
+	binary.LittleEndian.PutUint32(sum[4:], b)
+	binary.LittleEndian.PutUint32(sum[8:], c)
+	binary.LittleEndian.PutUint32(sum[12:], d)
+	return sum
+}
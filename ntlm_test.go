@@ -0,0 +1,124 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestMD4Sum(t *testing.T) {
+	cases := map[string]string{
+		"":    "31d6cfe0d16ae931b73c59d7e0c089c0",
+		"abc": "a448017aaf21d8525fc10ae87aa6729d",
+		"12345678901234567890123456789012345678901234567890123456789012345678901234567890": "e33b4ddc9c38f2199c3e7b164fcc0536",
+	}
+
+	for input, expected := range cases {
+		sum := md4Sum([]byte(input))
+		if hex.EncodeToString(sum[:]) != expected {
+			t.Errorf("Expected %s but got %x for %q", expected, sum, input)
+		}
+	}
+}
+
+// TestNTLMAuthenticateMessage uses the NTLMv2 example of MS-NLMP section 4.2.4.
+func TestNTLMAuthenticateMessage(t *testing.T) {
+	targetInfo, _ := hex.DecodeString("02000c0044006f006d00610069006e0001000c0053006500720076006500720000000000")
+	challenge := &ntlmChallenge{
+		flags:           ntlmNegotiateFlags | ntlmNegotiateTargetInfo,
+		serverChallenge: []byte{0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef},
+		targetInfo:      targetInfo,
+	}
+	clientChallenge := bytes.Repeat([]byte{0xaa}, 8)
+
+	msg := ntlmAuthenticateMessage(challenge, "Domain", "User", "Password", clientChallenge, make([]byte, 8))
+
+	lm := ntlmSecurityBuffer(msg, 12)
+	nt := ntlmSecurityBuffer(msg, 20)
+	if hex.EncodeToString(lm) != "86c35097ac9cec102554764a57cccc19aaaaaaaaaaaaaaaa" {
+		t.Errorf("Unexpected LMv2 response %x", lm)
+	}
+	if hex.EncodeToString(nt[:16]) != "68cd0ab851e51c96aabc927bebef6a1c" {
+		t.Errorf("Unexpected NTProofStr %x", nt[:16])
+	}
+}
+
+func TestHttpClient_NTLMHandshake(t *testing.T) {
+	f := func(w http.ResponseWriter, r *http.Request) {
+		auth := r.Header.Get("Authorization")
+		token, _ := base64.StdEncoding.DecodeString(strings.TrimPrefix(auth, "NTLM "))
+		switch {
+		case len(token) > 12 && token[8] == 1:
+			w.Header().Set("WWW-Authenticate", "NTLM "+base64.StdEncoding.EncodeToString(fixtureNTLMChallenge()))
+			w.WriteHeader(http.StatusUnauthorized)
+		case len(token) > 12 && token[8] == 3:
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.Header().Set("WWW-Authenticate", "NTLM")
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}
+	server := mockServerWith(http.HandlerFunc(f))
+	defer server.Close()
+
+	client := createTestHTTPClient(server.URL)
+	client.SetAuthProvider(NTLMAuth(StaticCredentials(`DOMAIN\user`, "secret")))
+
+	resp, err := client.GetFrom("")
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	assertResponseHasStatus(resp, http.StatusOK, t)
+}
+
+type stubSPNEGOMechanism struct {
+	spn string
+}
+
+func (m *stubSPNEGOMechanism) InitSecContext(ctx context.Context, spn string, input []byte) ([]byte, error) {
+	m.spn = spn
+	return []byte("kerberos-ticket"), nil
+}
+
+func TestHttpClient_NegotiateAuth(t *testing.T) {
+	var auth string
+	f := func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		w.WriteHeader(http.StatusOK)
+	}
+	server := mockServerWith(http.HandlerFunc(f))
+	defer server.Close()
+
+	mechanism := &stubSPNEGOMechanism{}
+	client := createTestHTTPClient(server.URL)
+	client.SetAuthProvider(NegotiateAuth(mechanism))
+	client.GetFrom("")
+
+	if auth != "Negotiate "+base64.StdEncoding.EncodeToString([]byte("kerberos-ticket")) {
+		t.Errorf("Expected Negotiate token but got %s", auth)
+	}
+	if mechanism.spn != "HTTP/127.0.0.1" {
+		t.Errorf("Expected SPN HTTP/127.0.0.1 but got %s", mechanism.spn)
+	}
+}
+
+func fixtureNTLMChallenge() []byte {
+	msg := make([]byte, 48)
+	copy(msg, ntlmSignature)
+	binary.LittleEndian.PutUint32(msg[8:], 2)
+	binary.LittleEndian.PutUint32(msg[20:], ntlmNegotiateFlags)
+	copy(msg[24:], "12345678")
+	binary.LittleEndian.PutUint32(msg[44:], 48)
+	return msg
+}
+
+func ntlmSecurityBuffer(msg []byte, field int) []byte {
+	length := int(binary.LittleEndian.Uint16(msg[field:]))
+	offset := int(binary.LittleEndian.Uint32(msg[field+4:]))
+	return msg[offset : offset+length]
+}
@@ -0,0 +1,336 @@
+package http
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	oidcDiscoveryPath = "/.well-known/openid-configuration"
+	formType          = "application/x-www-form-urlencoded"
+
+	// tokenExpirySkew renews tokens slightly before they expire to cover clock skew and latency.
+	tokenExpirySkew = 10 * time.Second
+
+	// token types of a token exchange (RFC 8693)
+	TokenTypeAccessToken  = "urn:ietf:params:oauth:token-type:access_token"
+	TokenTypeRefreshToken = "urn:ietf:params:oauth:token-type:refresh_token"
+	TokenTypeIDToken      = "urn:ietf:params:oauth:token-type:id_token"
+	TokenTypeJWT          = "urn:ietf:params:oauth:token-type:jwt"
+)
+
+// OIDCConfiguration is the provider metadata served at /.well-known/openid-configuration.
+type OIDCConfiguration struct {
+	Issuer                string   `json:"issuer"`
+	AuthorizationEndpoint string   `json:"authorization_endpoint"`
+	TokenEndpoint         string   `json:"token_endpoint"`
+	UserinfoEndpoint      string   `json:"userinfo_endpoint"`
+	JWKSURI               string   `json:"jwks_uri"`
+	GrantTypesSupported   []string `json:"grant_types_supported"`
+	ScopesSupported       []string `json:"scopes_supported"`
+}
+
+// JSONWebKey is a single public key of a JSON Web Key Set.
+type JSONWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+// JSONWebKeySet is the key set served at an OIDC provider's jwks_uri.
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+// TokenResponse is the successful response of an OAuth 2.0 token endpoint.
+type TokenResponse struct {
+	AccessToken     string `json:"access_token"`
+	TokenType       string `json:"token_type"`
+	ExpiresIn       int    `json:"expires_in"`
+	RefreshToken    string `json:"refresh_token,omitempty"`
+	IDToken         string `json:"id_token,omitempty"`
+	IssuedTokenType string `json:"issued_token_type,omitempty"`
+	Scope           string `json:"scope,omitempty"`
+}
+
+// OAuthError is returned when a token endpoint rejects a request.
+type OAuthError struct {
+	Message     string
+	Status      int
+	Code        string `json:"error"`
+	Description string `json:"error_description"`
+}
+
+func (e OAuthError) Error() string {
+	return e.Message
+}
+
+// OIDCError is provider metadata or a key which can not be used, like metadata of another issuer or a key
+// of an unsupported type.
+type OIDCError struct {
+	Message string
+}
+
+func (e OIDCError) Error() string {
+	return e.Message
+}
+
+// DiscoverOIDC fetches the provider metadata of issuer. Client must not itself authenticate with
+// tokens of this issuer.
+func DiscoverOIDC(ctx context.Context, client *HttpClient, issuer string) (*OIDCConfiguration, error) {
+	config := &OIDCConfiguration{}
+	if err := getJSON(ctx, client, strings.TrimSuffix(issuer, "/")+oidcDiscoveryPath, config); err != nil {
+		return nil, err
+	}
+
+	if strings.TrimSuffix(config.Issuer, "/") != strings.TrimSuffix(issuer, "/") {
+		return nil, &OIDCError{Message: fmt.Sprintf("OIDC issuer %s does not match the expected %s.", config.Issuer, issuer)}
+	}
+	return config, nil
+}
+
+// FetchJWKS fetches the JSON Web Key Set at jwksURI.
+func FetchJWKS(ctx context.Context, client *HttpClient, jwksURI string) (*JSONWebKeySet, error) {
+	set := &JSONWebKeySet{}
+	if err := getJSON(ctx, client, jwksURI, set); err != nil {
+		return nil, err
+	}
+	return set, nil
+}
+
+// Key returns the key with the given key ID.
+func (s *JSONWebKeySet) Key(kid string) (JSONWebKey, bool) {
+	for _, key := range s.Keys {
+		if key.Kid == kid {
+			return key, true
+		}
+	}
+	return JSONWebKey{}, false
+}
+
+// PublicKey converts an RSA or EC key to an *rsa.PublicKey or *ecdsa.PublicKey.
+func (k JSONWebKey) PublicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, &OIDCError{Message: fmt.Sprintf("Curve %s of key %q is not supported.", k.Crv, k.Kid)}
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}, nil
+	default:
+		return nil, &OIDCError{Message: fmt.Sprintf("Key type %s of key %q is not supported.", k.Kty, k.Kid)}
+	}
+}
+
+// TokenSource is a CredentialSource requesting access tokens from an OAuth 2.0 token endpoint.
+// Wrap it with CachedCredentials and BearerAuth to authenticate requests with the tokens.
+type TokenSource struct {
+	client        *HttpClient
+	tokenEndpoint string
+	clientID      string
+	clientSecret  string
+	params        func() (url.Values, error)
+	subject       CredentialSource
+
+	mu           sync.Mutex
+	refreshToken string
+}
+
+// NewClientCredentialsSource creates a TokenSource using the client credentials grant.
+func NewClientCredentialsSource(client *HttpClient, tokenEndpoint string, clientID string, clientSecret string, scopes ...string) *TokenSource {
+	source := newTokenSource(client, tokenEndpoint, clientID, clientSecret)
+	source.params = func() (url.Values, error) {
+		params := url.Values{"grant_type": {"client_credentials"}}
+		if len(scopes) > 0 {
+			params.Set("scope", strings.Join(scopes, " "))
+		}
+		return params, nil
+	}
+	return source
+}
+
+// NewRefreshTokenSource creates a TokenSource using the refresh token grant.
+// Refresh tokens rotated by the provider are picked up automatically.
+func NewRefreshTokenSource(client *HttpClient, tokenEndpoint string, clientID string, clientSecret string, refreshToken string) *TokenSource {
+	source := newTokenSource(client, tokenEndpoint, clientID, clientSecret)
+	source.refreshToken = refreshToken
+	source.params = func() (url.Values, error) {
+		return url.Values{"grant_type": {"refresh_token"}, "refresh_token": {source.refreshToken}}, nil
+	}
+	return source
+}
+
+// NewTokenExchangeSource creates a TokenSource exchanging the token of subject for a token
+// for audience (RFC 8693).
+func NewTokenExchangeSource(client *HttpClient, tokenEndpoint string, clientID string, clientSecret string, subject CredentialSource, subjectTokenType string, audience string) *TokenSource {
+	source := newTokenSource(client, tokenEndpoint, clientID, clientSecret)
+	source.params = func() (url.Values, error) {
+		return url.Values{
+			"grant_type":         {"urn:ietf:params:oauth:grant-type:token-exchange"},
+			"subject_token_type": {subjectTokenType},
+			"audience":           {audience},
+		}, nil
+	}
+	source.subject = subject
+	return source
+}
+
+func newTokenSource(client *HttpClient, tokenEndpoint string, clientID string, clientSecret string) *TokenSource {
+	if client == nil {
+		panic("client is nil")
+	}
+	return &TokenSource{
+		client:        client,
+		tokenEndpoint: tokenEndpoint,
+		clientID:      clientID,
+		clientSecret:  clientSecret,
+	}
+}
+
+// Credentials requests a new access token from the token endpoint.
+func (s *TokenSource) Credentials(ctx context.Context) (Credentials, error) {
+	token, err := s.Token(ctx)
+	if err != nil {
+		return Credentials{}, err
+	}
+
+	credentials := Credentials{Token: token.AccessToken}
+	if token.ExpiresIn > 0 {
+		credentials.Expiry = time.Now().Add(time.Duration(token.ExpiresIn)*time.Second - tokenExpirySkew)
+	}
+	return credentials, nil
+}
+
+// Token requests a new token from the token endpoint.
+func (s *TokenSource) Token(ctx context.Context) (*TokenResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	params, err := s.params()
+	if err != nil {
+		return nil, err
+	}
+	if s.subject != nil {
+		subject, err := s.subject.Credentials(ctx)
+		if err != nil {
+			return nil, err
+		}
+		params.Set("subject_token", subject.Token)
+	}
+	if s.clientSecret == "" && s.clientID != "" {
+		params.Set("client_id", s.clientID)
+	}
+
+	token, err := requestToken(ctx, s.client, s.tokenEndpoint, params, s.clientID, s.clientSecret)
+	if err != nil {
+		return nil, err
+	}
+	if token.RefreshToken != "" && s.refreshToken != "" {
+		s.refreshToken = token.RefreshToken
+	}
+	s.client.emit(Event{Type: EventTokenRefreshed, Host: hostOf(s.tokenEndpoint)})
+	return token, nil
+}
+
+// requestToken posts params to a token endpoint, authenticating with client_secret_basic if a secret is given.
+// The client's own credentials are never sent to the identity provider.
+func requestToken(ctx context.Context, client *HttpClient, tokenEndpoint string, params url.Values, clientID string, clientSecret string) (*TokenResponse, error) {
+	request, err := http.NewRequest(http.MethodPost, tokenEndpoint, strings.NewReader(params.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", formType)
+	request.Header.Set("Accept", jsonType)
+	auth := WithoutAuth()
+	if clientSecret != "" {
+		auth = WithAuth(BasicAuth(StaticCredentials(url.QueryEscape(clientID), url.QueryEscape(clientSecret))))
+	}
+
+	resp, err := client.ExecuteRequest(request.WithContext(WithRequestOptions(ctx, auth)))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		oauthErr := &OAuthError{Status: resp.StatusCode}
+		json.Unmarshal(body, oauthErr)
+		oauthErr.Message = fmt.Sprintf("Token request failed with %d: %s %s", resp.StatusCode, oauthErr.Code, oauthErr.Description)
+		return nil, oauthErr
+	}
+
+	token := &TokenResponse{}
+	if err := json.Unmarshal(body, token); err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+// getJSON gets public metadata of the identity provider without the client's credentials.
+func getJSON(ctx context.Context, client *HttpClient, rawURL string, out interface{}) error {
+	request, err := http.NewRequest(http.MethodGet, rawURL, nil)
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Accept", jsonType)
+
+	resp, err := client.ExecuteRequest(request.WithContext(WithRequestOptions(ctx, WithoutAuth())))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return &RemoteError{request.URL.Host, fmt.Errorf("%d: (%s)", resp.StatusCode, rawURL)}
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
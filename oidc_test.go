@@ -0,0 +1,157 @@
+package http
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func mockOIDCServer() *httptest.Server {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+
+	mux.HandleFunc(oidcDiscoveryPath, func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(OIDCConfiguration{
+			Issuer:        server.URL,
+			TokenEndpoint: server.URL + "/token",
+			JWKSURI:       server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"keys": [{"kty": "RSA", "kid": "key-1", "n": "sXchDaQebHnPiGvyDOAT4saGEUetSyo9MKLOoWFsueri23bOdgWp4Dy1WlUzewbgBHod5pcM9H95GQRV3JDXboIRROSBigeC5yjU1hGzHHyXss8UDprecbAYxknTcQkhslANGRUZmdTOQ5qTRsLAt6BTYuyvVRdhS8exSZEy_c4gs_7svlJJQ4H9_NxsiIoLwAEk7-Q3UXERGYw_75IDrGA84-lA_-Ct4eTlXHBIY2EaV7t7LjJaynVJCpkv4LKjTTAumiGUIuQhrNhZLuF_RJLqHpM2kgWFLU7-VTdL1VbC2tejvcI2BlMkEpk1BzBZI0KQB0GaDWFLN-aEAw3vRw", "e": "AQAB"}]}`)
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		clientID, clientSecret, _ := r.BasicAuth()
+		if clientID != "client" || clientSecret != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"error": "invalid_client"}`)
+			return
+		}
+		json.NewEncoder(w).Encode(TokenResponse{
+			AccessToken:  r.Form.Get("grant_type") + ":" + r.Form.Get("subject_token") + r.Form.Get("refresh_token"),
+			ExpiresIn:    3600,
+			RefreshToken: "rotated",
+		})
+	})
+
+	return server
+}
+
+func TestDiscoverOIDCAndFetchJWKS(t *testing.T) {
+	server := mockOIDCServer()
+	defer server.Close()
+	client := createTestHTTPClient(server.URL)
+
+	config, err := DiscoverOIDC(context.Background(), client, server.URL)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if config.TokenEndpoint != server.URL+"/token" {
+		t.Errorf("Expected token endpoint %s/token but got %s", server.URL, config.TokenEndpoint)
+	}
+
+	set, err := FetchJWKS(context.Background(), client, config.JWKSURI)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	key, ok := set.Key("key-1")
+	if !ok {
+		t.Fatal("Expected key key-1")
+	}
+	publicKey, err := key.PublicKey()
+	if rsaKey, isRSA := publicKey.(*rsa.PublicKey); err != nil || !isRSA || rsaKey.E != 65537 {
+		t.Errorf("Expected RSA key with exponent 65537 but got %v, %v", publicKey, err)
+	}
+}
+
+func TestRefreshTokenSource(t *testing.T) {
+	server := mockOIDCServer()
+	defer server.Close()
+
+	source := NewRefreshTokenSource(createTestHTTPClient(server.URL), server.URL+"/token", "client", "secret", "initial")
+
+	first, err := source.Credentials(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	second, _ := source.Credentials(context.Background())
+
+	if first.Token != "refresh_token:initial" || second.Token != "refresh_token:rotated" {
+		t.Errorf("Expected rotated refresh token to be used but got %s and %s", first.Token, second.Token)
+	}
+	if first.Expiry.IsZero() {
+		t.Error("Expected expiry to be set")
+	}
+}
+
+func TestTokenExchangeSource(t *testing.T) {
+	server := mockOIDCServer()
+	defer server.Close()
+
+	subject := CredentialSourceFunc(func(ctx context.Context) (Credentials, error) {
+		return Credentials{Token: "subject"}, nil
+	})
+	source := NewTokenExchangeSource(createTestHTTPClient(server.URL), server.URL+"/token", "client", "secret",
+		subject, TokenTypeAccessToken, "downstream")
+
+	credentials, err := source.Credentials(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if credentials.Token != "urn:ietf:params:oauth:grant-type:token-exchange:subject" {
+		t.Errorf("Unexpected token %s", credentials.Token)
+	}
+}
+
+func TestClientCredentialsSource_InvalidClient(t *testing.T) {
+	server := mockOIDCServer()
+	defer server.Close()
+
+	source := NewClientCredentialsSource(createTestHTTPClient(server.URL), server.URL+"/token", "client", "wrong")
+	_, err := source.Credentials(context.Background())
+
+	var oauthErr *OAuthError
+	if !errors.As(err, &oauthErr) || oauthErr.Code != "invalid_client" {
+		t.Errorf("Expected invalid_client OAuthError but got %v", err)
+	}
+}
+
+func TestDiscoverOIDC_WithoutClientCredentials(t *testing.T) {
+	var authorization []string
+	oidc := mockOIDCServer()
+	defer oidc.Close()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = append(authorization, r.Header.Get("Authorization"))
+		oidc.Config.Handler.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	client := NewHttpClientWithConfig(NewDefaultHttpConfig(server.URL, WithAuthProvider(BasicAuth(StaticCredentials("api", "secret")))))
+	if _, err := FetchJWKS(context.Background(), client, server.URL+"/jwks"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewClientCredentialsSource(client, server.URL+"/token", "client", "secret").Token(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	apiAuthorization := "Basic YXBpOnNlY3JldA=="
+	if len(authorization) != 2 || authorization[0] != "" || authorization[1] == "" || authorization[1] == apiAuthorization {
+		t.Errorf("Expected no API credentials sent to the identity provider but got %q", authorization)
+	}
+}
+
+func TestJSONWebKey_UnsupportedKeyType(t *testing.T) {
+	for _, key := range []JSONWebKey{{Kty: "oct", Kid: "a"}, {Kty: "EC", Crv: "secp256k1", Kid: "b"}} {
+		_, err := key.PublicKey()
+		var oidcErr *OIDCError
+		if !errors.As(err, &oidcErr) {
+			t.Errorf("Expected OIDCError for %s %s but got %v", key.Kty, key.Crv, err)
+		}
+	}
+}
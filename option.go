@@ -0,0 +1,69 @@
+package http
+
+import (
+	"crypto/tls"
+	"net/url"
+	"time"
+)
+
+// Option customizes a HttpConfig.
+type Option func(*HttpConfig)
+
+// Apply applies opts to the config and returns it.
+func (c *HttpConfig) Apply(opts ...Option) *HttpConfig {
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// WithBaseURL sets the base URL requests are resolved against.
+func WithBaseURL(baseURL string) Option {
+	return func(c *HttpConfig) {
+		c.baseURL = baseURL
+	}
+}
+
+// WithBasicAuth sets the username and password sent as basic auth.
+func WithBasicAuth(username string, password string) Option {
+	return func(c *HttpConfig) {
+		c.username = username
+		c.password = password
+	}
+}
+
+// WithAccept sets the content type accepted in responses, which is sent as Accept header of the requests created
+// by the client.
+func WithAccept(accept string) Option {
+	return func(c *HttpConfig) {
+		c.accept = accept
+	}
+}
+
+// WithAuthProvider sets the AuthProvider authenticating requests.
+func WithAuthProvider(provider AuthProvider) Option {
+	return func(c *HttpConfig) {
+		c.auth = provider
+	}
+}
+
+// WithTimeout sets the overall timeout of a request, zero disables it.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *HttpConfig) {
+		c.timeout = timeout
+	}
+}
+
+// WithProxy routes all requests through proxy instead of the proxy configured in the environment.
+func WithProxy(proxy *url.URL) Option {
+	return func(c *HttpConfig) {
+		c.proxy = proxy
+	}
+}
+
+// WithTLSConfig sets the TLS configuration of the transport.
+func WithTLSConfig(config *tls.Config) Option {
+	return func(c *HttpConfig) {
+		c.tls = config
+	}
+}
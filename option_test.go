@@ -0,0 +1,47 @@
+package http
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestNewHttpConfig_Options(t *testing.T) {
+	proxy, _ := url.Parse("http://proxy:3128")
+	tlsConfig := &tls.Config{ServerName: "api"}
+
+	config := NewDefaultHttpConfig(fixtureBaseURL,
+		WithBasicAuth("user", "secret"),
+		WithTimeout(3*time.Second),
+		WithProxy(proxy),
+		WithTLSConfig(tlsConfig))
+
+	if config.username != "user" || config.password != "secret" {
+		t.Errorf("Expected basic auth to be set but got %+v", config)
+	}
+
+	client := NewHttpClientWithConfig(config)
+	if client.client.Timeout != 3*time.Second {
+		t.Errorf("Expected client timeout 3s but got %s", client.client.Timeout)
+	}
+
+	transport := client.client.Transport.(*http.Transport)
+	if transport.TLSClientConfig != tlsConfig {
+		t.Error("Expected TLS config to be used by the transport")
+	}
+	proxyURL, err := transport.Proxy(&http.Request{URL: &url.URL{Scheme: "https", Host: "example.com"}})
+	if err != nil || proxyURL.String() != proxy.String() {
+		t.Errorf("Expected proxy %s but got %v", proxy, proxyURL)
+	}
+}
+
+func TestHttpConfig_ValidateOptions(t *testing.T) {
+	if err := NewDefaultHttpConfig(fixtureBaseURL, WithTimeout(-time.Second)).Validate(); err == nil {
+		t.Error("Expected error for negative timeout")
+	}
+	if err := NewDefaultHttpConfig(fixtureBaseURL, WithProxy(&url.URL{Path: "proxy"})).Validate(); err == nil {
+		t.Error("Expected error for proxy without host")
+	}
+}
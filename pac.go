@@ -0,0 +1,250 @@
+package http
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultPACRefreshInterval = 1 * time.Hour
+	pacProxyCheckInterval     = 1 * time.Minute
+	pacProxyDialTimeout       = 2 * time.Second
+)
+
+// PACError is a PAC file which can not be loaded or a PAC result which can not be used.
+type PACError struct {
+	Message string
+}
+
+func (e PACError) Error() string {
+	return e.Message
+}
+
+// PACEvaluator runs the FindProxyForURL function of a proxy auto-config script, e.g. backed by a
+// JavaScript interpreter such as goja or otto. Implementations may cache compiled scripts.
+type PACEvaluator interface {
+	FindProxyForURL(script string, url string, host string) (string, error)
+}
+
+// PACEvaluatorFunc adapts a function to the PACEvaluator interface.
+type PACEvaluatorFunc func(script string, url string, host string) (string, error)
+
+func (f PACEvaluatorFunc) FindProxyForURL(script string, url string, host string) (string, error) {
+	return f(script, url, host)
+}
+
+// PACProxy selects the proxy per destination by evaluating a PAC file loaded from a URL or file path.
+// The package does not interpret JavaScript, a PACEvaluator running the script is required.
+//
+// Results listing several entries like "PROXY a:8080; PROXY b:8080; DIRECT" fall back to the next entry
+// while a proxy can not be reached. Reachability is checked by connecting to the proxy, at most once a
+// minute per proxy.
+type PACProxy struct {
+	location  string
+	evaluator PACEvaluator
+	refresh   time.Duration
+	client    *http.Client
+
+	mu       sync.Mutex
+	script   string
+	loadedAt time.Time
+	loading  bool
+	checks   map[string]pacProxyCheck
+}
+
+type pacProxyCheck struct {
+	reachable bool
+	at        time.Time
+}
+
+// NewPACProxy creates a PACProxy for the PAC file at location, which is either a http(s) URL or a file path,
+// evaluated by evaluator.
+func NewPACProxy(location string, evaluator PACEvaluator) *PACProxy {
+	if evaluator == nil {
+		panic("evaluator is nil")
+	}
+
+	return &PACProxy{
+		location:  location,
+		evaluator: evaluator,
+		refresh:   defaultPACRefreshInterval,
+		// the PAC file itself is always fetched directly, it can not be looked up through a proxy
+		client: &http.Client{Timeout: defaultRequestTimeOut, Transport: &http.Transport{}},
+		checks: make(map[string]pacProxyCheck),
+	}
+}
+
+// WithRefreshInterval sets how long a loaded PAC file is used before it is loaded again.
+func (p *PACProxy) WithRefreshInterval(refresh time.Duration) *PACProxy {
+	p.refresh = refresh
+	return p
+}
+
+// Proxy returns the proxy for r and can be used as http.Transport.Proxy. It returns the first reachable
+// entry of the PAC result, or the last entry if none is. A nil URL means DIRECT.
+func (p *PACProxy) Proxy(r *http.Request) (*url.URL, error) {
+	script, err := p.load()
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := p.evaluator.FindProxyForURL(script, r.URL.String(), r.URL.Hostname())
+	if err != nil {
+		return nil, err
+	}
+	proxies, err := parsePACResults(result)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, proxy := range proxies {
+		if proxy == nil || i == len(proxies)-1 || p.reachable(proxy) {
+			return proxy, nil
+		}
+	}
+	return nil, nil
+}
+
+// UsePAC configures the client's transport to select proxies through the given PACProxy.
+func (h *HttpClient) UsePAC(pac *PACProxy) error {
+	ok := h.updateTransport(func(t *http.Transport) {
+		t.Proxy = pac.Proxy
+	})
+	if !ok {
+		return &PACError{Message: "PAC proxy selection requires an *http.Transport."}
+	}
+	return nil
+}
+
+// load returns the PAC script, fetching it without holding p.mu. While a refresh is in progress, other
+// requests keep using the previous script.
+func (p *PACProxy) load() (string, error) {
+	p.mu.Lock()
+	previous := p.script
+	if previous != "" && (p.loading || time.Since(p.loadedAt) < p.refresh) {
+		p.mu.Unlock()
+		return previous, nil
+	}
+	p.loading = true
+	p.mu.Unlock()
+
+	script, err := p.fetch()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.loading = false
+	if err != nil {
+		if previous != "" {
+			// keep using the last known script if the refresh fails
+			return previous, nil
+		}
+		return "", err
+	}
+
+	p.script = script
+	p.loadedAt = time.Now()
+	return script, nil
+}
+
+// reachable reports whether a connection to proxy can be opened, caching the answer for a minute.
+func (p *PACProxy) reachable(proxy *url.URL) bool {
+	p.mu.Lock()
+	check, ok := p.checks[proxy.Host]
+	p.mu.Unlock()
+	if ok && time.Since(check.at) < pacProxyCheckInterval {
+		return check.reachable
+	}
+
+	conn, err := net.DialTimeout("tcp", proxy.Host, pacProxyDialTimeout)
+	if err == nil {
+		conn.Close()
+	}
+
+	p.mu.Lock()
+	p.checks[proxy.Host] = pacProxyCheck{reachable: err == nil, at: time.Now()}
+	p.mu.Unlock()
+	return err == nil
+}
+
+func (p *PACProxy) fetch() (string, error) {
+	if !strings.HasPrefix(p.location, "http://") && !strings.HasPrefix(p.location, "https://") {
+		data, err := os.ReadFile(strings.TrimPrefix(p.location, "file://"))
+		if err != nil {
+			return "", &PACError{Message: fmt.Sprintf("PAC file %s not readable: %v.", p.location, err)}
+		}
+		return string(data), nil
+	}
+
+	resp, err := p.client.Get(p.location)
+	if err != nil {
+		return "", &PACError{Message: fmt.Sprintf("PAC file %s not loadable: %v.", p.location, err)}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", &PACError{Message: fmt.Sprintf("PAC file %s not loadable: %s.", p.location, resp.Status)}
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", &PACError{Message: fmt.Sprintf("PAC file %s not loadable: %v.", p.location, err)}
+	}
+	return string(data), nil
+}
+
+// parsePACResults converts all entries of a PAC result like "PROXY a:8080; PROXY b:8080; DIRECT" to proxy
+// URLs, nil for DIRECT.
+func parsePACResults(result string) ([]*url.URL, error) {
+	var proxies []*url.URL
+	for _, entry := range strings.Split(result, ";") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+		proxy, err := parsePACResult(entry)
+		if err != nil {
+			return nil, err
+		}
+		proxies = append(proxies, proxy)
+	}
+	if len(proxies) == 0 {
+		proxies = append(proxies, nil)
+	}
+	return proxies, nil
+}
+
+// parsePACResult converts the first entry of a PAC result like "PROXY host:8080; DIRECT" to a proxy URL.
+func parsePACResult(result string) (*url.URL, error) {
+	entry := strings.TrimSpace(strings.SplitN(result, ";", 2)[0])
+	if entry == "" {
+		return nil, nil
+	}
+
+	fields := strings.Fields(entry)
+	kind := strings.ToUpper(fields[0])
+	if kind == "DIRECT" {
+		return nil, nil
+	}
+	if len(fields) != 2 {
+		return nil, &PACError{Message: fmt.Sprintf("PAC result %q is invalid.", result)}
+	}
+
+	var scheme string
+	switch kind {
+	case "PROXY", "HTTP":
+		scheme = "http"
+	case "HTTPS":
+		scheme = "https"
+	case "SOCKS", "SOCKS5":
+		scheme = "socks5"
+	default:
+		return nil, &PACError{Message: fmt.Sprintf("PAC result %q is not supported.", result)}
+	}
+
+	return &url.URL{Scheme: scheme, Host: fields[1]}, nil
+}
@@ -0,0 +1,150 @@
+package http
+
+import (
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const fixturePACScript = `function FindProxyForURL(url, host) { return "PROXY proxy.example.com:8080; DIRECT"; }`
+
+func TestParsePACResult(t *testing.T) {
+	cases := map[string]string{
+		"DIRECT":                              "",
+		"PROXY proxy.example.com:8080":        "http://proxy.example.com:8080",
+		"HTTPS proxy.example.com:443; DIRECT": "https://proxy.example.com:443",
+		"SOCKS socks.example.com:1080":        "socks5://socks.example.com:1080",
+	}
+
+	for result, expected := range cases {
+		proxy, err := parsePACResult(result)
+		if err != nil {
+			t.Errorf("Unexpected error %v for %s", err, result)
+			continue
+		}
+		if proxy == nil && expected != "" || proxy != nil && proxy.String() != expected {
+			t.Errorf("Expected %s but got %v for %s", expected, proxy, result)
+		}
+	}
+
+	if _, err := parsePACResult("QUIC proxy.example.com:443"); err == nil {
+		t.Error("Expected error for unsupported result")
+	}
+}
+
+func TestPACProxy_FromFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "proxy.pac")
+	os.WriteFile(path, []byte(fixturePACScript), 0600)
+
+	var evaluatedScript, evaluatedHost string
+	evaluator := PACEvaluatorFunc(func(script string, url string, host string) (string, error) {
+		evaluatedScript, evaluatedHost = script, host
+		return "PROXY proxy.example.com:8080", nil
+	})
+
+	req, _ := http.NewRequest(http.MethodGet, fixtureBaseURL, nil)
+	proxy, err := NewPACProxy(path, evaluator).Proxy(req)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	assertURLIs(proxy, "http://proxy.example.com:8080", t)
+	if evaluatedScript != fixturePACScript || evaluatedHost != "github.com" {
+		t.Errorf("Expected script to be evaluated for github.com but got %s", evaluatedHost)
+	}
+}
+
+func TestPACProxy_FromURL(t *testing.T) {
+	server := mockServer(http.StatusOK, "application/x-ns-proxy-autoconfig", fixturePACScript)
+	defer server.Close()
+
+	evaluator := PACEvaluatorFunc(func(script string, url string, host string) (string, error) {
+		return "DIRECT", nil
+	})
+
+	req, _ := http.NewRequest(http.MethodGet, fixtureBaseURL, nil)
+	proxy, err := NewPACProxy(server.URL, evaluator).Proxy(req)
+	if err != nil || proxy != nil {
+		t.Errorf("Expected DIRECT but got %v, %v", proxy, err)
+	}
+}
+
+func TestPACProxy_FallsBackToReachableProxy(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	closed, _ := net.Listen("tcp", "127.0.0.1:0")
+	closed.Close()
+
+	path := filepath.Join(t.TempDir(), "proxy.pac")
+	os.WriteFile(path, []byte(fixturePACScript), 0600)
+	result := "PROXY " + closed.Addr().String() + "; PROXY " + listener.Addr().String() + "; DIRECT"
+	evaluator := PACEvaluatorFunc(func(script string, url string, host string) (string, error) {
+		return result, nil
+	})
+
+	req, _ := http.NewRequest(http.MethodGet, fixtureBaseURL, nil)
+	proxy, err := NewPACProxy(path, evaluator).Proxy(req)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	assertURLIs(proxy, "http://"+listener.Addr().String(), t)
+}
+
+func TestPACProxy_RefreshesWithoutBlocking(t *testing.T) {
+	release := make(chan struct{})
+	var calls int32
+	server := mockServerWith(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) > 1 {
+			<-release
+		}
+		w.Write([]byte(fixturePACScript))
+	})
+	defer server.Close()
+
+	evaluator := PACEvaluatorFunc(func(script string, url string, host string) (string, error) {
+		return "DIRECT", nil
+	})
+	pac := NewPACProxy(server.URL, evaluator).WithRefreshInterval(0)
+	req, _ := http.NewRequest(http.MethodGet, fixtureBaseURL, nil)
+	if _, err := pac.Proxy(req); err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	refreshed := make(chan struct{})
+	go func() {
+		pac.Proxy(req)
+		close(refreshed)
+	}()
+	for atomic.LoadInt32(&calls) < 2 {
+		time.Sleep(time.Millisecond)
+	}
+
+	start := time.Now()
+	if _, err := pac.Proxy(req); err != nil {
+		t.Errorf("Unexpected error %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected the previous script to be used during the refresh but waited %s", elapsed)
+	}
+	close(release)
+	<-refreshed
+}
+
+func TestPACProxy_MissingFile(t *testing.T) {
+	evaluator := PACEvaluatorFunc(func(script string, url string, host string) (string, error) {
+		return "DIRECT", nil
+	})
+
+	req, _ := http.NewRequest(http.MethodGet, fixtureBaseURL, nil)
+	_, err := NewPACProxy(filepath.Join(t.TempDir(), "missing.pac"), evaluator).Proxy(req)
+	if _, ok := err.(*PACError); !ok {
+		t.Errorf("Expected PACError but got %v", err)
+	}
+}
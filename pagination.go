@@ -0,0 +1,143 @@
+package http
+
+import (
+	"context"
+	"iter"
+	"net/http"
+	"strings"
+)
+
+// Pagination returns the URL of the page following resp, absolute or relative to its request, and whether
+// there is one.
+type Pagination func(resp *http.Response) (next string, ok bool)
+
+// LinkPagination follows the rel="next" URL of the Link header, see RFC 8288. It is the default Pagination.
+func LinkPagination(resp *http.Response) (string, bool) {
+	next, ok := Links(resp)["next"]
+	return next, ok
+}
+
+// Links returns the URLs of the Link headers of resp by relation type. Links with several relation types are
+// returned for each of them.
+func Links(resp *http.Response) map[string]string {
+	links := map[string]string{}
+	for _, header := range resp.Header.Values("Link") {
+		for _, link := range splitLinks(header) {
+			target, params, ok := strings.Cut(link, ";")
+			target = strings.TrimSpace(target)
+			if !ok || !strings.HasPrefix(target, "<") || !strings.HasSuffix(target, ">") {
+				continue
+			}
+			for _, param := range strings.Split(params, ";") {
+				key, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+				if !strings.EqualFold(strings.TrimSpace(key), "rel") {
+					continue
+				}
+				for _, rel := range strings.Fields(strings.Trim(strings.TrimSpace(value), `"`)) {
+					links[strings.ToLower(rel)] = target[1 : len(target)-1]
+				}
+			}
+		}
+	}
+	return links
+}
+
+// splitLinks splits a Link header at the commas between links, ignoring those within URLs and quoted values.
+func splitLinks(header string) []string {
+	var links []string
+	inURL, quoted := false, false
+	start := 0
+	for i, c := range header {
+		switch {
+		case c == '<' && !quoted:
+			inURL = true
+		case c == '>' && !quoted:
+			inURL = false
+		case c == '"' && !inURL:
+			quoted = !quoted
+		case c == ',' && !inURL && !quoted:
+			links = append(links, header[start:i])
+			start = i + 1
+		}
+	}
+	return append(links, header[start:])
+}
+
+// WithPagination sets how Pages finds the following page, LinkPagination by default.
+func WithPagination(pagination Pagination) Option {
+	if pagination == nil {
+		panic("pagination is nil")
+	}
+	return func(c *HttpConfig) {
+		c.pagination = pagination
+	}
+}
+
+// Pages gets path and the pages following it as told by the client's Pagination, until there is no next page,
+// a request fails or the loop is left. Unsuccessful statuses are reported as error, like UnauthorizedError or
+// NotFoundError, and end the iteration. The caller closes the body of each response:
+//
+//	for resp, err := range client.Pages(ctx, "/repos/owner/repo/issues") {
+//		if err != nil {
+//			return err
+//		}
+//		var issues []Issue
+//		err = client.DecodeResponse(resp, &issues)
+//		...
+//	}
+//
+// Pages on another host are requested without the headers the HeaderPolicy treats as sensitive and without the
+// client's credentials.
+func (h *HttpClient) Pages(ctx context.Context, path string) iter.Seq2[*http.Response, error] {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return func(yield func(*http.Response, error) bool) {
+		h.mu.RLock()
+		pagination := h.config.pagination
+		policy := h.config.effectiveHeaderPolicy()
+		h.mu.RUnlock()
+		if pagination == nil {
+			pagination = LinkPagination
+		}
+
+		request, err := h.newRequest(ctx, http.MethodGet, path, nil)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		request.Header.Del("Content-Type")
+		request = request.WithContext(ctx)
+
+		for {
+			resp, err := h.ExecuteRequest(request)
+			if err == nil && ClassifyStatus(resp.StatusCode) != StatusClassSuccess {
+				resp, err = nil, h.responseError(resp)
+			}
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+
+			next, ok := pagination(resp)
+			if !yield(resp, nil) || !ok {
+				return
+			}
+
+			u, err := request.URL.Parse(next)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			following := request.Clone(ctx)
+			following.URL = u
+			if u.Host != request.URL.Host {
+				following.Host = ""
+				for _, name := range policy.Sensitive() {
+					following.Header.Del(name)
+				}
+			}
+			request = following
+		}
+	}
+}
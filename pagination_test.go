@@ -0,0 +1,144 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestLinks(t *testing.T) {
+	resp := &http.Response{Header: http.Header{"Link": {
+		`<https://api.example.com/items?page=2>; rel="next", <https://api.example.com/items?page=5>; rel="last"`,
+		`<https://api.example.com/items?a=1,2>; rel="prev first"`,
+	}}}
+
+	links := Links(resp)
+	expected := map[string]string{
+		"next":  "https://api.example.com/items?page=2",
+		"last":  "https://api.example.com/items?page=5",
+		"prev":  "https://api.example.com/items?a=1,2",
+		"first": "https://api.example.com/items?a=1,2",
+	}
+	if len(links) != len(expected) {
+		t.Fatalf("Expected links %v but got %v", expected, links)
+	}
+	for rel, target := range expected {
+		if links[rel] != target {
+			t.Errorf("Expected %s link %q but got %q", rel, target, links[rel])
+		}
+	}
+}
+
+func TestPages(t *testing.T) {
+	server := mockServerWith(func(w http.ResponseWriter, r *http.Request) {
+		page := r.URL.Query().Get("page")
+		switch page {
+		case "":
+			w.Header().Set("Link", `</items?page=2>; rel="next"`)
+		case "2":
+			w.Header().Set("Link", fmt.Sprintf(`<http://%s/items?page=3>; rel="next"`, r.Host))
+		}
+		fmt.Fprintf(w, `{"page": %q}`, page)
+	})
+	defer server.Close()
+
+	client := NewDefaultHttpClient(server.URL)
+	var pages []string
+	for resp, err := range client.Pages(context.Background(), "/items") {
+		if err != nil {
+			t.Fatal(err)
+		}
+		var body struct {
+			Page string `json:"page"`
+		}
+		if err := client.DecodeResponse(resp, &body); err != nil {
+			t.Fatal(err)
+		}
+		pages = append(pages, body.Page)
+	}
+
+	if fmt.Sprint(pages) != "[ 2 3]" {
+		t.Errorf("Expected three pages but got %q", pages)
+	}
+}
+
+func TestPages_StopsOnError(t *testing.T) {
+	server := mockServerWith(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") == "2" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Link", `</?page=2>; rel="next"`)
+	})
+	defer server.Close()
+
+	client := NewDefaultHttpClient(server.URL)
+	var responses, errs int
+	for resp, err := range client.Pages(context.Background(), "/") {
+		if err != nil {
+			errs++
+			if _, ok := err.(*NotFoundError); !ok {
+				t.Errorf("Expected NotFoundError but got %T", err)
+			}
+			continue
+		}
+		resp.Body.Close()
+		responses++
+	}
+
+	if responses != 1 || errs != 1 {
+		t.Errorf("Expected one page and one error but got %d and %d", responses, errs)
+	}
+}
+
+func TestWithPagination(t *testing.T) {
+	var calls int
+	server := mockServerWith(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("X-Next", r.URL.Query().Get("cursor")+"x")
+	})
+	defer server.Close()
+
+	cursor := func(resp *http.Response) (string, bool) {
+		next := resp.Header.Get("X-Next")
+		return "?cursor=" + next, len(next) < 3
+	}
+	client := NewHttpClientWithConfig(NewDefaultHttpConfig(server.URL, WithPagination(cursor)))
+	for resp, err := range client.Pages(context.Background(), "/") {
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+
+	if calls != 3 {
+		t.Errorf("Expected 3 pages but got %d", calls)
+	}
+}
+
+func TestPages_OtherHostWithoutCredentials(t *testing.T) {
+	var authorization string
+	other := mockServerWith(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+	})
+	defer other.Close()
+	server := mockServerWith(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Link", fmt.Sprintf(`<%s/items?page=2>; rel="next"`, other.URL))
+	})
+	defer server.Close()
+
+	client := NewHttpClientWithConfig(NewDefaultHttpConfig(server.URL, WithAuthProvider(BasicAuth(StaticCredentials("u", "secret")))))
+	var pages int
+	for resp, err := range client.Pages(nil, "/items") {
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		pages++
+	}
+
+	if pages != 2 || authorization != "" {
+		t.Errorf("Expected the second page without credentials but got %d pages and %q", pages, authorization)
+	}
+}
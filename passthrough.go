@@ -0,0 +1,131 @@
+package http
+
+import (
+	"errors"
+	"io"
+	"net/http"
+)
+
+// DefaultPassthroughHeaders are the request headers the client may still set in passthrough mode: its
+// credentials and the trace ID.
+var DefaultPassthroughHeaders = []string{"Authorization", TraceIDHeader}
+
+// passthrough sends the headers of an inbound request unchanged, the client's values of the rewritable ones
+// take precedence.
+type passthrough struct {
+	header     http.Header
+	rewritable map[string]bool
+}
+
+// PassthroughHandler returns a http.Handler forwarding requests to the client's base URL, for building thin
+// reverse proxies. Requests keep their method, path, query and headers, only the hop-by-hop headers are removed
+// and the headers in rewritable, DefaultPassthroughHeaders if empty, may be overwritten by the client, e.g. with
+// its credentials. The client's middleware, events and graceful shutdown apply as usual.
+//
+// Bodies are streamed in both directions without decoding, a compressed response keeps its Content-Encoding,
+// and upstream trailers are forwarded. Responses of every status are passed on, failing to reach the upstream
+// answers 502 Bad Gateway.
+func (h *HttpClient) PassthroughHandler(rewritable ...string) http.Handler {
+	if len(rewritable) == 0 {
+		rewritable = DefaultPassthroughHeaders
+	}
+	allowed := make(map[string]bool, len(rewritable))
+	for _, name := range rewritable {
+		allowed[http.CanonicalHeaderKey(name)] = true
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := r.Header.Clone()
+		if header.Get("Accept-Encoding") == "" {
+			// keeps the transport from decoding the body
+			header.Set("Accept-Encoding", "identity")
+		}
+		ctx := WithRequestOptions(r.Context(), WithAcceptEncoding(header.Get("Accept-Encoding")), func(o *requestOptions) {
+			o.passthrough = &passthrough{header: header, rewritable: allowed}
+		})
+
+		outbound, err := h.newRequest(ctx, r.Method, r.URL.RequestURI(), r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		outbound = outbound.WithContext(ctx)
+		outbound.ContentLength = r.ContentLength
+		outbound.Trailer = r.Trailer
+
+		resp, err := h.transportRoundTrip(outbound)
+		if err != nil {
+			var closed *ClientClosedError
+			if errors.As(err, &closed) {
+				http.Error(w, err.Error(), http.StatusServiceUnavailable)
+				return
+			}
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		defer resp.Body.Close()
+
+		writePassthroughResponse(w, resp)
+	})
+}
+
+// writePassthroughResponse streams resp to w, forwarding its headers and trailers.
+func writePassthroughResponse(w http.ResponseWriter, resp *http.Response) {
+	for key, values := range resp.Header {
+		if !DefaultHeaderPolicy.IsForbidden(key) {
+			w.Header()[key] = values
+		}
+	}
+	for key := range resp.Trailer {
+		w.Header().Add("Trailer", key)
+	}
+	w.WriteHeader(resp.StatusCode)
+
+	var dst io.Writer = w
+	if resp.ContentLength < 0 {
+		dst = &flushWriter{w: w, controller: http.NewResponseController(w)}
+	}
+	if _, err := io.Copy(dst, resp.Body); err != nil {
+		// the status is sent already, aborting the response tells the client it is incomplete
+		panic(http.ErrAbortHandler)
+	}
+
+	for key, values := range resp.Trailer {
+		w.Header()[key] = values
+	}
+}
+
+// flushWriter flushes every write, so streamed responses reach the client without delay.
+type flushWriter struct {
+	w          io.Writer
+	controller *http.ResponseController
+}
+
+func (f *flushWriter) Write(p []byte) (int, error) {
+	n, err := f.w.Write(p)
+	if err == nil {
+		f.controller.Flush()
+	}
+	return n, err
+}
+
+// applyPassthrough wraps next to send the inbound headers of passthrough requests, taking only the rewritable
+// ones from r.
+func applyPassthrough(next RoundTripperFunc, p *passthrough) RoundTripperFunc {
+	return func(r *http.Request) (*http.Response, error) {
+		header := p.header.Clone()
+		for key := range header {
+			if DefaultHeaderPolicy.IsForbidden(key) {
+				delete(header, key)
+			}
+		}
+		for name := range p.rewritable {
+			if values, ok := r.Header[name]; ok {
+				header[name] = values
+			}
+		}
+		r = r.WithContext(r.Context())
+		r.Header = header
+		return next(r)
+	}
+}
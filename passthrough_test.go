@@ -0,0 +1,71 @@
+package http
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestPassthroughHandler(t *testing.T) {
+	compressed := gzipped("hello")
+	upstream := mockServerWith(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Custom") != "kept" || r.Header.Get("Accept") != "text/plain" {
+			t.Errorf("Expected inbound headers to be forwarded unchanged but got %v", r.Header)
+		}
+		if r.Header.Get("Content-Type") != "text/plain" {
+			t.Errorf("Expected inbound Content-Type but got %q", r.Header.Get("Content-Type"))
+		}
+		if user, _, _ := r.BasicAuth(); user != "proxy" {
+			t.Errorf("Expected the client's credentials but got %q", r.Header.Get("Authorization"))
+		}
+		if body, _ := io.ReadAll(r.Body); string(body) != "payload" || r.URL.RawQuery != "page=2" {
+			t.Errorf("Expected body and query to be forwarded but got %q, %q", body, r.URL.RawQuery)
+		}
+		w.Header().Set("Trailer", "X-Checksum")
+		w.Header().Set("Content-Encoding", "gzip")
+		w.WriteHeader(http.StatusTeapot)
+		w.Write(compressed)
+		w.Header().Set("X-Checksum", "abc")
+	})
+	defer upstream.Close()
+
+	client := NewHttpClientWithConfig(NewHttpConfig(upstream.URL, "proxy", "secret", ""))
+	proxy := httptest.NewServer(client.PassthroughHandler())
+	defer proxy.Close()
+
+	request, _ := http.NewRequest(http.MethodPost, proxy.URL+"/users?page=2", strings.NewReader("payload"))
+	request.Header.Set("X-Custom", "kept")
+	request.Header.Set("Accept", "text/plain")
+	request.Header.Set("Content-Type", "text/plain")
+	request.Header.Set("Accept-Encoding", "gzip")
+	request.Header.Set("Authorization", "Bearer downstream")
+	resp, err := http.DefaultClient.Do(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+
+	if resp.StatusCode != http.StatusTeapot {
+		t.Errorf("Expected the upstream status but got %d", resp.StatusCode)
+	}
+	if resp.Header.Get("Content-Encoding") != "gzip" || !bytes.Equal(body, compressed) {
+		t.Errorf("Expected the compressed body to be passed through but got %q", body)
+	}
+	if resp.Trailer.Get("X-Checksum") != "abc" {
+		t.Errorf("Expected the upstream trailer but got %v", resp.Trailer)
+	}
+}
+
+func TestPassthroughHandlerUnreachable(t *testing.T) {
+	client := NewHttpClientWithConfig(NewDefaultHttpConfig("http://127.0.0.1:1"))
+	recorder := httptest.NewRecorder()
+	client.PassthroughHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if recorder.Code != http.StatusBadGateway {
+		t.Errorf("Expected 502 but got %d", recorder.Code)
+	}
+}
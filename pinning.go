@@ -0,0 +1,82 @@
+package http
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+)
+
+// WithResolve pins connections to host to address, like curl's --resolve. host is either "name" or "name:port",
+// address either "ip" or "ip:port"; a missing port is taken from the dialed address. The URL, Host header and
+// TLS server name keep using host, e.g. to test a staging IP with the production certificate.
+func WithResolve(host string, address string) Option {
+	return func(c *HttpConfig) {
+		resolve := make(map[string]string, len(c.resolve)+1)
+		for k, v := range c.resolve {
+			resolve[k] = v
+		}
+		resolve[host] = address
+		c.resolve = resolve
+	}
+}
+
+// WithHostHeader sends host as Host header of the requests created by the client instead of the host of the
+// base URL, e.g. when the base URL is an IP address. Unless a TLS server name is configured, host is also used
+// to verify the server's certificate.
+func WithHostHeader(host string) Option {
+	return func(c *HttpConfig) {
+		c.hostHeader = host
+	}
+}
+
+// pinAddresses rewrites the addresses dialed according to resolve.
+func pinAddresses(dial dialFunc, resolve map[string]string) dialFunc {
+	if len(resolve) == 0 {
+		return dial
+	}
+
+	return func(ctx context.Context, network string, address string) (net.Conn, error) {
+		return dial(ctx, network, pinnedAddress(resolve, address))
+	}
+}
+
+func pinnedAddress(resolve map[string]string, address string) string {
+	if pinned, ok := resolve[address]; ok {
+		return withDefaultPort(pinned, address)
+	}
+
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return address
+	}
+	if pinned, ok := resolve[host]; ok {
+		return withDefaultPort(pinned, address)
+	}
+	return address
+}
+
+// withDefaultPort adds the port of original to address if it has none.
+func withDefaultPort(address string, original string) string {
+	if _, _, err := net.SplitHostPort(address); err == nil {
+		return address
+	}
+	_, port, _ := net.SplitHostPort(original)
+	return net.JoinHostPort(address, port)
+}
+
+// hostTLSConfig returns the TLS config verifying the server against the overridden Host header.
+func hostTLSConfig(config *HttpConfig) *tls.Config {
+	if config.hostHeader == "" || (config.tls != nil && config.tls.ServerName != "") {
+		return config.tls
+	}
+
+	tlsConfig := &tls.Config{}
+	if config.tls != nil {
+		tlsConfig = config.tls.Clone()
+	}
+	tlsConfig.ServerName = config.hostHeader
+	if host, _, err := net.SplitHostPort(config.hostHeader); err == nil {
+		tlsConfig.ServerName = host
+	}
+	return tlsConfig
+}
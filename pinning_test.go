@@ -0,0 +1,81 @@
+package http
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestWithResolve(t *testing.T) {
+	var host string
+	server := mockServerWith(func(w http.ResponseWriter, r *http.Request) {
+		host = r.Host
+	})
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+	client := NewHttpClientWithConfig(NewDefaultHttpConfig("http://api.example.test:"+serverURL.Port(),
+		WithResolve("api.example.test", "127.0.0.1")))
+
+	resp, err := client.GetFrom("/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if host != "api.example.test:"+serverURL.Port() {
+		t.Errorf("Expected Host header of the URL but got %s", host)
+	}
+}
+
+func TestWithHostHeader(t *testing.T) {
+	var host string
+	server := mockServerWith(func(w http.ResponseWriter, r *http.Request) {
+		host = r.Host
+	})
+	defer server.Close()
+
+	client := NewHttpClientWithConfig(NewDefaultHttpConfig(server.URL, WithHostHeader("api.example.test")))
+
+	resp, err := client.GetFrom("/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if host != "api.example.test" {
+		t.Errorf("Expected overridden Host header but got %s", host)
+	}
+}
+
+func TestPinnedAddress(t *testing.T) {
+	resolve := map[string]string{
+		"api.example.test":     "10.0.0.1",
+		"api.example.test:443": "10.0.0.2:8443",
+	}
+
+	cases := map[string]string{
+		"api.example.test:443":   "10.0.0.2:8443",
+		"api.example.test:80":    "10.0.0.1:80",
+		"other.example.test:443": "other.example.test:443",
+	}
+	for address, expected := range cases {
+		if actual := pinnedAddress(resolve, address); actual != expected {
+			t.Errorf("Expected %s to be pinned to %s but got %s", address, expected, actual)
+		}
+	}
+}
+
+func TestHostTLSConfig(t *testing.T) {
+	config := NewDefaultHttpConfig("https://10.0.0.1", WithHostHeader("api.example.test:443"))
+	if tlsConfig := hostTLSConfig(config); tlsConfig == nil || tlsConfig.ServerName != "api.example.test" {
+		t.Errorf("Expected server name of the Host header but got %+v", tlsConfig)
+	}
+
+	explicit := &tls.Config{ServerName: "explicit"}
+	config.Apply(WithTLSConfig(explicit))
+	if hostTLSConfig(config) != explicit {
+		t.Error("Expected explicit server name to be kept")
+	}
+}
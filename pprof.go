@@ -0,0 +1,44 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"runtime/pprof"
+)
+
+type endpointNameKey struct{}
+
+// WithEndpointName returns a context naming the logical endpoint of the requests executed with it, e.g.
+// "get_user", for profiles, metrics and traces aggregating by endpoint rather than by URL.
+func WithEndpointName(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, endpointNameKey{}, name)
+}
+
+// EndpointNameFromContext returns the endpoint name stored in ctx, if any.
+func EndpointNameFromContext(ctx context.Context) (string, bool) {
+	if ctx == nil {
+		return "", false
+	}
+	name, ok := ctx.Value(endpointNameKey{}).(string)
+	return name, ok
+}
+
+// ProfilerLabels returns a Middleware running requests with the pprof labels "http.method", "http.host" and,
+// if the context names one, "http.endpoint", so CPU and goroutine profiles attribute time to upstream calls.
+func ProfilerLabels() Middleware {
+	return func(next RoundTripperFunc) RoundTripperFunc {
+		return func(r *http.Request) (*http.Response, error) {
+			labels := []string{"http.method", r.Method, "http.host", r.URL.Host}
+			if name, ok := EndpointNameFromContext(r.Context()); ok {
+				labels = append(labels, "http.endpoint", name)
+			}
+
+			var resp *http.Response
+			var err error
+			pprof.Do(r.Context(), pprof.Labels(labels...), func(ctx context.Context) {
+				resp, err = next(r.WithContext(ctx))
+			})
+			return resp, err
+		}
+	}
+}
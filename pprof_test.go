@@ -0,0 +1,36 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"runtime/pprof"
+	"testing"
+)
+
+func TestProfilerLabels(t *testing.T) {
+	server := mockServer(http.StatusOK, contentTypeJSON, fixtureBasicJSON)
+	defer server.Close()
+
+	labels := map[string]string{}
+	client := createTestHTTPClient(server.URL)
+	client.Use(ProfilerLabels(), func(next RoundTripperFunc) RoundTripperFunc {
+		return func(r *http.Request) (*http.Response, error) {
+			pprof.ForLabels(r.Context(), func(key, value string) bool {
+				labels[key] = value
+				return true
+			})
+			return next(r)
+		}
+	})
+	client.DefinePreset("get_root", Preset{Path: "/"})
+
+	resp, err := client.ExecutePreset(context.Background(), "get_root", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if labels["http.method"] != http.MethodGet || labels["http.host"] == "" || labels["http.endpoint"] != "get_root" {
+		t.Errorf("Unexpected profiler labels %v", labels)
+	}
+}
@@ -0,0 +1,188 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Preset is a named request template. Path may contain {name} placeholders which are
+// filled from the parameters given when the preset is instantiated.
+type Preset struct {
+	Method  string
+	Path    string
+	Headers map[string]string
+	Query   map[string]string
+	Timeout time.Duration
+}
+
+// PresetNotFoundError is returned when instantiating a preset which was never defined.
+type PresetNotFoundError struct {
+	Message string
+	Name    string
+}
+
+func (e PresetNotFoundError) Error() string {
+	return e.Message
+}
+
+// PresetPathError is returned when the path of a preset can not be filled from the given parameters.
+type PresetPathError struct {
+	Message string
+	Path    string
+}
+
+func (e PresetPathError) Error() string {
+	return e.Message
+}
+
+// DefinePreset registers a named Preset on the client, replacing any preset with the same name.
+func (h *HttpClient) DefinePreset(name string, preset Preset) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.presets == nil {
+		h.presets = make(map[string]Preset)
+	}
+	h.presets[name] = preset
+}
+
+// PresetRequest creates a request from the named preset. Params fill the path placeholders,
+// any remaining params are set as query parameters overriding the preset's defaults.
+func (h *HttpClient) PresetRequest(name string, params map[string]string, body io.Reader) (*http.Request, error) {
+	preset, err := h.preset(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return preset.request(h, nil, params, body)
+}
+
+// ExecutePreset creates a request from the named preset and executes it, applying the preset's timeout.
+// The preset's name is used as endpoint name unless ctx already has one. A nil ctx defaults to
+// context.Background().
+func (h *HttpClient) ExecutePreset(ctx context.Context, name string, params map[string]string, body io.Reader) (*http.Response, error) {
+	preset, err := h.preset(name)
+	if err != nil {
+		return nil, err
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if _, ok := EndpointNameFromContext(ctx); !ok {
+		ctx = WithEndpointName(ctx, name)
+	}
+	request, err := preset.request(h, ctx, params, body)
+	if err != nil {
+		return nil, err
+	}
+
+	if preset.Timeout <= 0 {
+		return h.ExecuteRequest(request.WithContext(ctx))
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, preset.Timeout)
+	resp, err := h.ExecuteRequest(request.WithContext(ctx))
+	if resp == nil {
+		cancel()
+		return resp, err
+	}
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, err
+}
+
+func (h *HttpClient) preset(name string) (Preset, error) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	preset, ok := h.presets[name]
+	if !ok {
+		return preset, &PresetNotFoundError{Message: fmt.Sprintf("Preset %q not defined.", name), Name: name}
+	}
+	return preset, nil
+}
+
+func (p Preset) request(h *HttpClient, ctx context.Context, params map[string]string, body io.Reader) (*http.Request, error) {
+	method := p.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	path, rest, err := expandPath(p.Path, params)
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := h.newRequest(ctx, method, path, body)
+	if err != nil {
+		return nil, err
+	}
+
+	for key, value := range p.Headers {
+		request.Header.Set(key, value)
+	}
+
+	if len(p.Query) > 0 || len(rest) > 0 {
+		queryValues := request.URL.Query()
+		for key, value := range p.Query {
+			queryValues.Set(key, value)
+		}
+		for key, value := range rest {
+			queryValues.Set(key, value)
+		}
+		request.URL.RawQuery = queryValues.Encode()
+	}
+
+	return request, nil
+}
+
+// expandPath replaces {name} placeholders in path and returns the params which were not used.
+func expandPath(path string, params map[string]string) (string, map[string]string, error) {
+	rest := make(map[string]string, len(params))
+	for key, value := range params {
+		rest[key] = value
+	}
+
+	var b strings.Builder
+	for {
+		start := strings.IndexByte(path, '{')
+		if start < 0 {
+			b.WriteString(path)
+			break
+		}
+		end := strings.IndexByte(path[start:], '}')
+		if end < 0 {
+			return "", nil, &PresetPathError{Message: fmt.Sprintf("Path %q has an unterminated placeholder.", path), Path: path}
+		}
+		end += start
+
+		name := path[start+1 : end]
+		value, ok := params[name]
+		if !ok {
+			return "", nil, &PresetPathError{Message: fmt.Sprintf("Path placeholder %q has no value.", name), Path: path}
+		}
+		delete(rest, name)
+
+		b.WriteString(path[:start])
+		b.WriteString(url.PathEscape(value))
+		path = path[end+1:]
+	}
+
+	return b.String(), rest, nil
+}
+
+// cancelOnClose releases a request context once the response body is closed.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelOnClose) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
+}
@@ -0,0 +1,72 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestHttpClient_PresetRequest(t *testing.T) {
+	client := createTestHTTPClient(fixtureBaseURL)
+	client.DefinePreset("getRepo", Preset{
+		Method:  http.MethodGet,
+		Path:    "/repos/{owner}/{repo}",
+		Headers: map[string]string{"X-Preset": "yes"},
+		Query:   map[string]string{"per_page": "10"},
+	})
+
+	req, err := client.PresetRequest("getRepo", map[string]string{"owner": "hawky 4s", "repo": "client", "page": "2"}, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	assertURLIs(req.URL, fixtureBaseURL+"/repos/hawky%204s/client?page=2&per_page=10", t)
+	if req.Header.Get("X-Preset") != "yes" {
+		t.Errorf("Expected header X-Preset but got %v", req.Header)
+	}
+}
+
+func TestHttpClient_PresetRequestMissingParam(t *testing.T) {
+	client := createTestHTTPClient(fixtureBaseURL)
+	client.DefinePreset("getRepo", Preset{Path: "/repos/{owner}"})
+
+	if _, err := client.PresetRequest("getRepo", nil, nil); err == nil {
+		t.Error("Expected error for missing placeholder value")
+	} else if e, ok := err.(*PresetPathError); !ok || e.Path != "/repos/{owner}" {
+		t.Errorf("Expected PresetPathError but got %v", err)
+	}
+	if _, err := client.PresetRequest("unknown", nil, nil); err == nil {
+		t.Error("Expected PresetNotFoundError")
+	}
+}
+
+func TestHttpClient_ExecutePresetTimeout(t *testing.T) {
+	f := func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(50 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}
+	server := mockServerWith(http.HandlerFunc(f))
+	defer server.Close()
+
+	client := createTestHTTPClient(server.URL)
+	client.DefinePreset("slow", Preset{Path: "/", Timeout: 5 * time.Millisecond})
+
+	if _, err := client.ExecutePreset(context.Background(), "slow", nil, nil); err == nil {
+		t.Error("Expected timeout error")
+	}
+}
+
+func TestHttpClient_ExecutePresetNilContext(t *testing.T) {
+	server := mockServer(http.StatusOK, contentTypeJSON, fixtureBasicJSON)
+	defer server.Close()
+
+	client := createTestHTTPClient(server.URL)
+	client.DefinePreset("root", Preset{Path: "/"})
+
+	resp, err := client.ExecutePreset(nil, "root", nil, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	resp.Body.Close()
+}
@@ -0,0 +1,107 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Names of the profiles registered by default.
+const (
+	ProfileGitHubStyle        = "github-style"
+	ProfileStripeStyle        = "stripe-style"
+	ProfileRetryHeavyInternal = "retry-heavy-internal"
+)
+
+var profiles = struct {
+	sync.RWMutex
+	byName map[string][]Option
+}{byName: map[string][]Option{}}
+
+func init() {
+	RegisterProfile(ProfileGitHubStyle, GitHubStyle())
+	RegisterProfile(ProfileStripeStyle, StripeStyle())
+	RegisterProfile(ProfileRetryHeavyInternal, RetryHeavyInternal())
+}
+
+// RegisterProfile makes the bundle of opts available to WithProfile under name, replacing a profile registered
+// before under the same name. SDKs register the conventions of their API style once and their clients start
+// from them.
+func RegisterProfile(name string, opts ...Option) {
+	profiles.Lock()
+	defer profiles.Unlock()
+	profiles.byName[name] = append([]Option(nil), opts...)
+}
+
+// Profiles returns the names of the registered profiles in alphabetical order.
+func Profiles() []string {
+	profiles.RLock()
+	defer profiles.RUnlock()
+	names := make([]string, 0, len(profiles.byName))
+	for name := range profiles.byName {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// WithProfile applies the options of the profile registered under name. Options given after it override the
+// profile's settings:
+//
+//	config := http.NewDefaultHttpConfig("https://api.github.com", http.WithProfile(http.ProfileGitHubStyle), http.WithTimeout(time.Minute))
+//
+// It panics if no profile is registered under name.
+func WithProfile(name string) Option {
+	profiles.RLock()
+	opts, ok := profiles.byName[name]
+	profiles.RUnlock()
+	if !ok {
+		panic(fmt.Sprintf("profile %q is not registered", name))
+	}
+	return func(c *HttpConfig) {
+		c.Apply(opts...)
+	}
+}
+
+// GitHubStyle bundles the conventions of APIs like GitHub's: Pages follows the Link header, and rate limited
+// responses, 403 or 429 with Retry-After or an exhausted X-RateLimit-Remaining, are retried when the limit
+// resets, as long as that is within a minute. Server errors are retried as well.
+func GitHubStyle() Option {
+	policy := NewRetryPolicy(3).
+		WithRateLimitStatusCodes(http.StatusForbidden, http.StatusTooManyRequests).
+		WithMaxDelay(time.Minute)
+	return func(c *HttpConfig) {
+		c.Apply(WithPagination(LinkPagination), WithRetryPolicy(policy))
+	}
+}
+
+// StripeStyle bundles the conventions of APIs like Stripe's: bodies are sent as forms in bracket notation, POST
+// and PATCH requests get an Idempotency-Key, which makes them safe to retry, and rate limited requests and
+// server errors are retried.
+func StripeStyle() Option {
+	policy := NewRetryPolicy(3).WithStatusCodes(http.StatusTooManyRequests, http.StatusInternalServerError,
+		http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout)
+	return func(c *HttpConfig) {
+		c.Apply(WithContentType(formType), WithIdempotencyKeys(), WithRetryPolicy(policy))
+	}
+}
+
+// RetryHeavyInternal bundles aggressive retries for internal services behind load balancers and meshes, where
+// failures are mostly short-lived: up to 6 attempts with a backoff from 50ms up to 2s on every status
+// IsRetryableStatus reports, on network errors and on HTTP/2 stream errors. Only idempotent requests are retried.
+func RetryHeavyInternal() Option {
+	var statuses []int
+	for code := 100; code < 600; code++ {
+		if IsRetryableStatus(code) {
+			statuses = append(statuses, code)
+		}
+	}
+	policy := NewRetryPolicy(6).
+		WithBackoff(ExponentialBackoff(50*time.Millisecond, 2*time.Second)).
+		WithStatusCodes(statuses...)
+	return func(c *HttpConfig) {
+		c.Apply(WithRetryPolicy(policy), WithHTTP2Fallback())
+	}
+}
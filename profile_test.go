@@ -0,0 +1,118 @@
+package http
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestProfiles(t *testing.T) {
+	names := strings.Join(Profiles(), ",")
+	for _, name := range []string{ProfileGitHubStyle, ProfileStripeStyle, ProfileRetryHeavyInternal} {
+		if !strings.Contains(names, name) {
+			t.Errorf("Expected profile %s to be registered but got %s", name, names)
+		}
+	}
+}
+
+func TestRegisterProfile(t *testing.T) {
+	RegisterProfile("test-profile", WithTimeout(time.Second), WithIdempotencyKeys())
+
+	config := NewDefaultHttpConfig("http://localhost", WithProfile("test-profile"), WithTimeout(time.Minute))
+	if config.Timeout() != time.Minute || !config.IdempotencyKeys() {
+		t.Errorf("Expected profile options overridden by later options but got %s", config)
+	}
+}
+
+func TestWithProfile_Unknown(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected unknown profile to panic")
+		}
+	}()
+	WithProfile("unknown")
+}
+
+func TestGitHubStyle(t *testing.T) {
+	var calls int
+	server := mockServerWith(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		if calls == 2 {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+	})
+	defer server.Close()
+
+	client := NewHttpClientWithConfig(NewDefaultHttpConfig(server.URL, WithProfile(ProfileGitHubStyle)))
+	resp, err := client.Get(context.Background(), "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if calls != 2 || resp.StatusCode != http.StatusForbidden {
+		t.Errorf("Expected only the rate limited 403 to be retried but got %d after %d calls", resp.StatusCode, calls)
+	}
+	if client.Config().Pagination() == nil {
+		t.Error("Expected Link pagination")
+	}
+}
+
+func TestStripeStyle(t *testing.T) {
+	var contentType, key, body string
+	server := mockServerWith(func(w http.ResponseWriter, r *http.Request) {
+		contentType = r.Header.Get("Content-Type")
+		key = r.Header.Get(IdempotencyKeyHeader)
+		data, _ := io.ReadAll(r.Body)
+		body = string(data)
+		w.Header().Set("Content-Type", contentTypeJSON)
+		w.Write([]byte(`{"id": "ch_1"}`))
+	})
+	defer server.Close()
+
+	client := NewHttpClientWithConfig(NewDefaultHttpConfig(server.URL, WithProfile(ProfileStripeStyle)))
+	type charge struct {
+		ID     string            `json:"id,omitempty"`
+		Amount int               `json:"amount,omitempty"`
+		Meta   map[string]string `json:"metadata,omitempty"`
+	}
+	created, err := Call[charge, charge](context.Background(), client, Spec{Method: http.MethodPost, Path: "/v1/charges"},
+		charge{Amount: 100, Meta: map[string]string{"order": "7"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if created.ID != "ch_1" {
+		t.Errorf("Unexpected response %+v", created)
+	}
+	if contentType != "application/x-www-form-urlencoded" || body != "amount=100&metadata%5Border%5D=7" {
+		t.Errorf("Expected form body but got %s: %s", contentType, body)
+	}
+	if key == "" {
+		t.Error("Expected an idempotency key")
+	}
+}
+
+func TestRetryHeavyInternal(t *testing.T) {
+	config := NewDefaultHttpConfig("http://localhost", WithProfile(ProfileRetryHeavyInternal))
+	policy := config.RetryPolicy()
+	if policy == nil || policy.MaxAttempts() != 6 || !config.HTTP2Fallback() {
+		t.Fatalf("Unexpected config %s", config)
+	}
+
+	request, _ := http.NewRequest(http.MethodGet, "http://localhost", nil)
+	for _, code := range []int{http.StatusTooManyRequests, http.StatusInternalServerError} {
+		if !policy.IsRetryable(request, &http.Response{StatusCode: code, Header: http.Header{}}, nil) {
+			t.Errorf("Expected %d to be retried", code)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package http
+
+import (
+	"context"
+	"net/http"
+)
+
+// DefaultPropagatedHeaders are the headers PropagateHeaders copies if none are given.
+var DefaultPropagatedHeaders = []string{"Authorization", TraceIDHeader, "Traceparent", "Tracestate"}
+
+type inboundRequestKey struct{}
+
+// WithInboundRequest returns a context carrying the server request r, whose headers PropagateHeaders copies
+// onto requests executed with the context.
+func WithInboundRequest(ctx context.Context, r *http.Request) context.Context {
+	return context.WithValue(ctx, inboundRequestKey{}, r)
+}
+
+// InboundRequestFromContext returns the server request stored in ctx, if any.
+func InboundRequestFromContext(ctx context.Context) (*http.Request, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	r, ok := ctx.Value(inboundRequestKey{}).(*http.Request)
+	return r, ok && r != nil
+}
+
+// InboundRequestHandler stores every request in its own context before passing it to next, so outbound calls
+// made with r.Context() propagate its headers.
+func InboundRequestHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(WithInboundRequest(r.Context(), r)))
+	})
+}
+
+// PropagateHeaders is a Middleware copying headers from the inbound server request of a request's context, see
+// WithInboundRequest, for services passing identity and tracing downstream. DefaultPropagatedHeaders are copied
+// if no headers are given. Headers already set on the outbound request, including the client's own
+// credentials, are kept; use WithoutAuth to forward the caller's Authorization instead.
+func PropagateHeaders(headers ...string) Middleware {
+	if len(headers) == 0 {
+		headers = DefaultPropagatedHeaders
+	}
+	return func(next RoundTripperFunc) RoundTripperFunc {
+		return func(r *http.Request) (*http.Response, error) {
+			inbound, ok := InboundRequestFromContext(r.Context())
+			if !ok {
+				return next(r)
+			}
+
+			cloned := false
+			for _, header := range headers {
+				values := inbound.Header.Values(header)
+				if len(values) == 0 || r.Header.Get(header) != "" {
+					continue
+				}
+				if !cloned {
+					r = r.Clone(r.Context())
+					cloned = true
+				}
+				for _, value := range values {
+					r.Header.Add(header, value)
+				}
+			}
+			return next(r)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPropagateHeaders(t *testing.T) {
+	var received http.Header
+	upstream := mockServerWith(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+	})
+	defer upstream.Close()
+
+	client := createTestHTTPClient(upstream.URL)
+	client.Use(PropagateHeaders(append(DefaultPropagatedHeaders, "X-Tenant")...))
+
+	service := InboundRequestHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp, err := client.GetFromWithContext(r.Context(), "/")
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}))
+
+	inbound := httptest.NewRequest(http.MethodGet, "/", nil)
+	inbound.Header.Set("Authorization", "Bearer caller")
+	inbound.Header.Set("Traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	inbound.Header.Add("X-Tenant", "a")
+	inbound.Header.Add("X-Tenant", "b")
+	inbound.Header.Set("Cookie", "session=1")
+	service.ServeHTTP(httptest.NewRecorder(), inbound)
+
+	if received.Get("Authorization") != "Bearer caller" || received.Get("Traceparent") == "" {
+		t.Errorf("Expected identity and tracing headers to be propagated but got %v", received)
+	}
+	if tenants := received.Values("X-Tenant"); len(tenants) != 2 {
+		t.Errorf("Expected all tenant values but got %v", tenants)
+	}
+	if received.Get("Cookie") != "" {
+		t.Error("Expected headers not configured to be dropped")
+	}
+}
+
+func TestPropagateHeaders_KeepsExplicitHeaders(t *testing.T) {
+	var received http.Header
+	upstream := mockServerWith(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+	})
+	defer upstream.Close()
+
+	client := NewHttpClientWithConfig(NewHttpConfig(upstream.URL, "service", "secret", ""))
+	client.Use(PropagateHeaders())
+
+	inbound := httptest.NewRequest(http.MethodGet, "/", nil)
+	inbound.Header.Set("Authorization", "Bearer caller")
+	resp, err := client.GetFromWithContext(WithInboundRequest(inbound.Context(), inbound), "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if username, _, ok := (&http.Request{Header: received}).BasicAuth(); !ok || username != "service" {
+		t.Errorf("Expected the client's credentials to be kept but got %v", received.Get("Authorization"))
+	}
+}
@@ -0,0 +1,447 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultRedeliveryInterval = 30 * time.Second
+	defaultMaxDeliveries      = 10
+	maxRedeliveryBackoff      = 1 * time.Hour
+)
+
+// QueuedRequest is the persisted form of a request waiting for redelivery.
+type QueuedRequest struct {
+	ID          string      `json:"id"`
+	Method      string      `json:"method"`
+	URL         string      `json:"url"`
+	Header      http.Header `json:"header,omitempty"`
+	Body        []byte      `json:"body,omitempty"`
+	Attempts    int         `json:"attempts"`
+	CreatedAt   time.Time   `json:"createdAt"`
+	NextAttempt time.Time   `json:"nextAttempt"`
+	LastError   string      `json:"lastError,omitempty"`
+}
+
+// QueueStore persists queued requests between redelivery attempts.
+type QueueStore interface {
+	// Save inserts or replaces the given request.
+	Save(item *QueuedRequest) error
+	// Load returns all pending requests.
+	Load() ([]*QueuedRequest, error)
+	// Delete removes the request with the given id.
+	Delete(id string) error
+}
+
+// DurableQueue provides store-and-forward delivery on top of an HttpClient.
+// Requests which fail are persisted to a QueueStore and redelivered by a background worker.
+type DurableQueue struct {
+	client        *HttpClient
+	store         QueueStore
+	interval      time.Duration
+	maxDeliveries int
+	onDrop        func(item *QueuedRequest)
+	budget        *RetryBudget
+	latency       latencyEstimator
+	clock         Clock
+	backoff       Backoff
+
+	mu      sync.Mutex
+	flushMu sync.Mutex
+	cancel  context.CancelFunc
+	done    chan struct{}
+}
+
+// NewDurableQueue creates a new DurableQueue delivering through client and persisting failures to store.
+func NewDurableQueue(client *HttpClient, store QueueStore) *DurableQueue {
+	if client == nil {
+		panic("client is nil")
+	}
+	if store == nil {
+		panic("store is nil")
+	}
+
+	return &DurableQueue{
+		client:        client,
+		store:         store,
+		interval:      defaultRedeliveryInterval,
+		maxDeliveries: defaultMaxDeliveries,
+		clock:         SystemClock(),
+	}
+}
+
+// WithRedeliveryInterval sets how often the background worker looks for due requests.
+func (q *DurableQueue) WithRedeliveryInterval(interval time.Duration) *DurableQueue {
+	q.interval = interval
+	return q
+}
+
+// WithMaxDeliveries sets after how many failed deliveries a request is dropped.
+func (q *DurableQueue) WithMaxDeliveries(max int) *DurableQueue {
+	q.maxDeliveries = max
+	return q
+}
+
+// WithRetryBudget limits redeliveries to the given budget. Redeliveries exceeding it are postponed to the next
+// redelivery interval without counting as a delivery attempt.
+func (q *DurableQueue) WithRetryBudget(budget *RetryBudget) *DurableQueue {
+	q.budget = budget
+	return q
+}
+
+// WithClock sets the clock scheduling redeliveries.
+func (q *DurableQueue) WithClock(clock Clock) *DurableQueue {
+	q.clock = clock
+	return q
+}
+
+// WithBackoff sets the delay before a failed request is redelivered. By default the delay starts at the
+// redelivery interval and doubles with every failed delivery, up to an hour.
+func (q *DurableQueue) WithBackoff(backoff Backoff) *DurableQueue {
+	q.backoff = backoff
+	return q
+}
+
+// OnDrop registers a callback invoked for requests which exceeded the max deliveries.
+func (q *DurableQueue) OnDrop(f func(item *QueuedRequest)) *DurableQueue {
+	q.onDrop = f
+	return q
+}
+
+// Submit sends the request once with its context and persists it for redelivery if it fails.
+// The response is discarded, the returned error is only set if the request could not be queued.
+func (q *DurableQueue) Submit(r *http.Request) error {
+	item, err := newQueuedRequest(r, q.clock.Now())
+	if err != nil {
+		return err
+	}
+
+	return q.deliver(r.Context(), item)
+}
+
+// Start launches the background redelivery worker. It stops when ctx is done or Stop is called.
+func (q *DurableQueue) Start(ctx context.Context) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.cancel != nil {
+		return
+	}
+
+	ctx, q.cancel = context.WithCancel(ctx)
+	q.done = make(chan struct{})
+
+	go q.run(ctx, q.done)
+}
+
+// Stop halts the background worker and waits for it to exit.
+func (q *DurableQueue) Stop() {
+	q.mu.Lock()
+	cancel, done := q.cancel, q.done
+	q.cancel, q.done = nil, nil
+	q.mu.Unlock()
+
+	if cancel == nil {
+		return
+	}
+	cancel()
+	<-done
+}
+
+// Flush redelivers all requests which are due now. If ctx has a deadline, Flush stops with an
+// AttemptDeadlineError before a delivery which would typically not finish in time.
+func (q *DurableQueue) Flush(ctx context.Context) error {
+	q.flushMu.Lock()
+	defer q.flushMu.Unlock()
+
+	items, err := q.store.Load()
+	if err != nil {
+		return err
+	}
+
+	now := q.clock.Now()
+	for _, item := range items {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if item.NextAttempt.After(now) {
+			continue
+		}
+		if err := checkAttemptDeadline(ctx, 0, q.latency.typical()); err != nil {
+			return err
+		}
+		if err := q.deliver(ctx, item); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (q *DurableQueue) run(ctx context.Context, done chan struct{}) {
+	defer close(done)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-q.clock.After(q.interval):
+			q.Flush(ctx)
+		}
+	}
+}
+
+// deliver sends item with ctx. A delivery aborted because ctx is done is not counted as attempt.
+func (q *DurableQueue) deliver(ctx context.Context, item *QueuedRequest) error {
+	if q.budget != nil {
+		host := queuedHost(item)
+		if item.Attempts == 0 {
+			q.budget.RecordRequest(host)
+		} else if !q.budget.TryRetry(host) {
+			item.NextAttempt = q.clock.Now().Add(q.interval)
+			return q.store.Save(item)
+		}
+	}
+
+	item.Attempts++
+
+	deliveryErr := q.send(ctx, item)
+	if deliveryErr == nil {
+		return q.store.Delete(item.ID)
+	}
+	if ctx.Err() != nil {
+		item.Attempts--
+		return q.store.Save(item)
+	}
+
+	if item.Attempts >= q.maxDeliveries {
+		if q.onDrop != nil {
+			item.LastError = deliveryErr.Error()
+			q.onDrop(item)
+		}
+		return q.store.Delete(item.ID)
+	}
+
+	item.LastError = deliveryErr.Error()
+	delay := q.redeliveryBackoff(item.Attempts)
+	item.NextAttempt = q.clock.Now().Add(delay)
+	q.client.emit(Event{Type: EventRetryScheduled, Host: queuedHost(item), Err: deliveryErr, Attempt: item.Attempts, Delay: delay})
+
+	return q.store.Save(item)
+}
+
+func (q *DurableQueue) send(ctx context.Context, item *QueuedRequest) error {
+	request, err := http.NewRequestWithContext(ctx, item.Method, item.URL, bytes.NewReader(item.Body))
+	if err != nil {
+		return err
+	}
+	for key, values := range item.Header {
+		request.Header[key] = append([]string(nil), values...)
+	}
+
+	start := time.Now()
+	resp, err := q.client.ExecuteRequest(request)
+	q.latency.observe(time.Since(start))
+	if resp != nil {
+		drainAndClose(resp.Body)
+	}
+	if err != nil {
+		return err
+	}
+	if isDeliveryFailure(resp.StatusCode) {
+		return &RemoteError{request.URL.Host, errors.New(resp.Status)}
+	}
+
+	return nil
+}
+
+func queuedHost(item *QueuedRequest) string {
+	return hostOf(item.URL)
+}
+
+func isDeliveryFailure(statusCode int) bool {
+	return statusCode == http.StatusTooManyRequests || statusCode >= http.StatusInternalServerError
+}
+
+func (q *DurableQueue) redeliveryBackoff(attempts int) time.Duration {
+	if q.backoff != nil {
+		return q.backoff(attempts)
+	}
+	return ExponentialBackoff(q.interval, maxRedeliveryBackoff)(attempts)
+}
+
+func newQueuedRequest(r *http.Request, now time.Time) (*QueuedRequest, error) {
+	var body []byte
+	if r.Body != nil {
+		defer r.Body.Close()
+		b, err := io.ReadAll(r.Body)
+		if err != nil {
+			return nil, err
+		}
+		body = b
+	}
+
+	id, err := newQueueID()
+	if err != nil {
+		return nil, err
+	}
+
+	return &QueuedRequest{
+		ID:          id,
+		Method:      r.Method,
+		URL:         r.URL.String(),
+		Header:      r.Header.Clone(),
+		Body:        body,
+		CreatedAt:   now,
+		NextAttempt: now,
+	}, nil
+}
+
+func newQueueID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// MemoryQueueStore is a non-persistent QueueStore, mainly useful for tests.
+type MemoryQueueStore struct {
+	mu    sync.Mutex
+	items map[string]*QueuedRequest
+}
+
+// NewMemoryQueueStore creates an empty MemoryQueueStore.
+func NewMemoryQueueStore() *MemoryQueueStore {
+	return &MemoryQueueStore{items: make(map[string]*QueuedRequest)}
+}
+
+func (s *MemoryQueueStore) Save(item *QueuedRequest) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	copied := *item
+	s.items[item.ID] = &copied
+	return nil
+}
+
+func (s *MemoryQueueStore) Load() ([]*QueuedRequest, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	items := make([]*QueuedRequest, 0, len(s.items))
+	for _, item := range s.items {
+		copied := *item
+		items = append(items, &copied)
+	}
+	sortQueuedRequests(items)
+	return items, nil
+}
+
+func (s *MemoryQueueStore) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.items, id)
+	return nil
+}
+
+// FileQueueStore persists each queued request as a JSON file in a directory.
+//
+// The values of DefaultRedactedHeaders, like Authorization and Cookie, are never written to disk. Redeliveries
+// of loaded requests are authenticated by the client's AuthProvider; credentials set on the submitted request
+// itself are lost if the process restarts before the request is delivered.
+type FileQueueStore struct {
+	dir string
+}
+
+// NewFileQueueStore creates a FileQueueStore in dir, creating the directory if needed.
+func NewFileQueueStore(dir string) (*FileQueueStore, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	return &FileQueueStore{dir: dir}, nil
+}
+
+func (s *FileQueueStore) Save(item *QueuedRequest) error {
+	persisted := *item
+	persisted.Header = item.Header.Clone()
+	for _, name := range DefaultRedactedHeaders {
+		persisted.Header.Del(name)
+	}
+	data, err := json.Marshal(&persisted)
+	if err != nil {
+		return err
+	}
+
+	// write to a temporary file first so a crash never leaves a partial entry behind
+	tmp, err := os.CreateTemp(s.dir, ".tmp-")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	return os.Rename(tmp.Name(), s.path(item.ID))
+}
+
+func (s *FileQueueStore) Load() ([]*QueuedRequest, error) {
+	files, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]*QueuedRequest, 0, len(files))
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), ".json") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(s.dir, file.Name()))
+		if err != nil {
+			return nil, err
+		}
+		item := &QueuedRequest{}
+		if err := json.Unmarshal(data, item); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	sortQueuedRequests(items)
+
+	return items, nil
+}
+
+func (s *FileQueueStore) Delete(id string) error {
+	err := os.Remove(s.path(id))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+func (s *FileQueueStore) path(id string) string {
+	return filepath.Join(s.dir, filepath.Base(id)+".json")
+}
+
+func sortQueuedRequests(items []*QueuedRequest) {
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].CreatedAt.Before(items[j].CreatedAt)
+	})
+}
@@ -0,0 +1,160 @@
+package http
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDurableQueue_SubmitSucceeds(t *testing.T) {
+	server := mockEchoServer(http.StatusOK)
+	defer server.Close()
+
+	store := NewMemoryQueueStore()
+	queue := NewDurableQueue(createTestHTTPClient(server.URL), store)
+
+	req, _ := http.NewRequest(http.MethodPost, server.URL, strings.NewReader(fixtureBasicJSON))
+	if err := queue.Submit(req); err != nil {
+		t.Errorf("Unexpected error %v", err)
+	}
+
+	assertQueueLength(store, 0, t)
+}
+
+func TestDurableQueue_RedeliversFailedRequests(t *testing.T) {
+	var calls int32
+	var lastBody string
+	f := func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		lastBody = string(body)
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}
+	server := mockServerWith(http.HandlerFunc(f))
+	defer server.Close()
+
+	store := NewMemoryQueueStore()
+	queue := NewDurableQueue(createTestHTTPClient(server.URL), store).WithRedeliveryInterval(time.Millisecond)
+
+	req, _ := http.NewRequest(http.MethodPost, server.URL, strings.NewReader(fixtureBasicJSON))
+	if err := queue.Submit(req); err != nil {
+		t.Errorf("Unexpected error %v", err)
+	}
+	assertQueueLength(store, 1, t)
+
+	time.Sleep(5 * time.Millisecond)
+	if err := queue.Flush(context.Background()); err != nil {
+		t.Errorf("Unexpected error %v", err)
+	}
+
+	assertQueueLength(store, 0, t)
+	if lastBody != fixtureBasicJSON {
+		t.Errorf("Expected body %s but got %s", fixtureBasicJSON, lastBody)
+	}
+}
+
+func TestDurableQueue_DropsAfterMaxDeliveries(t *testing.T) {
+	server := mockServer(http.StatusInternalServerError, contentTypeJSON, "")
+	defer server.Close()
+
+	var dropped *QueuedRequest
+	store := NewMemoryQueueStore()
+	queue := NewDurableQueue(createTestHTTPClient(server.URL), store).
+		WithMaxDeliveries(1).
+		OnDrop(func(item *QueuedRequest) { dropped = item })
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	queue.Submit(req)
+
+	assertQueueLength(store, 0, t)
+	if dropped == nil || dropped.Attempts != 1 {
+		t.Errorf("Expected dropped request after 1 attempt but got %v", dropped)
+	}
+}
+
+func TestFileQueueStore(t *testing.T) {
+	store, err := NewFileQueueStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	item := &QueuedRequest{ID: "abc", Method: http.MethodPost, URL: fixtureBaseURL, Body: []byte(fixtureBasicJSON)}
+	if err := store.Save(item); err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	items, _ := store.Load()
+	if len(items) != 1 || string(items[0].Body) != fixtureBasicJSON {
+		t.Errorf("Expected persisted request but got %v", items)
+	}
+
+	store.Delete("abc")
+	assertQueueLength(store, 0, t)
+}
+
+func assertQueueLength(store QueueStore, expected int, t *testing.T) {
+	items, err := store.Load()
+	if err != nil {
+		t.Errorf("Unexpected error %v", err)
+	}
+	if len(items) != expected {
+		t.Errorf("Expected %d queued requests, got %d.", expected, len(items))
+	}
+}
+
+func TestDurableQueue_FlushAbortsWithContext(t *testing.T) {
+	release := make(chan struct{})
+	server := mockServerWith(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	})
+	defer server.Close()
+	defer close(release)
+
+	store := NewMemoryQueueStore()
+	store.Save(&QueuedRequest{ID: "slow", Method: http.MethodPost, URL: server.URL, Attempts: 1})
+	queue := NewDurableQueue(createTestHTTPClient(server.URL), store)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	queue.Flush(ctx)
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected the delivery to be aborted with the context but it took %s", elapsed)
+	}
+	items, _ := store.Load()
+	if len(items) != 1 || items[0].Attempts != 1 {
+		t.Errorf("Expected the aborted delivery to stay queued without counting but got %+v", items)
+	}
+}
+
+func TestFileQueueStore_OmitsCredentials(t *testing.T) {
+	store, err := NewFileQueueStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	header := http.Header{"Authorization": {"Bearer secret"}, "X-Request-Id": {"42"}}
+	item := &QueuedRequest{ID: "abc", Method: http.MethodPost, URL: fixtureBaseURL, Header: header}
+	if err := store.Save(item); err != nil {
+		t.Fatal(err)
+	}
+
+	items, _ := store.Load()
+	if len(items) != 1 || items[0].Header.Get("Authorization") != "" || items[0].Header.Get("X-Request-Id") != "42" {
+		t.Errorf("Expected credentials not to be persisted but got %v", items)
+	}
+	if item.Header.Get("Authorization") == "" {
+		t.Error("Expected the saved request to be left unchanged")
+	}
+}
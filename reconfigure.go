@@ -0,0 +1,109 @@
+package http
+
+import (
+	"net/http"
+)
+
+// Reconfigure applies opts to a copy of the client's config and atomically switches to it if it is valid.
+// Requests already in flight keep their settings. Changing the base URL, credentials or timeout keeps the
+// connection pool, while changing the proxy, TLS or dial settings moves to a new transport and closes idle connections.
+func (h *HttpClient) Reconfigure(opts ...Option) error {
+	previous, err := h.reconfigure(opts...)
+	if previous != nil {
+		// closing connections emits events, which must not happen while holding the lock
+		previous.CloseIdleConnections()
+	}
+	return err
+}
+
+// reconfigure switches to the new config and returns the transport replaced, if any.
+func (h *HttpClient) reconfigure(opts ...Option) (*http.Transport, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	config := *h.config
+	config.Apply(opts...)
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	client := *h.client
+	if config.timeout != h.config.timeout {
+		client.Timeout = config.timeout
+	}
+
+	dialChanged := config.fallbackDelay != h.config.fallbackDelay || config.addressFamily != h.config.addressFamily ||
+		!equalResolve(config.resolve, h.config.resolve) || config.socket != h.config.socket
+	tlsChanged := config.tls != h.config.tls || config.hostHeader != h.config.hostHeader
+	http2Changed := config.http2Fallback != h.config.http2Fallback || config.http2KeepAlive != h.config.http2KeepAlive
+	var previous *http.Transport
+	if config.proxy != h.config.proxy || tlsChanged || dialChanged || http2Changed {
+		updated, transport, ok := withTransport(&client, func(t *http.Transport) {
+			if config.proxy != h.config.proxy || config.socket != h.config.socket {
+				t.Proxy = proxyFunc(&config)
+			}
+			if tlsChanged {
+				t.TLSClientConfig = hostTLSConfig(&config)
+			}
+			if dialChanged {
+				t.DialContext = h.trackedDial(newDialContext(&config))
+			}
+			if http2Changed {
+				configureHTTP2(t, &config)
+			}
+		})
+		if !ok {
+			return nil, &ConfigError{Message: "Changing proxy, TLS, dial or HTTP/2 settings requires an *http.Transport.", Field: "transport"}
+		}
+		client = *updated
+		previous = transport
+	}
+
+	h.config = &config
+	h.client = &client
+	return previous, nil
+}
+
+// updateTransport atomically switches the client to a clone of its transport modified by update.
+// It returns false if the client does not use an *http.Transport.
+func (h *HttpClient) updateTransport(update func(t *http.Transport)) bool {
+	h.mu.Lock()
+	client, previous, ok := withTransport(h.client, update)
+	if ok {
+		h.client = client
+	}
+	h.mu.Unlock()
+
+	if ok {
+		previous.CloseIdleConnections()
+	}
+	return ok
+}
+
+// withTransport returns a copy of client using a clone of its *http.Transport modified by update, together with
+// the original transport. Transports are never modified in place as requests may be using them concurrently.
+func withTransport(client *http.Client, update func(t *http.Transport)) (*http.Client, *http.Transport, bool) {
+	transport, ok := client.Transport.(*http.Transport)
+	if !ok {
+		return nil, nil, false
+	}
+
+	updated := transport.Clone()
+	update(updated)
+
+	copied := *client
+	copied.Transport = updated
+	return &copied, transport, true
+}
+
+func equalResolve(a map[string]string, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if b[k] != v {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,91 @@
+package http
+
+import (
+	"crypto/tls"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestHttpClient_Reconfigure(t *testing.T) {
+	first := mockServer(http.StatusOK, contentTypeJSON, `"first"`)
+	defer first.Close()
+	second := mockServer(http.StatusOK, contentTypeJSON, `"second"`)
+	defer second.Close()
+
+	client := createTestHTTPClient(first.URL)
+	transport := client.client.Transport
+
+	resp, err := client.GetFrom("/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertResponseBodyIs(resp, `"first"`, t)
+
+	if err := client.Reconfigure(WithBaseURL(second.URL), WithTimeout(time.Second)); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err = client.GetFrom("/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertResponseBodyIs(resp, `"second"`, t)
+
+	if client.client.Timeout != time.Second {
+		t.Errorf("Expected timeout 1s but got %s", client.client.Timeout)
+	}
+	if client.client.Transport != transport {
+		t.Error("Expected transport and its connection pool to be kept")
+	}
+}
+
+func TestHttpClient_ReconfigureInvalid(t *testing.T) {
+	client := createTestHTTPClient(fixtureBaseURL)
+
+	if err := client.Reconfigure(WithBaseURL("ftp://example.com")); err == nil {
+		t.Error("Expected error for invalid base URL")
+	}
+	if client.config.baseURL != fixtureBaseURL {
+		t.Errorf("Expected config to be unchanged but got %s", client.config.baseURL)
+	}
+}
+
+func TestHttpClient_ReconfigureTLS(t *testing.T) {
+	client := createTestHTTPClient(fixtureBaseURL)
+	transport := client.client.Transport
+	tlsConfig := &tls.Config{ServerName: "api"}
+
+	if err := client.Reconfigure(WithTLSConfig(tlsConfig)); err != nil {
+		t.Fatal(err)
+	}
+
+	updated := client.client.Transport.(*http.Transport)
+	if updated == transport || updated.TLSClientConfig != tlsConfig {
+		t.Error("Expected a new transport using the TLS config")
+	}
+}
+
+func TestHttpClient_ReconfigureConcurrently(t *testing.T) {
+	server := mockServer(http.StatusOK, contentTypeJSON, fixtureBasicJSON)
+	defer server.Close()
+
+	client := createTestHTTPClient(server.URL)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if resp, err := client.GetFrom("/"); err == nil {
+				resp.Body.Close()
+			}
+		}()
+		go func(i int) {
+			defer wg.Done()
+			client.Reconfigure(WithTimeout(time.Duration(i+1) * time.Second))
+		}(i)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,190 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// DefaultRedactedParams are the query parameters whose values are masked in errors and logs.
+var DefaultRedactedParams = []string{
+	"access_token", "api_key", "apikey", "client_secret", "code", "key", "password", "secret", "sig", "signature", "token",
+}
+
+// DefaultRedactedHeaders are the headers whose values are masked in errors and traces.
+var DefaultRedactedHeaders = []string{
+	"Authorization", "Cookie", "Proxy-Authorization", "Set-Cookie", "X-Api-Key",
+}
+
+// WithRedactedParams masks the values of the given query parameters, in addition to DefaultRedactedParams,
+// wherever the client reports a URL: in errors, events and the logs and traces of its middleware.
+func WithRedactedParams(names ...string) Option {
+	return func(c *HttpConfig) {
+		c.redactedParams = append(append([]string(nil), c.redactedParams...), names...)
+	}
+}
+
+// WithRedactedHeaders masks the values of the given headers, in addition to DefaultRedactedHeaders, in errors,
+// events and the logs and traces of the client's middleware.
+func WithRedactedHeaders(names ...string) Option {
+	return func(c *HttpConfig) {
+		c.redactedHeaders = append(append([]string(nil), c.redactedHeaders...), names...)
+	}
+}
+
+// redaction decides which parts of requests are secret.
+type redaction struct {
+	params  map[string]bool
+	headers map[string]bool
+}
+
+func newRedaction(params []string, headers []string) *redaction {
+	r := &redaction{params: map[string]bool{}, headers: map[string]bool{}}
+	for _, name := range append(append([]string(nil), DefaultRedactedParams...), params...) {
+		r.params[strings.ToLower(name)] = true
+	}
+	for _, name := range append(append([]string(nil), DefaultRedactedHeaders...), headers...) {
+		r.headers[http.CanonicalHeaderKey(name)] = true
+	}
+	return r
+}
+
+// defaultRedaction is used where no client config is at hand, e.g. by middleware outside of a client.
+var defaultRedaction = newRedaction(nil, nil)
+
+type redactionKey struct{}
+
+// withRedaction makes the redaction of the client executing a request available to its middleware and events.
+func withRedaction(ctx context.Context, p *redaction) context.Context {
+	return context.WithValue(ctx, redactionKey{}, p)
+}
+
+// redactionFrom returns the redaction of the client executing a request with ctx, defaultRedaction if none.
+func redactionFrom(ctx context.Context) *redaction {
+	if ctx != nil {
+		if p, ok := ctx.Value(redactionKey{}).(*redaction); ok {
+			return p
+		}
+	}
+	return defaultRedaction
+}
+
+func (h *HttpClient) redaction() *redaction {
+	h.mu.RLock()
+	params, headers, policy := h.config.redactedParams, h.config.redactedHeaders, h.config.headerPolicy
+	h.mu.RUnlock()
+
+	if len(params) == 0 && len(headers) == 0 && policy == nil {
+		return defaultRedaction
+	}
+	if policy != nil {
+		headers = append(append([]string(nil), headers...), policy.Sensitive()...)
+	}
+	return newRedaction(params, headers)
+}
+
+// header reports whether the value of header is secret.
+func (p *redaction) header(key string) bool {
+	return p.headers[http.CanonicalHeaderKey(key)]
+}
+
+// headerValue returns value, or its masked form if header is secret. The scheme of credentials is kept.
+func (p *redaction) headerValue(key string, value string) string {
+	if !p.header(key) {
+		return value
+	}
+	key = http.CanonicalHeaderKey(key)
+	if scheme, _, ok := strings.Cut(value, " "); ok && key != "Cookie" && key != "Set-Cookie" {
+		return scheme + " " + redactedSecret
+	}
+	return redactedSecret
+}
+
+// url returns u without userinfo and with the values of secret query parameters masked.
+func (p *redaction) url(u *url.URL) string {
+	if u == nil {
+		return ""
+	}
+	sanitized := *u
+	sanitized.User = nil
+	if sanitized.RawQuery != "" {
+		sanitized.RawQuery = p.query(sanitized.RawQuery)
+	}
+	return sanitized.String()
+}
+
+// query masks secret values in rawQuery, keeping the order and encoding of the other parameters.
+func (p *redaction) query(rawQuery string) string {
+	pairs := strings.Split(rawQuery, "&")
+	for i, pair := range pairs {
+		key, _, hasValue := strings.Cut(pair, "=")
+		name, err := url.QueryUnescape(key)
+		if err != nil {
+			name = key
+		}
+		if hasValue && p.params[strings.ToLower(name)] {
+			pairs[i] = key + "=" + redactedSecret
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+// rawURL is like url for a URL in string form.
+func (p *redaction) rawURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	return p.url(u)
+}
+
+// message removes the URL and credentials of r from msg, e.g. the text of a wrapped *url.Error.
+func (p *redaction) message(msg string, r *http.Request, secrets ...string) string {
+	if r == nil || r.URL == nil {
+		return msg
+	}
+
+	sanitized := p.url(r.URL)
+	replacer := []string{r.URL.String(), sanitized, r.URL.Redacted(), sanitized}
+	if r.URL.User != nil {
+		// net/http reports URLs with the password replaced by ***
+		stripped := strings.Replace(r.URL.String(), r.URL.User.String()+"@", r.URL.User.Username()+":***@", 1)
+		replacer = append(replacer, stripped, sanitized)
+	}
+	msg = strings.NewReplacer(replacer...).Replace(msg)
+
+	for key, values := range r.Header {
+		if p.header(key) {
+			secrets = append(secrets, values...)
+		}
+	}
+	if r.URL.User != nil {
+		if password, ok := r.URL.User.Password(); ok {
+			secrets = append(secrets, password)
+		}
+	}
+	for _, secret := range secrets {
+		if secret != "" {
+			msg = strings.ReplaceAll(msg, secret, redactedSecret)
+		}
+	}
+	return msg
+}
+
+// sanitizeError removes the credentials of r from the URLs carried by err.
+func (p *redaction) sanitizeError(err error) {
+	var notFound *NotFoundError
+	if errors.As(err, &notFound) {
+		notFound.URL = p.rawURL(notFound.URL)
+	}
+	var unauthorized *UnauthorizedError
+	if errors.As(err, &unauthorized) {
+		unauthorized.URL = p.rawURL(unauthorized.URL)
+	}
+	var slow *SlowResponseError
+	if errors.As(err, &slow) {
+		slow.URL = p.rawURL(slow.URL)
+	}
+}
@@ -0,0 +1,285 @@
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ReplayEntry is a recorded request to be sent again by a Replayer.
+type ReplayEntry struct {
+	Method string
+	// URL is the recorded URL. Only its path and query are replayed, against the client's base URL.
+	URL    string
+	Header http.Header
+	Body   []byte
+}
+
+// ReplayResult is the outcome of replaying a single entry.
+type ReplayResult struct {
+	Entry      ReplayEntry
+	StatusCode int
+	Err        error
+	Duration   time.Duration
+}
+
+// ReplayReport summarizes a replay run.
+type ReplayReport struct {
+	Sent        int
+	Failed      int
+	StatusCodes map[int]int
+	Duration    time.Duration
+}
+
+// skippedReplayHeaders are set by the client or transport rather than copied from the recording.
+var skippedReplayHeaders = map[string]bool{
+	"Host":                true,
+	"Content-Length":      true,
+	"Connection":          true,
+	"Authorization":       true,
+	"Proxy-Authorization": true,
+	"Accept-Encoding":     true,
+}
+
+// ReadHAR reads the requests recorded in a HAR (HTTP Archive) file.
+func ReadHAR(r io.Reader) ([]ReplayEntry, error) {
+	var har struct {
+		Log struct {
+			Entries []struct {
+				Request struct {
+					Method  string `json:"method"`
+					URL     string `json:"url"`
+					Headers []struct {
+						Name  string `json:"name"`
+						Value string `json:"value"`
+					} `json:"headers"`
+					PostData *struct {
+						MimeType string `json:"mimeType"`
+						Text     string `json:"text"`
+					} `json:"postData"`
+				} `json:"request"`
+			} `json:"entries"`
+		} `json:"log"`
+	}
+	if err := json.NewDecoder(r).Decode(&har); err != nil {
+		return nil, fmt.Errorf("reading HAR: %w", err)
+	}
+
+	entries := make([]ReplayEntry, 0, len(har.Log.Entries))
+	for _, e := range har.Log.Entries {
+		entry := ReplayEntry{Method: e.Request.Method, URL: e.Request.URL, Header: http.Header{}}
+		for _, header := range e.Request.Headers {
+			// HTTP/2 recordings contain pseudo-headers like :authority
+			if strings.HasPrefix(header.Name, ":") {
+				continue
+			}
+			entry.Header.Add(header.Name, header.Value)
+		}
+		if e.Request.PostData != nil {
+			entry.Body = []byte(e.Request.PostData.Text)
+			if entry.Header.Get("Content-Type") == "" && e.Request.PostData.MimeType != "" {
+				entry.Header.Set("Content-Type", e.Request.PostData.MimeType)
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// ReadAccessLog reads the requests of an access log in Common or Combined Log Format, e.g.
+//
+//	127.0.0.1 - - [10/Oct/2000:13:55:36 -0700] "GET /users?page=2 HTTP/1.1" 200 2326
+//
+// Lines without a quoted request line are skipped. Access logs carry neither headers nor bodies.
+func ReadAccessLog(r io.Reader) ([]ReplayEntry, error) {
+	var entries []ReplayEntry
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		start := strings.IndexByte(line, '"')
+		if start < 0 {
+			continue
+		}
+		end := strings.IndexByte(line[start+1:], '"')
+		if end < 0 {
+			continue
+		}
+		fields := strings.Fields(line[start+1 : start+1+end])
+		if len(fields) < 2 {
+			continue
+		}
+		entries = append(entries, ReplayEntry{Method: fields[0], URL: fields[1], Header: http.Header{}})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading access log: %w", err)
+	}
+	return entries, nil
+}
+
+// Replayer sends recorded requests through a client again, e.g. to reproduce load or for regression tests.
+type Replayer struct {
+	client      *HttpClient
+	entries     []ReplayEntry
+	concurrency int
+	rate        float64
+	onResult    func(ReplayResult)
+}
+
+// NewReplayer creates a Replayer sending entries through client one at a time, as fast as possible.
+func NewReplayer(client *HttpClient, entries []ReplayEntry) *Replayer {
+	if client == nil {
+		panic("client is nil")
+	}
+
+	return &Replayer{client: client, entries: entries, concurrency: 1}
+}
+
+// WithConcurrency sets the number of requests in flight at once.
+func (r *Replayer) WithConcurrency(concurrency int) *Replayer {
+	if concurrency < 1 {
+		panic("concurrency must be positive")
+	}
+	r.concurrency = concurrency
+	return r
+}
+
+// WithRate limits the requests started per second. Zero means unlimited.
+func (r *Replayer) WithRate(perSecond float64) *Replayer {
+	if perSecond < 0 {
+		panic("rate must not be negative")
+	}
+	r.rate = perSecond
+	return r
+}
+
+// OnResult registers f to be called for every replayed entry. f may be called concurrently.
+func (r *Replayer) OnResult(f func(ReplayResult)) *Replayer {
+	r.onResult = f
+	return r
+}
+
+// Run replays all entries in order and returns once every request has completed or ctx is done.
+// Entries which were not started before ctx was done are not counted.
+func (r *Replayer) Run(ctx context.Context) (ReplayReport, error) {
+	report := ReplayReport{StatusCodes: map[int]int{}}
+	start := time.Now()
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	work := make(chan ReplayEntry)
+
+	for i := 0; i < r.concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for entry := range work {
+				result := r.replay(ctx, entry)
+
+				mu.Lock()
+				report.Sent++
+				if result.Err != nil {
+					report.Failed++
+				} else {
+					report.StatusCodes[result.StatusCode]++
+				}
+				mu.Unlock()
+
+				if r.onResult != nil {
+					r.onResult(result)
+				}
+			}
+		}()
+	}
+
+	var tick <-chan time.Time
+	if r.rate > 0 {
+		ticker := time.NewTicker(time.Duration(float64(time.Second) / r.rate))
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	err := r.dispatch(ctx, work, tick)
+	close(work)
+	wg.Wait()
+
+	report.Duration = time.Since(start)
+	return report, err
+}
+
+func (r *Replayer) dispatch(ctx context.Context, work chan<- ReplayEntry, tick <-chan time.Time) error {
+	for i, entry := range r.entries {
+		// the first request starts right away, the following ones wait for the rate limit
+		if tick != nil && i > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-tick:
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case work <- entry:
+		}
+	}
+	return nil
+}
+
+func (r *Replayer) replay(ctx context.Context, entry ReplayEntry) ReplayResult {
+	result := ReplayResult{Entry: entry}
+	start := time.Now()
+
+	request, err := r.newRequest(ctx, entry)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	resp, err := r.client.ExecuteRequest(request)
+	if resp != nil {
+		result.StatusCode = resp.StatusCode
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+	}
+	// status errors are reported by the status code, only transport failures count as failed
+	if resp == nil {
+		result.Err = err
+	}
+	result.Duration = time.Since(start)
+	return result
+}
+
+func (r *Replayer) newRequest(ctx context.Context, entry ReplayEntry) (*http.Request, error) {
+	u, err := url.Parse(entry.URL)
+	if err != nil {
+		return nil, fmt.Errorf("replaying %q: %w", entry.URL, err)
+	}
+
+	var body io.Reader
+	if len(entry.Body) > 0 {
+		body = bytes.NewReader(entry.Body)
+	}
+	request, err := r.client.newRequest(ctx, entry.Method, u.RequestURI(), body)
+	if err != nil {
+		return nil, err
+	}
+	request = request.WithContext(ctx)
+
+	for key, values := range entry.Header {
+		key = http.CanonicalHeaderKey(key)
+		if skippedReplayHeaders[key] {
+			continue
+		}
+		request.Header[key] = values
+	}
+	return request, nil
+}
@@ -0,0 +1,124 @@
+package http
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const fixtureHAR = `{"log": {"entries": [
+	{"request": {"method": "GET", "url": "https://example.com/users?page=2", "headers": [
+		{"name": ":authority", "value": "example.com"},
+		{"name": "X-Tenant", "value": "acme"},
+		{"name": "Authorization", "value": "Bearer recorded"}]}},
+	{"request": {"method": "POST", "url": "https://example.com/users", "headers": [],
+		"postData": {"mimeType": "application/json", "text": "{\"name\":\"bob\"}"}}}
+]}}`
+
+func TestReadHAR(t *testing.T) {
+	entries, err := ReadHAR(strings.NewReader(fixtureHAR))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries but got %d", len(entries))
+	}
+	if entries[0].Header.Get(":authority") != "" || entries[0].Header.Get("X-Tenant") != "acme" {
+		t.Errorf("Unexpected headers %v", entries[0].Header)
+	}
+	if string(entries[1].Body) != `{"name":"bob"}` || entries[1].Header.Get("Content-Type") != contentTypeJSON {
+		t.Errorf("Unexpected post entry %+v", entries[1])
+	}
+}
+
+func TestReadAccessLog(t *testing.T) {
+	log := `127.0.0.1 - - [10/Oct/2000:13:55:36 -0700] "GET /users?page=2 HTTP/1.1" 200 2326
+garbage
+10.0.0.1 - frank [10/Oct/2000:13:55:37 -0700] "DELETE /users/1 HTTP/1.1" 204 0 "-" "curl/8.0"
+`
+	entries, err := ReadAccessLog(strings.NewReader(log))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].URL != "/users?page=2" || entries[1].Method != http.MethodDelete {
+		t.Errorf("Unexpected entries %+v", entries)
+	}
+}
+
+func TestReplayer_Run(t *testing.T) {
+	var mu sync.Mutex
+	var received []string
+	server := mockServerWith(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		received = append(received, r.Method+" "+r.URL.RequestURI()+" "+r.Header.Get("X-Tenant")+" "+r.Header.Get("Authorization")+" "+string(body))
+		mu.Unlock()
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusCreated)
+		}
+	})
+	defer server.Close()
+
+	entries, _ := ReadHAR(strings.NewReader(fixtureHAR))
+	report, err := NewReplayer(createTestHTTPClient(server.URL), entries).Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if report.Sent != 2 || report.Failed != 0 || report.StatusCodes[200] != 1 || report.StatusCodes[201] != 1 {
+		t.Errorf("Unexpected report %+v", report)
+	}
+	expected := []string{"GET /users?page=2 acme  ", `POST /users   {"name":"bob"}`}
+	for i := range expected {
+		if received[i] != expected[i] {
+			t.Errorf("Expected %q but got %q", expected[i], received[i])
+		}
+	}
+}
+
+func TestReplayer_WithRate(t *testing.T) {
+	server := mockServerWith(nil)
+	defer server.Close()
+
+	entries := make([]ReplayEntry, 5)
+	for i := range entries {
+		entries[i] = ReplayEntry{Method: http.MethodGet, URL: "/"}
+	}
+
+	var results int32
+	report, err := NewReplayer(createTestHTTPClient(server.URL), entries).
+		WithConcurrency(3).
+		WithRate(100).
+		OnResult(func(ReplayResult) { atomic.AddInt32(&results, 1) }).
+		Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Sent != 5 || results != 5 {
+		t.Errorf("Expected 5 requests but got %+v", report)
+	}
+	// four intervals of 10ms between five requests
+	if report.Duration < 40*time.Millisecond {
+		t.Errorf("Expected rate limit to spread requests but took %s", report.Duration)
+	}
+}
+
+func TestReplayer_Canceled(t *testing.T) {
+	server := mockServerWith(nil)
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	report, err := NewReplayer(createTestHTTPClient(server.URL), []ReplayEntry{{Method: http.MethodGet, URL: "/"}, {Method: http.MethodGet, URL: "/"}}).
+		WithRate(1).
+		Run(ctx)
+	if err != context.Canceled || report.Sent > 1 {
+		t.Errorf("Expected cancellation but got %v, %+v", err, report)
+	}
+}
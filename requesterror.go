@@ -0,0 +1,56 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// RequestError is returned by ExecuteRequest, and the methods built on it, for every failed request.
+// It wraps the cause, so errors.As and errors.Is see through it, and adds the context needed in logs
+// and error reports.
+type RequestError struct {
+	Method string
+	// URL is the request URL without userinfo and with secret query parameters masked.
+	URL string
+	// Attempts is the number of round trips sent, zero if the request failed before sending.
+	Attempts int
+	Elapsed  time.Duration
+	Err      error
+
+	// cause is the text of Err without credentials
+	cause string
+}
+
+func (e RequestError) Error() string {
+	cause := e.cause
+	if cause == "" && e.Err != nil {
+		cause = e.Err.Error()
+	}
+	return fmt.Sprintf("%s %s failed after %d attempt(s) in %s: %s", e.Method, e.URL, e.Attempts, e.Elapsed.Round(time.Millisecond), cause)
+}
+
+func (e RequestError) Unwrap() error {
+	return e.Err
+}
+
+// newRequestError wraps err with the context of r. Credentials are removed from the message and from the URLs
+// of the wrapped errors.
+func (h *HttpClient) newRequestError(r *http.Request, start time.Time, err error) *RequestError {
+	h.mu.RLock()
+	password := h.config.password
+	h.mu.RUnlock()
+
+	localizeError(h.messageCatalog(), err)
+	redaction := h.redaction()
+	redaction.sanitizeError(err)
+
+	return &RequestError{
+		Method:   r.Method,
+		URL:      redaction.url(r.URL),
+		Attempts: attemptsFrom(r.Context()),
+		Elapsed:  time.Since(start),
+		Err:      err,
+		cause:    redaction.message(err.Error(), r, password),
+	}
+}
@@ -0,0 +1,49 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestRequestError(t *testing.T) {
+	server := mockServerWith(nil)
+	url := server.URL
+	server.Close()
+
+	client := createTestHTTPClient(url)
+	req, _ := http.NewRequest(http.MethodGet, strings.Replace(url, "http://", "http://user:secret@", 1)+"/users", nil)
+	_, err := client.ExecuteRequest(req)
+
+	var requestErr *RequestError
+	if !errors.As(err, &requestErr) {
+		t.Fatalf("Expected RequestError but got %T %v", err, err)
+	}
+	if requestErr.Method != http.MethodGet || requestErr.URL != url+"/users" || requestErr.Attempts != 1 || requestErr.Elapsed <= 0 {
+		t.Errorf("Unexpected error context %+v", requestErr)
+	}
+	if strings.Contains(err.Error(), "secret") || !strings.Contains(err.Error(), "GET "+url+"/users failed after 1 attempt(s)") {
+		t.Errorf("Unexpected message %s", err)
+	}
+
+	var remoteErr *RemoteError
+	if !errors.As(err, &remoteErr) {
+		t.Errorf("Expected the cause to be wrapped but got %v", err)
+	}
+}
+
+func TestRequestError_BeforeSending(t *testing.T) {
+	client := createTestHTTPClient(fixtureBaseURL)
+	client.Shutdown(context.Background())
+
+	req, _ := client.GetRequest("/users")
+	_, err := client.ExecuteRequest(req)
+
+	var requestErr *RequestError
+	var closedErr *ClientClosedError
+	if !errors.As(err, &requestErr) || requestErr.Attempts != 0 || !errors.As(err, &closedErr) {
+		t.Errorf("Expected RequestError wrapping ClientClosedError but got %v", err)
+	}
+}
@@ -0,0 +1,67 @@
+package http
+
+import (
+	"context"
+)
+
+// RequestOption customizes the handling of a single request. Options travel with the request's
+// context, so they apply to the convenience methods as well as to ExecuteRequest.
+type RequestOption func(*requestOptions)
+
+type requestOptions struct {
+	authOverride bool
+	auth         AuthProvider
+	watchdog     *watchdogLimits
+	// untimed skips the http.Client's timeout, which makes upgraded connections unwritable
+	untimed bool
+	// bufferLimit buffers the response body up to the limit, see WithBufferedResponse
+	bufferLimit int64
+	// compression and acceptEncoding override the client's compression, see WithRequestCompression
+	compression    *Compression
+	acceptEncoding string
+	// passthrough forwards the headers of an inbound request, see PassthroughHandler
+	passthrough *passthrough
+	// tls overrides the server name and expected certificates, see WithTLSServerName
+	tls *tlsOverride
+	// noRetry sends the request once despite the client's RetryPolicy, see WithoutRetry
+	noRetry bool
+}
+
+type requestOptionsKey struct{}
+
+// WithRequestOptions returns a context applying opts to the requests created or executed with it.
+func WithRequestOptions(ctx context.Context, opts ...RequestOption) context.Context {
+	options := requestOptions{}
+	if parent := requestOptionsFrom(ctx); parent != nil {
+		options = *parent
+	}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return context.WithValue(ctx, requestOptionsKey{}, &options)
+}
+
+// WithoutAuth sends the request without any of the client's credentials, e.g. for public
+// endpoints or pre-signed URLs.
+func WithoutAuth() RequestOption {
+	return func(o *requestOptions) {
+		o.authOverride = true
+		o.auth = nil
+	}
+}
+
+// WithAuth authenticates the request with provider instead of the client's credentials.
+func WithAuth(provider AuthProvider) RequestOption {
+	return func(o *requestOptions) {
+		o.authOverride = true
+		o.auth = provider
+	}
+}
+
+func requestOptionsFrom(ctx context.Context) *requestOptions {
+	if ctx == nil {
+		return nil
+	}
+	options, _ := ctx.Value(requestOptionsKey{}).(*requestOptions)
+	return options
+}
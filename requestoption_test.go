@@ -0,0 +1,53 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestHttpClient_WithoutAuth(t *testing.T) {
+	var auth string
+	f := func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		w.WriteHeader(http.StatusOK)
+	}
+	server := mockServerWith(http.HandlerFunc(f))
+	defer server.Close()
+
+	client := NewHttpClientWithConfig(NewHttpConfig(server.URL, "foo", "bar", contentTypeJSON))
+	client.SetAuthProvider(BearerAuth(StaticCredentials("", "")))
+
+	ctx := WithRequestOptions(context.Background(), WithoutAuth())
+	client.GetFromWithContext(ctx, "")
+	if auth != "" {
+		t.Errorf("Expected no Authorization header but got %s", auth)
+	}
+
+	req, _ := client.GetRequest("")
+	client.ExecuteRequest(req.WithContext(ctx))
+	if auth != "" {
+		t.Errorf("Expected Authorization header to be stripped but got %s", auth)
+	}
+}
+
+func TestHttpClient_WithAuth(t *testing.T) {
+	var auth string
+	f := func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		w.WriteHeader(http.StatusOK)
+	}
+	server := mockServerWith(http.HandlerFunc(f))
+	defer server.Close()
+
+	client := NewHttpClientWithConfig(NewHttpConfig(server.URL, "foo", "bar", contentTypeJSON))
+
+	override := BearerAuth(CredentialSourceFunc(func(ctx context.Context) (Credentials, error) {
+		return Credentials{Token: "presigned"}, nil
+	}))
+	client.GetFromWithContext(WithRequestOptions(context.Background(), WithAuth(override)), "")
+
+	if auth != "Bearer presigned" {
+		t.Errorf("Expected overridden Authorization header but got %s", auth)
+	}
+}
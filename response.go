@@ -0,0 +1,42 @@
+package http
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+)
+
+// Response wraps an *http.Response whose body has been read into memory, so helpers can
+// inspect it repeatedly.
+type Response struct {
+	*http.Response
+	body []byte
+}
+
+// NewResponse reads and closes the body of resp. The embedded response's Body is replaced by a
+// reader over the buffered content.
+func NewResponse(resp *http.Response) (*Response, error) {
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return &Response{Response: resp, body: body}, nil
+}
+
+// Bytes returns the buffered body.
+func (r *Response) Bytes() []byte {
+	return r.body
+}
+
+// String returns the buffered body as string.
+func (r *Response) String() string {
+	return string(r.body)
+}
+
+// JSONPath extracts a value from the JSON body, see ExtractJSONPath.
+func (r *Response) JSONPath(path string) (interface{}, error) {
+	return ExtractJSONPath(r.body, path)
+}
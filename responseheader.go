@@ -0,0 +1,54 @@
+package http
+
+import (
+	"mime"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// ContentLength returns the length of the body as announced by the server, false if it is unknown. It shadows
+// the ContentLength field of the embedded http.Response, which stays accessible as r.Response.ContentLength.
+func (r *Response) ContentLength() (int64, bool) {
+	if r.Response.ContentLength >= 0 {
+		return r.Response.ContentLength, true
+	}
+	length, err := strconv.ParseInt(r.Header.Get("Content-Length"), 10, 64)
+	if err != nil || length < 0 {
+		return 0, false
+	}
+	return length, true
+}
+
+// ContentType returns the media type of the Content-Type header in lower case and its parameters, like charset.
+// The media type is empty if the header is missing.
+func (r *Response) ContentType() (mediaType string, params map[string]string, err error) {
+	contentType := r.Header.Get("Content-Type")
+	if contentType == "" {
+		return "", nil, nil
+	}
+	return mime.ParseMediaType(contentType)
+}
+
+// Date returns the time the response was generated according to its Date header, false if it is missing or
+// invalid.
+func (r *Response) Date() (time.Time, bool) {
+	date, err := http.ParseTime(r.Header.Get("Date"))
+	if err != nil {
+		return time.Time{}, false
+	}
+	return date, true
+}
+
+// RetryAfter returns how long the server asks to wait before the next request, by a Retry-After header in
+// seconds or as HTTP date, or by an exhausted X-RateLimit-Remaining and the X-RateLimit-Reset time. It is false
+// if the response asks for neither.
+func (r *Response) RetryAfter() (time.Duration, bool) {
+	return retryAfter(r.Response, time.Now())
+}
+
+// Links returns the URLs of the Link headers by relation type, see Links. The embedded http.Response provides
+// the Location header resolved against the request URL.
+func (r *Response) Links() map[string]string {
+	return Links(r.Response)
+}
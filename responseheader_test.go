@@ -0,0 +1,78 @@
+package http
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func newHeaderResponse(header http.Header) *Response {
+	request, _ := http.NewRequest(http.MethodGet, "https://api.example.com/v1/items/", nil)
+	return &Response{Response: &http.Response{Header: header, ContentLength: -1, Request: request}}
+}
+
+func TestResponse_ContentLength(t *testing.T) {
+	resp := newHeaderResponse(http.Header{"Content-Length": {"42"}})
+	if length, ok := resp.ContentLength(); !ok || length != 42 {
+		t.Errorf("Expected length 42 but got %d, %t", length, ok)
+	}
+
+	resp = newHeaderResponse(http.Header{})
+	if _, ok := resp.ContentLength(); ok {
+		t.Error("Expected unknown length")
+	}
+
+	resp.Response.ContentLength = 7
+	if length, ok := resp.ContentLength(); !ok || length != 7 {
+		t.Errorf("Expected the length of the embedded response but got %d", length)
+	}
+}
+
+func TestResponse_ContentType(t *testing.T) {
+	resp := newHeaderResponse(http.Header{"Content-Type": {"Application/JSON; charset=UTF-8"}})
+	mediaType, params, err := resp.ContentType()
+	if err != nil || mediaType != "application/json" || params["charset"] != "UTF-8" {
+		t.Errorf("Unexpected content type %s %v %v", mediaType, params, err)
+	}
+
+	if mediaType, _, err := newHeaderResponse(http.Header{}).ContentType(); mediaType != "" || err != nil {
+		t.Errorf("Expected no content type but got %q, %v", mediaType, err)
+	}
+}
+
+func TestResponse_Date(t *testing.T) {
+	date := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	resp := newHeaderResponse(http.Header{"Date": {date.Format(http.TimeFormat)}})
+	if got, ok := resp.Date(); !ok || !got.Equal(date) {
+		t.Errorf("Expected %s but got %s", date, got)
+	}
+	if _, ok := newHeaderResponse(http.Header{"Date": {"yesterday"}}).Date(); ok {
+		t.Error("Expected invalid date to be rejected")
+	}
+}
+
+func TestResponse_RetryAfter(t *testing.T) {
+	if after, ok := newHeaderResponse(http.Header{"Retry-After": {"120"}}).RetryAfter(); !ok || after != 2*time.Minute {
+		t.Errorf("Expected 2m but got %s", after)
+	}
+	if _, ok := newHeaderResponse(http.Header{}).RetryAfter(); ok {
+		t.Error("Expected no Retry-After")
+	}
+}
+
+func TestResponse_LocationAndLinks(t *testing.T) {
+	resp := newHeaderResponse(http.Header{
+		"Location": {"../orders/7"},
+		"Link":     {`</v1/items/?page=2>; rel="next"`},
+	})
+
+	location, err := resp.Location()
+	if err != nil || location.String() != "https://api.example.com/v1/orders/7" {
+		t.Errorf("Expected resolved location but got %v, %v", location, err)
+	}
+	next, _ := url.Parse(resp.Links()["next"])
+	if next.Query().Get("page") != "2" {
+		t.Errorf("Unexpected links %v", resp.Links())
+	}
+}
@@ -0,0 +1,84 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Result carries a decoded value together with the status, headers and error of the response it came from.
+type Result[T any] struct {
+	Value  T
+	Status int
+	Header http.Header
+	Err    error
+}
+
+// Fetch executes a request against path and decodes a successful response into T.
+// Unsuccessful status codes are reported as error, like UnauthorizedError or NotFoundError. A nil ctx
+// defaults to context.Background().
+func Fetch[T any](ctx context.Context, client *HttpClient, method string, path string, body io.Reader) Result[T] {
+	result := Result[T]{}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	request, err := client.newRequest(ctx, method, path, body)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	resp, err := client.ExecuteRequest(request.WithContext(ctx))
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	result.Status = resp.StatusCode
+	result.Header = resp.Header
+	if ClassifyStatus(resp.StatusCode) != StatusClassSuccess {
+		result.Err = client.responseError(resp)
+		return result
+	}
+
+	result.Err = client.DecodeResponse(resp, &result.Value)
+	return result
+}
+
+// Ok reports whether the result carries no error.
+func (r Result[T]) Ok() bool {
+	return r.Err == nil
+}
+
+// Get returns the value and error, for callers preferring the usual two-value style.
+func (r Result[T]) Get() (T, error) {
+	return r.Value, r.Err
+}
+
+// Must returns the value and panics if the result carries an error.
+func (r Result[T]) Must() T {
+	if r.Err != nil {
+		panic(fmt.Sprintf("result has error: %v", r.Err))
+	}
+	return r.Value
+}
+
+// OrElse returns the value, or fallback if the result carries an error.
+func (r Result[T]) OrElse(fallback T) T {
+	if r.Err != nil {
+		return fallback
+	}
+	return r.Value
+}
+
+// MapResult converts the value of r with f, keeping status and headers. Errors are passed on
+// without calling f.
+func MapResult[T any, U any](r Result[T], f func(T) (U, error)) Result[U] {
+	mapped := Result[U]{Status: r.Status, Header: r.Header, Err: r.Err}
+	if r.Err == nil {
+		mapped.Value, mapped.Err = f(r.Value)
+	}
+	return mapped
+}
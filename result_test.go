@@ -0,0 +1,78 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+type fixtureID struct {
+	ID int `json:"id"`
+}
+
+func TestFetch(t *testing.T) {
+	server := mockServer(http.StatusOK, contentTypeJSON, fixtureBasicJSON)
+	defer server.Close()
+
+	result := Fetch[fixtureID](context.Background(), createTestHTTPClient(server.URL), http.MethodGet, "", nil)
+
+	if !result.Ok() || result.Must().ID != 1 {
+		t.Errorf("Expected id 1 but got %v, %v", result.Value, result.Err)
+	}
+	if result.Status != http.StatusOK {
+		t.Errorf("Expected status 200 but got %d", result.Status)
+	}
+}
+
+func TestFetch_NilContext(t *testing.T) {
+	server := mockServer(http.StatusOK, contentTypeJSON, fixtureBasicJSON)
+	defer server.Close()
+
+	result := Fetch[fixtureID](nil, createTestHTTPClient(server.URL), http.MethodGet, "", nil)
+
+	if !result.Ok() || result.Value.ID != 1 {
+		t.Errorf("Expected id 1 but got %v, %v", result.Value, result.Err)
+	}
+}
+
+func TestFetch_NotFound(t *testing.T) {
+	server := mockServer(http.StatusNotFound, contentTypeJSON, "")
+	defer server.Close()
+
+	result := Fetch[fixtureID](context.Background(), createTestHTTPClient(server.URL), http.MethodGet, "", nil)
+
+	var notFound *NotFoundError
+	if !errors.As(result.Err, &notFound) {
+		t.Errorf("Expected NotFoundError but got %v", result.Err)
+	}
+	if result.OrElse(fixtureID{ID: 42}).ID != 42 {
+		t.Error("Expected fallback value")
+	}
+}
+
+func TestMapResult(t *testing.T) {
+	result := Result[fixtureID]{Value: fixtureID{ID: 7}, Status: http.StatusOK}
+
+	mapped := MapResult(result, func(v fixtureID) (int, error) { return v.ID * 2, nil })
+	if mapped.Value != 14 || mapped.Status != http.StatusOK {
+		t.Errorf("Expected 14 with status 200 but got %v", mapped)
+	}
+
+	failed := MapResult(Result[fixtureID]{Err: errors.New("boom")}, func(v fixtureID) (int, error) {
+		t.Error("Expected mapping function not to be called")
+		return 0, nil
+	})
+	if failed.Err == nil {
+		t.Error("Expected error to be passed on")
+	}
+}
+
+func TestResult_MustPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected panic")
+		}
+	}()
+	Result[int]{Err: errors.New("boom")}.Must()
+}
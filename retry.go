@@ -0,0 +1,296 @@
+package http
+
+import (
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+const (
+	defaultRetryBaseDelay = 100 * time.Millisecond
+	defaultRetryMaxDelay  = 10 * time.Second
+)
+
+// DefaultRetryStatusCodes are the statuses retried by a RetryPolicy unless WithStatusCodes is used.
+var DefaultRetryStatusCodes = []int{http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout}
+
+// RetryPolicy decides which failed requests ExecuteRequest sends again and how long it waits in between.
+// Requests are retried on the policy's status codes and on network errors, like refused or reset connections and
+// timeouts, but only if they are idempotent and their body can be replayed. A Retry-After header longer than the
+// backoff is honored. Policies are immutable, the With methods return modified copies.
+type RetryPolicy struct {
+	maxAttempts   int
+	backoff       Backoff
+	statuses      map[int]bool
+	rateLimited   map[int]bool
+	maxDelay      time.Duration
+	networkErrors bool
+	nonIdempotent bool
+	budget        *RetryBudget
+	clock         Clock
+}
+
+// NewRetryPolicy creates a RetryPolicy sending requests at most maxAttempts times, including the first attempt.
+// It retries DefaultRetryStatusCodes and network errors with an exponential backoff from 100ms up to 10s.
+func NewRetryPolicy(maxAttempts int) *RetryPolicy {
+	p := &RetryPolicy{
+		maxAttempts:   maxAttempts,
+		backoff:       ExponentialBackoff(defaultRetryBaseDelay, defaultRetryMaxDelay),
+		networkErrors: true,
+		clock:         SystemClock(),
+	}
+	return p.WithStatusCodes(DefaultRetryStatusCodes...)
+}
+
+// WithBackoff sets the delay before each retry.
+func (p *RetryPolicy) WithBackoff(backoff Backoff) *RetryPolicy {
+	if backoff == nil {
+		panic("backoff is nil")
+	}
+	p = p.clone()
+	p.backoff = backoff
+	return p
+}
+
+// WithStatusCodes sets the response statuses which are retried, replacing the previous ones.
+func (p *RetryPolicy) WithStatusCodes(codes ...int) *RetryPolicy {
+	p = p.clone()
+	p.statuses = make(map[int]bool, len(codes))
+	for _, code := range codes {
+		p.statuses[code] = true
+	}
+	return p
+}
+
+// WithRateLimitStatusCodes retries responses with the given statuses only if they tell when to retry, by a
+// Retry-After header or an exhausted X-RateLimit-Remaining with X-RateLimit-Reset, like the 403 responses of
+// secondary rate limits. It replaces the previous rate limit statuses.
+func (p *RetryPolicy) WithRateLimitStatusCodes(codes ...int) *RetryPolicy {
+	p = p.clone()
+	p.rateLimited = make(map[int]bool, len(codes))
+	for _, code := range codes {
+		p.rateLimited[code] = true
+	}
+	return p
+}
+
+// WithMaxDelay gives up instead of retrying when the delay before the retry, including a Retry-After header, would
+// exceed max. Zero waits as long as the server asks for.
+func (p *RetryPolicy) WithMaxDelay(max time.Duration) *RetryPolicy {
+	p = p.clone()
+	p.maxDelay = max
+	return p
+}
+
+// WithoutNetworkErrors only retries responses with one of the policy's statuses, not requests failing without
+// response.
+func (p *RetryPolicy) WithoutNetworkErrors() *RetryPolicy {
+	p = p.clone()
+	p.networkErrors = false
+	return p
+}
+
+// WithNonIdempotent also retries requests which are not idempotent, like POST requests without Idempotency-Key
+// header. Only use it if the server tolerates receiving such requests twice.
+func (p *RetryPolicy) WithNonIdempotent() *RetryPolicy {
+	p = p.clone()
+	p.nonIdempotent = true
+	return p
+}
+
+// WithBudget limits the retries per host to budget, which may be shared by several clients.
+func (p *RetryPolicy) WithBudget(budget *RetryBudget) *RetryPolicy {
+	p = p.clone()
+	p.budget = budget
+	return p
+}
+
+// WithClock sets the clock the delays between attempts are waited with.
+func (p *RetryPolicy) WithClock(clock Clock) *RetryPolicy {
+	if clock == nil {
+		panic("clock is nil")
+	}
+	p = p.clone()
+	p.clock = clock
+	return p
+}
+
+// MaxAttempts returns how often a request is sent at most, including the first attempt.
+func (p *RetryPolicy) MaxAttempts() int {
+	return p.maxAttempts
+}
+
+// IsRetryable reports whether the outcome of an attempt of r is retried, regardless of the attempts left.
+func (p *RetryPolicy) IsRetryable(r *http.Request, resp *http.Response, err error) bool {
+	if !p.nonIdempotent && !isIdempotent(r) {
+		return false
+	}
+	if r.Body != nil && r.Body != http.NoBody && r.GetBody == nil {
+		return false
+	}
+	if resp != nil {
+		if err != nil {
+			return false
+		}
+		if p.rateLimited[resp.StatusCode] {
+			_, hinted := retryAfter(resp, p.clock.Now())
+			return hinted
+		}
+		return p.statuses[resp.StatusCode]
+	}
+	return p.networkErrors && r.Context().Err() == nil && isRetryableError(err)
+}
+
+// Delay returns how long to wait before attempt, the number of the retry's attempt starting at 2, after resp.
+// It is the larger of the backoff and the Retry-After or X-RateLimit-Reset header of resp.
+func (p *RetryPolicy) Delay(attempt int, resp *http.Response) time.Duration {
+	delay := p.backoff(attempt - 1)
+	if after, ok := retryAfter(resp, p.clock.Now()); ok && after > delay {
+		delay = after
+	}
+	return delay
+}
+
+func (p *RetryPolicy) clone() *RetryPolicy {
+	c := *p
+	return &c
+}
+
+// WithRetryPolicy lets ExecuteRequest, and with it all requests of the client, retry failed requests as policy
+// demands, so callers do not need retry loops of their own. Use WithoutRetry to send single requests only once, or
+// the flag FlagRetries to switch retries off at runtime.
+func WithRetryPolicy(policy *RetryPolicy) Option {
+	if policy == nil {
+		panic("policy is nil")
+	}
+	return func(c *HttpConfig) {
+		c.retry = policy
+	}
+}
+
+// WithoutRetry sends the request only once, even if the client has a RetryPolicy.
+func WithoutRetry() RequestOption {
+	return func(o *requestOptions) {
+		o.noRetry = true
+	}
+}
+
+// retryRoundTrip sends r with roundTrip until it succeeds, fails permanently or policy allows no more attempts.
+// The response or error of the last attempt is returned, or an AttemptDeadlineError if a retry would typically
+// not finish before the deadline of the request's context, judged by the latency of the client's attempts.
+func (h *HttpClient) retryRoundTrip(r *http.Request, policy *RetryPolicy) (*http.Response, error) {
+	if policy.budget != nil {
+		policy.budget.RecordRequest(r.URL.Host)
+	}
+
+	attempt := r
+	for i := 1; ; i++ {
+		start := time.Now()
+		resp, err := h.roundTrip(attempt)
+		h.latency.observe(time.Since(start))
+		if i >= policy.maxAttempts || !policy.IsRetryable(r, resp, err) {
+			return resp, err
+		}
+
+		delay := policy.Delay(i+1, resp)
+		if policy.maxDelay > 0 && delay > policy.maxDelay {
+			return resp, err
+		}
+		if deadlineErr := checkAttemptDeadline(r.Context(), delay, h.latency.typical()); deadlineErr != nil {
+			if resp != nil {
+				drainAndClose(resp.Body)
+			}
+			return nil, deadlineErr
+		}
+		if policy.budget != nil && !policy.budget.TryRetry(r.URL.Host) {
+			return resp, err
+		}
+		next, bodyErr := replayable(r)
+		if bodyErr != nil {
+			return resp, err
+		}
+
+		h.emit(Event{Type: EventRetryScheduled, Host: r.URL.Host, Request: r, Response: resp, Err: err, Attempt: i, Delay: delay})
+		if resp != nil {
+			drainAndClose(resp.Body)
+		}
+		if err := policy.clock.Sleep(r.Context(), delay); err != nil {
+			return nil, err
+		}
+		attempt = next
+	}
+}
+
+// replayable returns a copy of r with a fresh body to send it again.
+func replayable(r *http.Request) (*http.Request, error) {
+	next := r.WithContext(r.Context())
+	if r.Body == nil || r.Body == http.NoBody {
+		return next, nil
+	}
+	body, err := r.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	next.Body = body
+	return next, nil
+}
+
+// isIdempotent reports whether sending r twice has the same effect as sending it once, see RFC 9110 section 9.2.2.
+func isIdempotent(r *http.Request) bool {
+	switch r.Method {
+	case "", http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return r.Header.Get(IdempotencyKeyHeader) != "" || r.Header.Get("X-Idempotency-Key") != ""
+}
+
+// isRetryableError reports whether err is a network error after which sending the request again may succeed.
+func isRetryableError(err error) bool {
+	if err == nil {
+		return false
+	}
+	switch classified := classifyError(err).(type) {
+	case *TimeoutError, *ConnectionRefusedError:
+		return true
+	case *DNSError:
+		return classified.Temporary()
+	case *CanceledError, *TLSError:
+		return false
+	}
+	var opErr *net.OpError
+	return errors.As(err, &opErr) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET)
+}
+
+// retryAfter returns the delay resp asks for with its Retry-After header, given in seconds or as HTTP date, or with
+// an exhausted X-RateLimit-Remaining and the X-RateLimit-Reset time in Unix seconds.
+func retryAfter(resp *http.Response, now time.Time) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+	if value := resp.Header.Get("Retry-After"); value != "" {
+		if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+			return time.Duration(seconds) * time.Second, true
+		}
+		if at, err := http.ParseTime(value); err == nil {
+			return untilOrZero(at, now), true
+		}
+	}
+	if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		if reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+			return untilOrZero(time.Unix(reset, 0), now), true
+		}
+	}
+	return 0, false
+}
+
+func untilOrZero(at time.Time, now time.Time) time.Duration {
+	if d := at.Sub(now); d > 0 {
+		return d
+	}
+	return 0
+}
@@ -0,0 +1,292 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func noBackoff(int) time.Duration {
+	return 0
+}
+
+func TestRetryPolicy_RetriesStatus(t *testing.T) {
+	var calls int32
+	server := mockServerWith(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("ok"))
+	})
+	defer server.Close()
+
+	recorded := &recordedEvents{}
+	policy := NewRetryPolicy(3).WithBackoff(noBackoff)
+	client := NewHttpClientWithConfig(NewDefaultHttpConfig(server.URL, WithRetryPolicy(policy), WithEvents(recorded)))
+
+	resp, err := client.Get(context.Background(), "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if calls != 3 || resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected success after 3 attempts but got %d after %d", resp.StatusCode, calls)
+	}
+	var retries int
+	for _, e := range recorded.snapshot() {
+		if e.Type == EventRetryScheduled {
+			retries++
+		}
+	}
+	if retries != 2 {
+		t.Errorf("Expected 2 scheduled retries but got %d", retries)
+	}
+}
+
+func TestRetryPolicy_StopsAfterMaxAttempts(t *testing.T) {
+	var calls int32
+	server := mockServerWith(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	})
+	defer server.Close()
+
+	policy := NewRetryPolicy(2).WithBackoff(noBackoff)
+	client := NewHttpClientWithConfig(NewDefaultHttpConfig(server.URL, WithRetryPolicy(policy)))
+
+	resp, err := client.Get(context.Background(), "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusBadGateway || calls != 2 {
+		t.Errorf("Expected the last response after 2 attempts but got %d after %d", resp.StatusCode, calls)
+	}
+}
+
+func TestRetryPolicy_ReplaysBody(t *testing.T) {
+	var calls int32
+	var bodies []string
+	server := mockServerWith(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusGatewayTimeout)
+		}
+	})
+	defer server.Close()
+
+	policy := NewRetryPolicy(2).WithBackoff(noBackoff)
+	client := NewHttpClientWithConfig(NewDefaultHttpConfig(server.URL, WithRetryPolicy(policy)))
+
+	resp, err := client.Put(context.Background(), "/", strings.NewReader("payload"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if len(bodies) != 2 || bodies[1] != "payload" {
+		t.Errorf("Expected the body to be sent twice but got %q", bodies)
+	}
+}
+
+func TestRetryPolicy_SkipsNonIdempotent(t *testing.T) {
+	var calls int32
+	server := mockServerWith(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	defer server.Close()
+
+	policy := NewRetryPolicy(3).WithBackoff(noBackoff)
+	client := NewHttpClientWithConfig(NewDefaultHttpConfig(server.URL, WithRetryPolicy(policy)))
+
+	client.Post(context.Background(), "/", strings.NewReader("{}"))
+	if calls != 1 {
+		t.Errorf("Expected POST to be sent once but got %d attempts", calls)
+	}
+
+	atomic.StoreInt32(&calls, 0)
+	request, _ := client.PostRequest("/", strings.NewReader("{}"))
+	request.Header.Set("Idempotency-Key", "abc")
+	client.ExecuteRequest(request)
+	if calls != 3 {
+		t.Errorf("Expected POST with Idempotency-Key to be retried but got %d attempts", calls)
+	}
+}
+
+func TestRetryPolicy_NetworkError(t *testing.T) {
+	server := mockServer(http.StatusOK, contentTypeJSON, "{}")
+	url := server.URL
+	server.Close()
+
+	recorded := &recordedEvents{}
+	policy := NewRetryPolicy(3).WithBackoff(noBackoff)
+	client := NewHttpClientWithConfig(NewDefaultHttpConfig(url, WithRetryPolicy(policy), WithEvents(recorded)))
+
+	if _, err := client.Get(context.Background(), "/"); err == nil {
+		t.Fatal("Expected connection error")
+	}
+	var attempts int
+	for _, e := range recorded.snapshot() {
+		if e.Type == EventRetryScheduled {
+			attempts++
+		}
+	}
+	if attempts != 2 {
+		t.Errorf("Expected refused connections to be retried twice but got %d", attempts)
+	}
+}
+
+func TestRetryPolicy_WithoutRetry(t *testing.T) {
+	var calls int32
+	server := mockServerWith(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	defer server.Close()
+
+	policy := NewRetryPolicy(3).WithBackoff(noBackoff)
+	client := NewHttpClientWithConfig(NewDefaultHttpConfig(server.URL, WithRetryPolicy(policy)))
+
+	client.Get(WithRequestOptions(context.Background(), WithoutRetry()), "/")
+	if calls != 1 {
+		t.Errorf("Expected a single attempt but got %d", calls)
+	}
+}
+
+func TestRetryPolicy_Delay(t *testing.T) {
+	clock := NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	policy := NewRetryPolicy(5).WithBackoff(ExponentialBackoff(time.Second, time.Minute)).WithClock(clock)
+
+	if delay := policy.Delay(3, nil); delay != 2*time.Second {
+		t.Errorf("Expected backoff of 2s but got %s", delay)
+	}
+
+	resp := &http.Response{Header: http.Header{"Retry-After": {"30"}}}
+	if delay := policy.Delay(2, resp); delay != 30*time.Second {
+		t.Errorf("Expected Retry-After of 30s but got %s", delay)
+	}
+
+	resp.Header.Set("Retry-After", clock.Now().Add(time.Minute).Format(http.TimeFormat))
+	if delay := policy.Delay(2, resp); delay != time.Minute {
+		t.Errorf("Expected Retry-After date in 1m but got %s", delay)
+	}
+}
+
+func TestRetryPolicy_SleepsWithClock(t *testing.T) {
+	var calls int32
+	server := mockServerWith(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	})
+	defer server.Close()
+
+	clock := NewFakeClock(time.Now())
+	policy := NewRetryPolicy(2).WithClock(clock)
+	client := NewHttpClientWithConfig(NewDefaultHttpConfig(server.URL, WithRetryPolicy(policy)))
+
+	done := make(chan error, 1)
+	go func() {
+		resp, err := client.Get(context.Background(), "/")
+		if err == nil {
+			resp.Body.Close()
+		}
+		done <- err
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := clock.WaitForWaiters(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	if pending := clock.Pending(); len(pending) != 1 || pending[0] != defaultRetryBaseDelay {
+		t.Errorf("Expected a retry after %s but got %v", defaultRetryBaseDelay, pending)
+	}
+	clock.Advance(defaultRetryBaseDelay)
+
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRetryPolicy_Validate(t *testing.T) {
+	config := NewDefaultHttpConfig("http://localhost", WithRetryPolicy(NewRetryPolicy(0)))
+	if err := config.Validate(); err == nil {
+		t.Error("Expected a policy without attempts to be invalid")
+	}
+}
+
+func TestRetryPolicy_RateLimitStatusCodes(t *testing.T) {
+	policy := NewRetryPolicy(3).WithRateLimitStatusCodes(http.StatusForbidden).WithClock(NewFakeClock(time.Unix(1000, 0)))
+	request, _ := http.NewRequest(http.MethodGet, "http://localhost", nil)
+
+	forbidden := &http.Response{StatusCode: http.StatusForbidden, Header: http.Header{}}
+	if policy.IsRetryable(request, forbidden, nil) {
+		t.Error("Expected 403 without rate limit headers not to be retried")
+	}
+
+	forbidden.Header.Set("X-RateLimit-Remaining", "0")
+	forbidden.Header.Set("X-RateLimit-Reset", "1030")
+	if !policy.IsRetryable(request, forbidden, nil) {
+		t.Error("Expected exhausted rate limit to be retried")
+	}
+	if delay := policy.Delay(2, forbidden); delay != 30*time.Second {
+		t.Errorf("Expected to wait for the reset in 30s but waited %s", delay)
+	}
+}
+
+func TestRetryPolicy_MaxDelay(t *testing.T) {
+	var calls int32
+	server := mockServerWith(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	defer server.Close()
+
+	policy := NewRetryPolicy(3).WithMaxDelay(time.Second)
+	client := NewHttpClientWithConfig(NewDefaultHttpConfig(server.URL, WithRetryPolicy(policy)))
+	resp, err := client.Get(context.Background(), "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if calls != 1 {
+		t.Errorf("Expected to give up instead of waiting an hour but got %d attempts", calls)
+	}
+}
+
+func TestRetryPolicy_AttemptDeadline(t *testing.T) {
+	var calls int32
+	server := mockServerWith(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		time.Sleep(300 * time.Millisecond)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	defer server.Close()
+
+	policy := NewRetryPolicy(3).WithBackoff(noBackoff)
+	client := NewHttpClientWithConfig(NewDefaultHttpConfig(server.URL, WithRetryPolicy(policy)))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	resp, err := client.Get(ctx, "/")
+	var deadlineErr *AttemptDeadlineError
+	if !errors.As(err, &deadlineErr) || resp != nil {
+		t.Errorf("Expected AttemptDeadlineError but got %v, %v", resp, err)
+	}
+	if calls != 1 {
+		t.Errorf("Expected no retry which can not finish in time but got %d attempts", calls)
+	}
+}
@@ -0,0 +1,99 @@
+package http
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// RotatingCredentials is a CredentialSource whose credentials can be swapped on a live client.
+// After a rotation the previous credentials stay usable for a grace window, so requests rejected
+// because the server does not know the new credentials yet are retried with the old ones.
+type RotatingCredentials struct {
+	mu            sync.RWMutex
+	current       Credentials
+	previous      Credentials
+	previousUntil time.Time
+}
+
+// NewRotatingCredentials creates RotatingCredentials starting with initial.
+func NewRotatingCredentials(initial Credentials) *RotatingCredentials {
+	return &RotatingCredentials{current: initial}
+}
+
+// Credentials returns the current credentials.
+func (c *RotatingCredentials) Credentials(ctx context.Context) (Credentials, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.current, nil
+}
+
+// Rotate atomically replaces the current credentials. The replaced credentials are still
+// accepted as a fallback for grace, a grace of zero drops them immediately.
+func (c *RotatingCredentials) Rotate(next Credentials, grace time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.previous = c.current
+	c.previousUntil = time.Now().Add(grace)
+	c.current = next
+}
+
+// Previous returns the credentials replaced by the last rotation while they are within their grace window.
+func (c *RotatingCredentials) Previous() (Credentials, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if !time.Now().Before(c.previousUntil) {
+		return Credentials{}, false
+	}
+	return c.previous, true
+}
+
+// RotatingCertificate holds a TLS client certificate which can be swapped without rebuilding the transport.
+// New connections use the new certificate, established connections are kept.
+type RotatingCertificate struct {
+	mu   sync.RWMutex
+	cert *tls.Certificate
+}
+
+// NewRotatingCertificate creates a RotatingCertificate starting with cert.
+func NewRotatingCertificate(cert tls.Certificate) *RotatingCertificate {
+	return &RotatingCertificate{cert: &cert}
+}
+
+// Rotate atomically replaces the certificate presented on new connections.
+func (c *RotatingCertificate) Rotate(cert tls.Certificate) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cert = &cert
+}
+
+// GetClientCertificate implements the tls.Config callback of the same name.
+func (c *RotatingCertificate) GetClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.cert, nil
+}
+
+// ApplyTo configures transport to present the rotating certificate. Call it before the transport is in use.
+func (c *RotatingCertificate) ApplyTo(transport *http.Transport) {
+	config := &tls.Config{}
+	if transport.TLSClientConfig != nil {
+		config = transport.TLSClientConfig.Clone()
+	}
+	config.GetClientCertificate = c.GetClientCertificate
+	transport.TLSClientConfig = config
+}
+
+// UseClientCertificate configures the client's transport to present the rotating certificate.
+func (h *HttpClient) UseClientCertificate(cert *RotatingCertificate) error {
+	ok := h.updateTransport(cert.ApplyTo)
+	if !ok {
+		return errors.New("client certificates require an *http.Transport")
+	}
+	return nil
+}
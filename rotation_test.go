@@ -0,0 +1,75 @@
+package http
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRotatingCredentials_Rotate(t *testing.T) {
+	credentials := NewRotatingCredentials(Credentials{Token: "old"})
+	credentials.Rotate(Credentials{Token: "new"}, time.Hour)
+
+	current, _ := credentials.Credentials(context.Background())
+	if current.Token != "new" {
+		t.Errorf("Expected new but got %s", current.Token)
+	}
+	previous, ok := credentials.Previous()
+	if !ok || previous.Token != "old" {
+		t.Errorf("Expected old credentials within grace window but got %v", previous)
+	}
+
+	credentials.Rotate(Credentials{Token: "newer"}, 0)
+	if _, ok := credentials.Previous(); ok {
+		t.Error("Expected no previous credentials without grace window")
+	}
+}
+
+func TestHttpClient_RotationGraceWindow(t *testing.T) {
+	var bodies []string
+	f := func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		if r.Header.Get("Authorization") != "Bearer old" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}
+	server := mockServerWith(http.HandlerFunc(f))
+	defer server.Close()
+
+	credentials := NewRotatingCredentials(Credentials{Token: "old"})
+	client := createTestHTTPClient(server.URL)
+	client.SetAuthProvider(BearerAuth(credentials))
+
+	credentials.Rotate(Credentials{Token: "new"}, time.Hour)
+	resp, err := client.PostTo("", strings.NewReader(fixtureBasicJSON))
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	assertResponseHasStatus(resp, http.StatusOK, t)
+	if len(bodies) != 2 || bodies[1] != fixtureBasicJSON {
+		t.Errorf("Expected retry with replayed body but got %v", bodies)
+	}
+}
+
+func TestRotatingCertificate(t *testing.T) {
+	first := tls.Certificate{Certificate: [][]byte{[]byte("first")}}
+	second := tls.Certificate{Certificate: [][]byte{[]byte("second")}}
+
+	cert := NewRotatingCertificate(first)
+	transport := &http.Transport{}
+	cert.ApplyTo(transport)
+	cert.Rotate(second)
+
+	presented, _ := transport.TLSClientConfig.GetClientCertificate(nil)
+	if string(presented.Certificate[0]) != "second" {
+		t.Errorf("Expected rotated certificate but got %s", presented.Certificate[0])
+	}
+}
@@ -0,0 +1,176 @@
+package http
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// RunResult is the outcome of a single execution of a Runner.
+// The receiver is responsible for closing the Response body.
+type RunResult struct {
+	Response *http.Response
+	Err      error
+	Started  time.Time
+	Duration time.Duration
+}
+
+// Runner periodically executes a request, e.g. for heartbeats or keep-alive pings.
+// A tick is skipped while the previous execution is still in flight.
+type Runner struct {
+	client     *HttpClient
+	newRequest func() (*http.Request, error)
+	interval   time.Duration
+	jitter     time.Duration
+	clock      Clock
+
+	inFlight int32
+	skipped  int64
+
+	mu      sync.Mutex
+	results chan RunResult
+	cancel  context.CancelFunc
+	done    chan struct{}
+}
+
+// NewRunner creates a Runner which executes the request returned by newRequest every interval.
+// A RequestBuilder can be passed directly by its Build method.
+func NewRunner(client *HttpClient, newRequest func() (*http.Request, error), interval time.Duration) *Runner {
+	if client == nil {
+		panic("client is nil")
+	}
+	if newRequest == nil {
+		panic("newRequest is nil")
+	}
+	if interval <= 0 {
+		panic("interval must be positive")
+	}
+
+	return &Runner{
+		client:     client,
+		newRequest: newRequest,
+		interval:   interval,
+		clock:      SystemClock(),
+		results:    make(chan RunResult, 1),
+	}
+}
+
+// WithJitter adds a random delay of up to jitter to each interval.
+func (r *Runner) WithJitter(jitter time.Duration) *Runner {
+	r.jitter = jitter
+	return r
+}
+
+// WithClock sets the clock timing the executions.
+func (r *Runner) WithClock(clock Clock) *Runner {
+	r.clock = clock
+	return r
+}
+
+// Results returns the channel on which each execution of the current run is reported.
+// Results are dropped (and their bodies closed) if nobody is receiving. The channel is closed when the run
+// ends by Stop or its context being done; a restarted Runner reports on a new channel.
+func (r *Runner) Results() <-chan RunResult {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.results
+}
+
+// Skipped returns the number of ticks skipped because the previous execution was still running.
+func (r *Runner) Skipped() int64 {
+	return atomic.LoadInt64(&r.skipped)
+}
+
+// Start begins executing the request periodically until ctx is done or Stop is called. Starting a running
+// Runner has no effect, one whose run ended starts again.
+func (r *Runner) Start(ctx context.Context) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.done != nil {
+		select {
+		case <-r.done:
+			// the previous run ended, its results channel is closed
+			r.results = make(chan RunResult, 1)
+		default:
+			return
+		}
+	}
+	if r.cancel != nil {
+		r.cancel()
+	}
+
+	ctx, r.cancel = context.WithCancel(ctx)
+	r.done = make(chan struct{})
+
+	go r.run(ctx, r.results, r.done)
+}
+
+// Stop halts the Runner and waits for an in-flight execution; the results channel is closed when it returns.
+func (r *Runner) Stop() {
+	r.mu.Lock()
+	cancel, done := r.cancel, r.done
+	r.cancel = nil
+	r.mu.Unlock()
+
+	if cancel == nil {
+		return
+	}
+	cancel()
+	<-done
+}
+
+func (r *Runner) run(ctx context.Context, results chan RunResult, done chan struct{}) {
+	var wg sync.WaitGroup
+	defer close(done)
+	defer close(results)
+	defer wg.Wait()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-r.clock.After(r.nextDelay()):
+			if atomic.CompareAndSwapInt32(&r.inFlight, 0, 1) {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					defer atomic.StoreInt32(&r.inFlight, 0)
+					r.execute(ctx, results)
+				}()
+			} else {
+				atomic.AddInt64(&r.skipped, 1)
+			}
+		}
+	}
+}
+
+func (r *Runner) execute(ctx context.Context, results chan<- RunResult) {
+	result := RunResult{Started: time.Now()}
+
+	request, err := r.newRequest()
+	if err == nil {
+		result.Response, result.Err = r.client.ExecuteRequest(request.WithContext(ctx))
+	} else {
+		result.Err = err
+	}
+	result.Duration = time.Since(result.Started)
+
+	select {
+	case results <- result:
+	default:
+		if result.Response != nil {
+			result.Response.Body.Close()
+		}
+	}
+}
+
+func (r *Runner) nextDelay() time.Duration {
+	if r.jitter <= 0 {
+		return r.interval
+	}
+	return r.interval + time.Duration(rand.Int63n(int64(r.jitter)))
+}
@@ -0,0 +1,110 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRunner_DeliversResults(t *testing.T) {
+	server := mockServer(http.StatusOK, contentTypeJSON, fixtureBasicJSON)
+	defer server.Close()
+
+	runner := NewRunner(createTestHTTPClient(server.URL), NewRequestBuilder().Get().Path(server.URL).Build, time.Millisecond)
+	runner.Start(context.Background())
+	defer runner.Stop()
+
+	select {
+	case result := <-runner.Results():
+		if result.Err != nil {
+			t.Fatalf("Unexpected error %v", result.Err)
+		}
+		assertResponseHasStatus(result.Response, http.StatusOK, t)
+		assertResponseBodyIs(result.Response, fixtureBasicJSON, t)
+	case <-time.After(time.Second):
+		t.Error("Expected a result within 1s")
+	}
+}
+
+func TestRunner_SkipsOverlappingExecutions(t *testing.T) {
+	var concurrent, maxConcurrent int32
+	f := func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&concurrent, 1)
+		if n > atomic.LoadInt32(&maxConcurrent) {
+			atomic.StoreInt32(&maxConcurrent, n)
+		}
+		time.Sleep(20 * time.Millisecond)
+		atomic.AddInt32(&concurrent, -1)
+		w.WriteHeader(http.StatusOK)
+	}
+	server := mockServerWith(http.HandlerFunc(f))
+	defer server.Close()
+
+	runner := NewRunner(createTestHTTPClient(server.URL), NewRequestBuilder().Get().Path(server.URL).Build, time.Millisecond).
+		WithJitter(time.Millisecond)
+	runner.Start(context.Background())
+	time.Sleep(50 * time.Millisecond)
+	runner.Stop()
+
+	if atomic.LoadInt32(&maxConcurrent) != 1 {
+		t.Errorf("Expected at most 1 concurrent execution, got %d", maxConcurrent)
+	}
+	if runner.Skipped() == 0 {
+		t.Error("Expected skipped ticks while a request was in flight")
+	}
+}
+
+func TestRunner_Restart(t *testing.T) {
+	server := mockServer(http.StatusOK, contentTypeJSON, fixtureBasicJSON)
+	defer server.Close()
+
+	runner := NewRunner(createTestHTTPClient(server.URL), NewRequestBuilder().Get().Path(server.URL).Build, time.Millisecond)
+	for i := 0; i < 2; i++ {
+		runner.Start(context.Background())
+		results := runner.Results()
+		select {
+		case result := <-results:
+			if result.Err != nil {
+				t.Fatalf("Unexpected error %v in run %d", result.Err, i+1)
+			}
+			result.Response.Body.Close()
+		case <-time.After(time.Second):
+			t.Fatalf("Expected a result within 1s in run %d", i+1)
+		}
+		runner.Stop()
+		drainRunResults(t, results)
+	}
+}
+
+func TestRunner_ClosesResultsWhenContextDone(t *testing.T) {
+	server := mockServer(http.StatusOK, contentTypeJSON, fixtureBasicJSON)
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	runner := NewRunner(createTestHTTPClient(server.URL), NewRequestBuilder().Get().Path(server.URL).Build, time.Millisecond)
+	runner.Start(ctx)
+	cancel()
+
+	drainRunResults(t, runner.Results())
+	runner.Stop()
+}
+
+// drainRunResults receives from results until it is closed.
+func drainRunResults(t *testing.T, results <-chan RunResult) {
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case result, ok := <-results:
+			if !ok {
+				return
+			}
+			if result.Response != nil {
+				result.Response.Body.Close()
+			}
+		case <-timeout:
+			t.Fatal("Expected the results channel to be closed within 1s")
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// ScatterError is returned by FirstSuccess if no base URL answered successfully, and by DownloadFromMirrors if no
+// mirror served the artifact. Errors holds the error of every base URL or mirror in the order they were given.
+type ScatterError struct {
+	Message string
+	Errors  []error
+}
+
+func (e ScatterError) Error() string {
+	return e.Message
+}
+
+func (e ScatterError) Unwrap() []error {
+	return e.Errors
+}
+
+// FirstSuccess sends the same request for path to every base URL concurrently, e.g. mirrors of a service, and
+// returns the first successful response. The other requests are canceled and their responses discarded.
+// Unsuccessful statuses count as failure; if all base URLs fail, a ScatterError is returned.
+func (h *HttpClient) FirstSuccess(ctx context.Context, method string, path string, body []byte, baseURLs ...string) (*http.Response, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if len(baseURLs) == 0 {
+		return nil, &ScatterError{Message: "No base URLs to send the request to."}
+	}
+	_, username, password, err := h.resolveTarget(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	outcomes := make(chan scatterOutcome, len(baseURLs))
+	cancels := make([]context.CancelFunc, len(baseURLs))
+	for i, baseURL := range baseURLs {
+		requestCtx, cancel := context.WithCancel(ctx)
+		cancels[i] = cancel
+
+		go func(i int, baseURL string) {
+			request, err := createRequest(requestCtx, baseURL, path, method, bytes.NewReader(body), username, password)
+			if err != nil {
+				outcomes <- scatterOutcome{index: i, err: err}
+				return
+			}
+			h.negotiate(request)
+			resp, err := h.ExecuteRequest(request.WithContext(requestCtx))
+			if err == nil && ClassifyStatus(resp.StatusCode) != StatusClassSuccess {
+				drainAndClose(resp.Body)
+				resp, err = nil, statusError(resp)
+			}
+			outcomes <- scatterOutcome{index: i, resp: resp, err: err}
+		}(i, baseURL)
+	}
+
+	errs := make([]error, len(baseURLs))
+	for range baseURLs {
+		result := <-outcomes
+		if result.err != nil {
+			errs[result.index] = result.err
+			cancels[result.index]()
+			continue
+		}
+
+		for i, cancel := range cancels {
+			if i != result.index {
+				cancel()
+			}
+		}
+		go discardOutcomes(outcomes, len(baseURLs)-countErrors(errs)-1)
+		result.resp.Body = &cancelOnClose{ReadCloser: result.resp.Body, cancel: cancels[result.index]}
+		return result.resp, nil
+	}
+
+	return nil, &ScatterError{Message: fmt.Sprintf("All %d base URLs failed.", len(baseURLs)), Errors: errs}
+}
+
+type scatterOutcome struct {
+	index int
+	resp  *http.Response
+	err   error
+}
+
+// discardOutcomes closes the responses of the n requests still outstanding after a winner was found.
+func discardOutcomes(outcomes <-chan scatterOutcome, n int) {
+	for i := 0; i < n; i++ {
+		if result := <-outcomes; result.resp != nil {
+			drainAndClose(result.resp.Body)
+		}
+	}
+}
+
+func countErrors(errs []error) int {
+	count := 0
+	for _, err := range errs {
+		if err != nil {
+			count++
+		}
+	}
+	return count
+}
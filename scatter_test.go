@@ -0,0 +1,62 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestHttpClient_FirstSuccess(t *testing.T) {
+	canceled := make(chan bool, 1)
+	slow := mockServerWith(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+			canceled <- true
+		case <-time.After(time.Second):
+			canceled <- false
+		}
+	})
+	defer slow.Close()
+	failing := mockServerWith(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	defer failing.Close()
+	fast := mockServerWith(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(20 * time.Millisecond)
+		w.Write([]byte("mirror"))
+	})
+	defer fast.Close()
+
+	client := createTestHTTPClient(failing.URL)
+	resp, err := client.FirstSuccess(context.Background(), http.MethodGet, "/file", nil, slow.URL, failing.URL, fast.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(body) != "mirror" {
+		t.Errorf("Expected the response of the fast mirror but got %q", body)
+	}
+	if !<-canceled {
+		t.Error("Expected the slow request to be canceled")
+	}
+}
+
+func TestHttpClient_FirstSuccess_AllFail(t *testing.T) {
+	failing := mockServerWith(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	defer failing.Close()
+
+	client := createTestHTTPClient(failing.URL)
+	_, err := client.FirstSuccess(context.Background(), http.MethodGet, "/", nil, failing.URL, failing.URL)
+
+	var scatterErr *ScatterError
+	var notFound *NotFoundError
+	if !errors.As(err, &scatterErr) || len(scatterErr.Errors) != 2 || !errors.As(err, &notFound) {
+		t.Errorf("Expected a ScatterError with both errors but got %v", err)
+	}
+}
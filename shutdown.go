@@ -0,0 +1,137 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"sync"
+)
+
+// ClientClosedError is returned for requests executed after the client was shut down.
+type ClientClosedError struct {
+	Message string
+}
+
+func (e ClientClosedError) Error() string {
+	return e.Message
+}
+
+// inflightRequests tracks the requests executed by a client until their response bodies are closed.
+type inflightRequests struct {
+	mu        sync.Mutex
+	closed    bool
+	nextID    int
+	cancels   map[int]context.CancelFunc
+	drained   chan struct{}
+	isDrained bool
+}
+
+// Shutdown gracefully shuts down the client. New requests are rejected with a ClientClosedError right away,
+// in-flight requests are given until ctx is done to finish and close their response bodies. Requests still
+// running then are cancelled and ctx's error is returned. Idle connections are closed in either case.
+func (h *HttpClient) Shutdown(ctx context.Context) error {
+	drained := h.inflight.close()
+
+	var err error
+	select {
+	case <-drained:
+	case <-ctx.Done():
+		err = ctx.Err()
+		h.inflight.cancelAll()
+	}
+
+	h.mu.RLock()
+	client := h.client
+	h.mu.RUnlock()
+	client.CloseIdleConnections()
+	for _, transports := range []*sync.Map{&h.tlsTransports, &h.http1Transports, &h.pinnedTransports} {
+		transports.Range(func(_, transport interface{}) bool {
+			transport.(*http.Transport).CloseIdleConnections()
+			return true
+		})
+	}
+
+	return err
+}
+
+// begin registers r as in flight. It returns r with a cancellable context, applying the default timeout if r has
+// no context of its own and naming its endpoint, and the func releasing both once the request is done.
+func (h *HttpClient) begin(r *http.Request) (*http.Request, func(), error) {
+	endpoint, named := h.EndpointName(r)
+	redaction := h.redaction()
+
+	f := &h.inflight
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.closed {
+		return nil, nil, &ClientClosedError{Message: "Client is shut down."}
+	}
+
+	var ctx context.Context
+	var cancel context.CancelFunc
+	if r.Context() == context.Background() {
+		ctx, cancel = createDefaultContext(r.Context())
+	} else {
+		ctx, cancel = context.WithCancel(r.Context())
+	}
+
+	if f.cancels == nil {
+		f.cancels = make(map[int]context.CancelFunc)
+	}
+	id := f.nextID
+	f.nextID++
+	f.cancels[id] = cancel
+
+	var once sync.Once
+	done := func() {
+		once.Do(func() {
+			cancel()
+			f.finish(id)
+		})
+	}
+	if named {
+		ctx = WithEndpointName(ctx, endpoint)
+	}
+	return r.WithContext(withRedaction(withAttemptCounter(ctx), redaction)), done, nil
+}
+
+func (f *inflightRequests) finish(id int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	delete(f.cancels, id)
+	f.signalDrained()
+}
+
+// close stops accepting requests and returns a channel which is closed once no request is in flight.
+func (f *inflightRequests) close() <-chan struct{} {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.closed = true
+	if f.drained == nil {
+		f.drained = make(chan struct{})
+	}
+	f.signalDrained()
+	return f.drained
+}
+
+func (f *inflightRequests) signalDrained() {
+	if f.closed && !f.isDrained && len(f.cancels) == 0 {
+		f.isDrained = true
+		close(f.drained)
+	}
+}
+
+func (f *inflightRequests) cancelAll() {
+	f.mu.Lock()
+	cancels := make([]context.CancelFunc, 0, len(f.cancels))
+	for _, cancel := range f.cancels {
+		cancels = append(cancels, cancel)
+	}
+	f.mu.Unlock()
+
+	for _, cancel := range cancels {
+		cancel()
+	}
+}
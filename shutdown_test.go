@@ -0,0 +1,91 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestHttpClient_ShutdownWaitsForInflightRequests(t *testing.T) {
+	server := mockServer(http.StatusOK, contentTypeJSON, fixtureBasicJSON)
+	defer server.Close()
+
+	client := createTestHTTPClient(server.URL)
+
+	resp, err := client.GetFrom("/")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stopped := make(chan error)
+	go func() {
+		stopped <- client.Shutdown(context.Background())
+	}()
+
+	select {
+	case <-stopped:
+		t.Fatal("Expected Shutdown to wait for the open response")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	assertResponseBodyIs(resp, fixtureBasicJSON, t)
+
+	if err := <-stopped; err != nil {
+		t.Errorf("Expected graceful shutdown but got %v", err)
+	}
+
+	_, err = client.GetFrom("/")
+	var closedErr *ClientClosedError
+	if !errors.As(err, &closedErr) {
+		t.Errorf("Expected ClientClosedError but got %v", err)
+	}
+}
+
+func TestHttpClient_ShutdownCancelsAfterDeadline(t *testing.T) {
+	release := make(chan struct{})
+	server := mockServerWith(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	})
+	defer server.Close()
+	defer close(release)
+
+	client := createTestHTTPClient(server.URL)
+
+	failed := make(chan error)
+	go func() {
+		_, err := client.GetFromWithContext(context.Background(), "/")
+		failed <- err
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := client.Shutdown(ctx); err != context.DeadlineExceeded {
+		t.Errorf("Expected deadline exceeded but got %v", err)
+	}
+
+	select {
+	case err := <-failed:
+		if err == nil {
+			t.Error("Expected the in-flight request to be cancelled")
+		}
+	case <-time.After(time.Second):
+		t.Error("Expected the in-flight request to be cancelled")
+	}
+}
+
+func TestHttpClient_ShutdownIdle(t *testing.T) {
+	client := createTestHTTPClient(fixtureBaseURL)
+
+	if err := client.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Shutdown(context.Background()); err != nil {
+		t.Errorf("Expected repeated Shutdown to succeed but got %v", err)
+	}
+}
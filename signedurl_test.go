@@ -0,0 +1,69 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestURLSigner(t *testing.T) {
+	clock := NewFakeClock(time.Unix(1000, 0))
+	signer := NewURLSigner([]byte("secret")).WithClock(clock)
+
+	signed, err := signer.Sign(http.MethodGet, "https://files.example.com/reports/q1.pdf?b=2&a=1", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(signed, "expires=1060") || !strings.Contains(signed, "signature=") {
+		t.Errorf("Expected expiry and signature in %s", signed)
+	}
+	if err := signer.Verify(http.MethodGet, signed); err != nil {
+		t.Errorf("Expected the signed URL to be valid but got %v", err)
+	}
+
+	var signedErr *SignedURLError
+	for _, invalid := range []struct{ method, url string }{
+		{http.MethodPut, signed},
+		{http.MethodGet, strings.Replace(signed, "q1", "q2", 1)},
+		{http.MethodGet, strings.Replace(signed, "a=1", "a=3", 1)},
+		{http.MethodGet, "https://files.example.com/reports/q1.pdf"},
+	} {
+		if err := signer.Verify(invalid.method, invalid.url); !errors.As(err, &signedErr) || signedErr.Expired {
+			t.Errorf("Expected %s %s to be rejected but got %v", invalid.method, invalid.url, err)
+		}
+	}
+	if err := NewURLSigner([]byte("other")).Verify(http.MethodGet, signed); err == nil {
+		t.Error("Expected a signature of another key to be rejected")
+	}
+
+	clock.Advance(time.Minute)
+	if err := signer.Verify(http.MethodGet, signed); !errors.As(err, &signedErr) || !signedErr.Expired {
+		t.Errorf("Expected the URL to be expired but got %v", err)
+	}
+}
+
+func TestURLSigner_Handler(t *testing.T) {
+	signer := NewURLSigner([]byte("secret"))
+	server := httptest.NewServer(signer.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("file"))
+	})))
+	defer server.Close()
+
+	signed, _ := signer.Sign(http.MethodGet, server.URL+"/download", time.Minute)
+	client := createTestHTTPClient(server.URL)
+	for rawURL, status := range map[string]int{signed: http.StatusOK, server.URL + "/download": http.StatusForbidden} {
+		request, _ := http.NewRequest(http.MethodGet, rawURL, nil)
+		resp, err := client.ExecuteRequest(request.WithContext(WithRequestOptions(context.Background(), WithoutAuth())))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != status {
+			t.Errorf("Expected %d for %s but got %d", status, rawURL, resp.StatusCode)
+		}
+	}
+}
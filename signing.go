@@ -0,0 +1,187 @@
+package http
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// EmptyBodyDigest is the hex encoded SHA-256 of an empty body.
+const EmptyBodyDigest = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// CanonicalRequest returns the canonical form of r for custom signature schemes, one part per line: the upper
+// case method, the escaped path, CanonicalQuery of the query, the CanonicalHeaders of signedHeaders, the list of
+// signed header names and the hex encoded BodyDigest. It is the layout popularized by AWS Signature Version 4;
+// schemes differing from it can combine the individual helpers instead.
+func CanonicalRequest(r *http.Request, signedHeaders ...string) (string, error) {
+	digest, err := BodyDigest(r)
+	if err != nil {
+		return "", err
+	}
+	headers, signed := CanonicalHeaders(requestHeaders(r), signedHeaders...)
+
+	return strings.Join([]string{
+		strings.ToUpper(r.Method),
+		CanonicalPath(r.URL),
+		CanonicalQuery(r.URL.Query()),
+		headers,
+		signed,
+		hex.EncodeToString(digest),
+	}, "\n"), nil
+}
+
+// CanonicalPath returns the escaped path of u, "/" if it is empty.
+func CanonicalPath(u *url.URL) string {
+	path := u.EscapedPath()
+	if path == "" {
+		return "/"
+	}
+	return path
+}
+
+// CanonicalQuery returns query sorted by key and value, escaped as RFC 3986 demands, e.g. spaces as %20.
+// Parameters in exclude, like the signature itself, are left out.
+func CanonicalQuery(query url.Values, exclude ...string) string {
+	excluded := make(map[string]bool, len(exclude))
+	for _, name := range exclude {
+		excluded[name] = true
+	}
+
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		if !excluded[key] {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	var pairs []string
+	for _, key := range keys {
+		values := append([]string(nil), query[key]...)
+		sort.Strings(values)
+		for _, value := range values {
+			pairs = append(pairs, EscapeRFC3986(key)+"="+EscapeRFC3986(value))
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+// EscapeRFC3986 percent-encodes everything in s but the unreserved characters of RFC 3986.
+func EscapeRFC3986(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || c == '-' || c == '.' || c == '_' || c == '~' {
+			b.WriteByte(c)
+			continue
+		}
+		b.WriteString("%" + strings.ToUpper(hex.EncodeToString([]byte{c})))
+	}
+	return b.String()
+}
+
+// CanonicalHeaders returns the headers in names, all of header if none are given, as sorted "name:value" lines
+// with lower case names, trimmed values and inner whitespace collapsed; values of repeated headers are joined by
+// commas. signed lists the names included, separated by semicolons. Names missing from header are skipped.
+func CanonicalHeaders(header http.Header, names ...string) (canonical string, signed string) {
+	if len(names) == 0 {
+		for name := range header {
+			names = append(names, name)
+		}
+	}
+
+	seen := make(map[string]bool, len(names))
+	var included []string
+	for _, name := range names {
+		lower := strings.ToLower(name)
+		if _, ok := header[http.CanonicalHeaderKey(name)]; ok && !seen[lower] {
+			seen[lower] = true
+			included = append(included, lower)
+		}
+	}
+	sort.Strings(included)
+
+	lines := make([]string, len(included))
+	for i, name := range included {
+		values := header.Values(name)
+		normalized := make([]string, len(values))
+		for j, value := range values {
+			normalized[j] = strings.Join(strings.Fields(value), " ")
+		}
+		lines[i] = name + ":" + strings.Join(normalized, ",")
+	}
+	return strings.Join(lines, "\n"), strings.Join(included, ";")
+}
+
+// BodyDigest returns the SHA-256 of the body of r, leaving the body readable for sending r. Bodies are read
+// through GetBody if available, otherwise they are buffered.
+func BodyDigest(r *http.Request) ([]byte, error) {
+	hash := sha256.New()
+	if r.Body == nil || r.Body == http.NoBody {
+		return hash.Sum(nil), nil
+	}
+
+	if r.GetBody != nil {
+		body, err := r.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		defer body.Close()
+		if _, err := io.Copy(hash, body); err != nil {
+			return nil, err
+		}
+		return hash.Sum(nil), nil
+	}
+
+	data, err := io.ReadAll(r.Body)
+	r.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	r.Body = io.NopCloser(bytes.NewReader(data))
+	r.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(data)), nil
+	}
+	hash.Write(data)
+	return hash.Sum(nil), nil
+}
+
+// requestHeaders returns the headers of r including Host, which net/http keeps apart.
+func requestHeaders(r *http.Request) http.Header {
+	header := r.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	host := r.Host
+	if host == "" && r.URL != nil {
+		host = r.URL.Host
+	}
+	if host != "" && header.Get("Host") == "" {
+		header.Set("Host", host)
+	}
+	return header
+}
+
+// canonicalRequest returns the string a URL is signed over: method, escaped path and CanonicalQuery, each on
+// its own line. Parameters in exclude, like the signature itself, are left out.
+func canonicalRequest(method string, u *url.URL, exclude ...string) string {
+	return strings.ToUpper(method) + "\n" + CanonicalPath(u) + "\n" + CanonicalQuery(u.Query(), exclude...)
+}
+
+// hmacSHA256 returns the hex encoded HMAC-SHA256 of data with key.
+func hmacSHA256(key []byte, data string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// verifyHMACSHA256 compares signature with the HMAC-SHA256 of data in constant time.
+func verifyHMACSHA256(key []byte, data string, signature string) bool {
+	return hmac.Equal([]byte(hmacSHA256(key, data)), []byte(strings.ToLower(signature)))
+}
@@ -0,0 +1,76 @@
+package http
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestCanonicalRequest(t *testing.T) {
+	u, _ := url.Parse("https://example.com/a%20b?z=1&a=2&a=1&signature=x")
+
+	canonical := canonicalRequest("get", u, "signature")
+	if canonical != "GET\n/a%20b\na=1&a=2&z=1" {
+		t.Errorf("Unexpected canonical request %q", canonical)
+	}
+	if !verifyHMACSHA256([]byte("key"), canonical, hmacSHA256([]byte("key"), canonical)) {
+		t.Error("Expected the HMAC to verify")
+	}
+}
+
+func TestCanonicalQuery(t *testing.T) {
+	query := url.Values{"b": {"x y", "a"}, "a": {"1~*"}, "sig": {"s"}}
+	if canonical := CanonicalQuery(query, "sig"); canonical != "a=1~%2A&b=a&b=x%20y" {
+		t.Errorf("Unexpected canonical query %q", canonical)
+	}
+}
+
+func TestCanonicalHeaders(t *testing.T) {
+	header := http.Header{
+		"X-Amz-Date":   {"20260101T000000Z"},
+		"Content-Type": {"  application/json  "},
+		"X-Multi":      {"a   b", "c"},
+		"User-Agent":   {"test"},
+	}
+
+	canonical, signed := CanonicalHeaders(header, "x-multi", "Content-Type", "X-Amz-Date", "X-Missing")
+	if canonical != "content-type:application/json\nx-amz-date:20260101T000000Z\nx-multi:a b,c" {
+		t.Errorf("Unexpected canonical headers %q", canonical)
+	}
+	if signed != "content-type;x-amz-date;x-multi" {
+		t.Errorf("Unexpected signed headers %q", signed)
+	}
+
+	if _, all := CanonicalHeaders(header); all != "content-type;user-agent;x-amz-date;x-multi" {
+		t.Errorf("Expected all headers without names but got %q", all)
+	}
+}
+
+func TestCanonicalRequestExported(t *testing.T) {
+	request, _ := http.NewRequest(http.MethodPost, "https://api.test/v1/users?b=2&a=1", io.NopCloser(strings.NewReader(`{"name":"jane"}`)))
+	request.Header.Set("Content-Type", "application/json")
+
+	canonical, err := CanonicalRequest(request, "host", "content-type")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256([]byte(`{"name":"jane"}`))
+	expected := "POST\n/v1/users\na=1&b=2\ncontent-type:application/json\nhost:api.test\ncontent-type;host\n" + hex.EncodeToString(sum[:])
+	if canonical != expected {
+		t.Errorf("Expected %q but got %q", expected, canonical)
+	}
+
+	body, _ := io.ReadAll(request.Body)
+	if string(body) != `{"name":"jane"}` {
+		t.Errorf("Expected the body to stay readable but got %q", body)
+	}
+
+	empty, _ := http.NewRequest(http.MethodGet, "https://api.test", nil)
+	if digest, _ := BodyDigest(empty); hex.EncodeToString(digest) != EmptyBodyDigest {
+		t.Errorf("Unexpected digest of an empty body %x", digest)
+	}
+}
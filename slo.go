@@ -0,0 +1,93 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// SLOBudget is the total time budget of an operation calling several downstream services in sequence. Every
+// request executed with its context gets the remaining budget minus a reserve as deadline, so the reserve is
+// left for the operation's own work and a slow call can not overrun the budget of the whole chain.
+type SLOBudget struct {
+	deadline time.Time
+	reserve  time.Duration
+}
+
+// SLOBudgetExhaustedError is returned instead of sending a request when no more than the reserve of the
+// operation's SLOBudget remains.
+type SLOBudgetExhaustedError struct {
+	Message   string
+	Remaining time.Duration
+	Reserve   time.Duration
+}
+
+func (e SLOBudgetExhaustedError) Error() string {
+	return e.Message
+}
+
+// Unwrap makes errors.Is(err, context.DeadlineExceeded) hold, the request would have exceeded the budget.
+func (e SLOBudgetExhaustedError) Unwrap() error {
+	return context.DeadlineExceeded
+}
+
+type sloBudgetKey struct{}
+
+// WithSLOBudget returns a context limiting the operation to total, which is split across the requests executed
+// with it, keeping reserve of the remaining budget for the operation itself. Nested budgets are bounded by
+// their parent. The context's deadline is set to the end of the budget; call cancel when the operation ends.
+func WithSLOBudget(ctx context.Context, total time.Duration, reserve time.Duration) (context.Context, context.CancelFunc) {
+	budget := &SLOBudget{deadline: time.Now().Add(total), reserve: reserve}
+	if parent, ok := SLOBudgetFromContext(ctx); ok {
+		if parentDeadline := parent.callDeadline(); parentDeadline.Before(budget.deadline) {
+			budget.deadline = parentDeadline
+		}
+	}
+
+	ctx, cancel := context.WithDeadline(ctx, budget.deadline)
+	return context.WithValue(ctx, sloBudgetKey{}, budget), cancel
+}
+
+// SLOBudgetFromContext returns the SLOBudget of the operation ctx belongs to, if any.
+func SLOBudgetFromContext(ctx context.Context) (*SLOBudget, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	budget, ok := ctx.Value(sloBudgetKey{}).(*SLOBudget)
+	return budget, ok
+}
+
+// Remaining returns the time left of the budget.
+func (b *SLOBudget) Remaining() time.Duration {
+	return time.Until(b.deadline)
+}
+
+// CallTimeout returns the time a downstream call may take now, the remaining budget minus the reserve.
+func (b *SLOBudget) CallTimeout() time.Duration {
+	return b.Remaining() - b.reserve
+}
+
+func (b *SLOBudget) callDeadline() time.Time {
+	return b.deadline.Add(-b.reserve)
+}
+
+// applySLOBudget limits r to the call deadline of the SLOBudget of its context. The returned cancel, nil without
+// budget, must be called once the response is consumed.
+func applySLOBudget(r *http.Request) (*http.Request, context.CancelFunc, error) {
+	budget, ok := SLOBudgetFromContext(r.Context())
+	if !ok {
+		return r, nil, nil
+	}
+
+	if timeout := budget.CallTimeout(); timeout <= 0 {
+		remaining := budget.Remaining()
+		return nil, nil, &SLOBudgetExhaustedError{
+			Message:   fmt.Sprintf("Only %s of the SLO budget remain, keeping a reserve of %s.", remaining, budget.reserve),
+			Remaining: remaining,
+			Reserve:   budget.reserve,
+		}
+	}
+	ctx, cancel := context.WithDeadline(r.Context(), budget.callDeadline())
+	return r.WithContext(ctx), cancel, nil
+}
@@ -0,0 +1,63 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestSLOBudget(t *testing.T) {
+	deadlines := make(chan time.Duration, 2)
+	server := mockServerWith(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(50 * time.Millisecond)
+	})
+	defer server.Close()
+
+	client := createTestHTTPClient(server.URL)
+	client.Use(func(next RoundTripperFunc) RoundTripperFunc {
+		return func(r *http.Request) (*http.Response, error) {
+			deadline, _ := r.Context().Deadline()
+			deadlines <- time.Until(deadline)
+			return next(r)
+		}
+	})
+
+	ctx, cancel := WithSLOBudget(context.Background(), 300*time.Millisecond, 150*time.Millisecond)
+	defer cancel()
+
+	resp, err := client.Get(ctx, "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if timeout := <-deadlines; timeout > 150*time.Millisecond {
+		t.Errorf("Expected the call deadline to keep the reserve but got %s", timeout)
+	}
+
+	// the first call used a third of the budget, the second one times out within the rest
+	budget, _ := SLOBudgetFromContext(ctx)
+	if budget.CallTimeout() >= 100*time.Millisecond {
+		t.Errorf("Expected the budget to shrink but %s remain", budget.CallTimeout())
+	}
+	time.Sleep(budget.CallTimeout())
+
+	_, err = client.Get(ctx, "/")
+	var exhausted *SLOBudgetExhaustedError
+	if !errors.As(err, &exhausted) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected SLOBudgetExhaustedError but got %v", err)
+	}
+}
+
+func TestWithSLOBudget_Nested(t *testing.T) {
+	parent, cancel := WithSLOBudget(context.Background(), time.Second, 400*time.Millisecond)
+	defer cancel()
+	child, cancel := WithSLOBudget(parent, time.Minute, 0)
+	defer cancel()
+
+	budget, _ := SLOBudgetFromContext(child)
+	if remaining := budget.Remaining(); remaining > 600*time.Millisecond {
+		t.Errorf("Expected the nested budget to be bounded by its parent but got %s", remaining)
+	}
+}
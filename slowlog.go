@@ -0,0 +1,151 @@
+package http
+
+import (
+	"log/slog"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// SlowRequestLog is an Events consumer logging requests slower than a threshold with full diagnostic detail:
+// the redacted URL and headers, and every attempt with its phase timings and outcome. Requests below the
+// threshold are not logged, keeping the regular logging cheap while capturing outliers richly:
+//
+//	client := http.NewHttpClientWithConfig(http.NewDefaultHttpConfig(baseURL,
+//		http.WithEvents(http.NewSlowRequestLog(logger, 2*time.Second))))
+//
+// Attempt history is only available for requests executed by the client, as it is kept per request until the
+// request finishes.
+type SlowRequestLog struct {
+	logger    *slog.Logger
+	threshold time.Duration
+	level     slog.Level
+	// redaction overrides the redaction of the client, nil to use the client's
+	redaction *redaction
+
+	mu       sync.Mutex
+	attempts map[*int32][]Event
+}
+
+// NewSlowRequestLog creates a SlowRequestLog logging requests taking threshold or longer to logger at warn
+// level.
+func NewSlowRequestLog(logger *slog.Logger, threshold time.Duration) *SlowRequestLog {
+	if logger == nil {
+		panic("logger is nil")
+	}
+	return &SlowRequestLog{logger: logger, threshold: threshold, level: slog.LevelWarn, attempts: map[*int32][]Event{}}
+}
+
+// WithLevel logs slow requests at level instead of warn.
+func (l *SlowRequestLog) WithLevel(level slog.Level) *SlowRequestLog {
+	l.level = level
+	return l
+}
+
+// WithRedactedHeaders masks the values of the given headers, in addition to DefaultRedactedHeaders, instead of
+// the parameters and headers redacted by the client.
+func (l *SlowRequestLog) WithRedactedHeaders(names ...string) *SlowRequestLog {
+	l.redaction = newRedaction(nil, names)
+	return l
+}
+
+// Emit implements Events.
+func (l *SlowRequestLog) Emit(e Event) {
+	if e.Request == nil {
+		return
+	}
+	key, ok := e.Request.Context().Value(attemptCounterKey{}).(*int32)
+	if !ok {
+		return
+	}
+
+	switch e.Type {
+	case EventAttemptFinished:
+		l.mu.Lock()
+		l.attempts[key] = append(l.attempts[key], e)
+		l.mu.Unlock()
+	case EventRequestFinished:
+		l.mu.Lock()
+		attempts := l.attempts[key]
+		delete(l.attempts, key)
+		l.mu.Unlock()
+
+		if e.Duration >= l.threshold && l.logger.Enabled(e.Request.Context(), l.level) {
+			l.log(e, attempts)
+		}
+	}
+}
+
+func (l *SlowRequestLog) log(e Event, attempts []Event) {
+	r := e.Request
+	redaction := l.redaction
+	if redaction == nil {
+		redaction = redactionFrom(r.Context())
+	}
+	attrs := []slog.Attr{
+		slog.String("method", r.Method),
+		slog.String("url", redaction.url(r.URL)),
+		slog.Duration("duration", e.Duration),
+		slog.Duration("threshold", l.threshold),
+	}
+	if e.Response != nil {
+		attrs = append(attrs, slog.Int("status", e.Response.StatusCode))
+	}
+	if e.Err != nil {
+		attrs = append(attrs, slog.String("error", redaction.message(e.Err.Error(), r)))
+	}
+	if traceID, ok := TraceIDFromContext(r.Context()); ok {
+		attrs = append(attrs, slog.String("trace_id", traceID))
+	}
+	if endpoint, ok := EndpointNameFromContext(r.Context()); ok {
+		attrs = append(attrs, slog.String("endpoint", endpoint))
+	}
+	attrs = append(attrs, l.headerAttr(redaction, "request_headers", r.Header))
+	if e.Response != nil {
+		attrs = append(attrs, l.headerAttr(redaction, "response_headers", e.Response.Header))
+	}
+
+	history := make([]any, 0, len(attempts))
+	for _, attempt := range attempts {
+		group := []any{
+			slog.Int("attempt", attempt.Attempt),
+			slog.Duration("duration", attempt.Duration),
+			slog.Bool("conn_reused", attempt.ConnReused),
+			slog.Duration("dns", attempt.Timings.DNS),
+			slog.Duration("connect", attempt.Timings.Connect),
+			slog.Duration("tls", attempt.Timings.TLS),
+			slog.Duration("ttfb", attempt.Timings.TimeToFirstByte),
+		}
+		if attempt.Response != nil {
+			group = append(group, slog.Int("status", attempt.Response.StatusCode))
+		}
+		if attempt.Err != nil {
+			group = append(group, slog.String("error", redaction.message(attempt.Err.Error(), r)))
+		}
+		history = append(history, slog.Group(strconv.Itoa(attempt.Attempt), group...))
+	}
+	attrs = append(attrs, slog.Group("attempts", history...))
+
+	l.logger.LogAttrs(r.Context(), l.level, "slow http request", attrs...)
+}
+
+// headerAttr returns header as a group with the values of secret headers masked.
+func (l *SlowRequestLog) headerAttr(redaction *redaction, name string, header http.Header) slog.Attr {
+	keys := make([]string, 0, len(header))
+	for key := range header {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	values := make([]any, 0, len(keys))
+	for _, key := range keys {
+		masked := make([]string, len(header[key]))
+		for i, value := range header[key] {
+			masked[i] = redaction.headerValue(key, value)
+		}
+		values = append(values, slog.Any(key, masked))
+	}
+	return slog.Group(name, values...)
+}
@@ -0,0 +1,57 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSlowRequestLog(t *testing.T) {
+	server := mockServerWith(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			time.Sleep(30 * time.Millisecond)
+		}
+		w.Header().Set("X-Served-By", "cache-1")
+	})
+	defer server.Close()
+
+	var out bytes.Buffer
+	log := NewSlowRequestLog(slog.New(slog.NewTextHandler(&out, nil)), 20*time.Millisecond).WithRedactedHeaders("X-Tenant-Secret")
+	client := NewHttpClientWithConfig(NewDefaultHttpConfig(server.URL, WithEvents(log)))
+	client.Use(func(next RoundTripperFunc) RoundTripperFunc {
+		return func(r *http.Request) (*http.Response, error) {
+			r.Header.Set("X-Tenant-Secret", "s3cr3t")
+			r.Header.Set("Authorization", "Bearer t0ken")
+			return next(r)
+		}
+	})
+
+	for _, path := range []string{"/fast", "/slow"} {
+		resp, err := client.Get(context.Background(), path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+
+	logged := out.String()
+	if strings.Count(logged, "slow http request") != 1 || strings.Contains(logged, "/fast") {
+		t.Fatalf("Expected only the slow request to be logged but got %s", logged)
+	}
+	for _, expected := range []string{"/slow", "status=200", "attempts.1.ttfb=", "attempts.1.conn_reused=",
+		"response_headers.X-Served-By=[cache-1]", "request_headers.Authorization=\"[Bearer xxxxx]\""} {
+		if !strings.Contains(logged, expected) {
+			t.Errorf("Expected %s in %s", expected, logged)
+		}
+	}
+	if strings.Contains(logged, "s3cr3t") || strings.Contains(logged, "t0ken") {
+		t.Errorf("Expected secrets to be redacted but got %s", logged)
+	}
+	if len(log.attempts) != 0 {
+		t.Errorf("Expected no attempts to be kept after the requests finished but got %d", len(log.attempts))
+	}
+}
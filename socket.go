@@ -0,0 +1,64 @@
+package http
+
+import (
+	"context"
+	"net"
+	"os"
+)
+
+// localSocket is a Unix domain socket or Windows named pipe all connections are dialed to.
+type localSocket struct {
+	network string
+	address string
+}
+
+const networkNamedPipe = "pipe"
+
+// WithUnixSocket sends all requests over the Unix domain socket at path, e.g. "/var/run/docker.sock".
+// The base URL then only provides the Host header and path, e.g. "http://docker".
+func WithUnixSocket(path string) Option {
+	return func(c *HttpConfig) {
+		c.socket = &localSocket{network: "unix", address: path}
+	}
+}
+
+// WithNamedPipe sends all requests over the Windows named pipe at path, e.g. `\\.\pipe\docker_engine`,
+// like WithUnixSocket does for Unix domain sockets. Dialing fails on other platforms.
+func WithNamedPipe(path string) Option {
+	return func(c *HttpConfig) {
+		c.socket = &localSocket{network: networkNamedPipe, address: path}
+	}
+}
+
+// dialSocket returns a dial func connecting to socket whatever address is requested.
+func dialSocket(dialer *net.Dialer, socket *localSocket) dialFunc {
+	return func(ctx context.Context, network string, address string) (net.Conn, error) {
+		if socket.network == networkNamedPipe {
+			return dialNamedPipe(ctx, socket.address)
+		}
+		return dialer.DialContext(ctx, socket.network, socket.address)
+	}
+}
+
+// pipeConn adapts an opened named pipe to net.Conn.
+type pipeConn struct {
+	*os.File
+}
+
+func (c *pipeConn) LocalAddr() net.Addr {
+	return pipeAddr(c.Name())
+}
+
+func (c *pipeConn) RemoteAddr() net.Addr {
+	return pipeAddr(c.Name())
+}
+
+type pipeAddr string
+
+func (a pipeAddr) Network() string {
+	return networkNamedPipe
+}
+
+func (a pipeAddr) String() string {
+	return string(a)
+}
@@ -0,0 +1,14 @@
+//go:build !windows
+
+package http
+
+import (
+	"context"
+	"errors"
+	"net"
+)
+
+// dialNamedPipe fails, named pipes only exist on Windows.
+func dialNamedPipe(ctx context.Context, path string) (net.Conn, error) {
+	return nil, errors.New("named pipes are only supported on Windows")
+}
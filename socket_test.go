@@ -0,0 +1,60 @@
+package http
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestWithUnixSocket(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Unix domain sockets are not available")
+	}
+
+	dir, err := os.MkdirTemp("", "socket")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "api.sock")
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Host + r.URL.Path))
+	}))
+	server.Listener = listener
+	server.Start()
+	defer server.Close()
+
+	client := NewHttpClientWithConfig(NewDefaultHttpConfig("http://docker", WithUnixSocket(path)))
+
+	resp, err := client.GetFrom("/version")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertResponseBodyIs(resp, "docker/version", t)
+
+	if proxy := client.client.Transport.(*http.Transport).Proxy; proxy != nil {
+		t.Error("Expected requests over a local socket not to be proxied")
+	}
+}
+
+func TestWithNamedPipe_Unsupported(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("named pipes are supported")
+	}
+
+	client := NewHttpClientWithConfig(NewDefaultHttpConfig("http://docker", WithNamedPipe(`\\.\pipe\docker_engine`)))
+
+	if _, err := client.GetFrom("/version"); err == nil {
+		t.Error("Expected named pipes to fail outside of Windows")
+	}
+}
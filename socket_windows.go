@@ -0,0 +1,32 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"syscall"
+	"time"
+)
+
+// errorPipeBusy is returned while all instances of a named pipe are in use.
+const errorPipeBusy syscall.Errno = 231
+
+// dialNamedPipe opens the named pipe at path, waiting while the server has no free instance.
+func dialNamedPipe(ctx context.Context, path string) (net.Conn, error) {
+	for {
+		file, err := os.OpenFile(path, os.O_RDWR, 0)
+		if err == nil {
+			return &pipeConn{File: file}, nil
+		}
+		if !errors.Is(err, errorPipeBusy) {
+			return nil, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package http
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// SpiedRequest is a copy of an outgoing request recorded by a Spy.
+type SpiedRequest struct {
+	Method string
+	URL    *url.URL
+	Header http.Header
+	Body   []byte
+	Time   time.Time
+}
+
+// Spy records the outgoing requests of a client, so tests can assert exactly what was sent:
+//
+//	spy := NewSpy()
+//	client.Use(spy.Middleware())
+//	...
+//	if spy.Requests().WithMethod("POST").WithPath("/users").Count() != 1 { ... }
+//
+// Requests are recorded as they are handed to the transport, after authentication and the middleware
+// added before the spy.
+type Spy struct {
+	mu       sync.Mutex
+	requests []SpiedRequest
+}
+
+// NewSpy creates an empty Spy.
+func NewSpy() *Spy {
+	return &Spy{}
+}
+
+// Middleware returns the Middleware recording requests into the spy.
+func (s *Spy) Middleware() Middleware {
+	return func(next RoundTripperFunc) RoundTripperFunc {
+		return func(r *http.Request) (*http.Response, error) {
+			spied := SpiedRequest{
+				Method: r.Method,
+				URL:    cloneURL(r.URL),
+				Header: r.Header.Clone(),
+				Time:   time.Now(),
+			}
+			if r.Body != nil && r.Body != http.NoBody {
+				body, err := io.ReadAll(r.Body)
+				r.Body.Close()
+				if err != nil {
+					return nil, err
+				}
+				spied.Body = body
+				r = r.Clone(r.Context())
+				r.Body = io.NopCloser(bytes.NewReader(body))
+				r.GetBody = func() (io.ReadCloser, error) {
+					return io.NopCloser(bytes.NewReader(body)), nil
+				}
+			}
+
+			s.mu.Lock()
+			s.requests = append(s.requests, spied)
+			s.mu.Unlock()
+
+			return next(r)
+		}
+	}
+}
+
+// Requests returns the recorded requests in the order they were sent.
+func (s *Spy) Requests() SpiedRequests {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append(SpiedRequests(nil), s.requests...)
+}
+
+// Reset discards the recorded requests.
+func (s *Spy) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = nil
+}
+
+// SpiedRequests is a list of recorded requests with filters for assertions.
+type SpiedRequests []SpiedRequest
+
+// Where returns the requests matching f.
+func (l SpiedRequests) Where(f func(SpiedRequest) bool) SpiedRequests {
+	var matching SpiedRequests
+	for _, r := range l {
+		if f(r) {
+			matching = append(matching, r)
+		}
+	}
+	return matching
+}
+
+// WithMethod returns the requests with method.
+func (l SpiedRequests) WithMethod(method string) SpiedRequests {
+	return l.Where(func(r SpiedRequest) bool { return r.Method == method })
+}
+
+// WithPath returns the requests to path.
+func (l SpiedRequests) WithPath(path string) SpiedRequests {
+	return l.Where(func(r SpiedRequest) bool { return r.URL.Path == path })
+}
+
+// WithHost returns the requests to host.
+func (l SpiedRequests) WithHost(host string) SpiedRequests {
+	return l.Where(func(r SpiedRequest) bool { return r.URL.Host == host })
+}
+
+// WithHeader returns the requests whose header has value.
+func (l SpiedRequests) WithHeader(header string, value string) SpiedRequests {
+	return l.Where(func(r SpiedRequest) bool { return r.Header.Get(header) == value })
+}
+
+// WithQuery returns the requests whose query parameter has value.
+func (l SpiedRequests) WithQuery(param string, value string) SpiedRequests {
+	return l.Where(func(r SpiedRequest) bool { return r.URL.Query().Get(param) == value })
+}
+
+// Count returns the number of requests.
+func (l SpiedRequests) Count() int {
+	return len(l)
+}
+
+// First returns the first request, or false if the list is empty.
+func (l SpiedRequests) First() (SpiedRequest, bool) {
+	if len(l) == 0 {
+		return SpiedRequest{}, false
+	}
+	return l[0], true
+}
+
+// Last returns the last request, or false if the list is empty.
+func (l SpiedRequests) Last() (SpiedRequest, bool) {
+	if len(l) == 0 {
+		return SpiedRequest{}, false
+	}
+	return l[len(l)-1], true
+}
+
+func cloneURL(u *url.URL) *url.URL {
+	clone := *u
+	if u.User != nil {
+		user := *u.User
+		clone.User = &user
+	}
+	return &clone
+}
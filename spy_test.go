@@ -0,0 +1,47 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestSpy(t *testing.T) {
+	server := mockEchoServer(http.StatusOK)
+	defer server.Close()
+
+	spy := NewSpy()
+	client := NewHttpClientWithConfig(NewHttpConfig(server.URL, "user", "secret", contentTypeJSON))
+	client.Use(spy.Middleware())
+
+	client.GetFrom("/users?page=2")
+	resp, _ := client.PostTo("/users", strings.NewReader(fixtureBasicJSON))
+	client.DeleteFrom("/users/1")
+
+	// the request body still reaches the server
+	assertResponseBodyIs(resp, fixtureBasicJSON, t)
+
+	requests := spy.Requests()
+	if requests.Count() != 3 || requests.WithPath("/users").Count() != 2 {
+		t.Errorf("Unexpected requests %+v", requests)
+	}
+	if requests.WithPath("/users").WithQuery("page", "2").Count() != 1 {
+		t.Error("Expected request with page query")
+	}
+
+	post, ok := requests.WithMethod(http.MethodPost).First()
+	if !ok || string(post.Body) != fixtureBasicJSON {
+		t.Errorf("Expected post body to be recorded but got %+v", post)
+	}
+	if _, _, ok := (&http.Request{Header: post.Header}).BasicAuth(); !ok {
+		t.Error("Expected authentication to be recorded")
+	}
+	if last, _ := requests.Last(); last.Method != http.MethodDelete {
+		t.Errorf("Expected delete last but got %s", last.Method)
+	}
+
+	spy.Reset()
+	if spy.Requests().Count() != 0 {
+		t.Error("Expected no requests after Reset")
+	}
+}
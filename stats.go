@@ -0,0 +1,178 @@
+package http
+
+import (
+	"encoding/json"
+	"expvar"
+	"net/http"
+	"sort"
+	"sync"
+)
+
+// Stats counts the events of one or more clients. Register it with WithEvents, publish it with Publish or
+// serve it with DebugHandler.
+type Stats struct {
+	mu       sync.Mutex
+	snapshot StatsSnapshot
+}
+
+// StatsSnapshot is a point-in-time copy of the counters of Stats.
+type StatsSnapshot struct {
+	Requests          int64            `json:"requests"`
+	Attempts          int64            `json:"attempts"`
+	Errors            int64            `json:"errors"`
+	ErrorsByClass     map[string]int64 `json:"errorsByClass"`
+	Retries           int64            `json:"retries"`
+	CacheHits         int64            `json:"cacheHits"`
+	CacheMisses       int64            `json:"cacheMisses"`
+	CacheStaleHits    int64            `json:"cacheStaleHits"`
+	CacheHitRatio     float64          `json:"cacheHitRatio"`
+	CacheRevalidated  int64            `json:"cacheRevalidated"`
+	CacheStores       int64            `json:"cacheStores"`
+	CacheEvictions    int64            `json:"cacheEvictions"`
+	CircuitsOpened    int64            `json:"circuitsOpened"`
+	TokenRefreshes    int64            `json:"tokenRefreshes"`
+	ConnectionsOpened int64            `json:"connectionsOpened"`
+	ConnectionsReused int64            `json:"connectionsReused"`
+}
+
+// NewStats creates Stats with all counters at zero.
+func NewStats() *Stats {
+	return &Stats{snapshot: StatsSnapshot{ErrorsByClass: make(map[string]int64)}}
+}
+
+// Emit implements Events.
+func (s *Stats) Emit(e Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch e.Type {
+	case EventRequestFinished:
+		s.snapshot.Requests++
+		if class, failed := errorClass(e); failed {
+			s.snapshot.Errors++
+			s.snapshot.ErrorsByClass[class]++
+		}
+	case EventAttemptFinished:
+		s.snapshot.Attempts++
+		if e.Err == nil && e.ConnReused {
+			s.snapshot.ConnectionsReused++
+		} else if e.Err == nil {
+			s.snapshot.ConnectionsOpened++
+		}
+	case EventRetryScheduled:
+		s.snapshot.Retries++
+	case EventCacheHit:
+		s.snapshot.CacheHits++
+		if e.CacheStatus == CacheStatusStale {
+			s.snapshot.CacheStaleHits++
+		}
+	case EventCacheMiss:
+		s.snapshot.CacheMisses++
+	case EventCacheRevalidated:
+		s.snapshot.CacheRevalidated++
+	case EventCacheStored:
+		s.snapshot.CacheStores++
+	case EventCacheEvicted:
+		s.snapshot.CacheEvictions++
+	case EventCircuitOpened:
+		s.snapshot.CircuitsOpened++
+	case EventTokenRefreshed:
+		s.snapshot.TokenRefreshes++
+	}
+}
+
+// Snapshot returns a copy of the current counters.
+func (s *Stats) Snapshot() StatsSnapshot {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snapshot := s.snapshot
+	snapshot.ErrorsByClass = make(map[string]int64, len(s.snapshot.ErrorsByClass))
+	for class, count := range s.snapshot.ErrorsByClass {
+		snapshot.ErrorsByClass[class] = count
+	}
+	// caches reporting misses define the ratio, others answer without a request reaching the client
+	hits := snapshot.CacheHits + snapshot.CacheRevalidated
+	if snapshot.CacheMisses > 0 {
+		snapshot.CacheHitRatio = float64(hits) / float64(hits+snapshot.CacheMisses)
+	} else if total := snapshot.Requests + hits; total > 0 {
+		snapshot.CacheHitRatio = float64(hits) / float64(total)
+	}
+	return snapshot
+}
+
+// Publish exposes the counters as expvar variable name, e.g. served at /debug/vars.
+// Like expvar.Publish it panics if the name is already in use.
+func (s *Stats) Publish(name string) {
+	expvar.Publish(name, expvar.Func(func() interface{} {
+		return s.Snapshot()
+	}))
+}
+
+// errorClass returns the class of a failed request, "transport" if no response was received.
+func errorClass(e Event) (string, bool) {
+	if e.Response == nil {
+		return "transport", e.Err != nil
+	}
+	if e.Response.StatusCode < http.StatusBadRequest {
+		return "", false
+	}
+	return ClassifyStatus(e.Response.StatusCode).String(), true
+}
+
+// clientState is the JSON document served by DebugHandler.
+type clientState struct {
+	Config       string         `json:"config"`
+	Environment  string         `json:"environment,omitempty"`
+	Environments []string       `json:"environments,omitempty"`
+	Presets      []string       `json:"presets,omitempty"`
+	Middleware   int            `json:"middleware"`
+	Transformers int            `json:"transformers"`
+	InFlight     int            `json:"inFlight"`
+	ShutDown     bool           `json:"shutDown"`
+	Stats        *StatsSnapshot `json:"stats,omitempty"`
+}
+
+// DebugHandler returns a http.Handler dumping the current state of the client as JSON, including the counters
+// of stats if it is not nil. Credentials are redacted.
+func (h *HttpClient) DebugHandler(stats *Stats) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		state := h.state()
+		if stats != nil {
+			snapshot := stats.Snapshot()
+			state.Stats = &snapshot
+		}
+
+		w.Header().Set("Content-Type", jsonType)
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		encoder.Encode(state)
+	})
+}
+
+func (h *HttpClient) state() clientState {
+	h.mu.RLock()
+	state := clientState{
+		Config:       h.config.Redacted(),
+		Environment:  h.environment,
+		Middleware:   len(h.middleware),
+		Transformers: len(h.transformers),
+	}
+	for name := range h.environments {
+		state.Environments = append(state.Environments, name)
+	}
+	for name := range h.presets {
+		state.Presets = append(state.Presets, name)
+	}
+	h.mu.RUnlock()
+
+	sort.Strings(state.Environments)
+	sort.Strings(state.Presets)
+
+	h.inflight.mu.Lock()
+	state.InFlight = len(h.inflight.cancels)
+	state.ShutDown = h.inflight.closed
+	h.inflight.mu.Unlock()
+
+	return state
+}
@@ -0,0 +1,73 @@
+package http
+
+import (
+	"encoding/json"
+	"expvar"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestStats(t *testing.T) {
+	server := mockServerWith(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	defer server.Close()
+
+	stats := NewStats()
+	client := NewHttpClientWithConfig(NewDefaultHttpConfig(server.URL, WithEvents(stats)))
+
+	for _, path := range []string{"/", "/", "/missing"} {
+		resp, err := client.GetFrom(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertResponseBodyIs(resp, "", t)
+	}
+	stats.Emit(Event{Type: EventCacheHit})
+
+	snapshot := stats.Snapshot()
+	if snapshot.Requests != 3 || snapshot.Attempts != 3 || snapshot.Errors != 1 || snapshot.ErrorsByClass["4xx"] != 1 {
+		t.Errorf("Unexpected request counters %+v", snapshot)
+	}
+	if snapshot.ConnectionsOpened != 1 || snapshot.ConnectionsReused != 2 {
+		t.Errorf("Expected pooled connections to be reused but got %+v", snapshot)
+	}
+	if snapshot.CacheHitRatio != 0.25 {
+		t.Errorf("Expected cache hit ratio of 0.25 but got %f", snapshot.CacheHitRatio)
+	}
+}
+
+func TestStats_Publish(t *testing.T) {
+	stats := NewStats()
+	stats.Emit(Event{Type: EventRetryScheduled})
+	stats.Publish("clean-http-client-test")
+
+	published := expvar.Get("clean-http-client-test")
+	if published == nil || !strings.Contains(published.String(), `"retries":1`) {
+		t.Errorf("Expected published counters but got %v", published)
+	}
+}
+
+func TestHttpClient_DebugHandler(t *testing.T) {
+	client := NewHttpClientWithConfig(NewHttpConfig(fixtureBaseURL, "user", "secret", ""))
+	client.AddEnvironment(Environment{Name: EnvironmentStaging, BaseURL: fixtureBaseURL})
+
+	recorder := httptest.NewRecorder()
+	client.DebugHandler(NewStats()).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/debug/client", nil))
+
+	if strings.Contains(recorder.Body.String(), "secret") {
+		t.Errorf("Expected credentials to be redacted in %s", recorder.Body)
+	}
+
+	state := clientState{}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &state); err != nil {
+		t.Fatal(err)
+	}
+	if len(state.Environments) != 1 || state.Stats == nil {
+		t.Errorf("Unexpected client state %+v", state)
+	}
+}
@@ -0,0 +1,93 @@
+package http
+
+import (
+	"net/http"
+)
+
+// StatusClass groups HTTP status codes by their first digit.
+type StatusClass int
+
+const (
+	StatusClassUnknown StatusClass = iota
+	StatusClassInformational
+	StatusClassSuccess
+	StatusClassRedirect
+	StatusClassClientError
+	StatusClassServerError
+)
+
+// ClassifyStatus returns the StatusClass of code.
+func ClassifyStatus(code int) StatusClass {
+	switch {
+	case code >= 100 && code < 200:
+		return StatusClassInformational
+	case code >= 200 && code < 300:
+		return StatusClassSuccess
+	case code >= 300 && code < 400:
+		return StatusClassRedirect
+	case code >= 400 && code < 500:
+		return StatusClassClientError
+	case code >= 500 && code < 600:
+		return StatusClassServerError
+	default:
+		return StatusClassUnknown
+	}
+}
+
+func (c StatusClass) String() string {
+	switch c {
+	case StatusClassInformational:
+		return "1xx"
+	case StatusClassSuccess:
+		return "2xx"
+	case StatusClassRedirect:
+		return "3xx"
+	case StatusClassClientError:
+		return "4xx"
+	case StatusClassServerError:
+		return "5xx"
+	default:
+		return "unknown"
+	}
+}
+
+// IsRetryableStatus reports whether a request failing with code may succeed when sent again.
+func IsRetryableStatus(code int) bool {
+	switch code {
+	case http.StatusRequestTimeout, http.StatusTooEarly, http.StatusTooManyRequests,
+		http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// StatusClass returns the StatusClass of the response.
+func (r *Response) StatusClass() StatusClass {
+	return ClassifyStatus(r.StatusCode)
+}
+
+// IsSuccess reports a 2xx status.
+func (r *Response) IsSuccess() bool {
+	return r.StatusClass() == StatusClassSuccess
+}
+
+// IsRedirect reports a 3xx status.
+func (r *Response) IsRedirect() bool {
+	return r.StatusClass() == StatusClassRedirect
+}
+
+// IsClientError reports a 4xx status.
+func (r *Response) IsClientError() bool {
+	return r.StatusClass() == StatusClassClientError
+}
+
+// IsServerError reports a 5xx status.
+func (r *Response) IsServerError() bool {
+	return r.StatusClass() == StatusClassServerError
+}
+
+// IsRetryable reports whether the request may succeed when sent again.
+func (r *Response) IsRetryable() bool {
+	return IsRetryableStatus(r.StatusCode)
+}
@@ -0,0 +1,39 @@
+package http
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestClassifyStatus(t *testing.T) {
+	cases := map[int]StatusClass{
+		http.StatusContinue:           StatusClassInformational,
+		http.StatusNoContent:          StatusClassSuccess,
+		http.StatusFound:              StatusClassRedirect,
+		http.StatusNotFound:           StatusClassClientError,
+		http.StatusServiceUnavailable: StatusClassServerError,
+		999:                           StatusClassUnknown,
+	}
+
+	for code, expected := range cases {
+		if actual := ClassifyStatus(code); actual != expected {
+			t.Errorf("Expected %s but got %s for %d", expected, actual, code)
+		}
+	}
+}
+
+func TestResponse_StatusPredicates(t *testing.T) {
+	resp := &Response{Response: &http.Response{StatusCode: http.StatusServiceUnavailable}}
+
+	if resp.IsSuccess() || resp.IsRedirect() || resp.IsClientError() {
+		t.Error("Expected only IsServerError to be true")
+	}
+	if !resp.IsServerError() || !resp.IsRetryable() {
+		t.Error("Expected 503 to be a retryable server error")
+	}
+
+	resp.StatusCode = http.StatusNotImplemented
+	if resp.IsRetryable() {
+		t.Error("Expected 501 not to be retryable")
+	}
+}
@@ -0,0 +1,96 @@
+package http
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"iter"
+	"sync"
+)
+
+// NDJSONContentType is the content type of bodies created by NDJSONBody.
+const NDJSONContentType = "application/x-ndjson"
+
+// CSVContentType is the content type of bodies created by CSVBody.
+const CSVContentType = "text/csv"
+
+// ChannelBody returns a request body streaming the chunks received from ch until it is closed, so producers can
+// pipe data to ingest APIs without materializing it. Its length is unknown, so it is sent chunked. Producers
+// should stop on the cancellation of the request's context, since nobody receives after the request ended.
+func ChannelBody(ch <-chan []byte) io.ReadCloser {
+	if ch == nil {
+		panic("channel is nil")
+	}
+	return &channelBody{ch: ch, closed: make(chan struct{})}
+}
+
+type channelBody struct {
+	ch      <-chan []byte
+	pending []byte
+	closed  chan struct{}
+	once    sync.Once
+}
+
+func (b *channelBody) Read(p []byte) (int, error) {
+	for len(b.pending) == 0 {
+		select {
+		case chunk, ok := <-b.ch:
+			if !ok {
+				return 0, io.EOF
+			}
+			b.pending = chunk
+		case <-b.closed:
+			return 0, io.ErrClosedPipe
+		}
+	}
+	n := copy(p, b.pending)
+	b.pending = b.pending[n:]
+	return n, nil
+}
+
+func (b *channelBody) Close() error {
+	b.once.Do(func() { close(b.closed) })
+	return nil
+}
+
+// NDJSONBody returns a request body streaming the records of seq as newline delimited JSON, see
+// NDJSONContentType. The iteration stops once the body is closed, e.g. because the request failed.
+func NDJSONBody[T any](seq iter.Seq[T]) io.ReadCloser {
+	return pipeBody(func(w io.Writer) error {
+		encoder := json.NewEncoder(w)
+		for record := range seq {
+			if err := encoder.Encode(record); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// CSVBody returns a request body streaming the records of seq as CSV, see CSVContentType. The iteration stops
+// once the body is closed.
+func CSVBody(seq iter.Seq[[]string]) io.ReadCloser {
+	return pipeBody(func(w io.Writer) error {
+		writer := csv.NewWriter(w)
+		for record := range seq {
+			if err := writer.Write(record); err != nil {
+				return err
+			}
+			// flush every record, so the server receives data as it is produced
+			writer.Flush()
+			if err := writer.Error(); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// pipeBody streams what produce writes, passing its error to the reader. Writes fail once the reader is closed.
+func pipeBody(produce func(w io.Writer) error) io.ReadCloser {
+	reader, writer := io.Pipe()
+	go func() {
+		writer.CloseWithError(produce(writer))
+	}()
+	return reader
+}
@@ -0,0 +1,70 @@
+package http
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestStreamingBodies(t *testing.T) {
+	var received []string
+	var chunked []bool
+	server := mockServerWith(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received = append(received, string(body))
+		chunked = append(chunked, len(r.TransferEncoding) > 0 && r.TransferEncoding[0] == "chunked")
+	})
+	defer server.Close()
+
+	client := createTestHTTPClient(server.URL)
+	ch := make(chan []byte)
+	go func() {
+		defer close(ch)
+		ch <- []byte("hello ")
+		ch <- []byte("world")
+	}()
+	records := func(yield func(map[string]int) bool) {
+		for i := 0; i < 2; i++ {
+			if !yield(map[string]int{"n": i}) {
+				return
+			}
+		}
+	}
+	rows := func(yield func([]string) bool) {
+		yield([]string{"a", "b,c"})
+	}
+
+	for _, body := range []io.ReadCloser{ChannelBody(ch), NDJSONBody(records), CSVBody(rows)} {
+		resp, err := client.Post(context.Background(), "/ingest", body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+
+	expected := []string{"hello world", "{\"n\":0}\n{\"n\":1}\n", "a,\"b,c\"\n"}
+	if strings.Join(received, "|") != strings.Join(expected, "|") {
+		t.Errorf("Expected %q but got %q", expected, received)
+	}
+	for i, ok := range chunked {
+		if !ok {
+			t.Errorf("Expected body %d to be sent chunked", i)
+		}
+	}
+}
+
+func TestNDJSONBody_StopsOnClose(t *testing.T) {
+	stopped := make(chan struct{})
+	body := NDJSONBody(func(yield func(int) bool) {
+		defer close(stopped)
+		for i := 0; yield(i); i++ {
+		}
+	})
+
+	buf := make([]byte, 4)
+	body.Read(buf)
+	body.Close()
+	<-stopped
+}
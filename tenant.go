@@ -0,0 +1,78 @@
+package http
+
+import (
+	"context"
+	"fmt"
+)
+
+// TenantResolver resolves the base URL and credentials to use for a tenant.
+type TenantResolver interface {
+	ResolveTenant(ctx context.Context, tenantID string) (Environment, error)
+}
+
+// TenantResolverFunc adapts a function to the TenantResolver interface.
+type TenantResolverFunc func(ctx context.Context, tenantID string) (Environment, error)
+
+func (f TenantResolverFunc) ResolveTenant(ctx context.Context, tenantID string) (Environment, error) {
+	return f(ctx, tenantID)
+}
+
+// TenantError is returned when the tenant of a request can not be resolved.
+type TenantError struct {
+	Message  string
+	TenantID string
+	err      error
+}
+
+func (e TenantError) Error() string {
+	return e.Message
+}
+
+func (e TenantError) Unwrap() error {
+	return e.err
+}
+
+type tenantKey struct{}
+
+// WithTenant returns a context carrying the tenant ID used to resolve base URL and credentials.
+func WithTenant(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenantID)
+}
+
+// TenantFromContext returns the tenant ID stored in ctx, if any.
+func TenantFromContext(ctx context.Context) (string, bool) {
+	if ctx == nil {
+		return "", false
+	}
+	tenantID, ok := ctx.Value(tenantKey{}).(string)
+	return tenantID, ok
+}
+
+// SetTenantResolver sets the resolver consulted for requests whose context carries a tenant ID.
+func (h *HttpClient) SetTenantResolver(resolver TenantResolver) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.tenantResolver = resolver
+}
+
+// resolveTenant resolves the tenant of ctx. Credentials never fall back to the client's own,
+// only an empty base URL is completed from fallbackURL.
+func (h *HttpClient) resolveTenant(ctx context.Context, tenantID string, fallbackURL string) (string, string, string, error) {
+	h.mu.RLock()
+	resolver := h.tenantResolver
+	h.mu.RUnlock()
+
+	if resolver == nil {
+		return "", "", "", &TenantError{Message: fmt.Sprintf("No resolver for tenant %q configured.", tenantID), TenantID: tenantID}
+	}
+
+	env, err := resolver.ResolveTenant(ctx, tenantID)
+	if err != nil {
+		return "", "", "", &TenantError{Message: fmt.Sprintf("Tenant %q could not be resolved.", tenantID), TenantID: tenantID, err: err}
+	}
+
+	if env.BaseURL == "" {
+		env.BaseURL = fallbackURL
+	}
+	return env.BaseURL, env.Username, env.Password, nil
+}
@@ -0,0 +1,81 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestHttpClient_TenantFromContext(t *testing.T) {
+	var username, password string
+	f := func(w http.ResponseWriter, r *http.Request) {
+		username, password, _ = r.BasicAuth()
+		w.WriteHeader(http.StatusOK)
+	}
+	server := mockServerWith(http.HandlerFunc(f))
+	defer server.Close()
+
+	client := NewHttpClientWithConfig(NewHttpConfig(server.URL, "shared", "secret", contentTypeJSON))
+	client.SetTenantResolver(TenantResolverFunc(func(ctx context.Context, tenantID string) (Environment, error) {
+		return Environment{Username: tenantID, Password: tenantID + "-secret"}, nil
+	}))
+
+	resp, err := client.GetFromWithContext(WithTenant(context.Background(), "acme"), "")
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	assertResponseHasStatus(resp, http.StatusOK, t)
+	if username != "acme" || password != "acme-secret" {
+		t.Errorf("Expected tenant credentials but got %s:%s", username, password)
+	}
+}
+
+func TestHttpClient_TenantWithoutResolver(t *testing.T) {
+	client := createTestHTTPClient(fixtureBaseURL)
+
+	_, err := client.GetFromWithContext(WithTenant(context.Background(), "acme"), "")
+
+	var tenantErr *TenantError
+	if !errors.As(err, &tenantErr) || tenantErr.TenantID != "acme" {
+		t.Errorf("Expected TenantError but got %v", err)
+	}
+}
+
+func TestHttpClient_TenantResolverFails(t *testing.T) {
+	resolveErr := errors.New("unknown tenant")
+	client := createTestHTTPClient(fixtureBaseURL)
+	client.SetTenantResolver(TenantResolverFunc(func(ctx context.Context, tenantID string) (Environment, error) {
+		return Environment{}, resolveErr
+	}))
+
+	_, err := client.GetFromWithContext(WithTenant(context.Background(), "acme"), "")
+
+	if !errors.Is(err, resolveErr) {
+		t.Errorf("Expected wrapped resolver error but got %v", err)
+	}
+}
+
+func TestHttpClient_TenantWithoutClientAuthProvider(t *testing.T) {
+	var authorization string
+	server := mockServerWith(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+	})
+	defer server.Close()
+
+	client := NewHttpClientWithConfig(NewDefaultHttpConfig(fixtureBaseURL, WithAuthProvider(BasicAuth(StaticCredentials("shared", "secret")))))
+	client.SetTenantResolver(TenantResolverFunc(func(ctx context.Context, tenantID string) (Environment, error) {
+		return Environment{BaseURL: server.URL}, nil
+	}))
+
+	resp, err := client.Get(WithTenant(context.Background(), "acme"), "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if authorization != "" {
+		t.Errorf("Expected no credentials for the tenant host but got %q", authorization)
+	}
+}
@@ -0,0 +1,90 @@
+package testserver
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+)
+
+// Protocol is the HTTP version a test server or transport is restricted to.
+type Protocol int
+
+const (
+	HTTP1 Protocol = iota + 1
+	HTTP2
+)
+
+// NewProtocolServer starts a TLS server speaking only protocol. The server's Client negotiates it.
+func NewProtocolServer(protocol Protocol, handler http.Handler) *httptest.Server {
+	server := httptest.NewUnstartedServer(handler)
+	server.EnableHTTP2 = protocol == HTTP2
+	if protocol == HTTP1 {
+		server.TLS = &tls.Config{NextProtos: []string{"http/1.1"}}
+	}
+	server.StartTLS()
+	return server
+}
+
+// ForceProtocol restricts transport to protocol, e.g. to test HTTP/1.1 against a server preferring
+// HTTP/2. It modifies transport, so it must be called before the transport is used.
+func ForceProtocol(transport *http.Transport, protocol Protocol) {
+	switch protocol {
+	case HTTP1:
+		// a non-nil, empty map disables the automatic HTTP/2 upgrade
+		transport.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+		transport.ForceAttemptHTTP2 = false
+		if transport.TLSClientConfig != nil {
+			transport.TLSClientConfig.NextProtos = []string{"http/1.1"}
+		}
+	case HTTP2:
+		transport.ForceAttemptHTTP2 = true
+		transport.TLSNextProto = nil
+	}
+}
+
+// GoAwayHandler passes requests to next and closes the connection afterwards: HTTP/2 connections receive
+// a GOAWAY frame, HTTP/1.1 connections a "Connection: close". The client has to open a new connection for
+// the following request. A nil next answers 200 without a body.
+func GoAwayHandler(next http.Handler) http.Handler {
+	next = orOK(next)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// net/http translates this header into a GOAWAY for HTTP/2
+		w.Header().Set("Connection", "close")
+		next.ServeHTTP(w, r)
+	})
+}
+
+// ResetAfter passes requests to next but aborts the response once n body bytes have been sent: HTTP/2
+// streams are reset, HTTP/1.1 connections are closed. The client sees the headers and the first n bytes
+// before reading the body fails. A nil next answers 200 without a body, which is never reset.
+func ResetAfter(n int, next http.Handler) http.Handler {
+	next = orOK(next)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(&resettingWriter{ResponseWriter: w, remaining: n}, r)
+	})
+}
+
+type resettingWriter struct {
+	http.ResponseWriter
+	remaining int
+}
+
+func (w *resettingWriter) Write(p []byte) (int, error) {
+	if len(p) < w.remaining {
+		w.remaining -= len(p)
+		return w.ResponseWriter.Write(p)
+	}
+
+	w.ResponseWriter.Write(p[:w.remaining])
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+	// aborts the handler without logging and resets the stream or closes the connection
+	panic(http.ErrAbortHandler)
+}
+
+func (w *resettingWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
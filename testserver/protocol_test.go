@@ -0,0 +1,87 @@
+package testserver
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"strings"
+	"testing"
+)
+
+func TestNewProtocolServer(t *testing.T) {
+	for protocol, major := range map[Protocol]int{HTTP1: 1, HTTP2: 2} {
+		server := NewProtocolServer(protocol, nil)
+
+		resp, err := server.Client().Get(server.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.ProtoMajor != major {
+			t.Errorf("Expected HTTP/%d but got %s", major, resp.Proto)
+		}
+		server.Close()
+	}
+}
+
+func TestForceProtocol(t *testing.T) {
+	server := NewProtocolServer(HTTP2, nil)
+	defer server.Close()
+
+	client := server.Client()
+	transport := client.Transport.(*http.Transport).Clone()
+	ForceProtocol(transport, HTTP1)
+	client.Transport = transport
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.ProtoMajor != 1 {
+		t.Errorf("Expected HTTP/1.1 but got %s", resp.Proto)
+	}
+}
+
+func TestGoAwayHandler(t *testing.T) {
+	for _, protocol := range []Protocol{HTTP1, HTTP2} {
+		server := NewProtocolServer(protocol, GoAwayHandler(nil))
+		client := server.Client()
+
+		var reused []bool
+		for i := 0; i < 2; i++ {
+			trace := &httptrace.ClientTrace{GotConn: func(info httptrace.GotConnInfo) { reused = append(reused, info.Reused) }}
+			req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+			resp, err := client.Do(req.WithContext(httptrace.WithClientTrace(req.Context(), trace)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			io.ReadAll(resp.Body)
+			resp.Body.Close()
+		}
+		if len(reused) != 2 || reused[1] {
+			t.Errorf("Expected a new connection after GOAWAY for protocol %d but got %v", protocol, reused)
+		}
+		server.Close()
+	}
+}
+
+func TestResetAfter(t *testing.T) {
+	body := strings.Repeat("x", 1000)
+	for _, protocol := range []Protocol{HTTP1, HTTP2} {
+		server := NewProtocolServer(protocol, ResetAfter(100, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(body))
+		})))
+
+		resp, err := server.Client().Get(server.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		read, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err == nil || len(read) != 100 {
+			t.Errorf("Expected reset after 100 bytes for protocol %d but read %d bytes: %v", protocol, len(read), err)
+		}
+		server.Close()
+	}
+}
@@ -0,0 +1,132 @@
+package testserver
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// RecordedRequest is a copy of a request received by a Recorder.
+type RecordedRequest struct {
+	Method string
+	Path   string
+	Query  map[string][]string
+	Header http.Header
+	Body   []byte
+}
+
+// Recorder is an http.Handler recording the requests it receives before passing them on.
+type Recorder struct {
+	next http.Handler
+
+	mu       sync.Mutex
+	requests []RecordedRequest
+}
+
+// NewRecorder creates a Recorder passing requests to next. A nil next answers 200 without a body.
+func NewRecorder(next http.Handler) *Recorder {
+	return &Recorder{next: orOK(next)}
+}
+
+// NewRecordingServer starts a server recording the requests before passing them to next.
+func NewRecordingServer(next http.Handler) (*httptest.Server, *Recorder) {
+	recorder := NewRecorder(next)
+	return httptest.NewServer(recorder), recorder
+}
+
+func (rec *Recorder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	r.Body.Close()
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	rec.mu.Lock()
+	rec.requests = append(rec.requests, RecordedRequest{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Query:  r.URL.Query(),
+		Header: r.Header.Clone(),
+		Body:   body,
+	})
+	rec.mu.Unlock()
+
+	rec.next.ServeHTTP(w, r)
+}
+
+// Requests returns the recorded requests in the order they were received.
+func (rec *Recorder) Requests() []RecordedRequest {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	return append([]RecordedRequest(nil), rec.requests...)
+}
+
+// Count returns the number of recorded requests.
+func (rec *Recorder) Count() int {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	return len(rec.requests)
+}
+
+// Last returns the most recent request, or false if none was recorded.
+func (rec *Recorder) Last() (RecordedRequest, bool) {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	if len(rec.requests) == 0 {
+		return RecordedRequest{}, false
+	}
+	return rec.requests[len(rec.requests)-1], true
+}
+
+// Reset discards the recorded requests.
+func (rec *Recorder) Reset() {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	rec.requests = nil
+}
+
+// AssertCount fails t unless exactly n requests were recorded.
+func (rec *Recorder) AssertCount(t testing.TB, n int) {
+	t.Helper()
+	if count := rec.Count(); count != n {
+		t.Errorf("Expected %d requests but got %d", n, count)
+	}
+}
+
+// AssertReceived fails t unless a request with method and path was recorded.
+func (rec *Recorder) AssertReceived(t testing.TB, method string, path string) {
+	t.Helper()
+	for _, r := range rec.Requests() {
+		if r.Method == method && r.Path == path {
+			return
+		}
+	}
+	t.Errorf("Expected a %s %s request", method, path)
+}
+
+// AssertHeader fails t unless the last request carried header with value.
+func (rec *Recorder) AssertHeader(t testing.TB, header string, value string) {
+	t.Helper()
+	last, ok := rec.Last()
+	if !ok {
+		t.Errorf("Expected a request with %s: %s but got none", header, value)
+		return
+	}
+	if actual := last.Header.Get(header); actual != value {
+		t.Errorf("Expected header %s to be %q but got %q", header, value, actual)
+	}
+}
+
+// AssertBody fails t unless the body of the last request was body.
+func (rec *Recorder) AssertBody(t testing.TB, body string) {
+	t.Helper()
+	last, ok := rec.Last()
+	if !ok {
+		t.Errorf("Expected a request with body %q but got none", body)
+		return
+	}
+	if string(last.Body) != body {
+		t.Errorf("Expected body %q but got %q", body, last.Body)
+	}
+}
@@ -0,0 +1,41 @@
+package testserver
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestRecorder(t *testing.T) {
+	server, recorder := NewRecordingServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the body is still readable after recording
+		body, _ := io.ReadAll(r.Body)
+		w.Write(body)
+	}))
+	defer server.Close()
+
+	http.Get(server.URL + "/health")
+	req, _ := http.NewRequest(http.MethodPut, server.URL+"/users/1", strings.NewReader(`{"id":1}`))
+	req.Header.Set("X-Tenant", "acme")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	if string(body) != `{"id":1}` {
+		t.Errorf("Expected body to be passed on but got %q", body)
+	}
+	recorder.AssertCount(t, 2)
+	recorder.AssertReceived(t, http.MethodGet, "/health")
+	recorder.AssertReceived(t, http.MethodPut, "/users/1")
+	recorder.AssertHeader(t, "X-Tenant", "acme")
+	recorder.AssertBody(t, `{"id":1}`)
+
+	recorder.Reset()
+	if _, ok := recorder.Last(); ok || recorder.Count() != 0 {
+		t.Error("Expected no requests after Reset")
+	}
+}
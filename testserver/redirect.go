@@ -0,0 +1,78 @@
+package testserver
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+)
+
+// RedirectHandler redirects every request to location with status, e.g. 301 or 307.
+func RedirectHandler(status int, location string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, location, status)
+	})
+}
+
+// RedirectChainHandler answers a request to "/" with a redirect of statuses[0] to "/hop/1", which is
+// redirected with statuses[1] to "/hop/2", and so on. The last hop is passed to final, together with any
+// path not belonging to the chain. A nil final answers 200 without a body.
+func RedirectChainHandler(final http.Handler, statuses ...int) http.Handler {
+	final = orOK(final)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hop, ok := chainHop(r.URL.Path)
+		if !ok || hop >= len(statuses) {
+			final.ServeHTTP(w, r)
+			return
+		}
+		http.Redirect(w, r, fmt.Sprintf("/hop/%d", hop+1), statuses[hop])
+	})
+}
+
+// NewRedirectChainServer starts a server with RedirectChainHandler.
+func NewRedirectChainServer(final http.Handler, statuses ...int) *httptest.Server {
+	return httptest.NewServer(RedirectChainHandler(final, statuses...))
+}
+
+func chainHop(path string) (int, bool) {
+	if path == "/" {
+		return 0, true
+	}
+	if !strings.HasPrefix(path, "/hop/") {
+		return 0, false
+	}
+	hop, err := strconv.Atoi(strings.TrimPrefix(path, "/hop/"))
+	return hop, err == nil && hop > 0
+}
+
+// CrossHostRedirectHandler redirects every request with status to the same path and query on target,
+// e.g. the URL of another test server. Clients must not forward credentials across such a redirect.
+func CrossHostRedirectHandler(status int, target string) http.Handler {
+	target = strings.TrimSuffix(target, "/")
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, target+r.URL.RequestURI(), status)
+	})
+}
+
+// NewCrossHostRedirectServer starts a server with CrossHostRedirectHandler.
+func NewCrossHostRedirectServer(status int, target string) *httptest.Server {
+	return httptest.NewServer(CrossHostRedirectHandler(status, target))
+}
+
+// RedirectLoopHandler redirects "/loop/a" to "/loop/b" and every other path to "/loop/a", so a client
+// following redirects never arrives.
+func RedirectLoopHandler(status int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		location := "/loop/a"
+		if r.URL.Path == location {
+			location = "/loop/b"
+		}
+		http.Redirect(w, r, location, status)
+	})
+}
+
+// NewRedirectLoopServer starts a server with RedirectLoopHandler.
+func NewRedirectLoopServer(status int) *httptest.Server {
+	return httptest.NewServer(RedirectLoopHandler(status))
+}
@@ -0,0 +1,62 @@
+package testserver
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestRedirectChainServer(t *testing.T) {
+	server, recorder := NewRecordingServer(RedirectChainHandler(nil, 301, 302, 303, 307, 308))
+	defer server.Close()
+
+	resp, err := http.Post(server.URL, "text/plain", strings.NewReader("body"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK || resp.Request.URL.Path != "/hop/5" {
+		t.Errorf("Expected to arrive at /hop/5 but got %d %s", resp.StatusCode, resp.Request.URL)
+	}
+	// 301, 302 and 303 turn the POST into a GET, 307 and 308 keep the method
+	var methods []string
+	for _, r := range recorder.Requests() {
+		methods = append(methods, r.Method)
+	}
+	if strings.Join(methods, " ") != "POST GET GET GET GET GET" {
+		t.Errorf("Unexpected methods %v", methods)
+	}
+}
+
+func TestCrossHostRedirectServer(t *testing.T) {
+	target, recorder := NewRecordingServer(nil)
+	defer target.Close()
+	server := NewCrossHostRedirectServer(http.StatusTemporaryRedirect, strings.Replace(target.URL, "127.0.0.1", "localhost", 1))
+	defer server.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/users?page=2", nil)
+	req.SetBasicAuth("user", "secret")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	last, ok := recorder.Last()
+	if !ok || last.Path != "/users" || last.Query["page"][0] != "2" {
+		t.Fatalf("Expected redirect to the target but got %+v", last)
+	}
+	if last.Header.Get("Authorization") != "" {
+		t.Error("Expected credentials to be stripped on a cross-host redirect")
+	}
+}
+
+func TestRedirectLoopServer(t *testing.T) {
+	server := NewRedirectLoopServer(http.StatusFound)
+	defer server.Close()
+
+	if _, err := http.Get(server.URL); err == nil || !strings.Contains(err.Error(), "stopped after 10 redirects") {
+		t.Errorf("Expected redirect loop to be detected but got %v", err)
+	}
+}
@@ -0,0 +1,149 @@
+package testserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+)
+
+// ResponseBuilder builds fake *http.Response values for stub transports, without a server or
+// httptest.ResponseRecorder:
+//
+//	resp := testserver.NewResponse(http.StatusCreated).JSON(user).Header("Location", "/users/1").Build(req)
+type ResponseBuilder struct {
+	status  int
+	header  http.Header
+	trailer http.Header
+	body    []byte
+}
+
+// NewResponse creates a ResponseBuilder for a response with status and no body.
+func NewResponse(status int) *ResponseBuilder {
+	return &ResponseBuilder{status: status, header: http.Header{}}
+}
+
+// Header adds a header value.
+func (b *ResponseBuilder) Header(key string, value string) *ResponseBuilder {
+	b.header.Add(key, value)
+	return b
+}
+
+// Trailer adds a trailer value. Like with a real response, trailers are only available once the body
+// has been read to the end.
+func (b *ResponseBuilder) Trailer(key string, value string) *ResponseBuilder {
+	if b.trailer == nil {
+		b.trailer = http.Header{}
+	}
+	b.trailer.Add(key, value)
+	return b
+}
+
+// Body sets the body.
+func (b *ResponseBuilder) Body(body string) *ResponseBuilder {
+	b.body = []byte(body)
+	return b
+}
+
+// JSON sets the body to v encoded as JSON, and the Content-Type accordingly. It panics if v can not be
+// encoded.
+func (b *ResponseBuilder) JSON(v interface{}) *ResponseBuilder {
+	body, err := json.Marshal(v)
+	if err != nil {
+		panic(fmt.Sprintf("encoding response body: %v", err))
+	}
+	b.body = body
+	b.header.Set("Content-Type", "application/json")
+	return b
+}
+
+// Build returns a new response answering req. Every call returns a fresh, unread body.
+func (b *ResponseBuilder) Build(req *http.Request) *http.Response {
+	resp := &http.Response{
+		Status:        fmt.Sprintf("%d %s", b.status, http.StatusText(b.status)),
+		StatusCode:    b.status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        b.header.Clone(),
+		ContentLength: int64(len(b.body)),
+		Request:       req,
+	}
+	resp.Header.Set("Content-Length", strconv.Itoa(len(b.body)))
+
+	var body io.Reader = bytes.NewReader(b.body)
+	if b.trailer != nil {
+		// announce the trailer keys and fill in the values at EOF, like net/http does
+		resp.Trailer = http.Header{}
+		for key := range b.trailer {
+			resp.Trailer[key] = nil
+		}
+		resp.ContentLength = -1
+		resp.TransferEncoding = []string{"chunked"}
+		resp.Header.Del("Content-Length")
+		body = &trailerReader{Reader: body, trailer: b.trailer, target: resp.Trailer}
+	}
+	resp.Body = io.NopCloser(body)
+	return resp
+}
+
+type trailerReader struct {
+	io.Reader
+	trailer http.Header
+	target  http.Header
+}
+
+func (r *trailerReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	if err == io.EOF && r.trailer != nil {
+		for key, values := range r.trailer {
+			r.target[key] = values
+		}
+		r.trailer = nil
+	}
+	return n, err
+}
+
+// StubTransport is an http.RoundTripper answering the n-th request with the n-th response, without any
+// network access. Once the responses are exhausted the last one is repeated.
+type StubTransport struct {
+	mu        sync.Mutex
+	responses []*ResponseBuilder
+	requests  []*http.Request
+}
+
+// NewStubTransport creates a StubTransport answering with responses. It panics without responses.
+func NewStubTransport(responses ...*ResponseBuilder) *StubTransport {
+	if len(responses) == 0 {
+		panic("no responses")
+	}
+	return &StubTransport{responses: responses}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (s *StubTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		io.Copy(io.Discard, req.Body)
+		req.Body.Close()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	next := len(s.requests)
+	if next >= len(s.responses) {
+		next = len(s.responses) - 1
+	}
+	s.requests = append(s.requests, req)
+	return s.responses[next].Build(req), nil
+}
+
+// Requests returns the requests received so far. Their bodies have been consumed.
+func (s *StubTransport) Requests() []*http.Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*http.Request(nil), s.requests...)
+}
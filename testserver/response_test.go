@@ -0,0 +1,64 @@
+package testserver
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+)
+
+func TestResponseBuilder(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, "https://example.com/users/1", nil)
+	builder := NewResponse(http.StatusCreated).
+		JSON(map[string]int{"id": 1}).
+		Header("Location", "/users/1").
+		Trailer("X-Checksum", "abc")
+
+	resp := builder.Build(req)
+	if resp.StatusCode != http.StatusCreated || resp.Status != "201 Created" || resp.Request != req {
+		t.Errorf("Unexpected response %+v", resp)
+	}
+	if resp.Header.Get("Content-Type") != "application/json" || resp.Header.Get("Location") != "/users/1" {
+		t.Errorf("Unexpected headers %v", resp.Header)
+	}
+	if _, announced := resp.Trailer["X-Checksum"]; !announced || resp.Trailer.Get("X-Checksum") != "" {
+		t.Errorf("Expected trailer to be announced but not set before reading the body, got %v", resp.Trailer)
+	}
+
+	var body map[string]int
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil || body["id"] != 1 {
+		t.Errorf("Unexpected body %v: %v", body, err)
+	}
+	io.ReadAll(resp.Body)
+	if resp.Trailer.Get("X-Checksum") != "abc" {
+		t.Errorf("Expected trailer after reading the body but got %v", resp.Trailer)
+	}
+
+	// every build has its own body
+	second, _ := io.ReadAll(builder.Build(req).Body)
+	if string(second) != `{"id":1}` {
+		t.Errorf("Expected fresh body but got %q", second)
+	}
+}
+
+func TestStubTransport(t *testing.T) {
+	transport := NewStubTransport(
+		NewResponse(http.StatusServiceUnavailable),
+		NewResponse(http.StatusOK).Body("ok"),
+	)
+	client := &http.Client{Transport: transport}
+
+	for _, expected := range []int{503, 200, 200} {
+		resp, err := client.Get("https://example.com/health")
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != expected {
+			t.Errorf("Expected %d but got %d", expected, resp.StatusCode)
+		}
+	}
+	if len(transport.Requests()) != 3 {
+		t.Errorf("Expected 3 requests but got %d", len(transport.Requests()))
+	}
+}
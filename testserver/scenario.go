@@ -0,0 +1,166 @@
+package testserver
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+// Matcher reports whether a request is the one a Step expects.
+type Matcher func(*http.Request) bool
+
+// HasHeader matches requests carrying header.
+func HasHeader(header string) Matcher {
+	return func(r *http.Request) bool {
+		return r.Header.Get(header) != ""
+	}
+}
+
+// HeaderIs matches requests whose header has value.
+func HeaderIs(header string, value string) Matcher {
+	return func(r *http.Request) bool {
+		return r.Header.Get(header) == value
+	}
+}
+
+// MethodPath matches requests with method and path.
+func MethodPath(method string, path string) Matcher {
+	return func(r *http.Request) bool {
+		return r.Method == method && r.URL.Path == path
+	}
+}
+
+// All matches requests matched by all matchers.
+func All(matchers ...Matcher) Matcher {
+	return func(r *http.Request) bool {
+		for _, match := range matchers {
+			if !match(r) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Step is a stage of a Scenario answering one or more requests.
+type Step struct {
+	// Name identifies the step in failure messages.
+	Name string
+	// Match checks the request the step receives. A nil Match accepts any request.
+	Match Matcher
+	// Response is sent for each request of the step.
+	Response Response
+	// Times is the number of requests the step answers before the scenario moves on. Zero means once.
+	Times int
+}
+
+// Challenge returns a 401 response asking for authentication with scheme, e.g. "Basic".
+func Challenge(scheme string, realm string) Response {
+	return Response{
+		Status: http.StatusUnauthorized,
+		Header: http.Header{"Www-Authenticate": {fmt.Sprintf("%s realm=%q", scheme, realm)}},
+	}
+}
+
+// TooManyRequests returns a 429 response asking to retry after seconds.
+func TooManyRequests(seconds int) Response {
+	return Response{
+		Status: http.StatusTooManyRequests,
+		Header: http.Header{"Retry-After": {strconv.Itoa(seconds)}},
+	}
+}
+
+// Scenario is an http.Handler playing through its steps in order, e.g. a 429 with Retry-After followed
+// by a success, to test the client's retry, authentication and backoff behavior end to end.
+// Requests not matching the current step, or arriving after the last step, are answered with the
+// fallback response and reported by AssertComplete.
+type Scenario struct {
+	mu       sync.Mutex
+	steps    []Step
+	current  int
+	answered int
+	fallback Response
+	failures []string
+}
+
+// NewScenario creates a Scenario playing steps. The fallback response is a 500.
+func NewScenario(steps ...Step) *Scenario {
+	return &Scenario{
+		steps:    steps,
+		fallback: Response{Status: http.StatusInternalServerError, Body: "unexpected request"},
+	}
+}
+
+// NewScenarioServer starts a server playing steps.
+func NewScenarioServer(steps ...Step) (*httptest.Server, *Scenario) {
+	scenario := NewScenario(steps...)
+	return httptest.NewServer(scenario), scenario
+}
+
+// WithFallback sets the response to unexpected requests.
+func (s *Scenario) WithFallback(resp Response) *Scenario {
+	s.fallback = resp
+	return s
+}
+
+func (s *Scenario) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.next(r).write(w, r)
+}
+
+func (s *Scenario) next(r *http.Request) Response {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.current >= len(s.steps) {
+		s.failures = append(s.failures, fmt.Sprintf("unexpected %s %s after the last step", r.Method, r.URL.Path))
+		return s.fallback
+	}
+
+	step := s.steps[s.current]
+	if step.Match != nil && !step.Match(r) {
+		s.failures = append(s.failures, fmt.Sprintf("%s %s does not match step %s", r.Method, r.URL.Path, s.stepName(s.current)))
+		return s.fallback
+	}
+
+	s.answered++
+	times := step.Times
+	if times <= 0 {
+		times = 1
+	}
+	if s.answered >= times {
+		s.current++
+		s.answered = 0
+	}
+	return step.Response
+}
+
+func (s *Scenario) stepName(i int) string {
+	if s.steps[i].Name != "" {
+		return strconv.Quote(s.steps[i].Name)
+	}
+	return strconv.Itoa(i + 1)
+}
+
+// Done reports whether all steps have been played.
+func (s *Scenario) Done() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.current >= len(s.steps)
+}
+
+// AssertComplete fails t unless all steps have been played and no unexpected request was received.
+func (s *Scenario) AssertComplete(t testing.TB) {
+	t.Helper()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, failure := range s.failures {
+		t.Error(failure)
+	}
+	if s.current < len(s.steps) {
+		t.Errorf("Scenario stopped at step %s of %d", s.stepName(s.current), len(s.steps))
+	}
+}
@@ -0,0 +1,61 @@
+package testserver
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestScenario(t *testing.T) {
+	server, scenario := NewScenarioServer(
+		Step{Name: "challenge", Response: Challenge("Basic", "api")},
+		Step{Name: "authorized", Match: HasHeader("Authorization"), Response: TooManyRequests(1)},
+		Step{Name: "success", Match: All(HasHeader("Authorization"), MethodPath(http.MethodGet, "/users")), Times: 2},
+	)
+	defer server.Close()
+
+	get := func(auth bool) *http.Response {
+		req, _ := http.NewRequest(http.MethodGet, server.URL+"/users", nil)
+		if auth {
+			req.SetBasicAuth("user", "secret")
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp
+	}
+
+	if resp := get(false); resp.StatusCode != http.StatusUnauthorized || resp.Header.Get("WWW-Authenticate") != `Basic realm="api"` {
+		t.Errorf("Expected challenge but got %d %v", resp.StatusCode, resp.Header)
+	}
+	if resp := get(true); resp.StatusCode != http.StatusTooManyRequests || resp.Header.Get("Retry-After") != "1" {
+		t.Errorf("Expected 429 but got %d %v", resp.StatusCode, resp.Header)
+	}
+	for i := 0; i < 2; i++ {
+		if resp := get(true); resp.StatusCode != http.StatusOK {
+			t.Errorf("Expected 200 but got %d", resp.StatusCode)
+		}
+	}
+
+	if !scenario.Done() {
+		t.Error("Expected scenario to be done")
+	}
+	scenario.AssertComplete(t)
+}
+
+func TestScenario_Unexpected(t *testing.T) {
+	server, scenario := NewScenarioServer(Step{Name: "authorized", Match: HasHeader("Authorization")})
+	defer server.Close()
+	scenario.WithFallback(Response{Status: http.StatusTeapot})
+
+	resp, _ := http.Get(server.URL)
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusTeapot || scenario.Done() {
+		t.Errorf("Expected fallback but got %d", resp.StatusCode)
+	}
+	if len(scenario.failures) != 1 {
+		t.Errorf("Expected a failure but got %v", scenario.failures)
+	}
+}
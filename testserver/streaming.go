@@ -0,0 +1,166 @@
+package testserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+)
+
+// SSEEvent is a server-sent event.
+type SSEEvent struct {
+	ID    string
+	Event string
+	Data  string
+	// Retry tells the client how long to wait before reconnecting.
+	Retry time.Duration
+}
+
+func (e SSEEvent) String() string {
+	var b strings.Builder
+	if e.ID != "" {
+		fmt.Fprintf(&b, "id: %s\n", e.ID)
+	}
+	if e.Event != "" {
+		fmt.Fprintf(&b, "event: %s\n", e.Event)
+	}
+	if e.Retry > 0 {
+		fmt.Fprintf(&b, "retry: %d\n", e.Retry.Milliseconds())
+	}
+	for _, line := range strings.Split(e.Data, "\n") {
+		fmt.Fprintf(&b, "data: %s\n", line)
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+// SSEStream is an http.Handler emitting a text/event-stream. A client reconnecting with a Last-Event-ID
+// header resumes after that event.
+type SSEStream struct {
+	Events []SSEEvent
+	// Interval is waited before each event.
+	Interval time.Duration
+	// DropAfter ends each connection after that many events to make the client reconnect. Zero sends all
+	// events on one connection.
+	DropAfter int
+}
+
+// NewSSEServer starts a server with stream.
+func NewSSEServer(stream SSEStream) *httptest.Server {
+	return httptest.NewServer(stream)
+}
+
+func (s SSEStream) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	events := s.Events
+	if last := r.Header.Get("Last-Event-ID"); last != "" {
+		for i, event := range events {
+			if event.ID == last {
+				events = events[i+1:]
+				break
+			}
+		}
+	}
+	if s.DropAfter > 0 && len(events) > s.DropAfter {
+		events = events[:s.DropAfter]
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flush(w)
+
+	for _, event := range events {
+		if !wait(r, s.Interval) {
+			return
+		}
+		fmt.Fprint(w, event)
+		flush(w)
+	}
+}
+
+// NDJSONHandler streams values as newline-delimited JSON, one flushed line every interval.
+// It panics if a value can not be encoded.
+func NDJSONHandler(interval time.Duration, values ...interface{}) http.Handler {
+	lines := make([][]byte, len(values))
+	for i, v := range values {
+		line, err := json.Marshal(v)
+		if err != nil {
+			panic(fmt.Sprintf("encoding value %d: %v", i, err))
+		}
+		lines[i] = append(line, '\n')
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.WriteHeader(http.StatusOK)
+		flush(w)
+
+		for _, line := range lines {
+			if !wait(r, interval) {
+				return
+			}
+			w.Write(line)
+			flush(w)
+		}
+	})
+}
+
+// NewNDJSONServer starts a server with NDJSONHandler.
+func NewNDJSONServer(interval time.Duration, values ...interface{}) *httptest.Server {
+	return httptest.NewServer(NDJSONHandler(interval, values...))
+}
+
+// SlowDripHandler sends body in chunks of chunkSize bytes, waiting interval before each chunk, e.g. to
+// test read timeouts and partial reads.
+func SlowDripHandler(body []byte, chunkSize int, interval time.Duration) http.Handler {
+	if chunkSize <= 0 {
+		panic("chunkSize must be positive")
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		flush(w)
+
+		for rest := body; len(rest) > 0; {
+			if !wait(r, interval) {
+				return
+			}
+			n := chunkSize
+			if n > len(rest) {
+				n = len(rest)
+			}
+			w.Write(rest[:n])
+			flush(w)
+			rest = rest[n:]
+		}
+	})
+}
+
+// NewSlowDripServer starts a server with SlowDripHandler.
+func NewSlowDripServer(body []byte, chunkSize int, interval time.Duration) *httptest.Server {
+	return httptest.NewServer(SlowDripHandler(body, chunkSize, interval))
+}
+
+// wait waits for d and reports false if the client went away in the meantime.
+func wait(r *http.Request, d time.Duration) bool {
+	if d <= 0 {
+		return r.Context().Err() == nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-r.Context().Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+func flush(w http.ResponseWriter) {
+	if flusher, ok := w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
@@ -0,0 +1,102 @@
+package testserver
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSSEStream(t *testing.T) {
+	server := NewSSEServer(SSEStream{
+		Events: []SSEEvent{
+			{ID: "1", Event: "greeting", Data: "hello\nworld", Retry: time.Second},
+			{ID: "2", Data: "second"},
+			{ID: "3", Data: "third"},
+		},
+		DropAfter: 2,
+	})
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	expected := "id: 1\nevent: greeting\nretry: 1000\ndata: hello\ndata: world\n\nid: 2\ndata: second\n\n"
+	if resp.Header.Get("Content-Type") != "text/event-stream" || string(body) != expected {
+		t.Errorf("Unexpected stream %q", body)
+	}
+
+	// reconnecting resumes after the last received event
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	req.Header.Set("Last-Event-ID", "2")
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ = io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(body) != "id: 3\ndata: third\n\n" {
+		t.Errorf("Unexpected resumed stream %q", body)
+	}
+}
+
+func TestNDJSONHandler(t *testing.T) {
+	server := NewNDJSONServer(10*time.Millisecond, map[string]int{"id": 1}, map[string]int{"id": 2})
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	// the first line can be decoded before the stream is complete
+	reader := bufio.NewReader(resp.Body)
+	var ids []int
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			break
+		}
+		var value map[string]int
+		if err := json.Unmarshal(line, &value); err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, value["id"])
+	}
+	if len(ids) != 2 || ids[0] != 1 || ids[1] != 2 {
+		t.Errorf("Unexpected values %v", ids)
+	}
+}
+
+func TestSlowDripHandler(t *testing.T) {
+	server := NewSlowDripServer([]byte("abcdefgh"), 3, 20*time.Millisecond)
+	defer server.Close()
+
+	start := time.Now()
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	first := make([]byte, 8)
+	n, _ := resp.Body.Read(first)
+	if string(first[:n]) != "abc" {
+		t.Errorf("Expected the first chunk but got %q", first[:n])
+	}
+	rest, _ := io.ReadAll(resp.Body)
+	if string(first[:n])+string(rest) != "abcdefgh" {
+		t.Errorf("Unexpected body %q", strings.Join([]string{string(first[:n]), string(rest)}, ""))
+	}
+	if time.Since(start) < 60*time.Millisecond {
+		t.Errorf("Expected three chunks 20ms apart but took %s", time.Since(start))
+	}
+}
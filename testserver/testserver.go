@@ -0,0 +1,156 @@
+// Package testserver provides configurable HTTP servers for testing code built on the client:
+// servers echoing requests, responding slowly, failing a number of times or following a script,
+// and a recorder capturing the requests they receive.
+//
+// Every builder comes as an http.Handler, which can be composed, and as a started *httptest.Server
+// the caller has to Close. Tests which don't need a server at all can answer requests with a
+// StubTransport playing responses made by a ResponseBuilder.
+package testserver
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+)
+
+// EchoedRequest is the JSON document an echo server answers with.
+type EchoedRequest struct {
+	Method string              `json:"method"`
+	Path   string              `json:"path"`
+	Query  map[string][]string `json:"query"`
+	Header http.Header         `json:"header"`
+	Body   string              `json:"body"`
+}
+
+// EchoHandler answers every request with a JSON encoded EchoedRequest describing it.
+func EchoHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(EchoedRequest{
+			Method: r.Method,
+			Path:   r.URL.Path,
+			Query:  r.URL.Query(),
+			Header: r.Header,
+			Body:   string(body),
+		})
+	})
+}
+
+// NewEchoServer starts a server with EchoHandler.
+func NewEchoServer() *httptest.Server {
+	return httptest.NewServer(EchoHandler())
+}
+
+// DelayHandler waits for delay before passing the request to next. A nil next answers 200 without a body.
+// The delay ends early when the client gives up on the request.
+func DelayHandler(delay time.Duration, next http.Handler) http.Handler {
+	next = orOK(next)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+
+		select {
+		case <-r.Context().Done():
+			return
+		case <-timer.C:
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// NewDelayServer starts a server with DelayHandler.
+func NewDelayServer(delay time.Duration, next http.Handler) *httptest.Server {
+	return httptest.NewServer(DelayHandler(delay, next))
+}
+
+// FlakyHandler answers the first failures requests with status and passes the following ones to next.
+// A nil next answers 200 without a body.
+func FlakyHandler(failures int, status int, next http.Handler) http.Handler {
+	next = orOK(next)
+	var mu sync.Mutex
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		fail := failures > 0
+		failures--
+		mu.Unlock()
+
+		if fail {
+			http.Error(w, http.StatusText(status), status)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// NewFlakyServer starts a server with FlakyHandler.
+func NewFlakyServer(failures int, status int, next http.Handler) *httptest.Server {
+	return httptest.NewServer(FlakyHandler(failures, status, next))
+}
+
+// Response is a scripted response of a sequence server.
+type Response struct {
+	// Status defaults to 200.
+	Status int
+	Header http.Header
+	Body   string
+	// Delay is waited before responding.
+	Delay time.Duration
+}
+
+func (resp Response) write(w http.ResponseWriter, r *http.Request) {
+	if resp.Delay > 0 {
+		timer := time.NewTimer(resp.Delay)
+		defer timer.Stop()
+		select {
+		case <-r.Context().Done():
+			return
+		case <-timer.C:
+		}
+	}
+
+	for key, values := range resp.Header {
+		w.Header()[key] = values
+	}
+	status := resp.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	w.WriteHeader(status)
+	w.Write([]byte(resp.Body))
+}
+
+// SequenceHandler answers the n-th request with the n-th response. Once the script is exhausted the
+// last response is repeated. Without responses every request is answered with 200.
+func SequenceHandler(responses ...Response) http.Handler {
+	var mu sync.Mutex
+	next := 0
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		resp := Response{}
+		if len(responses) > 0 {
+			resp = responses[next]
+		}
+		if next < len(responses)-1 {
+			next++
+		}
+		mu.Unlock()
+
+		resp.write(w, r)
+	})
+}
+
+// NewSequenceServer starts a server with SequenceHandler.
+func NewSequenceServer(responses ...Response) *httptest.Server {
+	return httptest.NewServer(SequenceHandler(responses...))
+}
+
+func orOK(handler http.Handler) http.Handler {
+	if handler != nil {
+		return handler
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+}
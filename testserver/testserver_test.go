@@ -0,0 +1,92 @@
+package testserver
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEchoServer(t *testing.T) {
+	server := NewEchoServer()
+	defer server.Close()
+
+	req, _ := http.NewRequest(http.MethodPost, server.URL+"/users?page=2", strings.NewReader("hello"))
+	req.Header.Set("X-Tenant", "acme")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	var echoed EchoedRequest
+	if err := json.NewDecoder(resp.Body).Decode(&echoed); err != nil {
+		t.Fatal(err)
+	}
+	if echoed.Method != http.MethodPost || echoed.Path != "/users" || echoed.Query["page"][0] != "2" ||
+		echoed.Header.Get("X-Tenant") != "acme" || echoed.Body != "hello" {
+		t.Errorf("Unexpected echo %+v", echoed)
+	}
+}
+
+func TestDelayServer(t *testing.T) {
+	server := NewDelayServer(50*time.Millisecond, nil)
+	defer server.Close()
+
+	start := time.Now()
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if time.Since(start) < 50*time.Millisecond {
+		t.Errorf("Expected response after 50ms but got it after %s", time.Since(start))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	if _, err := http.DefaultClient.Do(req); err == nil {
+		t.Error("Expected timeout")
+	}
+}
+
+func TestFlakyServer(t *testing.T) {
+	server := NewFlakyServer(2, http.StatusServiceUnavailable, nil)
+	defer server.Close()
+
+	for _, expected := range []int{503, 503, 200, 200} {
+		resp, err := http.Get(server.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != expected {
+			t.Errorf("Expected %d but got %d", expected, resp.StatusCode)
+		}
+	}
+}
+
+func TestSequenceServer(t *testing.T) {
+	server := NewSequenceServer(
+		Response{Status: http.StatusTooManyRequests, Header: http.Header{"Retry-After": {"1"}}},
+		Response{Body: "done"},
+	)
+	defer server.Close()
+
+	for _, expected := range []string{"429 ", "200 done", "200 done"} {
+		resp, err := http.Get(server.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		if actual := strings.Fields(resp.Status)[0] + " " + string(body); actual != expected {
+			t.Errorf("Expected %q but got %q", expected, actual)
+		}
+	}
+}
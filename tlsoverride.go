@@ -0,0 +1,134 @@
+package http
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// CertificateMismatchError is returned when the server's certificate matches none of the fingerprints
+// expected by WithCertificateFingerprints. It is reported wrapped in a TLSError.
+type CertificateMismatchError struct {
+	Message     string
+	Fingerprint string
+}
+
+func (e CertificateMismatchError) Error() string {
+	return e.Message
+}
+
+// tlsOverride customizes the TLS handshake of a single request.
+type tlsOverride struct {
+	serverName   string
+	fingerprints string
+}
+
+// WithTLSServerName verifies the server's certificate against name and sends it as SNI, instead of the host of
+// the URL, e.g. for appliances sharing an IP address.
+func WithTLSServerName(name string) RequestOption {
+	return func(o *requestOptions) {
+		override := tlsOverride{}
+		if o.tls != nil {
+			override = *o.tls
+		}
+		override.serverName = name
+		o.tls = &override
+	}
+}
+
+// WithCertificateFingerprints accepts the server only if its leaf certificate has one of the given SHA-256
+// fingerprints, hex encoded with or without colons, e.g. during a certificate migration. The chain is still
+// verified, unless the client's TLS config skips verification; the fingerprints are then the only check.
+func WithCertificateFingerprints(fingerprints ...string) RequestOption {
+	normalized := make([]string, 0, len(fingerprints))
+	for _, fingerprint := range fingerprints {
+		normalized = append(normalized, normalizeFingerprint(fingerprint))
+	}
+	sort.Strings(normalized)
+
+	return func(o *requestOptions) {
+		override := tlsOverride{}
+		if o.tls != nil {
+			override = *o.tls
+		}
+		override.fingerprints = strings.Join(normalized, ",")
+		o.tls = &override
+	}
+}
+
+// CertificateFingerprint returns the SHA-256 fingerprint of the certificate in DER form, as accepted by
+// WithCertificateFingerprints.
+func CertificateFingerprint(der []byte) string {
+	sum := sha256.Sum256(der)
+	return hex.EncodeToString(sum[:])
+}
+
+func normalizeFingerprint(fingerprint string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(fingerprint), ":", ""))
+}
+
+// verifyConnection checks the leaf certificate of the connection against the fingerprints of o.
+func (o tlsOverride) verifyConnection(next func(tls.ConnectionState) error) func(tls.ConnectionState) error {
+	return func(state tls.ConnectionState) error {
+		if next != nil {
+			if err := next(state); err != nil {
+				return err
+			}
+		}
+		if o.fingerprints == "" {
+			return nil
+		}
+		if len(state.PeerCertificates) == 0 {
+			return &CertificateMismatchError{Message: "Server presented no certificate."}
+		}
+		fingerprint := CertificateFingerprint(state.PeerCertificates[0].Raw)
+		for _, expected := range strings.Split(o.fingerprints, ",") {
+			if fingerprint == expected {
+				return nil
+			}
+		}
+		return &CertificateMismatchError{
+			Message:     fmt.Sprintf("Certificate fingerprint %s is not expected.", fingerprint),
+			Fingerprint: fingerprint,
+		}
+	}
+}
+
+// tlsTransportKey identifies the transport of an override, connections are never shared across overrides.
+type tlsTransportKey struct {
+	base     *http.Transport
+	override tlsOverride
+}
+
+// tlsOverrideClient returns a copy of client using a transport with the override applied. Transports are cached,
+// so connections are reused between requests with the same override.
+func (h *HttpClient) tlsOverrideClient(client *http.Client, override tlsOverride) (*http.Client, error) {
+	base, ok := client.Transport.(*http.Transport)
+	if !ok {
+		return nil, &ConfigError{Message: "TLS overrides require a *http.Transport.", Field: "tls"}
+	}
+
+	key := tlsTransportKey{base: base, override: override}
+	transport, ok := h.tlsTransports.Load(key)
+	if !ok {
+		updated := base.Clone()
+		config := &tls.Config{}
+		if updated.TLSClientConfig != nil {
+			config = updated.TLSClientConfig.Clone()
+		}
+		if override.serverName != "" {
+			config.ServerName = override.serverName
+		}
+		config.VerifyConnection = override.verifyConnection(config.VerifyConnection)
+		updated.TLSClientConfig = config
+		transport, _ = h.tlsTransports.LoadOrStore(key, updated)
+	}
+
+	copied := *client
+	copied.Transport = transport.(*http.Transport)
+	return &copied, nil
+}
@@ -0,0 +1,64 @@
+package http
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestTLSOverrides(t *testing.T) {
+	var mu sync.Mutex
+	var serverNames []string
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.TLS = &tls.Config{GetConfigForClient: func(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+		mu.Lock()
+		serverNames = append(serverNames, hello.ServerName)
+		mu.Unlock()
+		return nil, nil
+	}}
+	server.StartTLS()
+	defer server.Close()
+
+	trusted := server.Client().Transport.(*http.Transport).TLSClientConfig
+	client := NewHttpClientWithConfig(NewDefaultHttpConfig(server.URL, WithTLSConfig(trusted)))
+	fingerprint := CertificateFingerprint(server.Certificate().Raw)
+
+	get := func(opts ...RequestOption) error {
+		resp, err := client.Get(WithRequestOptions(context.Background(), opts...), "/")
+		if err == nil {
+			resp.Body.Close()
+		}
+		return err
+	}
+
+	if err := get(WithTLSServerName("example.com")); err != nil {
+		t.Errorf("Expected the certificate to be valid for example.com but got %v", err)
+	}
+	mu.Lock()
+	if len(serverNames) != 1 || serverNames[0] != "example.com" {
+		t.Errorf("Expected SNI example.com but got %v", serverNames)
+	}
+	mu.Unlock()
+
+	var tlsErr *TLSError
+	if err := get(WithTLSServerName("other.test")); !errors.As(err, &tlsErr) {
+		t.Errorf("Expected TLSError for a foreign server name but got %v", err)
+	}
+
+	if err := get(WithCertificateFingerprints("AA:BB", fingerprint)); err != nil {
+		t.Errorf("Expected the expected fingerprint to be accepted but got %v", err)
+	}
+
+	var mismatch *CertificateMismatchError
+	if err := get(WithCertificateFingerprints("aabb")); !errors.As(err, &mismatch) || mismatch.Fingerprint != fingerprint {
+		t.Errorf("Expected CertificateMismatchError but got %v", err)
+	}
+
+	if err := get(); err != nil {
+		t.Errorf("Expected requests without overrides to be unaffected but got %v", err)
+	}
+}
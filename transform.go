@@ -0,0 +1,153 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"unicode"
+)
+
+// xssiPrefixes are the anti-JSON-hijacking prefixes APIs prepend to JSON responses.
+var xssiPrefixes = []string{")]}',", ")]}'", "while(1);", "for(;;);"}
+
+// ResponseTransformer rewrites a response body before it is decoded.
+type ResponseTransformer interface {
+	Transform(resp *http.Response, body []byte) ([]byte, error)
+}
+
+// ResponseTransformerFunc adapts a function to the ResponseTransformer interface.
+type ResponseTransformerFunc func(resp *http.Response, body []byte) ([]byte, error)
+
+func (f ResponseTransformerFunc) Transform(resp *http.Response, body []byte) ([]byte, error) {
+	return f(resp, body)
+}
+
+// AddResponseTransformer appends transformers run in order by DecodeResponse.
+func (h *HttpClient) AddResponseTransformer(transformers ...ResponseTransformer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.transformers = append(h.transformers, transformers...)
+}
+
+// DecodeResponse reads and closes the body of resp, runs the response transformers and decodes the result into
+// out with the Codec registered for its Content-Type, as JSON if there is none. With an Envelope configured,
+// see WithEnvelope, its data key is decoded instead.
+func (h *HttpClient) DecodeResponse(resp *http.Response, out interface{}) error {
+	if h.envelope() != nil {
+		return h.DecodeEnvelope(resp, out, nil)
+	}
+	body, err := h.transformedBody(resp)
+	if err != nil {
+		return err
+	}
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil
+	}
+	if codec, ok := CodecFor(resp.Header.Get("Content-Type")); ok {
+		return codec.Decode(body, out)
+	}
+	return json.Unmarshal(body, out)
+}
+
+// transformedBody reads and closes the body of resp and runs the response transformers on it.
+func (h *HttpClient) transformedBody(resp *http.Response) ([]byte, error) {
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	h.mu.RLock()
+	transformers := h.transformers
+	h.mu.RUnlock()
+
+	for _, transformer := range transformers {
+		if body, err = transformer.Transform(resp, body); err != nil {
+			return nil, err
+		}
+	}
+	return body, nil
+}
+
+// StripXSSIPrefix removes anti-hijacking prefixes like )]}' from JSON responses.
+func StripXSSIPrefix() ResponseTransformer {
+	return ResponseTransformerFunc(func(resp *http.Response, body []byte) ([]byte, error) {
+		trimmed := bytes.TrimLeft(body, " \t\r\n")
+		for _, prefix := range xssiPrefixes {
+			if bytes.HasPrefix(trimmed, []byte(prefix)) {
+				return trimmed[len(prefix):], nil
+			}
+		}
+		return body, nil
+	})
+}
+
+// UnwrapEnvelope replaces a JSON object body by the value of its key, e.g. "data".
+// Bodies which are no object or lack the key are left untouched.
+func UnwrapEnvelope(key string) ResponseTransformer {
+	return ResponseTransformerFunc(func(resp *http.Response, body []byte) ([]byte, error) {
+		var envelope map[string]json.RawMessage
+		if err := json.Unmarshal(body, &envelope); err != nil {
+			return body, nil
+		}
+		if value, ok := envelope[key]; ok {
+			return value, nil
+		}
+		return body, nil
+	})
+}
+
+// SnakeToCamelKeys renames snake_case object keys to camelCase, so they match Go field names
+// without json tags.
+func SnakeToCamelKeys() ResponseTransformer {
+	return ResponseTransformerFunc(func(resp *http.Response, body []byte) ([]byte, error) {
+		var value interface{}
+		decoder := json.NewDecoder(bytes.NewReader(body))
+		decoder.UseNumber()
+		if err := decoder.Decode(&value); err != nil {
+			return body, nil
+		}
+		return json.Marshal(renameKeys(value, snakeToCamel))
+	})
+}
+
+func renameKeys(value interface{}, rename func(string) string) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		renamed := make(map[string]interface{}, len(v))
+		for key, child := range v {
+			renamed[rename(key)] = renameKeys(child, rename)
+		}
+		return renamed
+	case []interface{}:
+		for i, child := range v {
+			v[i] = renameKeys(child, rename)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+func snakeToCamel(key string) string {
+	if !strings.Contains(key, "_") {
+		return key
+	}
+
+	var b strings.Builder
+	upper := false
+	for i, r := range key {
+		switch {
+		case r == '_' && i > 0:
+			upper = true
+		case upper:
+			b.WriteRune(unicode.ToUpper(r))
+			upper = false
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,53 @@
+package http
+
+import (
+	"net/http"
+	"testing"
+)
+
+type fixtureUser struct {
+	UserID    int
+	FirstName string
+}
+
+func TestHttpClient_DecodeResponseWithTransformers(t *testing.T) {
+	server := mockServer(http.StatusOK, contentTypeJSON, ")]}'\n{\"data\": {\"user_id\": 1, \"first_name\": \"Jane\"}}")
+	defer server.Close()
+
+	client := createTestHTTPClient(server.URL)
+	client.AddResponseTransformer(StripXSSIPrefix(), UnwrapEnvelope("data"), SnakeToCamelKeys())
+
+	resp, _ := client.GetFrom("")
+	user := fixtureUser{}
+	if err := client.DecodeResponse(resp, &user); err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	if user.UserID != 1 || user.FirstName != "Jane" {
+		t.Errorf("Expected user 1 Jane but got %v", user)
+	}
+}
+
+func TestUnwrapEnvelope_KeepsOtherBodies(t *testing.T) {
+	body := []byte("[1, 2]")
+	transformed, err := UnwrapEnvelope("data").Transform(nil, body)
+
+	if err != nil || string(transformed) != "[1, 2]" {
+		t.Errorf("Expected body to be untouched but got %s, %v", transformed, err)
+	}
+}
+
+func TestSnakeToCamel(t *testing.T) {
+	cases := map[string]string{
+		"user_id":     "userId",
+		"_private":    "_private",
+		"created_at_": "createdAt",
+		"plain":       "plain",
+	}
+
+	for input, expected := range cases {
+		if actual := snakeToCamel(input); actual != expected {
+			t.Errorf("Expected %s but got %s for %s", expected, actual, input)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package http
+
+import (
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Transport returns a http.RoundTripper sending requests through the client's pipeline: middleware, events,
+// the response watchdog, authentication, idempotency keys, the RetryPolicy and graceful shutdown. It lets existing code using *http.Client or
+// third-party SDKs benefit from the client without adopting the Client interface:
+//
+//	sdk := thirdparty.New(&http.Client{Transport: client.Transport()})
+//
+// Unlike ExecuteRequest, unsuccessful statuses are returned as responses, as http.RoundTripper requires.
+// Requests keep their URL; the client's AuthProvider only authenticates requests to the host of its base URL,
+// so credentials are not leaked to other hosts an SDK talks to. Username and password of the HttpConfig are
+// set when creating requests through the client and therefore not applied.
+func (h *HttpClient) Transport() http.RoundTripper {
+	return RoundTripperFunc(h.transportRoundTrip)
+}
+
+func (h *HttpClient) transportRoundTrip(r *http.Request) (*http.Response, error) {
+	start := time.Now()
+	prepared, done, err := h.begin(r)
+	if err != nil {
+		return nil, h.newRequestError(r, start, err)
+	}
+	r = h.restrictAuth(prepared)
+
+	h.emit(Event{Type: EventRequestStarted, Time: start, Request: r})
+	resp, err := h.dispatch(r)
+	if err != nil {
+		if resp != nil {
+			drainAndClose(resp.Body)
+			resp = nil
+		}
+		err = h.newRequestError(r, start, &RemoteError{r.URL.Host, classifyError(err)})
+	}
+	h.emit(Event{Type: EventRequestFinished, Request: r, Response: resp, Err: err, Duration: time.Since(start)})
+
+	if resp == nil || resp.Body == nil {
+		done()
+		return resp, err
+	}
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: done}
+	return resp, nil
+}
+
+// restrictAuth sends r without the client's credentials if it targets another host than the client's base URL.
+func (h *HttpClient) restrictAuth(r *http.Request) *http.Request {
+	if options := requestOptionsFrom(r.Context()); options != nil && options.authOverride {
+		return r
+	}
+
+	baseURL, _, _, err := h.resolveTarget(r.Context())
+	if err == nil {
+		if base, err := url.Parse(baseURL); err == nil && base.Host == r.URL.Host {
+			return r
+		}
+	}
+	return r.WithContext(WithRequestOptions(r.Context(), WithoutAuth()))
+}
@@ -0,0 +1,95 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestHttpClient_Transport(t *testing.T) {
+	var authorization []string
+	server := mockServerWith(func(w http.ResponseWriter, r *http.Request) {
+		authorization = append(authorization, r.Header.Get("Authorization"))
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	defer server.Close()
+	other := mockServerWith(func(w http.ResponseWriter, r *http.Request) {
+		authorization = append(authorization, r.Header.Get("Authorization"))
+	})
+	defer other.Close()
+
+	stats := NewStats()
+	client := NewHttpClientWithConfig(NewDefaultHttpConfig(server.URL, WithEvents(stats)))
+	client.SetAuthProvider(BearerAuth(CredentialSourceFunc(func(ctx context.Context) (Credentials, error) {
+		return Credentials{Token: "token"}, nil
+	})))
+	var used bool
+	client.Use(func(next RoundTripperFunc) RoundTripperFunc {
+		return func(r *http.Request) (*http.Response, error) {
+			used = true
+			return next(r)
+		}
+	})
+
+	plain := &http.Client{Transport: client.Transport()}
+	resp, err := plain.Get(server.URL + "/missing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected the status to be returned as response but got %d", resp.StatusCode)
+	}
+
+	resp, err = plain.Get(other.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if !used || stats.Snapshot().Requests != 2 {
+		t.Errorf("Expected requests to pass the pipeline but got %+v", stats.Snapshot())
+	}
+	if len(authorization) != 2 || authorization[0] != "Bearer token" || authorization[1] != "" {
+		t.Errorf("Expected credentials only for the base URL's host but got %v", authorization)
+	}
+}
+
+func TestHttpClient_TransportError(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	client := createTestHTTPClient(server.URL)
+	_, err := (&http.Client{Transport: client.Transport()}).Get(server.URL)
+	if err == nil || !strings.Contains(err.Error(), "refused") {
+		t.Errorf("Expected a classified transport error but got %v", err)
+	}
+}
+
+func TestHttpClient_TransportRetries(t *testing.T) {
+	var calls int32
+	server := mockServerWith(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	})
+	defer server.Close()
+
+	policy := NewRetryPolicy(2).WithBackoff(noBackoff)
+	client := NewHttpClientWithConfig(NewDefaultHttpConfig(server.URL, WithRetryPolicy(policy)))
+
+	resp, err := (&http.Client{Transport: client.Transport()}).Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK || calls != 2 {
+		t.Errorf("Expected success after a retried 503 but got %d after %d attempts", resp.StatusCode, calls)
+	}
+}
@@ -0,0 +1,111 @@
+package http
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// VerboseTrace returns a Middleware writing connection, TLS, request and response header events to w in the
+// style of curl -v: "*" lines describe the connection, ">" lines what was sent and "<" lines what was received.
+// DefaultRedactedHeaders and DefaultRedactedParams are masked, bodies are not written.
+func VerboseTrace(w io.Writer) Middleware {
+	if w == nil {
+		panic("writer is nil")
+	}
+	out := &syncWriter{w: w}
+
+	return func(next RoundTripperFunc) RoundTripperFunc {
+		return func(r *http.Request) (*http.Response, error) {
+			var headers []string
+			trace := &httptrace.ClientTrace{
+				ConnectStart: func(network string, addr string) {
+					out.printf("*   Trying %s...\n", addr)
+				},
+				ConnectDone: func(network string, addr string, err error) {
+					if err != nil {
+						out.printf("* connect to %s failed: %v\n", addr, err)
+						return
+					}
+					out.printf("* Connected to %s (%s)\n", r.URL.Hostname(), addr)
+				},
+				GotConn: func(info httptrace.GotConnInfo) {
+					if info.Reused {
+						out.printf("* Re-using existing connection with host %s\n", r.URL.Hostname())
+					}
+				},
+				TLSHandshakeDone: func(state tls.ConnectionState, err error) {
+					if err != nil {
+						out.printf("* TLS handshake failed: %v\n", err)
+						return
+					}
+					out.printf("* SSL connection using %s / %s\n", tls.VersionName(state.Version), tls.CipherSuiteName(state.CipherSuite))
+					if state.NegotiatedProtocol != "" {
+						out.printf("* ALPN: server accepted %s\n", state.NegotiatedProtocol)
+					}
+					if len(state.PeerCertificates) > 0 {
+						cert := state.PeerCertificates[0]
+						out.printf("* Server certificate:\n*  subject: %s\n*  expire date: %s\n*  issuer: %s\n",
+							cert.Subject, cert.NotAfter.UTC().Format("Jan  2 15:04:05 2006 GMT"), cert.Issuer)
+					}
+				},
+				WroteHeaderField: func(key string, values []string) {
+					for _, value := range values {
+						headers = append(headers, fmt.Sprintf("> %s: %s\n", key, redactionFrom(r.Context()).headerValue(key, value)))
+					}
+				},
+				WroteHeaders: func() {
+					out.printf("> %s %s %s\n%s>\n", r.Method, requestURI(redactionFrom(r.Context()), r.URL), r.Proto, strings.Join(headers, ""))
+				},
+			}
+
+			resp, err := next(r.WithContext(httptrace.WithClientTrace(r.Context(), trace)))
+			if err != nil {
+				out.printf("* error: %v\n", err)
+				return resp, err
+			}
+
+			var b strings.Builder
+			fmt.Fprintf(&b, "< %s %s\n", resp.Proto, resp.Status)
+			keys := make([]string, 0, len(resp.Header))
+			for key := range resp.Header {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				for _, value := range resp.Header[key] {
+					fmt.Fprintf(&b, "< %s: %s\n", key, redactionFrom(r.Context()).headerValue(key, value))
+				}
+			}
+			b.WriteString("<\n")
+			out.printf("%s", b.String())
+
+			return resp, nil
+		}
+	}
+}
+
+// requestURI returns the request URI of u with secret query parameters masked.
+func requestURI(p *redaction, u *url.URL) string {
+	masked := *u
+	masked.RawQuery = p.query(u.RawQuery)
+	return masked.RequestURI()
+}
+
+// syncWriter serializes writes of trace callbacks which may run on different goroutines.
+type syncWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (s *syncWriter) printf(format string, args ...interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fmt.Fprintf(s.w, format, args...)
+}
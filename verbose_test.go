@@ -0,0 +1,45 @@
+package http
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestVerboseTrace(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Set-Cookie", "session=secret-session")
+		w.Header().Set("Content-Type", contentTypeJSON)
+	}))
+	defer server.Close()
+
+	var output bytes.Buffer
+	client := NewHttpClientWithConfigAndClient(NewHttpConfig(server.URL, "user", "secret", ""), server.Client())
+	client.Use(VerboseTrace(&output))
+
+	resp, err := client.GetFrom("/users")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	trace := output.String()
+	for _, expected := range []string{
+		"*   Trying 127.0.0.1:",
+		"* SSL connection using TLS",
+		"> GET /users HTTP/1.1\n",
+		"> Authorization: Basic xxxxx\n",
+		"< HTTP/1.1 200 OK\n",
+		"< Content-Type: application/json\n",
+		"< Set-Cookie: xxxxx\n",
+	} {
+		if !strings.Contains(trace, expected) {
+			t.Errorf("Expected %q in trace\n%s", expected, trace)
+		}
+	}
+	if strings.Contains(trace, "secret") {
+		t.Errorf("Expected secrets to be redacted\n%s", trace)
+	}
+}
@@ -0,0 +1,149 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Phases of a response the watchdog reports in a SlowResponseError.
+const (
+	SlowResponseHeaders = "headers"
+	SlowResponseBody    = "body"
+)
+
+// SlowResponseError is returned when the watchdog aborts a request because the response headers did not
+// arrive in time or reading the body stalled.
+type SlowResponseError struct {
+	Message string
+	URL     string
+	Phase   string
+	Timeout time.Duration
+}
+
+func (e SlowResponseError) Error() string {
+	return e.Message
+}
+
+// watchdogLimits are the timeouts of the response watchdog, zero disables a limit.
+type watchdogLimits struct {
+	headerTimeout time.Duration
+	stallTimeout  time.Duration
+}
+
+func (l watchdogLimits) enabled() bool {
+	return l.headerTimeout > 0 || l.stallTimeout > 0
+}
+
+// WithDefaultWatchdog aborts requests whose response headers do not arrive within headerTimeout or whose body
+// delivers no bytes for stallTimeout with a SlowResponseError. Zero disables a limit.
+func WithDefaultWatchdog(headerTimeout time.Duration, stallTimeout time.Duration) Option {
+	return func(c *HttpConfig) {
+		c.watchdog = watchdogLimits{headerTimeout: headerTimeout, stallTimeout: stallTimeout}
+	}
+}
+
+// WithWatchdog overrides the client's watchdog limits for a single request, see WithDefaultWatchdog.
+func WithWatchdog(headerTimeout time.Duration, stallTimeout time.Duration) RequestOption {
+	return func(o *requestOptions) {
+		o.watchdog = &watchdogLimits{headerTimeout: headerTimeout, stallTimeout: stallTimeout}
+	}
+}
+
+// watchdog wraps next to enforce limits on the time to the response headers and between body reads.
+func watchdog(next RoundTripperFunc, limits watchdogLimits) RoundTripperFunc {
+	return func(r *http.Request) (*http.Response, error) {
+		ctx, cancel := context.WithCancel(r.Context())
+
+		var timer *time.Timer
+		fired := make(chan struct{})
+		if limits.headerTimeout > 0 {
+			timer = time.AfterFunc(limits.headerTimeout, func() {
+				close(fired)
+				cancel()
+			})
+		}
+
+		resp, err := next(r.WithContext(ctx))
+		if timer != nil && !timer.Stop() {
+			<-fired
+			cancel()
+			if resp != nil {
+				drainAndClose(resp.Body)
+			}
+			return nil, &SlowResponseError{
+				Message: fmt.Sprintf("No response headers within %s.", limits.headerTimeout),
+				URL:     redactionFrom(r.Context()).url(r.URL),
+				Phase:   SlowResponseHeaders,
+				Timeout: limits.headerTimeout,
+			}
+		}
+		if err != nil || resp.Body == nil {
+			cancel()
+			return resp, err
+		}
+
+		if limits.stallTimeout <= 0 {
+			resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+			return resp, nil
+		}
+		resp.Body = newStallReader(resp.Body, cancel, redactionFrom(r.Context()).url(r.URL), limits.stallTimeout)
+		return resp, nil
+	}
+}
+
+// stallReader aborts reading a body once no bytes arrived for the stall timeout.
+type stallReader struct {
+	body    io.ReadCloser
+	cancel  context.CancelFunc
+	url     string
+	timeout time.Duration
+	timer   *time.Timer
+
+	mu      sync.Mutex
+	stalled bool
+}
+
+func newStallReader(body io.ReadCloser, cancel context.CancelFunc, url string, timeout time.Duration) *stallReader {
+	s := &stallReader{body: body, cancel: cancel, url: url, timeout: timeout}
+	s.timer = time.AfterFunc(timeout, s.stall)
+	return s
+}
+
+func (s *stallReader) stall() {
+	s.mu.Lock()
+	s.stalled = true
+	s.mu.Unlock()
+	s.cancel()
+}
+
+func (s *stallReader) Read(p []byte) (int, error) {
+	n, err := s.body.Read(p)
+
+	s.mu.Lock()
+	stalled := s.stalled
+	s.mu.Unlock()
+	if stalled {
+		return n, &SlowResponseError{
+			Message: fmt.Sprintf("Response body stalled for %s.", s.timeout),
+			URL:     s.url,
+			Phase:   SlowResponseBody,
+			Timeout: s.timeout,
+		}
+	}
+
+	if n > 0 {
+		s.timer.Reset(s.timeout)
+	}
+	return n, err
+}
+
+func (s *stallReader) Close() error {
+	s.timer.Stop()
+	err := s.body.Close()
+	s.cancel()
+	return err
+}
@@ -0,0 +1,71 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestWatchdog_HeaderTimeout(t *testing.T) {
+	server := mockServerWith(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(time.Second):
+		case <-r.Context().Done():
+		}
+	})
+	defer server.Close()
+
+	client := NewHttpClientWithConfig(NewDefaultHttpConfig(server.URL, WithDefaultWatchdog(50*time.Millisecond, 0)))
+
+	_, err := client.GetFrom("/")
+	var slowErr *SlowResponseError
+	if !errors.As(err, &slowErr) || slowErr.Phase != SlowResponseHeaders {
+		t.Errorf("Expected SlowResponseError for headers but got %v", err)
+	}
+}
+
+func TestWatchdog_BodyStall(t *testing.T) {
+	server := mockServerWith(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("partial"))
+		w.(http.Flusher).Flush()
+		select {
+		case <-time.After(time.Second):
+		case <-r.Context().Done():
+		}
+	})
+	defer server.Close()
+
+	client := createTestHTTPClient(server.URL)
+	ctx := WithRequestOptions(context.Background(), WithWatchdog(0, 50*time.Millisecond))
+
+	resp, err := client.GetFromWithContext(ctx, "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	var slowErr *SlowResponseError
+	if !errors.As(err, &slowErr) || slowErr.Phase != SlowResponseBody {
+		t.Errorf("Expected SlowResponseError for body but got %v", err)
+	}
+	if string(body) != "partial" {
+		t.Errorf("Expected partial body but got %q", body)
+	}
+}
+
+func TestWatchdog_FastResponse(t *testing.T) {
+	server := mockServer(http.StatusOK, contentTypeJSON, fixtureBasicJSON)
+	defer server.Close()
+
+	client := NewHttpClientWithConfig(NewDefaultHttpConfig(server.URL, WithDefaultWatchdog(time.Second, time.Second)))
+
+	resp, err := client.GetFrom("/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertResponseBodyIs(resp, fixtureBasicJSON, t)
+}
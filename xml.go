@@ -0,0 +1,46 @@
+package http
+
+import (
+	"context"
+	"encoding/xml"
+	"net/http"
+)
+
+const xmlType = "application/xml"
+
+// XMLCodec returns the Codec for application/xml and text/xml, which is registered by default. Values are
+// marshaled with encoding/xml, so xml tags apply.
+func XMLCodec() Codec {
+	return xmlCodec{}
+}
+
+type xmlCodec struct{}
+
+func (xmlCodec) ContentTypes() []string {
+	return []string{xmlType, "text/xml"}
+}
+
+func (xmlCodec) Encode(v interface{}) ([]byte, error) {
+	data, err := xml.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), data...), nil
+}
+
+func (xmlCodec) Decode(data []byte, v interface{}) error {
+	return xml.Unmarshal(data, v)
+}
+
+// GetXML gets path and decodes the XML response into out, applying the client's response transformers. The
+// body is closed in any case, and discarded if out is nil. Unsuccessful statuses are reported as error, like
+// UnauthorizedError or NotFoundError.
+func (h *HttpClient) GetXML(ctx context.Context, path string, out interface{}) error {
+	return h.sendEncoded(ctx, http.MethodGet, path, XMLCodec(), nil, out)
+}
+
+// PostXML posts in encoded as XML to path and decodes the XML response into out like GetXML. A nil in is sent
+// without body.
+func (h *HttpClient) PostXML(ctx context.Context, path string, in interface{}, out interface{}) error {
+	return h.sendEncoded(ctx, http.MethodPost, path, XMLCodec(), in, out)
+}
@@ -0,0 +1,112 @@
+package http
+
+import (
+	"context"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+type xmlUser struct {
+	XMLName xml.Name `xml:"user"`
+	ID      int      `xml:"id,attr"`
+	Name    string   `xml:"name"`
+}
+
+func TestXMLCodec(t *testing.T) {
+	codec, ok := CodecFor("text/xml; charset=utf-8")
+	if !ok {
+		t.Fatal("Expected XML codec to be registered by default")
+	}
+
+	data, err := codec.Encode(xmlUser{ID: 7, Name: "Ada"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(string(data), `<user id="7"><name>Ada</name></user>`) {
+		t.Errorf("Unexpected encoding %s", data)
+	}
+
+	var decoded xmlUser
+	if err := codec.Decode(data, &decoded); err != nil || decoded.ID != 7 || decoded.Name != "Ada" {
+		t.Errorf("Unexpected decoding %+v, %v", decoded, err)
+	}
+}
+
+func TestHttpClient_GetXML(t *testing.T) {
+	server := mockServerWith(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept") != xmlType {
+			t.Errorf("Expected XML to be accepted but got %q", r.Header.Get("Accept"))
+		}
+		w.Header().Set("Content-Type", xmlType)
+		w.Write([]byte(`<user id="7"><name>Ada</name></user>`))
+	})
+	defer server.Close()
+
+	var user xmlUser
+	if err := NewDefaultHttpClient(server.URL).GetXML(context.Background(), "/users/7", &user); err != nil {
+		t.Fatal(err)
+	}
+	if user.ID != 7 || user.Name != "Ada" {
+		t.Errorf("Unexpected user %+v", user)
+	}
+}
+
+func TestHttpClient_PostXML(t *testing.T) {
+	server := mockServerWith(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.Header.Get("Content-Type") != xmlType || !strings.Contains(string(body), "<name>Ada</name>") {
+			t.Errorf("Expected XML body but got %q: %s", r.Header.Get("Content-Type"), body)
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`<user id="8"><name>Ada</name></user>`))
+	})
+	defer server.Close()
+
+	var created xmlUser
+	if err := NewDefaultHttpClient(server.URL).PostXML(context.Background(), "/users", xmlUser{Name: "Ada"}, &created); err != nil {
+		t.Fatal(err)
+	}
+	if created.ID != 8 {
+		t.Errorf("Unexpected user %+v", created)
+	}
+}
+
+func TestHttpClient_GetXMLNotFound(t *testing.T) {
+	server := mockServer(http.StatusNotFound, xmlType, "<error/>")
+	defer server.Close()
+
+	err := NewDefaultHttpClient(server.URL).GetXML(context.Background(), "/users/9", &xmlUser{})
+	if _, ok := err.(*NotFoundError); !ok {
+		t.Errorf("Expected NotFoundError but got %v", err)
+	}
+}
+
+func TestHttpClient_ContentNegotiation(t *testing.T) {
+	server := mockServerWith(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Header.Get("Accept") + " " + r.Header.Get("Content-Type")))
+	})
+	defer server.Close()
+
+	client := NewHttpClientWithConfig(NewDefaultHttpConfig(server.URL, WithAccept(xmlType), WithContentType(xmlType)))
+	resp, err := client.Post(context.Background(), "/users", strings.NewReader("<user/>"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if body, _ := io.ReadAll(resp.Body); string(body) != xmlType+" "+xmlType {
+		t.Errorf("Expected XML to be negotiated but got %q", body)
+	}
+}
+
+func TestRequestBuilder_AsXml(t *testing.T) {
+	request, err := NewRequestBuilder().Post().Path("http://localhost/users").WithContent(strings.NewReader("<user/>")).AsXml().Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if request.Header.Get("Accept") != xmlType || request.Header.Get("Content-Type") != xmlType {
+		t.Errorf("Expected XML headers but got %v", request.Header)
+	}
+}
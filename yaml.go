@@ -0,0 +1,733 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// YAMLError is returned when a YAML document can not be decoded.
+type YAMLError struct {
+	Message string
+	Line    int
+}
+
+func (e YAMLError) Error() string {
+	return e.Message
+}
+
+// YAMLCodec returns an optional Codec for application/yaml and text/yaml, used by configuration and
+// Kubernetes-adjacent APIs. Register it with RegisterCodec.
+//
+// Values are converted through JSON, so json tags apply and documents are limited to what JSON can express.
+// Numbers are decoded into string fields in their canonical form, e.g. 007 as "7"; quote them to keep them verbatim.
+// Decoding supports block and flow collections, plain and quoted scalars, literal and folded block scalars and
+// comments, but neither anchors, aliases, tags nor multiple documents.
+func YAMLCodec() Codec {
+	return yamlCodec{}
+}
+
+type yamlCodec struct{}
+
+func (yamlCodec) ContentTypes() []string {
+	return []string{"application/yaml", "text/yaml", "application/x-yaml"}
+}
+
+func (yamlCodec) Encode(v interface{}) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	value, err := decodeOrderedJSON(decoder)
+	if err != nil {
+		return nil, err
+	}
+
+	var b strings.Builder
+	writeYAML(&b, value, 0)
+	return []byte(b.String()), nil
+}
+
+func (yamlCodec) Decode(data []byte, v interface{}) error {
+	value, err := parseYAML(string(data))
+	if err != nil {
+		return err
+	}
+	for {
+		converted, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		err = json.Unmarshal(converted, v)
+
+		// plain scalars like 1.2 resolve to numbers, but may be decoded into strings like with other YAML libraries
+		var typeErr *json.UnmarshalTypeError
+		if !errors.As(err, &typeErr) || typeErr.Value != "number" || typeErr.Type.Kind() != reflect.String ||
+			!quoteYAMLNumber(value, strings.Split(typeErr.Field, ".")) {
+			return err
+		}
+	}
+}
+
+// quoteYAMLNumber replaces the number at path in value by its string form and reports whether there was one.
+func quoteYAMLNumber(value interface{}, path []string) bool {
+	mapping, ok := value.(yamlMapping)
+	if !ok || len(path) == 0 {
+		return false
+	}
+	for i, pair := range mapping {
+		if !strings.EqualFold(pair.key, path[0]) {
+			continue
+		}
+		if len(path) > 1 {
+			return quoteYAMLNumber(pair.value, path[1:])
+		}
+		if number, ok := pair.value.(json.Number); ok {
+			mapping[i].value = string(number)
+			return true
+		}
+		return false
+	}
+	return false
+}
+
+// yamlMapping is a mapping keeping the order of its keys.
+type yamlMapping []yamlPair
+
+type yamlPair struct {
+	key   string
+	value interface{}
+}
+
+func (m yamlMapping) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteString("{")
+	for i, pair := range m {
+		if i > 0 {
+			b.WriteString(",")
+		}
+		key, _ := json.Marshal(pair.key)
+		value, err := json.Marshal(pair.value)
+		if err != nil {
+			return nil, err
+		}
+		b.Write(key)
+		b.WriteString(":")
+		b.Write(value)
+	}
+	b.WriteString("}")
+	return b.Bytes(), nil
+}
+
+// decodeOrderedJSON decodes the next JSON value, keeping the order of object keys.
+func decodeOrderedJSON(decoder *json.Decoder) (interface{}, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch token {
+	case json.Delim('{'):
+		mapping := yamlMapping{}
+		for decoder.More() {
+			key, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := decodeOrderedJSON(decoder)
+			if err != nil {
+				return nil, err
+			}
+			mapping = append(mapping, yamlPair{key: key.(string), value: value})
+		}
+		_, err = decoder.Token()
+		return mapping, err
+	case json.Delim('['):
+		sequence := []interface{}{}
+		for decoder.More() {
+			value, err := decodeOrderedJSON(decoder)
+			if err != nil {
+				return nil, err
+			}
+			sequence = append(sequence, value)
+		}
+		_, err = decoder.Token()
+		return sequence, err
+	}
+	return token, nil
+}
+
+func writeYAML(b *strings.Builder, value interface{}, indent int) {
+	prefix := strings.Repeat(" ", indent)
+	switch v := value.(type) {
+	case yamlMapping:
+		if len(v) == 0 {
+			b.WriteString(prefix + "{}\n")
+			return
+		}
+		for _, pair := range v {
+			b.WriteString(prefix + formatYAMLScalar(pair.key) + ":")
+			switch child := pair.value.(type) {
+			case yamlMapping:
+				if len(child) > 0 {
+					b.WriteString("\n")
+					writeYAML(b, child, indent+2)
+					continue
+				}
+			case []interface{}:
+				if len(child) > 0 {
+					b.WriteString("\n")
+					writeYAML(b, child, indent)
+					continue
+				}
+			}
+			b.WriteString(" " + inlineYAML(pair.value) + "\n")
+		}
+	case []interface{}:
+		if len(v) == 0 {
+			b.WriteString(prefix + "[]\n")
+			return
+		}
+		for _, item := range v {
+			var nested strings.Builder
+			writeYAML(&nested, item, indent+2)
+			b.WriteString(prefix + "- " + strings.TrimPrefix(nested.String(), prefix+"  "))
+		}
+	default:
+		b.WriteString(prefix + inlineYAML(v) + "\n")
+	}
+}
+
+// inlineYAML formats scalars and empty collections.
+func inlineYAML(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return strconv.FormatBool(v)
+	case json.Number:
+		return v.String()
+	case string:
+		return formatYAMLScalar(v)
+	case yamlMapping:
+		return "{}"
+	case []interface{}:
+		return "[]"
+	}
+	return fmt.Sprint(value)
+}
+
+// formatYAMLScalar returns s plain if it is read back as the same string, otherwise double quoted.
+func formatYAMLScalar(s string) string {
+	if s == "" || strings.TrimSpace(s) != s {
+		return strconv.Quote(s)
+	}
+	for i, r := range s {
+		plain := r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("_./", r) ||
+			i > 0 && strings.ContainsRune(" -+=()@", r)
+		if !plain {
+			return strconv.Quote(s)
+		}
+	}
+	if resolved, _ := resolveYAMLScalar(s); resolved != s {
+		return strconv.Quote(s)
+	}
+	return s
+}
+
+type yamlLine struct {
+	number int
+	indent int
+	raw    string
+	text   string
+}
+
+type yamlParser struct {
+	lines []yamlLine
+	pos   int
+}
+
+func parseYAML(document string) (interface{}, error) {
+	p := &yamlParser{}
+	for i, raw := range strings.Split(strings.ReplaceAll(document, "\r\n", "\n"), "\n") {
+		text := strings.TrimRight(stripYAMLComment(raw), " \t")
+		trimmed := strings.TrimLeft(text, " ")
+		if strings.HasPrefix(trimmed, "\t") {
+			return nil, &YAMLError{Message: fmt.Sprintf("Tabs are not allowed for indentation in line %d.", i+1), Line: i + 1}
+		}
+		if text == "---" || text == "..." || strings.HasPrefix(text, "%") {
+			if p.hasContent() && text == "---" {
+				return nil, &YAMLError{Message: "Multiple YAML documents are not supported.", Line: i + 1}
+			}
+			text, trimmed = "", ""
+		}
+		p.lines = append(p.lines, yamlLine{number: i + 1, indent: len(text) - len(trimmed), raw: raw, text: trimmed})
+	}
+
+	p.skipBlank()
+	if p.pos == len(p.lines) {
+		return nil, nil
+	}
+	value, err := p.parseNode(p.lines[p.pos].indent)
+	if err != nil {
+		return nil, err
+	}
+	if p.skipBlank(); p.pos < len(p.lines) {
+		return nil, p.errorf("Unexpected content %q", p.lines[p.pos].text)
+	}
+	return value, nil
+}
+
+func (p *yamlParser) hasContent() bool {
+	for _, line := range p.lines {
+		if line.text != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *yamlParser) skipBlank() {
+	for p.pos < len(p.lines) && p.lines[p.pos].text == "" {
+		p.pos++
+	}
+}
+
+func (p *yamlParser) errorf(format string, args ...interface{}) error {
+	line := 0
+	if p.pos < len(p.lines) {
+		line = p.lines[p.pos].number
+	} else if len(p.lines) > 0 {
+		line = p.lines[len(p.lines)-1].number
+	}
+	return &YAMLError{Message: fmt.Sprintf(format+" in line %d.", append(args, line)...), Line: line}
+}
+
+// parseNode parses the node starting at the current line, which is indented by indent.
+func (p *yamlParser) parseNode(indent int) (interface{}, error) {
+	line := p.lines[p.pos]
+	switch {
+	case isYAMLSequenceItem(line.text):
+		return p.parseSequence(indent)
+	case isYAMLMappingLine(line.text):
+		return p.parseMapping(indent)
+	}
+	p.pos++
+	return p.parseInline(line.text)
+}
+
+func (p *yamlParser) parseSequence(indent int) (interface{}, error) {
+	sequence := []interface{}{}
+	for p.skipBlank(); p.pos < len(p.lines); p.skipBlank() {
+		line := p.lines[p.pos]
+		if line.indent < indent || line.indent == indent && !isYAMLSequenceItem(line.text) {
+			break
+		}
+		if line.indent > indent {
+			return nil, p.errorf("Unexpected indentation")
+		}
+
+		rest := strings.TrimLeft(line.text[1:], " ")
+		var item interface{}
+		var err error
+		if rest == "" {
+			p.pos++
+			item, err = p.parseNested(indent, false)
+		} else {
+			// the item's content continues as if it started on its own line
+			p.lines[p.pos].indent = indent + len(line.text) - len(rest)
+			p.lines[p.pos].text = rest
+			if isYAMLBlockScalar(rest) {
+				p.pos++
+				item, err = p.parseBlockScalar(rest, indent)
+			} else {
+				item, err = p.parseNode(p.lines[p.pos].indent)
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+		sequence = append(sequence, item)
+	}
+	return sequence, nil
+}
+
+func (p *yamlParser) parseMapping(indent int) (interface{}, error) {
+	mapping := yamlMapping{}
+	for p.skipBlank(); p.pos < len(p.lines); p.skipBlank() {
+		line := p.lines[p.pos]
+		if line.indent < indent || line.indent == indent && isYAMLSequenceItem(line.text) {
+			break
+		}
+		if line.indent > indent {
+			return nil, p.errorf("Unexpected indentation")
+		}
+
+		key, rest, ok := splitYAMLMappingLine(line.text)
+		if !ok {
+			return nil, p.errorf("Expected a mapping key")
+		}
+		p.pos++
+
+		var value interface{}
+		var err error
+		switch {
+		case rest == "":
+			value, err = p.parseNested(indent, true)
+		case isYAMLBlockScalar(rest):
+			value, err = p.parseBlockScalar(rest, indent)
+		default:
+			value, err = p.parseInline(rest)
+		}
+		if err != nil {
+			return nil, err
+		}
+		mapping = append(mapping, yamlPair{key: key, value: value})
+	}
+	return mapping, nil
+}
+
+// parseNested parses the node on the lines following a key or sequence item without inline value. The
+// sequence of a key may be indented like the key itself.
+func (p *yamlParser) parseNested(indent int, sequenceAtIndent bool) (interface{}, error) {
+	p.skipBlank()
+	if p.pos == len(p.lines) {
+		return nil, nil
+	}
+	next := p.lines[p.pos]
+	if next.indent > indent || sequenceAtIndent && next.indent == indent && isYAMLSequenceItem(next.text) {
+		return p.parseNode(next.indent)
+	}
+	return nil, nil
+}
+
+// parseBlockScalar parses a literal (|) or folded (>) block scalar whose lines are indented deeper than indent.
+func (p *yamlParser) parseBlockScalar(header string, indent int) (interface{}, error) {
+	var lines []string
+	contentIndent := -1
+	for ; p.pos < len(p.lines); p.pos++ {
+		raw := strings.TrimRight(p.lines[p.pos].raw, " \t\r")
+		trimmed := strings.TrimLeft(raw, " ")
+		if trimmed == "" {
+			lines = append(lines, "")
+			continue
+		}
+		lineIndent := len(raw) - len(trimmed)
+		if lineIndent <= indent {
+			break
+		}
+		if contentIndent < 0 {
+			contentIndent = lineIndent
+		}
+		if lineIndent < contentIndent {
+			break
+		}
+		lines = append(lines, raw[contentIndent:])
+	}
+
+	// trailing blank lines belong to the following content, apart from keep chomping
+	trailing := 0
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+		trailing++
+	}
+	for p.pos > 0 && trailing > 0 && p.lines[p.pos-1].text == "" {
+		p.pos--
+		trailing--
+	}
+
+	var text string
+	if strings.HasPrefix(header, ">") {
+		var b strings.Builder
+		for i, line := range lines {
+			switch {
+			case i == 0 || lines[i-1] == "" && line != "":
+			case line == "" || strings.HasPrefix(line, " ") || strings.HasPrefix(lines[i-1], " "):
+				b.WriteString("\n")
+			default:
+				b.WriteString(" ")
+			}
+			b.WriteString(line)
+		}
+		text = b.String()
+	} else {
+		text = strings.Join(lines, "\n")
+	}
+
+	switch {
+	case len(lines) == 0:
+		return "", nil
+	case strings.Contains(header, "-"):
+		return text, nil
+	case strings.Contains(header, "+"):
+		return text + "\n" + strings.Repeat("\n", trailing), nil
+	}
+	return text + "\n", nil
+}
+
+// parseInline parses a scalar or flow collection written on a single line.
+func (p *yamlParser) parseInline(text string) (interface{}, error) {
+	flow := &yamlFlow{text: text}
+	value, err := flow.parse()
+	if err != nil {
+		return nil, p.errorf("%s", err.Error())
+	}
+	if flow.skipSpace(); flow.pos < len(flow.text) {
+		return nil, p.errorf("Unexpected %q", flow.text[flow.pos:])
+	}
+	return value, nil
+}
+
+// yamlFlow parses flow collections and scalars.
+type yamlFlow struct {
+	text  string
+	pos   int
+	depth int
+}
+
+func (f *yamlFlow) skipSpace() {
+	for f.pos < len(f.text) && f.text[f.pos] == ' ' {
+		f.pos++
+	}
+}
+
+func (f *yamlFlow) parse() (interface{}, error) {
+	f.skipSpace()
+	if f.pos == len(f.text) {
+		return nil, nil
+	}
+
+	switch f.text[f.pos] {
+	case '[':
+		f.pos++
+		f.depth++
+		sequence := []interface{}{}
+		for {
+			if f.skipSpace(); f.pos == len(f.text) {
+				return nil, fmt.Errorf("Unterminated flow sequence")
+			}
+			if f.consume(']') {
+				f.depth--
+				return sequence, nil
+			}
+			value, err := f.parse()
+			if err != nil {
+				return nil, err
+			}
+			sequence = append(sequence, value)
+			if err := f.separator(']'); err != nil {
+				return nil, err
+			}
+		}
+	case '{':
+		f.pos++
+		f.depth++
+		mapping := yamlMapping{}
+		for {
+			if f.skipSpace(); f.pos == len(f.text) {
+				return nil, fmt.Errorf("Unterminated flow mapping")
+			}
+			if f.consume('}') {
+				f.depth--
+				return mapping, nil
+			}
+			key, err := f.scalar(true)
+			if err != nil {
+				return nil, err
+			}
+			var value interface{}
+			if f.skipSpace(); f.consume(':') {
+				if value, err = f.parse(); err != nil {
+					return nil, err
+				}
+			}
+			mapping = append(mapping, yamlPair{key: fmt.Sprint(key), value: value})
+			if err := f.separator('}'); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return f.scalar(false)
+}
+
+func (f *yamlFlow) consume(c byte) bool {
+	if f.pos < len(f.text) && f.text[f.pos] == c {
+		f.pos++
+		return true
+	}
+	return false
+}
+
+func (f *yamlFlow) separator(end byte) error {
+	f.skipSpace()
+	if f.consume(',') {
+		return nil
+	}
+	if f.pos == len(f.text) {
+		return fmt.Errorf("Expected ',' or '%c' but the line ended", end)
+	}
+	if f.text[f.pos] == end {
+		return nil
+	}
+	return fmt.Errorf("Expected ',' or '%c'", end)
+}
+
+// scalar parses a quoted or plain scalar. Keys are returned as written, values are resolved.
+func (f *yamlFlow) scalar(key bool) (interface{}, error) {
+	start := f.pos
+	if f.pos == len(f.text) {
+		return nil, nil
+	}
+	switch f.text[f.pos] {
+	case '"':
+		for f.pos++; f.pos < len(f.text) && f.text[f.pos] != '"'; f.pos++ {
+			if f.text[f.pos] == '\\' {
+				f.pos++
+			}
+		}
+		if f.pos >= len(f.text) {
+			return nil, fmt.Errorf("Unterminated double quoted string")
+		}
+		f.pos++
+		unquoted, err := strconv.Unquote(strings.ReplaceAll(f.text[start:f.pos], `\/`, "/"))
+		if err != nil {
+			return nil, fmt.Errorf("Invalid double quoted string %s", f.text[start:f.pos])
+		}
+		return unquoted, nil
+	case '\'':
+		var b strings.Builder
+		for f.pos++; ; f.pos++ {
+			if f.pos >= len(f.text) {
+				return nil, fmt.Errorf("Unterminated single quoted string")
+			}
+			if f.text[f.pos] == '\'' {
+				if f.pos+1 < len(f.text) && f.text[f.pos+1] == '\'' {
+					b.WriteByte('\'')
+					f.pos++
+					continue
+				}
+				f.pos++
+				return b.String(), nil
+			}
+			b.WriteByte(f.text[f.pos])
+		}
+	case '&', '*', '!':
+		return nil, fmt.Errorf("Anchors, aliases and tags are not supported")
+	}
+
+	for f.pos < len(f.text) {
+		c := f.text[f.pos]
+		if f.depth > 0 && strings.IndexByte(",[]{}", c) >= 0 {
+			break
+		}
+		if c == ':' && (f.pos+1 == len(f.text) || f.text[f.pos+1] == ' ' || f.depth > 0 && strings.IndexByte(",]}", f.text[f.pos+1]) >= 0) {
+			break
+		}
+		f.pos++
+	}
+	plain := strings.TrimSpace(f.text[start:f.pos])
+	if key {
+		return plain, nil
+	}
+	return resolveYAMLScalar(plain)
+}
+
+// resolveYAMLScalar resolves a plain scalar to null, a bool, a number or a string like the YAML core schema.
+func resolveYAMLScalar(s string) (interface{}, error) {
+	switch s {
+	case "", "~", "null", "Null", "NULL":
+		return nil, nil
+	case "true", "True", "TRUE":
+		return true, nil
+	case "false", "False", "FALSE":
+		return false, nil
+	}
+
+	digits := strings.TrimLeft(s, "+-")
+	if len(digits) > 2 && (strings.HasPrefix(digits, "0x") || strings.HasPrefix(digits, "0o")) {
+		if i, err := strconv.ParseInt(s, 0, 64); err == nil {
+			return json.Number(strconv.FormatInt(i, 10)), nil
+		}
+		return s, nil
+	}
+	if digits == "" || strings.Trim(digits, "0123456789.eE+-") != "" || strings.IndexAny(digits, "0123456789") != 0 && digits[0] != '.' {
+		return s, nil
+	}
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return json.Number(strconv.FormatInt(i, 10)), nil
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil && !math.IsInf(f, 0) {
+		return json.Number(strconv.FormatFloat(f, 'g', -1, 64)), nil
+	}
+	return s, nil
+}
+
+func isYAMLSequenceItem(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+func isYAMLBlockScalar(text string) bool {
+	return len(text) > 0 && (text[0] == '|' || text[0] == '>') && strings.Trim(text[1:], "+-0123456789") == ""
+}
+
+func isYAMLMappingLine(text string) bool {
+	_, _, ok := splitYAMLMappingLine(text)
+	return ok
+}
+
+// splitYAMLMappingLine splits "key: value" into its unquoted key and the value text.
+func splitYAMLMappingLine(text string) (string, string, bool) {
+	if text == "" || strings.IndexByte("[{", text[0]) >= 0 {
+		return "", "", false
+	}
+
+	if text[0] == '"' || text[0] == '\'' {
+		flow := &yamlFlow{text: text}
+		key, err := flow.scalar(true)
+		if err != nil {
+			return "", "", false
+		}
+		rest := strings.TrimLeft(text[flow.pos:], " ")
+		if rest != ":" && !strings.HasPrefix(rest, ": ") {
+			return "", "", false
+		}
+		return key.(string), strings.TrimSpace(rest[1:]), true
+	}
+
+	for i := 0; i < len(text); i++ {
+		if text[i] == ':' && (i+1 == len(text) || text[i+1] == ' ') {
+			return strings.TrimSpace(text[:i]), strings.TrimSpace(text[i+1:]), true
+		}
+	}
+	return "", "", false
+}
+
+// stripYAMLComment removes a comment starting with # at the beginning or after whitespace, outside of quotes.
+func stripYAMLComment(line string) string {
+	quote := byte(0)
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case quote != 0:
+			if c == '\\' && quote == '"' || c == '\'' && quote == c && i+1 < len(line) && line[i+1] == c {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			// quotes only start a scalar, apostrophes within words are literal
+			if i == 0 || strings.IndexByte(" \t[{,:-", line[i-1]) >= 0 {
+				quote = c
+			}
+		case c == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return line[:i]
+		}
+	}
+	return line
+}
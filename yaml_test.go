@@ -0,0 +1,168 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type yamlDeployment struct {
+	Name     string            `json:"name"`
+	Replicas int               `json:"replicas"`
+	Ratio    float64           `json:"ratio"`
+	Enabled  bool              `json:"enabled"`
+	Labels   map[string]string `json:"labels"`
+	Ports    []yamlPort        `json:"ports"`
+	Args     []string          `json:"args"`
+	Script   string            `json:"script"`
+	Owner    *string           `json:"owner"`
+}
+
+type yamlPort struct {
+	Name string `json:"name"`
+	Port int    `json:"port"`
+}
+
+func TestYAMLCodec_Decode(t *testing.T) {
+	document := `# deployment
+---
+name: "web: frontend"
+replicas: 3 # comment
+ratio: 0.5
+enabled: yes-not-a-bool
+labels:
+  app: web
+  'tier': "front # end"
+ports:
+- name: http
+  port: 80
+-   name: https
+    port: 443
+args: [--verbose, "--level=2", 'it''s']
+script: |
+  echo hello
+    indented
+
+  echo bye
+owner: ~
+`
+	var decoded struct {
+		yamlDeployment
+		Enabled string `json:"enabled"`
+	}
+	if err := YAMLCodec().Decode([]byte(document), &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := yamlDeployment{
+		Name:     "web: frontend",
+		Replicas: 3,
+		Ratio:    0.5,
+		Labels:   map[string]string{"app": "web", "tier": "front # end"},
+		Ports:    []yamlPort{{Name: "http", Port: 80}, {Name: "https", Port: 443}},
+		Args:     []string{"--verbose", "--level=2", "it's"},
+		Script:   "echo hello\n  indented\n\necho bye\n",
+	}
+	if !reflect.DeepEqual(decoded.yamlDeployment, expected) || decoded.Enabled != "yes-not-a-bool" {
+		t.Errorf("Expected %+v but got %+v", expected, decoded)
+	}
+}
+
+func TestYAMLCodec_DecodeScalarsAndFolding(t *testing.T) {
+	var decoded map[string]interface{}
+	document := "folded: >-\n  one\n  two\n\n  three\nnested:\n  - - 1\n    - 2\n  - {a: 1, b: [x, y]}\nempty:\nhex: 0x1F\n"
+	if err := YAMLCodec().Decode([]byte(document), &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]interface{}{
+		"folded": "one two\nthree",
+		"nested": []interface{}{[]interface{}{1.0, 2.0}, map[string]interface{}{"a": 1.0, "b": []interface{}{"x", "y"}}},
+		"empty":  nil,
+		"hex":    31.0,
+	}
+	if !reflect.DeepEqual(decoded, expected) {
+		t.Errorf("Expected %v but got %v", expected, decoded)
+	}
+}
+
+func TestYAMLCodec_DecodeErrors(t *testing.T) {
+	for _, document := range []string{"a: 1\n   b: 2\n", "a: &anchor 1\n", "a: [1, 2\n", "a: 1\n---\nb: 2\n"} {
+		var decoded interface{}
+		err := YAMLCodec().Decode([]byte(document), &decoded)
+		var yamlErr *YAMLError
+		if !errors.As(err, &yamlErr) || yamlErr.Line == 0 {
+			t.Errorf("Expected a YAMLError with line for %q but got %v", document, err)
+		}
+	}
+}
+
+func TestYAMLCodec_DecodeUnterminatedFlow(t *testing.T) {
+	for _, document := range []string{"{", "[", "{a:", "[1,", "a: {b: [1,"} {
+		var decoded interface{}
+		err := YAMLCodec().Decode([]byte(document), &decoded)
+		var yamlErr *YAMLError
+		if !errors.As(err, &yamlErr) {
+			t.Errorf("Expected a YAMLError for %q but got %v", document, err)
+		}
+	}
+}
+
+func TestYAMLCodec_RoundTrip(t *testing.T) {
+	owner := "true"
+	deployment := yamlDeployment{
+		Name:     "web",
+		Replicas: 2,
+		Ratio:    1.5,
+		Enabled:  true,
+		Labels:   map[string]string{"app": "web", "url": "http://example.com/a b"},
+		Ports:    []yamlPort{{Name: "http", Port: 80}},
+		Args:     []string{},
+		Script:   "line 1\nline 2",
+		Owner:    &owner,
+	}
+
+	encoded, err := YAMLCodec().Encode(deployment)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(encoded), "name: web\nreplicas: 2\n") || !strings.Contains(string(encoded), "ports:\n- name: http\n  port: 80\n") {
+		t.Errorf("Expected block style YAML in field order but got\n%s", encoded)
+	}
+
+	var decoded yamlDeployment
+	if err := YAMLCodec().Decode(encoded, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, deployment) {
+		t.Errorf("Expected %+v but got %+v from\n%s", deployment, decoded, encoded)
+	}
+}
+
+func TestHttpClient_DecodeYAMLResponse(t *testing.T) {
+	RegisterCodec(YAMLCodec())
+	server := mockServerWith(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/yaml; charset=utf-8")
+		w.Write([]byte("name: web\nreplicas: 2\n"))
+	})
+	defer server.Close()
+
+	client := createTestHTTPClient(server.URL)
+	resp, err := client.GetFrom("/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded yamlDeployment
+	if err := client.DecodeResponse(resp, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Name != "web" || decoded.Replicas != 2 {
+		t.Errorf("Unexpected deployment %+v", decoded)
+	}
+
+	if _, err := EncodeBody("application/x-unknown", decoded); err == nil {
+		t.Error("Expected an error for a content type without codec")
+	}
+}